@@ -0,0 +1,216 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// env returns a lookupEnv backed by a map.
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	anonymous := map[string]string{"AUTH_ANONYMOUS": "true"}
+
+	t.Run("Defaults", func(t *testing.T) {
+		cfg, opts, err := Load(nil, env(anonymous), io.Discard)
+
+		require.NoError(t, err)
+		assert.False(t, opts.PrintConfig)
+		assert.Equal(t, 8000, cfg.Server.Port)
+		assert.Equal(t, 10*time.Second, cfg.Server.RequestTimeout)
+		assert.Empty(t, cfg.CORS.AllowedOrigins)
+		assert.True(t, cfg.Features.Swagger)
+		assert.False(t, cfg.Features.Metrics, "/metrics is opt-in")
+	})
+
+	t.Run("File, then environment, then flags", func(t *testing.T) {
+		path := writeFile(t, "api.yaml", `
+server:
+  port: 9000
+  request_timeout: 5s
+  idle_timeout: 2m
+cors:
+  allowed_origins: [https://a.example.com, https://b.example.com]
+auth:
+  anonymous: true
+`)
+		vars := map[string]string{"CONFIG_FILE": path, "PORT": "9100", "REQUEST_TIMEOUT": "7s"}
+
+		cfg, opts, err := Load([]string{"--server.port", "9200"}, env(vars), io.Discard)
+
+		require.NoError(t, err)
+		assert.Equal(t, path, opts.File)
+		assert.Equal(t, 9200, cfg.Server.Port, "flag beats env and file")
+		assert.Equal(t, 7*time.Second, cfg.Server.RequestTimeout, "env beats file")
+		assert.Equal(t, 2*time.Minute, cfg.Server.IdleTimeout, "file beats default")
+		assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, cfg.CORS.AllowedOrigins)
+		assert.True(t, cfg.Auth.Anonymous)
+	})
+
+	t.Run("TOML file named by flag", func(t *testing.T) {
+		path := writeFile(t, "api.toml", `
+[database]
+max_open_conns = 50
+
+[features]
+jobs = false
+`)
+
+		cfg, _, err := Load([]string{"--config", path, "--features.require-if-match"}, env(anonymous), io.Discard)
+
+		require.NoError(t, err)
+		assert.Equal(t, 50, cfg.Database.MaxOpenConns)
+		assert.False(t, cfg.Features.Jobs)
+		assert.True(t, cfg.Features.RequireIfMatch)
+	})
+
+	t.Run("Environment list is comma separated", func(t *testing.T) {
+		vars := map[string]string{"AUTH_ANONYMOUS": "true", "CORS_ALLOWED_ORIGINS": "https://a.example.com, *"}
+
+		cfg, _, err := Load(nil, env(vars), io.Discard)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://a.example.com", "*"}, cfg.CORS.AllowedOrigins)
+	})
+
+	t.Run("Unknown file key", func(t *testing.T) {
+		path := writeFile(t, "api.yaml", "server:\n  prot: 9000\n")
+
+		_, _, err := Load([]string{"--config", path}, env(anonymous), io.Discard)
+
+		assert.ErrorContains(t, err, `unknown key "server.prot"`)
+	})
+
+	t.Run("Unsupported file format", func(t *testing.T) {
+		path := writeFile(t, "api.json", "{}")
+
+		_, _, err := Load([]string{"--config", path}, env(anonymous), io.Discard)
+
+		assert.ErrorContains(t, err, "unsupported format")
+	})
+
+	t.Run("Malformed values", func(t *testing.T) {
+		_, _, err := Load(nil, env(map[string]string{"AUTH_ANONYMOUS": "yes please"}), io.Discard)
+		assert.ErrorContains(t, err, "AUTH_ANONYMOUS")
+
+		_, _, err = Load([]string{"--server.read-timeout", "15"}, env(anonymous), io.Discard)
+		assert.ErrorContains(t, err, "--server.read-timeout")
+	})
+
+	t.Run("Projection commands", func(t *testing.T) {
+		_, opts, err := Load([]string{"--repair-projection"}, env(anonymous), io.Discard)
+
+		require.NoError(t, err)
+		assert.False(t, opts.CheckProjection)
+		assert.True(t, opts.RepairProjection)
+	})
+
+	t.Run("Help", func(t *testing.T) {
+		var out bytes.Buffer
+
+		_, _, err := Load([]string{"-h"}, env(nil), &out)
+
+		assert.ErrorIs(t, err, flag.ErrHelp)
+		assert.Contains(t, out.String(), "-print-config")
+		assert.Contains(t, out.String(), "env DATABASE_URL")
+		assert.NotContains(t, out.String(), "app:app@", "secrets in defaults are redacted")
+	})
+
+	t.Run("Print round-trips as a config file", func(t *testing.T) {
+		vars := map[string]string{"AUTH_HMAC_SECRET": "s3cret", "CORS_ALLOWED_ORIGINS": "https://a.example.com"}
+		cfg, _, err := Load(nil, env(vars), io.Discard)
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		require.NoError(t, Print(&out, cfg))
+		assert.NotContains(t, out.String(), "s3cret")
+		assert.NotContains(t, out.String(), "app:app@")
+
+		path := writeFile(t, "printed.yaml", out.String())
+		reloaded, _, err := Load([]string{"--config", path}, env(nil), io.Discard)
+		require.NoError(t, err)
+		assert.Equal(t, cfg.Redacted(), reloaded)
+	})
+}
+
+// TestExampleConfig tests that config.example.yaml loads and lists every setting
+func TestExampleConfig(t *testing.T) {
+	path := filepath.Join("..", "..", "..", "..", "config.example.yaml")
+
+	_, _, err := Load([]string{"--config", path}, env(nil), io.Discard)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var raw map[string]any
+	require.NoError(t, yaml.Unmarshal(content, &raw))
+	values := map[string]any{}
+	flatten("", raw, values)
+	for _, s := range settings {
+		assert.Contains(t, values, s.key)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := Default()
+	valid.Auth.Anonymous = true
+	require.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name   string
+		mutate func(c *Config)
+		want   string
+	}{
+		{"Missing database URL", func(c *Config) { c.Database.URL = "" }, "database.url: is required"},
+		{"Negative pool size", func(c *Config) { c.Database.MaxOpenConns = -1 }, "database.max_open_conns"},
+		{"Port out of range", func(c *Config) { c.Server.Port = 70000 }, "server.port"},
+		{"Write timeout not above request timeout", func(c *Config) { c.Server.WriteTimeout = c.Server.RequestTimeout }, "server.write_timeout"},
+		{"Bad CORS origin", func(c *Config) { c.CORS.AllowedOrigins = []string{"example.com"} }, "cors.allowed_origins"},
+		{"No auth keys", func(c *Config) { c.Auth.Anonymous = false }, "auth:"},
+		{"Zero max limit", func(c *Config) { c.Pagination.MaxLimit = 0 }, "pagination.max_limit"},
+		{"Bad schedule", func(c *Config) { c.Jobs.OverdueSweepSchedule = "often" }, "jobs.overdue_sweep_schedule"},
+		{"Bad log format", func(c *Config) { c.Log.Format = "xml" }, "invalid log format"},
+		{"Bad trace exporter", func(c *Config) { c.Tracing.Exporter = "jaeger" }, "tracing.exporter"},
+		{"Bad OTLP endpoint", func(c *Config) { c.Tracing.OTLPEndpoint = "collector:4318" }, "tracing.otlp_endpoint"},
+		{"Short audit signing key", func(c *Config) { c.Audit.SigningKey = "c2hvcnQ=" }, "audit.signing_key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.mutate(&cfg)
+			assert.ErrorContains(t, cfg.Validate(), tt.want)
+		})
+	}
+
+	t.Run("Reports every problem", func(t *testing.T) {
+		cfg := valid
+		cfg.Server.Port = 0
+		cfg.Pagination.MaxLimit = 0
+
+		err := cfg.Validate()
+
+		assert.ErrorContains(t, err, "server.port")
+		assert.ErrorContains(t, err, "pagination.max_limit")
+	})
+}
@@ -0,0 +1,294 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// setting binds one configuration field to its file key, environment variable and flag.
+type setting struct {
+	// key is the dotted file key, e.g. "server.port"; the flag is the same with
+	// hyphens for underscores (--server.port, --database.max-open-conns).
+	key   string
+	env   string
+	usage string
+	field func(c *Config) any
+}
+
+func (s setting) flagName() string {
+	return strings.ReplaceAll(s.key, "_", "-")
+}
+
+var settings = []setting{
+	{"database.url", "DATABASE_URL", "Postgres connection URL", func(c *Config) any { return &c.Database.URL }},
+	{"database.max_open_conns", "DB_MAX_OPEN_CONNS", "maximum open connections (0 is unlimited)", func(c *Config) any { return &c.Database.MaxOpenConns }},
+	{"database.max_idle_conns", "DB_MAX_IDLE_CONNS", "maximum idle connections", func(c *Config) any { return &c.Database.MaxIdleConns }},
+	{"database.conn_max_lifetime", "DB_CONN_MAX_LIFETIME", "maximum age of a connection (0 is unlimited)", func(c *Config) any { return &c.Database.ConnMaxLifetime }},
+	{"database.conn_max_idle_time", "DB_CONN_MAX_IDLE_TIME", "maximum idle time of a connection (0 is unlimited)", func(c *Config) any { return &c.Database.ConnMaxIdleTime }},
+
+	{"server.port", "PORT", "HTTP port", func(c *Config) any { return &c.Server.Port }},
+	{"server.request_timeout", "REQUEST_TIMEOUT", "deadline of each /api request (0 disables it)", func(c *Config) any { return &c.Server.RequestTimeout }},
+	{"server.read_timeout", "HTTP_READ_TIMEOUT", "time to read a request", func(c *Config) any { return &c.Server.ReadTimeout }},
+	{"server.write_timeout", "HTTP_WRITE_TIMEOUT", "time to write a response", func(c *Config) any { return &c.Server.WriteTimeout }},
+	{"server.idle_timeout", "HTTP_IDLE_TIMEOUT", "keep-alive idle time", func(c *Config) any { return &c.Server.IdleTimeout }},
+	{"server.shutdown_timeout", "SHUTDOWN_TIMEOUT", "time to drain connections on shutdown", func(c *Config) any { return &c.Server.ShutdownTimeout }},
+
+	{"cors.allowed_origins", "CORS_ALLOWED_ORIGINS", "comma-separated browser origins allowed to call the API (* for any)", func(c *Config) any { return &c.CORS.AllowedOrigins }},
+
+	{"auth.anonymous", "AUTH_ANONYMOUS", "accept unauthenticated requests (local dev only)", func(c *Config) any { return &c.Auth.Anonymous }},
+	{"auth.hmac_secret", "AUTH_HMAC_SECRET", "HS256 JWT secret", func(c *Config) any { return &c.Auth.HMACSecret }},
+	{"auth.rsa_public_keys", "AUTH_RSA_PUBLIC_KEYS", "RS256 public keys (see server.ParseRSAKeySet)", func(c *Config) any { return &c.Auth.RSAPublicKeys }},
+	{"auth.issuer", "AUTH_ISSUER", "required JWT issuer", func(c *Config) any { return &c.Auth.Issuer }},
+	{"auth.audience", "AUTH_AUDIENCE", "required JWT audience", func(c *Config) any { return &c.Auth.Audience }},
+
+	{"pagination.max_limit", "PAGINATION_MAX_LIMIT", "largest page size list endpoints return", func(c *Config) any { return &c.Pagination.MaxLimit }},
+
+	{"features.require_if_match", "REQUIRE_IF_MATCH", "reject writes without If-Match", func(c *Config) any { return &c.Features.RequireIfMatch }},
+	{"features.swagger", "FEATURE_SWAGGER", "serve the API docs under /swagger", func(c *Config) any { return &c.Features.Swagger }},
+	{"features.jobs", "FEATURE_JOBS", "run background jobs in this process", func(c *Config) any { return &c.Features.Jobs }},
+	{"features.metrics", "FEATURE_METRICS", "serve Prometheus metrics under /metrics (see metrics.token)", func(c *Config) any { return &c.Features.Metrics }},
+	{"metrics.token", "METRICS_TOKEN", "bearer token /metrics scrapes must send (empty leaves it open)", func(c *Config) any { return &c.Metrics.Token }},
+
+	{"jobs.overdue_sweep_schedule", "OVERDUE_SWEEP_SCHEDULE", "cron schedule of the overdue sweep", func(c *Config) any { return &c.Jobs.OverdueSweepSchedule }},
+
+	{"log.format", "LOG_FORMAT", "log output format: text or json", func(c *Config) any { return &c.Log.Format }},
+	{"log.level", "LOG_LEVEL", "minimum log level: debug, info, warn or error", func(c *Config) any { return &c.Log.Level }},
+	{"tracing.exporter", "TRACING_EXPORTER", "trace exporter: none, stdout or otlp", func(c *Config) any { return &c.Tracing.Exporter }},
+	{"tracing.otlp_endpoint", "TRACING_OTLP_ENDPOINT", "OTLP/HTTP collector URL (default from OTEL_EXPORTER_OTLP_ENDPOINT)", func(c *Config) any { return &c.Tracing.OTLPEndpoint }},
+	{"tracing.service_name", "TRACING_SERVICE_NAME", "service.name reported on spans", func(c *Config) any { return &c.Tracing.ServiceName }},
+
+	{"audit.signing_key", "AUDIT_SIGNING_KEY", "base64 Ed25519 seed signing the exported audit chain head", func(c *Config) any { return &c.Audit.SigningKey }},
+}
+
+// configFileEnv names the config file when --config is not given.
+const configFileEnv = "CONFIG_FILE"
+
+// Options are the flags that control loading rather than the configuration itself.
+type Options struct {
+	// File is the YAML (.yaml, .yml) or TOML (.toml) file that was loaded, if any.
+	File string
+	// PrintConfig asks the caller to print the redacted configuration and exit.
+	PrintConfig bool
+	// CheckProjection asks the caller to compare the tools table with the event log,
+	// print the divergences and exit; RepairProjection also rewrites the diverging tools.
+	CheckProjection  bool
+	RepairProjection bool
+}
+
+// Load builds the configuration from defaults, then the config file, then the
+// environment, then args (without the program name), and validates the result.
+// lookupEnv is normally os.LookupEnv. With -h it returns flag.ErrHelp.
+func Load(args []string, lookupEnv func(string) (string, bool), output io.Writer) (Config, Options, error) {
+	var opts Options
+	fs := flag.NewFlagSet("api", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.StringVar(&opts.File, "config", "", "YAML or TOML config file (default $"+configFileEnv+")")
+	fs.BoolVar(&opts.PrintConfig, "print-config", false, "print the effective configuration with secrets redacted, then exit")
+	fs.BoolVar(&opts.CheckProjection, "check-projection", false, "report the tools whose state diverges from the event log, then exit")
+	fs.BoolVar(&opts.RepairProjection, "repair-projection", false, "like --check-projection, and rewrite the diverging tools from the event log")
+
+	// flags are recorded here and applied last, after the file and the environment
+	flagValues := map[string]string{}
+	defaults := Default().Redacted()
+	for _, s := range settings {
+		record := func(v string) error {
+			flagValues[s.key] = v
+			return nil
+		}
+		usage := fmt.Sprintf("%s (env %s, default %s)", s.usage, s.env, formatValue(s.field(&defaults)))
+		if _, ok := s.field(&defaults).(*bool); ok {
+			fs.BoolFunc(s.flagName(), usage, record)
+		} else {
+			fs.Func(s.flagName(), usage, record)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return Config{}, opts, err
+	}
+	if fs.NArg() > 0 {
+		return Config{}, opts, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	cfg := Default()
+
+	if opts.File == "" {
+		opts.File, _ = lookupEnv(configFileEnv)
+	}
+	if opts.File != "" {
+		if err := loadFile(&cfg, opts.File); err != nil {
+			return Config{}, opts, err
+		}
+	}
+
+	for _, s := range settings {
+		if v, ok := lookupEnv(s.env); ok {
+			if err := setValue(s.field(&cfg), v); err != nil {
+				return Config{}, opts, fmt.Errorf("invalid %s: %w", s.env, err)
+			}
+		}
+	}
+
+	for _, s := range settings {
+		if v, ok := flagValues[s.key]; ok {
+			if err := setValue(s.field(&cfg), v); err != nil {
+				return Config{}, opts, fmt.Errorf("invalid --%s: %w", s.flagName(), err)
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, opts, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, opts, nil
+}
+
+// loadFile applies a YAML or TOML file on top of cfg. Unknown keys are errors so typos do not go unnoticed.
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	raw := map[string]any{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return fmt.Errorf("config file %s: unsupported format %q, use .yaml, .yml or .toml", path, ext)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := map[string]any{}
+	flatten("", raw, values)
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	byKey := map[string]setting{}
+	for _, s := range settings {
+		byKey[s.key] = s
+	}
+	for _, key := range keys {
+		s, ok := byKey[key]
+		if !ok {
+			return fmt.Errorf("config file %s: unknown key %q", path, key)
+		}
+		if err := setFileValue(s.field(cfg), values[key]); err != nil {
+			return fmt.Errorf("config file %s: invalid %s: %w", path, key, err)
+		}
+	}
+	return nil
+}
+
+// flatten turns nested tables into dotted keys ({"server": {"port": 1}} becomes "server.port").
+func flatten(prefix string, raw map[string]any, out map[string]any) {
+	for k, v := range raw {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if nested, ok := v.(map[string]any); ok {
+			flatten(key, nested, out)
+			continue
+		}
+		out[key] = v
+	}
+}
+
+// setFileValue applies a decoded file value, which may be a list or a native scalar.
+func setFileValue(field any, v any) error {
+	if list, ok := v.([]any); ok {
+		p, ok := field.(*[]string)
+		if !ok {
+			return fmt.Errorf("a list is not allowed here")
+		}
+		*p = make([]string, 0, len(list))
+		for _, item := range list {
+			*p = append(*p, fmt.Sprint(item))
+		}
+		return nil
+	}
+	if v == nil {
+		return setValue(field, "")
+	}
+	return setValue(field, fmt.Sprint(v))
+}
+
+// setValue parses a string (from the environment, a flag or a file scalar) into field.
+func setValue(field any, v string) error {
+	switch p := field.(type) {
+	case *string:
+		*p = v
+	case *int:
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", v)
+		}
+		*p = n
+	case *bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", v)
+		}
+		*p = b
+	case *time.Duration:
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("%q is not a duration such as 15s", v)
+		}
+		*p = d
+	case *[]string:
+		*p = nil
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				*p = append(*p, item)
+			}
+		}
+	default:
+		panic(fmt.Sprintf("config: unsupported field type %T", field))
+	}
+	return nil
+}
+
+// formatValue renders a field's value for flag usage.
+func formatValue(field any) string {
+	switch p := field.(type) {
+	case *string:
+		return strconv.Quote(*p)
+	case *[]string:
+		return strconv.Quote(strings.Join(*p, ","))
+	case *int:
+		return strconv.Itoa(*p)
+	case *bool:
+		return strconv.FormatBool(*p)
+	case *time.Duration:
+		return p.String()
+	}
+	return ""
+}
+
+// Print writes the configuration as YAML with secrets redacted. The output is a valid config file.
+func Print(w io.Writer, cfg Config) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(cfg.Redacted()); err != nil {
+		return err
+	}
+	return enc.Close()
+}
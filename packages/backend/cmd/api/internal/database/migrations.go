@@ -1,11 +1,14 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"embed"
 	"fmt"
+	"log/slog"
 	"path/filepath"
 	"sort"
+	"time"
 )
 
 // Embed all migration files into the binary at compile time
@@ -14,7 +17,8 @@ import (
 //go:embed migrations/*.sql
 var migrationFiles embed.FS
 
-func RunMigrations(db *sql.DB) error {
+// RunMigrations applies every embedded migration that has not been applied yet, in name order.
+func RunMigrations(db *sql.DB, logger *slog.Logger) error {
 	// Step 1: Create the tracking table
 	err := createMigrationsTable(db)
 	if err != nil {
@@ -29,7 +33,7 @@ func RunMigrations(db *sql.DB) error {
 
 	// Step 3: Apply each migration that hasn't been applied yet
 	for _, migrationName := range migrationNames {
-		err := applyMigration(db, migrationName)
+		err := applyMigration(db, logger, migrationName)
 		if err != nil {
 			return fmt.Errorf("failed to apply migration %s: %w", migrationName, err)
 		}
@@ -69,14 +73,14 @@ func getMigrationFiles() ([]string, error) {
 }
 
 // Applies a single migration if it hasn't been applied yet
-func applyMigration(db *sql.DB, migrationName string) error {
+func applyMigration(db *sql.DB, logger *slog.Logger, migrationName string) error {
 	alreadyApplied, err := isMigrationApplied(db, migrationName)
 	if err != nil {
 		return err
 	}
 
 	if alreadyApplied {
-		fmt.Printf("Migration %s already applied, skipping\n", migrationName)
+		logger.Debug("migration already applied, skipping", "migration", migrationName)
 		return nil
 	}
 
@@ -87,7 +91,8 @@ func applyMigration(db *sql.DB, migrationName string) error {
 	}
 
 	// Execute the SQL
-	fmt.Printf("Applying migration: %s\n", migrationName)
+	logger.Info("applying migration", "migration", migrationName)
+	start := time.Now()
 	_, err = db.Exec(string(content))
 	if err != nil {
 		return fmt.Errorf("failed to execute SQL: %w", err)
@@ -99,7 +104,7 @@ func applyMigration(db *sql.DB, migrationName string) error {
 		return fmt.Errorf("failed to mark migration as applied: %w", err)
 	}
 
-	fmt.Printf("Successfully applied migration: %s\n", migrationName)
+	logger.Info("applied migration", "migration", migrationName, "latency_ms", time.Since(start).Milliseconds())
 	return nil
 }
 
@@ -149,3 +154,42 @@ type MigrationStatus struct {
 	Name    string
 	Applied bool
 }
+
+// PendingMigrations lists the embedded migrations not yet recorded in schema_migrations.
+// A database that has never been migrated reports every migration as pending.
+func PendingMigrations(ctx context.Context, db *sql.DB) ([]string, error) {
+	migrationNames, err := getMigrationFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		var exists bool
+		if qerr := db.QueryRowContext(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists); qerr == nil && !exists {
+			return migrationNames, nil
+		}
+		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[string]bool{}
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+
+	var pending []string
+	for _, name := range migrationNames {
+		if !applied[name] {
+			pending = append(pending, name)
+		}
+	}
+	return pending, nil
+}
@@ -0,0 +1,24 @@
+-- Add row versions to tools and users for optimistic concurrency (ETag / If-Match)
+ALTER TABLE tools ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+-- Bump the version on every update, so writes that bypass the repos are covered too
+CREATE OR REPLACE FUNCTION bump_version()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version := OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS bump_tools_version ON tools;
+CREATE TRIGGER bump_tools_version
+    BEFORE UPDATE ON tools
+    FOR EACH ROW
+    EXECUTE FUNCTION bump_version();
+
+DROP TRIGGER IF EXISTS bump_users_version ON users;
+CREATE TRIGGER bump_users_version
+    BEFORE UPDATE ON users
+    FOR EACH ROW
+    EXECUTE FUNCTION bump_version();
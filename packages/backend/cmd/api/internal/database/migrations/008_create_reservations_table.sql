@@ -0,0 +1,35 @@
+-- Reservation lifecycle and event types
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'reservation_status') THEN
+        CREATE TYPE reservation_status AS ENUM ('ACTIVE','CANCELLED','CONVERTED');
+    END IF;
+END$$;
+
+ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'RESERVATION_CREATED';
+ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'RESERVATION_CANCELLED';
+ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'RESERVATION_CONVERTED';
+
+-- Create reservations table; a reservation books a tool over [starts_at, ends_at)
+CREATE TABLE IF NOT EXISTS reservations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tool_id UUID NOT NULL REFERENCES tools(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status reservation_status NOT NULL DEFAULT 'ACTIVE',
+    notes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (ends_at > starts_at)
+);
+
+-- Create indexes for reservations
+CREATE INDEX IF NOT EXISTS idx_reservations_tool_window ON reservations(tool_id, starts_at, ends_at) WHERE status = 'ACTIVE';
+CREATE INDEX IF NOT EXISTS idx_reservations_user_id ON reservations(user_id, starts_at DESC);
+
+DROP TRIGGER IF EXISTS update_reservations_updated_at ON reservations;
+CREATE TRIGGER update_reservations_updated_at
+    BEFORE UPDATE ON reservations
+    FOR EACH ROW
+    EXECUTE FUNCTION set_updated_at();
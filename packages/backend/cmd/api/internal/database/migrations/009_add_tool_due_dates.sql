@@ -0,0 +1,9 @@
+-- Due dates for checkouts and a per-tool default loan period
+ALTER TABLE tools ADD COLUMN IF NOT EXISTS due_at TIMESTAMP WITH TIME ZONE NULL;
+ALTER TABLE tools ADD COLUMN IF NOT EXISTS default_loan_days INTEGER NULL CHECK (default_loan_days > 0);
+-- Set when the TOOL_OVERDUE event of the current checkout has been emitted
+ALTER TABLE tools ADD COLUMN IF NOT EXISTS overdue_notified_at TIMESTAMP WITH TIME ZONE NULL;
+
+ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'TOOL_OVERDUE';
+
+CREATE INDEX IF NOT EXISTS idx_tools_due_at ON tools(due_at) WHERE current_user_id IS NOT NULL AND due_at IS NOT NULL;
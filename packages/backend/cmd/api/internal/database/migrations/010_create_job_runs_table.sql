@@ -0,0 +1,23 @@
+-- Job run history for the background job scheduler
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'job_run_status') THEN
+        CREATE TYPE job_run_status AS ENUM ('RUNNING','SUCCEEDED','FAILED');
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'job_run_trigger') THEN
+        CREATE TYPE job_run_trigger AS ENUM ('SCHEDULE','MANUAL');
+    END IF;
+END$$;
+
+CREATE TABLE IF NOT EXISTS job_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    job_name TEXT NOT NULL,
+    trigger job_run_trigger NOT NULL,
+    status job_run_status NOT NULL DEFAULT 'RUNNING',
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE NULL,
+    duration_ms BIGINT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_name_started_at ON job_runs(job_name, started_at DESC);
@@ -0,0 +1,2 @@
+-- Backs the per-type aggregates of the admin stats (events of a type since a point in time)
+CREATE INDEX IF NOT EXISTS idx_events_type_created ON events(type, created_at DESC);
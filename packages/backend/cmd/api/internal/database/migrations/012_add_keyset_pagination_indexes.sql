@@ -0,0 +1,6 @@
+-- Back cursor pagination: lists are ordered by (created_at, id) newest first and
+-- each page starts strictly after the last row of the previous one
+CREATE INDEX IF NOT EXISTS idx_events_created_id ON events(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_events_tool_created_id ON events(tool_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_tools_created_id ON tools(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_users_created_id ON users(created_at DESC, id DESC);
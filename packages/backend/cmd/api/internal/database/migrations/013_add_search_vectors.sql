@@ -0,0 +1,18 @@
+-- Full-text search over tool names, user names and emails, and event notes.
+-- The vectors are generated columns so they can never drift from the text;
+-- emails are split on their punctuation so "ann" finds ann.smith@example.com.
+ALTER TABLE tools ADD COLUMN IF NOT EXISTS search_vector tsvector
+	GENERATED ALWAYS AS (to_tsvector('english'::regconfig, name)) STORED;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS search_vector tsvector
+	GENERATED ALWAYS AS (
+		setweight(to_tsvector('english'::regconfig, name), 'A') ||
+		setweight(to_tsvector('english'::regconfig, translate(email::text, '@._-+', '     ')), 'B')
+	) STORED;
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS search_vector tsvector
+	GENERATED ALWAYS AS (to_tsvector('english'::regconfig, coalesce(notes, ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_tools_search ON tools USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_users_search ON users USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_events_search ON events USING GIN (search_vector);
@@ -0,0 +1,14 @@
+-- Soft delete: deleting a tool or user stamps deleted_at instead of removing the row,
+-- so events keep pointing at it and it can be restored
+ALTER TABLE tools ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE NULL;
+
+-- A deleted user's email may be taken by a new user; only live users must be unique
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_live ON users(email) WHERE deleted_at IS NULL;
+
+ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'TOOL_RESTORED';
+ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'USER_RESTORED';
+
+-- The repos stamp deleted_at themselves; this trigger function from 001 was never attached
+DROP FUNCTION IF EXISTS soft_delete();
@@ -0,0 +1,27 @@
+-- Tamper-evident audit log: each event stores a hash of its contents chained to the
+-- previous event's hash (see domain.HashEvent). Events logged before this migration
+-- stay unchained, with a NULL chain_seq.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS chain_seq BIGINT NULL;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS prev_hash TEXT NULL;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS hash TEXT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_events_chain_seq ON events(chain_seq);
+
+-- The hash covers tool_id, user_id and actor_id, so deleting a referenced row must
+-- not null them out: that would rewrite chained events. Deletes are soft now, and
+-- a hard delete of a tool or user with events is refused instead.
+ALTER TABLE events
+    DROP CONSTRAINT IF EXISTS events_tool_id_fkey,
+    ADD CONSTRAINT events_tool_id_fkey FOREIGN KEY (tool_id) REFERENCES tools(id) ON DELETE RESTRICT,
+    DROP CONSTRAINT IF EXISTS events_user_id_fkey,
+    ADD CONSTRAINT events_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    DROP CONSTRAINT IF EXISTS events_actor_id_fkey,
+    ADD CONSTRAINT events_actor_id_fkey FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE RESTRICT;
+
+-- The head of the chain, a single row. Inserting an event advances it in the same
+-- statement, so its row lock serializes the inserts.
+CREATE TABLE IF NOT EXISTS event_chain (
+    id INT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    seq BIGINT NOT NULL DEFAULT 0,
+    hash TEXT NOT NULL DEFAULT ''
+);
+INSERT INTO event_chain (id) VALUES (1) ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// APIKeyPrefix marks a bearer token as a personal API key rather than a JWT.
+const APIKeyPrefix = "tt_"
+
+// APIKey is a long-lived personal credential for scripts and integrations.
+// Only a hash of the secret is stored; the plaintext is shown once on creation.
+type APIKey struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Hash       string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewAPIKey constructs an APIKey and validates it.
+func NewAPIKey(userID, name string, scopes []string, expiresAt *time.Time) (APIKey, error) {
+	if scopes == nil {
+		scopes = []string{}
+	}
+	k := APIKey{UserID: userID, Name: name, Scopes: scopes, ExpiresAt: expiresAt}
+	return k, k.Validate()
+}
+
+func (k *APIKey) Validate() error {
+	if err := ValidateUUID(k.UserID, "user_id"); err != nil {
+		return err
+	}
+	if k.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrValidation)
+	}
+	for _, s := range k.Scopes {
+		if s == "" {
+			return fmt.Errorf("%w: scopes must not be empty", ErrValidation)
+		}
+	}
+	if k.ExpiresAt != nil && k.ExpiresAt.Before(time.Now()) {
+		return fmt.Errorf("%w: expires_at must be in the future", ErrValidation)
+	}
+	return nil
+}
+
+// IsActive reports whether the key is neither revoked nor expired at the given time.
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// HasScope reports whether the key grants scope. A key without scopes is unrestricted.
+func (k *APIKey) HasScope(scope string) bool {
+	if len(k.Scopes) == 0 {
+		return true
+	}
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewAPIKey tests api key construction and validation
+func TestNewAPIKey(t *testing.T) {
+	const userID = "456e7890-e89b-12d3-a456-426614174000"
+
+	t.Run("Valid api key creation", func(t *testing.T) {
+		expires := time.Now().Add(24 * time.Hour)
+		key, err := NewAPIKey(userID, "warehouse script", []string{"tools:read"}, &expires)
+
+		require.NoError(t, err)
+		assert.Equal(t, userID, key.UserID)
+		assert.Equal(t, "warehouse script", key.Name)
+		assert.Equal(t, []string{"tools:read"}, key.Scopes)
+	})
+
+	t.Run("Nil scopes default to empty", func(t *testing.T) {
+		key, err := NewAPIKey(userID, "script", nil, nil)
+
+		require.NoError(t, err)
+		assert.NotNil(t, key.Scopes)
+		assert.Empty(t, key.Scopes)
+	})
+
+	t.Run("Empty name should fail", func(t *testing.T) {
+		_, err := NewAPIKey(userID, "", nil, nil)
+
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Contains(t, err.Error(), "name is required")
+	})
+
+	t.Run("Invalid user ID should fail", func(t *testing.T) {
+		_, err := NewAPIKey("invalid-uuid", "script", nil, nil)
+
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Contains(t, err.Error(), "user_id must be a valid UUID")
+	})
+
+	t.Run("Expiry in the past should fail", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		_, err := NewAPIKey(userID, "script", nil, &past)
+
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Contains(t, err.Error(), "expires_at must be in the future")
+	})
+}
+
+// TestAPIKey_IsActive tests revocation and expiry checks
+func TestAPIKey_IsActive(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		key      APIKey
+		expected bool
+	}{
+		{"No expiry", APIKey{}, true},
+		{"Future expiry", APIKey{ExpiresAt: &future}, true},
+		{"Expired", APIKey{ExpiresAt: &past}, false},
+		{"Revoked", APIKey{RevokedAt: &past}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.key.IsActive(now))
+		})
+	}
+}
+
+// TestAPIKey_HasScope tests scope matching
+func TestAPIKey_HasScope(t *testing.T) {
+	unscoped := APIKey{Scopes: []string{}}
+	scoped := APIKey{Scopes: []string{"tools:read", "tools:checkout"}}
+
+	assert.True(t, unscoped.HasScope("admin"))
+	assert.True(t, scoped.HasScope("tools:read"))
+	assert.True(t, scoped.HasScope("tools:checkout"))
+	assert.False(t, scoped.HasScope("tools:write"))
+}
@@ -3,9 +3,18 @@ package domain
 import "errors"
 
 var (
-	ErrToolNotFound  = errors.New("tool not found")
-	ErrValidation    = errors.New("validation failed")
-	ErrUserNotFound  = errors.New("user not found")
-	ErrEventNotFound = errors.New("event not found")
-	ErrConflict      = errors.New("conflict")
+	ErrToolNotFound        = errors.New("tool not found")
+	ErrValidation          = errors.New("validation failed")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrEventNotFound       = errors.New("event not found")
+	ErrAPIKeyNotFound      = errors.New("api key not found")
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrJobNotFound         = errors.New("job not found")
+	ErrConflict            = errors.New("conflict")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrForbidden           = errors.New("forbidden")
+	ErrNotConfigured       = errors.New("not configured")
+
+	ErrPreconditionFailed   = errors.New("precondition failed")
+	ErrPreconditionRequired = errors.New("precondition required")
 )
@@ -15,24 +15,37 @@ const (
 	EventTypeToolCheckedIn   EventType = "TOOL_CHECKED_IN"
 	EventTypeToolMaintenance EventType = "TOOL_MAINTENANCE"
 	EventTypeToolLost        EventType = "TOOL_LOST"
+	EventTypeToolOverdue     EventType = "TOOL_OVERDUE"
+	EventTypeToolRestored    EventType = "TOOL_RESTORED"
 	EventTypeUserCreated     EventType = "USER_CREATED"
 	EventTypeUserUpdated     EventType = "USER_UPDATED"
 	EventTypeUserDeleted     EventType = "USER_DELETED"
+	EventTypeUserRestored    EventType = "USER_RESTORED"
+
+	EventTypeReservationCreated   EventType = "RESERVATION_CREATED"
+	EventTypeReservationCancelled EventType = "RESERVATION_CANCELLED"
+	EventTypeReservationConverted EventType = "RESERVATION_CONVERTED"
 )
 
 type Event struct {
-	ID        string    `json:"id"`
-	Type      EventType `json:"type"`
-	ToolID    *string   `json:"tool_id,omitempty"`
-	UserID    *string   `json:"user_id,omitempty"`
-	ActorID   *string   `json:"actor_id,omitempty"`
-	Notes     string    `json:"notes"`
-	Metadata  *string   `json:"metadata,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
+	ID      string    `json:"id"`
+	Type    EventType `json:"type"`
+	ToolID  *string   `json:"tool_id,omitempty"`
+	UserID  *string   `json:"user_id,omitempty"`
+	ActorID *string   `json:"actor_id,omitempty"`
+	Notes   string    `json:"notes"`
+	// Metadata holds the details of the event; which fields it may set depends on Type.
+	Metadata  *EventMetadata `json:"metadata,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	// ChainSeq, PrevHash and Hash place the event in the audit log's hash chain (see
+	// HashEvent); events logged before the chain existed have none.
+	ChainSeq *int64 `json:"chain_seq,omitempty"`
+	PrevHash string `json:"prev_hash,omitempty"`
+	Hash     string `json:"hash,omitempty"`
 }
 
 // NewEvent constructs an Event and validates it.
-func NewEvent(eventType EventType, toolID *string, userID *string, actorID *string, notes string, metadata *string) (Event, error) {
+func NewEvent(eventType EventType, toolID *string, userID *string, actorID *string, notes string, metadata *EventMetadata) (Event, error) {
 	e := Event{Type: eventType, ToolID: toolID, UserID: userID, ActorID: actorID, Notes: notes, Metadata: metadata}
 	return e, e.Validate()
 }
@@ -52,14 +65,20 @@ func (e *Event) Validate() error {
 	if err := ValidateEventType(e.Type); err != nil {
 		return err
 	}
-	return nil
+	// validated even when absent, so the fields a type requires are enforced
+	var metadata EventMetadata
+	if e.Metadata != nil {
+		metadata = *e.Metadata
+	}
+	return metadata.Validate(e.Type)
 }
 
 func (t EventType) IsValid() bool {
 	switch t {
 	case EventTypeToolCreated, EventTypeToolUpdated, EventTypeToolDeleted,
-		EventTypeToolCheckedOut, EventTypeToolCheckedIn, EventTypeToolMaintenance, EventTypeToolLost,
-		EventTypeUserCreated, EventTypeUserUpdated, EventTypeUserDeleted:
+		EventTypeToolCheckedOut, EventTypeToolCheckedIn, EventTypeToolMaintenance, EventTypeToolLost, EventTypeToolOverdue, EventTypeToolRestored,
+		EventTypeUserCreated, EventTypeUserUpdated, EventTypeUserDeleted, EventTypeUserRestored,
+		EventTypeReservationCreated, EventTypeReservationCancelled, EventTypeReservationConverted:
 		return true
 	default:
 		return false
@@ -76,8 +95,14 @@ func ValidEventTypes() []EventType {
 		EventTypeToolCheckedIn,
 		EventTypeToolMaintenance,
 		EventTypeToolLost,
+		EventTypeToolOverdue,
+		EventTypeToolRestored,
 		EventTypeUserCreated,
 		EventTypeUserUpdated,
 		EventTypeUserDeleted,
+		EventTypeUserRestored,
+		EventTypeReservationCreated,
+		EventTypeReservationCancelled,
+		EventTypeReservationConverted,
 	}
 }
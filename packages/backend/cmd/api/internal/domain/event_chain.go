@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ChainHead is the last link of the audit log's hash chain: the sequence number and
+// hash of the newest chained event. The empty chain is at Seq 0 with an empty Hash.
+type ChainHead struct {
+	Seq  int64  `json:"seq"`
+	Hash string `json:"hash"`
+}
+
+// HashEvent returns the hex SHA-256 of e's contents chained to prevHash, the hash of
+// the event at seq-1 ("" for the first). Every field an event is stored with is
+// covered. Metadata is hashed in a canonical JSON form so it hashes the same whether
+// it is read back from JSONB, which reorders keys, or as it was written; CreatedAt
+// is hashed in UTC at the database's microsecond precision, and IDs in the lowercase
+// form Postgres returns.
+func HashEvent(e Event, seq int64, prevHash string) (string, error) {
+	metadata, err := canonicalMetadata(e.Metadata)
+	if err != nil {
+		return "", err
+	}
+	content, err := json.Marshal(struct {
+		Seq       int64           `json:"seq"`
+		PrevHash  string          `json:"prev_hash"`
+		ID        string          `json:"id"`
+		Type      EventType       `json:"type"`
+		ToolID    *string         `json:"tool_id"`
+		UserID    *string         `json:"user_id"`
+		ActorID   *string         `json:"actor_id"`
+		Notes     string          `json:"notes"`
+		Metadata  json.RawMessage `json:"metadata"`
+		CreatedAt string          `json:"created_at"`
+	}{
+		Seq:       seq,
+		PrevHash:  prevHash,
+		ID:        CanonicalUUID(e.ID),
+		Type:      e.Type,
+		ToolID:    CanonicalUUIDPtr(e.ToolID),
+		UserID:    CanonicalUUIDPtr(e.UserID),
+		ActorID:   CanonicalUUIDPtr(e.ActorID),
+		Notes:     e.Notes,
+		Metadata:  metadata,
+		CreatedAt: e.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode event for hashing: %w", err)
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalMetadata encodes m with object keys sorted at every level and numbers
+// kept as written; empty metadata is null, as it is stored.
+func canonicalMetadata(m *EventMetadata) (json.RawMessage, error) {
+	if m == nil || m.IsZero() {
+		return json.RawMessage("null"), nil
+	}
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event metadata for hashing: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("failed to decode event metadata for hashing: %w", err)
+	}
+	return json.Marshal(v)
+}
+
+// BrokenLink is the first place the audit log's hash chain does not hold.
+type BrokenLink struct {
+	// Seq is the position in the chain that failed.
+	Seq int64 `json:"seq"`
+	// EventID is the event stored at Seq; empty when the event is missing.
+	EventID string `json:"event_id,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+// ChainVerification is the outcome of walking the audit log's hash chain.
+type ChainVerification struct {
+	// Valid is true when every chained event up to Head hashes as stored and links to its predecessor.
+	Valid bool `json:"valid"`
+	// EventsChecked counts the chained events that were walked.
+	EventsChecked int       `json:"events_checked"`
+	Head          ChainHead `json:"head"`
+	// UnchainedEvents counts events without a place in the chain. Events logged
+	// before the chain existed are unchained; the count should never grow.
+	UnchainedEvents int         `json:"unchained_events"`
+	BrokenLink      *BrokenLink `json:"broken_link,omitempty"`
+	VerifiedAt      time.Time   `json:"verified_at"`
+}
+
+// ChainHeadSignatureAlgorithm is the signature scheme of SignedChainHead.
+const ChainHeadSignatureAlgorithm = "ed25519"
+
+// SignedChainHead is the chain head signed for anchoring outside the database:
+// storing it elsewhere lets a later export prove the chain up to Seq was not
+// rewritten, even by someone able to recompute every hash.
+type SignedChainHead struct {
+	ChainHead
+	ExportedAt time.Time `json:"exported_at"`
+	// Payload is the exact text that was signed (see ChainHeadPayload).
+	Payload   string `json:"payload"`
+	Algorithm string `json:"algorithm"`
+	// PublicKey and Signature are base64 encoded.
+	PublicKey string `json:"public_key"`
+	Signature string `json:"signature"`
+}
+
+// ChainHeadPayload returns the text signed for head at exportedAt.
+func ChainHeadPayload(head ChainHead, exportedAt time.Time) string {
+	return fmt.Sprintf("tool-tracker audit chain head\nseq=%d\nhash=%s\nexported_at=%s\n",
+		head.Seq, head.Hash, exportedAt.UTC().Format(time.RFC3339Nano))
+}
+
+// SignChainHead signs head at exportedAt with key.
+func SignChainHead(head ChainHead, exportedAt time.Time, key ed25519.PrivateKey) SignedChainHead {
+	payload := ChainHeadPayload(head, exportedAt)
+	return SignedChainHead{
+		ChainHead:  head,
+		ExportedAt: exportedAt.UTC(),
+		Payload:    payload,
+		Algorithm:  ChainHeadSignatureAlgorithm,
+		PublicKey:  base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+		Signature:  base64.StdEncoding.EncodeToString(ed25519.Sign(key, []byte(payload))),
+	}
+}
+
+// ParseChainSigningKey decodes a base64 Ed25519 seed (32 bytes) into the key the
+// chain head is signed with.
+func ParseChainSigningKey(s string) (ed25519.PrivateKey, error) {
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("signing key is not base64: %w", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("signing key must be a %d byte Ed25519 seed, got %d bytes", ed25519.SeedSize, len(seed))
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
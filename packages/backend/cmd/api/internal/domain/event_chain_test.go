@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHashEvent tests hashing an event into the audit chain
+func TestHashEvent(t *testing.T) {
+	toolID := "tool-1"
+	created := time.Date(2025, 6, 9, 17, 0, 0, 123456789, time.FixedZone("EEST", 3*60*60))
+	event := Event{
+		ID:        "event-1",
+		Type:      EventTypeToolUpdated,
+		ToolID:    &toolID,
+		Notes:     "renamed",
+		Metadata:  &EventMetadata{After: json.RawMessage(`{"name":"Drill","status":"IN_OFFICE"}`)},
+		CreatedAt: created,
+	}
+	hash, err := HashEvent(event, 2, "prev")
+	require.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	t.Run("Stored form hashes the same", func(t *testing.T) {
+		// as read back: JSONB reorders keys and adds spaces, timestamps come back in UTC at microseconds
+		stored := event
+		stored.Metadata = &EventMetadata{After: json.RawMessage(`{"status": "IN_OFFICE", "name": "Drill"}`)}
+		stored.CreatedAt = created.UTC().Truncate(time.Microsecond)
+
+		got, err := HashEvent(stored, 2, "prev")
+
+		require.NoError(t, err)
+		assert.Equal(t, hash, got)
+	})
+
+	t.Run("Uppercase IDs hash as stored", func(t *testing.T) {
+		// Postgres accepts UUIDs in either case and reads them back lowercase
+		lower := "5f0c6a52-3b1e-4c7d-9a8e-2f4b6d8c0e1a"
+		upper := strings.ToUpper(lower)
+		written := event
+		written.ID, written.ToolID, written.UserID, written.ActorID = upper, &upper, &upper, &upper
+		stored := event
+		stored.ID, stored.ToolID, stored.UserID, stored.ActorID = lower, &lower, &lower, &lower
+
+		a, err := HashEvent(written, 2, "prev")
+		require.NoError(t, err)
+		b, err := HashEvent(stored, 2, "prev")
+		require.NoError(t, err)
+		assert.Equal(t, b, a)
+	})
+
+	t.Run("Empty metadata hashes as none", func(t *testing.T) {
+		bare := event
+		bare.Metadata = nil
+		empty := event
+		empty.Metadata = &EventMetadata{}
+
+		a, err := HashEvent(bare, 2, "prev")
+		require.NoError(t, err)
+		b, err := HashEvent(empty, 2, "prev")
+		require.NoError(t, err)
+		assert.Equal(t, a, b)
+	})
+
+	t.Run("Any change changes the hash", func(t *testing.T) {
+		otherTool := "tool-2"
+		renamed := &EventMetadata{After: json.RawMessage(`{"name":"Saw"}`)}
+		changes := map[string]func(e *Event, seq *int64, prev *string){
+			"notes":      func(e *Event, _ *int64, _ *string) { e.Notes = "renamed!" },
+			"tool":       func(e *Event, _ *int64, _ *string) { e.ToolID = &otherTool },
+			"type":       func(e *Event, _ *int64, _ *string) { e.Type = EventTypeToolDeleted },
+			"metadata":   func(e *Event, _ *int64, _ *string) { e.Metadata = renamed },
+			"created_at": func(e *Event, _ *int64, _ *string) { e.CreatedAt = e.CreatedAt.Add(time.Microsecond) },
+			"seq":        func(_ *Event, seq *int64, _ *string) { *seq = 3 },
+			"prev_hash":  func(_ *Event, _ *int64, prev *string) { *prev = "other" },
+		}
+		for name, change := range changes {
+			e, seq, prev := event, int64(2), "prev"
+			change(&e, &seq, &prev)
+
+			got, err := HashEvent(e, seq, prev)
+
+			require.NoError(t, err)
+			assert.NotEqual(t, hash, got, name)
+		}
+	})
+}
+
+// TestSignChainHead tests signing the chain head for export
+func TestSignChainHead(t *testing.T) {
+	seed := strings.Repeat("k", ed25519.SeedSize)
+	key, err := ParseChainSigningKey(base64.StdEncoding.EncodeToString([]byte(seed)))
+	require.NoError(t, err)
+	head := ChainHead{Seq: 42, Hash: "abc"}
+	exported := time.Date(2025, 6, 9, 17, 0, 0, 0, time.UTC)
+
+	signed := SignChainHead(head, exported, key)
+
+	assert.Equal(t, head, signed.ChainHead)
+	assert.Equal(t, ChainHeadPayload(head, exported), signed.Payload)
+	assert.Contains(t, signed.Payload, "seq=42\nhash=abc\n")
+	assert.Equal(t, ChainHeadSignatureAlgorithm, signed.Algorithm)
+
+	publicKey, err := base64.StdEncoding.DecodeString(signed.PublicKey)
+	require.NoError(t, err)
+	signature, err := base64.StdEncoding.DecodeString(signed.Signature)
+	require.NoError(t, err)
+	assert.True(t, ed25519.Verify(publicKey, []byte(signed.Payload), signature))
+	assert.False(t, ed25519.Verify(publicKey, []byte(ChainHeadPayload(ChainHead{Seq: 42, Hash: "abd"}, exported)), signature))
+}
+
+// TestParseChainSigningKey tests decoding the signing key setting
+func TestParseChainSigningKey(t *testing.T) {
+	_, err := ParseChainSigningKey("not base64!")
+	assert.ErrorContains(t, err, "not base64")
+
+	_, err = ParseChainSigningKey(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.ErrorContains(t, err, "32 byte Ed25519 seed, got 5 bytes")
+}
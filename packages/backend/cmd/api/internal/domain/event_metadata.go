@@ -0,0 +1,239 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ToolCondition is the state a tool is in when it is checked out.
+type ToolCondition string
+
+const (
+	ToolConditionGood    ToolCondition = "GOOD"
+	ToolConditionFair    ToolCondition = "FAIR"
+	ToolConditionPoor    ToolCondition = "POOR"
+	ToolConditionDamaged ToolCondition = "DAMAGED"
+)
+
+// ValidateToolCondition checks c; the empty condition means it was not recorded.
+func ValidateToolCondition(c ToolCondition) error {
+	switch c {
+	case "", ToolConditionGood, ToolConditionFair, ToolConditionPoor, ToolConditionDamaged:
+		return nil
+	default:
+		return fmt.Errorf("%w: invalid condition %s", ErrValidation, c)
+	}
+}
+
+// MaxVendorLength bounds the vendor recorded with a maintenance event, in characters.
+const MaxVendorLength = 200
+
+// EventMetadata is the JSON object stored in events.metadata. Which fields an event
+// may carry depends on its type (see eventMetadataSchema). Events that change a tool
+// or user carry its state before and after the change, so the history of a record
+// still shows what it was after the record itself is deleted.
+type EventMetadata struct {
+	// APIKeyID and APIKeyPrefix identify the API key the event was logged with, if any.
+	APIKeyID     string `json:"api_key_id,omitempty"`
+	APIKeyPrefix string `json:"api_key_prefix,omitempty"`
+	// DueAt is the due date of a checkout or of an overdue loan.
+	DueAt *time.Time `json:"due_at,omitempty"`
+	// Condition is the state of the tool at checkout.
+	Condition ToolCondition `json:"condition,omitempty"`
+	// Vendor and CostCents describe the repair a tool is sent to maintenance for.
+	Vendor    string `json:"vendor,omitempty"`
+	CostCents *int64 `json:"cost_cents,omitempty"`
+	// ReservationID is the reservation a reservation event concerns.
+	ReservationID string `json:"reservation_id,omitempty"`
+	// Changes lists the fields an update changed.
+	Changes []FieldChange `json:"changes,omitempty"`
+	// Before and After are JSON snapshots of the tool or user; Before is absent on
+	// creation and After on deletion.
+	Before json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After  json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+}
+
+// eventMetadataSchema lists the metadata fields each event type may carry, by JSON
+// name, besides the API key any event may record. Fields marked required must be set.
+var eventMetadataSchema = map[EventType]struct{ allowed, required []string }{
+	EventTypeToolCreated:          {allowed: []string{"after"}},
+	EventTypeToolUpdated:          {allowed: []string{"before", "after", "changes"}},
+	EventTypeToolDeleted:          {allowed: []string{"before"}},
+	EventTypeToolRestored:         {allowed: []string{"before", "after"}},
+	EventTypeToolCheckedOut:       {allowed: []string{"before", "after", "due_at", "condition"}},
+	EventTypeToolCheckedIn:        {allowed: []string{"before", "after"}},
+	EventTypeToolMaintenance:      {allowed: []string{"before", "after", "vendor", "cost_cents"}},
+	EventTypeToolLost:             {allowed: []string{"before", "after"}},
+	EventTypeToolOverdue:          {allowed: []string{"due_at"}, required: []string{"due_at"}},
+	EventTypeUserCreated:          {allowed: []string{"after"}},
+	EventTypeUserUpdated:          {allowed: []string{"before", "after", "changes"}},
+	EventTypeUserDeleted:          {allowed: []string{"before"}},
+	EventTypeUserRestored:         {allowed: []string{"before", "after"}},
+	EventTypeReservationCreated:   {allowed: []string{"reservation_id"}, required: []string{"reservation_id"}},
+	EventTypeReservationCancelled: {allowed: []string{"reservation_id"}, required: []string{"reservation_id"}},
+	EventTypeReservationConverted: {allowed: []string{"reservation_id"}, required: []string{"reservation_id"}},
+}
+
+// commonMetadataFields may be set on events of every type.
+var commonMetadataFields = []string{"api_key_id", "api_key_prefix"}
+
+// Snapshot captures v (a Tool or User) for EventMetadata.Before or After.
+func Snapshot(v any) json.RawMessage {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return raw
+}
+
+// IsZero reports whether no field is set, in which case the event stores no metadata.
+func (m EventMetadata) IsZero() bool {
+	return len(m.fields()) == 0
+}
+
+// fields returns the JSON names of the fields that are set.
+func (m EventMetadata) fields() []string {
+	raw, _ := json.Marshal(m)
+	var set map[string]json.RawMessage
+	_ = json.Unmarshal(raw, &set)
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks m against the schema of eventType and the values of its fields.
+func (m EventMetadata) Validate(eventType EventType) error {
+	schema := eventMetadataSchema[eventType]
+	set := m.fields()
+	for _, name := range set {
+		if !slices.Contains(schema.allowed, name) && !slices.Contains(commonMetadataFields, name) {
+			return fmt.Errorf("%w: metadata field %s is not allowed on %s events", ErrValidation, name, eventType)
+		}
+	}
+	for _, name := range schema.required {
+		if !slices.Contains(set, name) {
+			return fmt.Errorf("%w: metadata field %s is required on %s events", ErrValidation, name, eventType)
+		}
+	}
+
+	if err := ValidateToolCondition(m.Condition); err != nil {
+		return err
+	}
+	if len([]rune(m.Vendor)) > MaxVendorLength {
+		return fmt.Errorf("%w: vendor must be at most %d characters", ErrValidation, MaxVendorLength)
+	}
+	if m.CostCents != nil && *m.CostCents < 0 {
+		return fmt.Errorf("%w: cost_cents must not be negative", ErrValidation)
+	}
+	if m.ReservationID != "" {
+		if err := ValidateUUID(m.ReservationID, "reservation_id"); err != nil {
+			return err
+		}
+	}
+	for _, c := range m.Changes {
+		if c.Field == "" {
+			return fmt.Errorf("%w: metadata changes must name their field", ErrValidation)
+		}
+	}
+	if !isSnapshot(m.Before) || !isSnapshot(m.After) {
+		return fmt.Errorf("%w: metadata snapshots must be JSON objects", ErrValidation)
+	}
+	return nil
+}
+
+// isSnapshot reports whether raw is absent or holds a JSON object.
+func isSnapshot(raw json.RawMessage) bool {
+	return len(raw) == 0 || bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{"))
+}
+
+// HasSnapshot reports whether the event recorded the state of its tool or user.
+func (m EventMetadata) HasSnapshot() bool {
+	return len(m.Before) > 0 || len(m.After) > 0
+}
+
+// FieldChange is one field whose value differs between an event's snapshots.
+// A null side means the field was unset, or the record did not exist.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// Diff lists the fields that differ between Before and After, by field name.
+func (m EventMetadata) Diff() ([]FieldChange, error) {
+	before, err := snapshotFields(m.Before)
+	if err != nil {
+		return nil, err
+	}
+	after, err := snapshotFields(m.After)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(before)+len(after))
+	for f := range before {
+		fields = append(fields, f)
+	}
+	for f := range after {
+		if _, ok := before[f]; !ok {
+			fields = append(fields, f)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := []FieldChange{}
+	for _, f := range fields {
+		if !reflect.DeepEqual(before[f], after[f]) {
+			changes = append(changes, FieldChange{Field: f, Before: before[f], After: after[f]})
+		}
+	}
+	return changes, nil
+}
+
+func snapshotFields(raw json.RawMessage) (map[string]any, error) {
+	fields := map[string]any{}
+	if len(raw) == 0 {
+		return fields, nil
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("invalid event snapshot: %w", err)
+	}
+	return fields, nil
+}
+
+// metadataFilterKeys are the scalar metadata fields events can be filtered on.
+var metadataFilterKeys = []string{"api_key_id", "api_key_prefix", "due_at", "condition", "vendor", "cost_cents", "reservation_id"}
+
+var snapshotFieldName = regexp.MustCompile(`^[a-z_]+$`)
+
+// ValidateMetadataFilterKey checks a key events are filtered on: a scalar metadata
+// field, or a field of a snapshot written as before.<field> or after.<field>.
+func ValidateMetadataFilterKey(key string) error {
+	if slices.Contains(metadataFilterKeys, key) {
+		return nil
+	}
+	if side, field, ok := strings.Cut(key, "."); ok && (side == "before" || side == "after") && snapshotFieldName.MatchString(field) {
+		return nil
+	}
+	return fmt.Errorf("%w: cannot filter on metadata key %s", ErrValidation, key)
+}
+
+// EventDiff is what one event changed on its tool or user.
+type EventDiff struct {
+	EventID   string        `json:"event_id"`
+	Type      EventType     `json:"type"`
+	ToolID    *string       `json:"tool_id,omitempty"`
+	UserID    *string       `json:"user_id,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+	Changes   []FieldChange `json:"changes"`
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEventMetadata_Diff tests listing the fields that differ between snapshots
+func TestEventMetadata_Diff(t *testing.T) {
+	t.Run("Update lists only the changed fields", func(t *testing.T) {
+		id := "123e4567-e89b-12d3-a456-426614174000"
+		userID := "456e7890-e89b-12d3-a456-426614174000"
+		before := Tool{ID: &id, Name: "Drill", Status: ToolStatusInOffice, Version: 1}
+		after := before
+		after.Status = ToolStatusCheckedOut
+		after.CurrentUserId = &userID
+		after.Version = 2
+
+		changes, err := EventMetadata{Before: Snapshot(before), After: Snapshot(after)}.Diff()
+
+		require.NoError(t, err)
+		assert.Equal(t, []FieldChange{
+			{Field: "current_user_id", Before: nil, After: userID},
+			{Field: "status", Before: "IN_OFFICE", After: "CHECKED_OUT"},
+			{Field: "version", Before: float64(1), After: float64(2)},
+		}, changes)
+	})
+
+	t.Run("Delete takes every field to null", func(t *testing.T) {
+		changes, err := EventMetadata{Before: []byte(`{"name":"Drill","status":"LOST"}`)}.Diff()
+
+		require.NoError(t, err)
+		assert.Equal(t, []FieldChange{
+			{Field: "name", Before: "Drill", After: nil},
+			{Field: "status", Before: "LOST", After: nil},
+		}, changes)
+	})
+
+	t.Run("No snapshots means no changes", func(t *testing.T) {
+		changes, err := EventMetadata{ReservationID: "r1"}.Diff()
+
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("Malformed snapshot", func(t *testing.T) {
+		_, err := EventMetadata{After: []byte(`[1]`)}.Diff()
+
+		assert.Error(t, err)
+	})
+}
+
+// TestEventMetadata_Validate tests the per-type metadata schema
+func TestEventMetadata_Validate(t *testing.T) {
+	cost := int64(12500)
+	negative := int64(-1)
+	due := time.Date(2025, 6, 9, 17, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		eventType EventType
+		metadata  EventMetadata
+		wantErr   string
+	}{
+		{"Checkout with condition", EventTypeToolCheckedOut, EventMetadata{DueAt: &due, Condition: ToolConditionFair}, ""},
+		{"Maintenance with vendor and cost", EventTypeToolMaintenance, EventMetadata{Vendor: "Acme Repairs", CostCents: &cost}, ""},
+		{"Update with changes", EventTypeToolUpdated, EventMetadata{Changes: []FieldChange{{Field: "name", Before: "a", After: "b"}}}, ""},
+		{"API key on any event", EventTypeUserCreated, EventMetadata{APIKeyID: "k1", APIKeyPrefix: "abcd1234"}, ""},
+		{"Vendor not allowed on checkout", EventTypeToolCheckedOut, EventMetadata{Vendor: "Acme"}, "vendor is not allowed"},
+		{"Condition not allowed on maintenance", EventTypeToolMaintenance, EventMetadata{Condition: ToolConditionGood}, "condition is not allowed"},
+		{"Overdue requires due_at", EventTypeToolOverdue, EventMetadata{}, "due_at is required"},
+		{"Reservation requires its ID", EventTypeReservationCreated, EventMetadata{}, "reservation_id is required"},
+		{"Reservation ID must be a UUID", EventTypeReservationCreated, EventMetadata{ReservationID: "r1"}, "reservation_id must be a valid UUID"},
+		{"Unknown condition", EventTypeToolCheckedOut, EventMetadata{Condition: "SHINY"}, "invalid condition"},
+		{"Negative cost", EventTypeToolMaintenance, EventMetadata{CostCents: &negative}, "must not be negative"},
+		{"Vendor too long", EventTypeToolMaintenance, EventMetadata{Vendor: strings.Repeat("v", MaxVendorLength+1)}, "vendor must be at most"},
+		{"Snapshot must be an object", EventTypeToolCreated, EventMetadata{After: []byte(`"drill"`)}, "must be JSON objects"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.metadata.Validate(tt.eventType)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrValidation)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// TestValidateMetadataFilterKey tests which metadata keys events can be filtered on
+func TestValidateMetadataFilterKey(t *testing.T) {
+	for _, key := range []string{"vendor", "condition", "reservation_id", "after.status", "before.current_user_id"} {
+		assert.NoError(t, ValidateMetadataFilterKey(key), key)
+	}
+	for _, key := range []string{"", "changes", "before", "after.", "during.status", "after.status.x", "vendor'--"} {
+		assert.ErrorIs(t, ValidateMetadataFilterKey(key), ErrValidation, key)
+	}
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -22,10 +23,12 @@ func TestEventType_IsValid(t *testing.T) {
 		{"Valid TOOL_CHECKED_IN", EventTypeToolCheckedIn, true},
 		{"Valid TOOL_MAINTENANCE", EventTypeToolMaintenance, true},
 		{"Valid TOOL_LOST", EventTypeToolLost, true},
+		{"Valid TOOL_RESTORED", EventTypeToolRestored, true},
 		// User events
 		{"Valid USER_CREATED", EventTypeUserCreated, true},
 		{"Valid USER_UPDATED", EventTypeUserUpdated, true},
 		{"Valid USER_DELETED", EventTypeUserDeleted, true},
+		{"Valid USER_RESTORED", EventTypeUserRestored, true},
 		// Invalid cases
 		{"Invalid empty", EventType(""), false},
 		{"Invalid random", EventType("INVALID_EVENT"), false},
@@ -45,7 +48,8 @@ func TestNewEvent(t *testing.T) {
 	toolID := "tool-123"
 	userID := "user-456"
 	actorID := "actor-789"
-	metadata := `{"key": "value"}`
+	due := time.Date(2025, 6, 9, 17, 0, 0, 0, time.UTC)
+	metadata := &EventMetadata{DueAt: &due, Condition: ToolConditionGood}
 
 	t.Run("Valid event creation with all fields", func(t *testing.T) {
 		event, err := NewEvent(
@@ -54,7 +58,7 @@ func TestNewEvent(t *testing.T) {
 			&userID,
 			&actorID,
 			"Tool checked out to user",
-			&metadata,
+			metadata,
 		)
 
 		require.NoError(t, err)
@@ -63,7 +67,7 @@ func TestNewEvent(t *testing.T) {
 		assert.Equal(t, &userID, event.UserID)
 		assert.Equal(t, &actorID, event.ActorID)
 		assert.Equal(t, "Tool checked out to user", event.Notes)
-		assert.Equal(t, &metadata, event.Metadata)
+		assert.Equal(t, metadata, event.Metadata)
 	})
 
 	t.Run("Valid event creation with minimal fields", func(t *testing.T) {
@@ -112,6 +116,20 @@ func TestNewEvent(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "event type is required")
 	})
+
+	t.Run("Metadata not allowed on the type should fail", func(t *testing.T) {
+		_, err := NewEvent(
+			EventTypeToolCheckedIn,
+			&toolID,
+			&userID,
+			&actorID,
+			"Some notes",
+			&EventMetadata{Vendor: "Acme"},
+		)
+
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Contains(t, err.Error(), "vendor is not allowed")
+	})
 }
 
 // TestEvent_Validate tests the Event Validate method comprehensively
@@ -139,18 +157,37 @@ func TestEvent_Validate(t *testing.T) {
 
 	t.Run("All valid event types should pass validation", func(t *testing.T) {
 		validTypes := ValidEventTypes()
+		due := time.Date(2025, 6, 9, 17, 0, 0, 0, time.UTC)
 
 		for _, eventType := range validTypes {
 			event := Event{
 				Type:  eventType,
 				Notes: "Test notes",
 			}
+			// Some types require metadata
+			switch eventType {
+			case EventTypeToolOverdue:
+				event.Metadata = &EventMetadata{DueAt: &due}
+			case EventTypeReservationCreated, EventTypeReservationCancelled, EventTypeReservationConverted:
+				event.Metadata = &EventMetadata{ReservationID: "123e4567-e89b-12d3-a456-426614174000"}
+			}
 
 			err := event.Validate()
 			assert.NoError(t, err, "Event type %s should be valid", eventType)
 		}
 	})
 
+	t.Run("Missing required metadata should fail validation", func(t *testing.T) {
+		event := Event{
+			Type:  EventTypeReservationCreated,
+			Notes: "Reservation created",
+		}
+
+		err := event.Validate()
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Contains(t, err.Error(), "reservation_id is required")
+	})
+
 	t.Run("Invalid event type should fail validation", func(t *testing.T) {
 		invalidTypes := []EventType{
 			EventType(""),
@@ -180,21 +217,29 @@ func TestEvent_Validate(t *testing.T) {
 func TestValidEventTypes(t *testing.T) {
 	types := ValidEventTypes()
 
-	assert.Len(t, types, 10)
+	assert.Len(t, types, 16)
 
 	// Check tool events
 	assert.Contains(t, types, EventTypeToolCreated)
 	assert.Contains(t, types, EventTypeToolUpdated)
 	assert.Contains(t, types, EventTypeToolDeleted)
 	assert.Contains(t, types, EventTypeToolCheckedOut)
+	assert.Contains(t, types, EventTypeToolOverdue)
 	assert.Contains(t, types, EventTypeToolCheckedIn)
 	assert.Contains(t, types, EventTypeToolMaintenance)
 	assert.Contains(t, types, EventTypeToolLost)
+	assert.Contains(t, types, EventTypeToolRestored)
 
 	// Check user events
 	assert.Contains(t, types, EventTypeUserCreated)
 	assert.Contains(t, types, EventTypeUserUpdated)
 	assert.Contains(t, types, EventTypeUserDeleted)
+	assert.Contains(t, types, EventTypeUserRestored)
+
+	// Check reservation events
+	assert.Contains(t, types, EventTypeReservationCreated)
+	assert.Contains(t, types, EventTypeReservationCancelled)
+	assert.Contains(t, types, EventTypeReservationConverted)
 }
 
 // TestEventTypeCategories tests logical groupings
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+type JobRunStatus string
+
+const (
+	JobRunStatusRunning   JobRunStatus = "RUNNING"
+	JobRunStatusSucceeded JobRunStatus = "SUCCEEDED"
+	JobRunStatusFailed    JobRunStatus = "FAILED"
+)
+
+// JobRunTrigger says what started a job run.
+type JobRunTrigger string
+
+const (
+	JobRunTriggerSchedule JobRunTrigger = "SCHEDULE"
+	JobRunTriggerManual   JobRunTrigger = "MANUAL"
+)
+
+// JobRun records one execution of a background job.
+type JobRun struct {
+	ID         string        `json:"id"`
+	JobName    string        `json:"job_name"`
+	Trigger    JobRunTrigger `json:"trigger"`
+	Status     JobRunStatus  `json:"status"`
+	Error      string        `json:"error,omitempty"`
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+	DurationMs *int64        `json:"duration_ms,omitempty"`
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Cursor marks a row in a list ordered by (created_at, id), newest first.
+// The next page holds the rows strictly after it, so rows inserted meanwhile
+// neither shift the page nor show up twice.
+//
+// Paging is best-effort: created_at is stamped when the row's transaction
+// starts, not when it commits, so a row committed after a page was read can
+// sort behind that page's cursor and never be served.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Encode returns the opaque form of the cursor handed to clients.
+func (c Cursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor produced by Cursor.Encode.
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: cursor is malformed", ErrValidation)
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return Cursor{}, fmt.Errorf("%w: cursor is malformed", ErrValidation)
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil || ValidateUUID(id, "cursor") != nil {
+		return Cursor{}, fmt.Errorf("%w: cursor is malformed", ErrValidation)
+	}
+	return Cursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+// PageRequest selects one page of a list: the rows after Cursor when it is set,
+// else the rows at Offset (deprecated, kept for older clients).
+type PageRequest struct {
+	Limit  int
+	Offset int
+	Cursor string
+}
+
+// Page is one page of a list. Limit, Offset and Cursor echo the request as served
+// (Offset is zero when Cursor is set), and Total counts every row matching the filters.
+// NextCursor is set when HasMore is.
+type Page[T any] struct {
+	Items      []T
+	Total      int
+	Limit      int
+	Offset     int
+	Cursor     string
+	NextCursor string
+	HasMore    bool
+}
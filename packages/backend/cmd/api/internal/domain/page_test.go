@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	c := Cursor{CreatedAt: time.Date(2025, 6, 1, 12, 30, 0, 123456000, time.UTC), ID: "123e4567-e89b-12d3-a456-426614174000"}
+
+	decoded, err := DecodeCursor(c.Encode())
+
+	require.NoError(t, err)
+	assert.Equal(t, c, decoded)
+}
+
+func TestDecodeCursor_Malformed(t *testing.T) {
+	notUUID := Cursor{CreatedAt: time.Now(), ID: "tool1"}.Encode()
+	for _, s := range []string{"%%%", "bm8tc2VwYXJhdG9y", "eWVzdGVyZGF5fDEyM2U0NTY3LWU4OWItMTJkMy1hNDU2LTQyNjYxNDE3NDAwMA", notUUID} {
+		_, err := DecodeCursor(s)
+		assert.ErrorIs(t, err, ErrValidation, s)
+	}
+}
@@ -0,0 +1,46 @@
+package domain
+
+// ToolState is the part of a tool that its events determine.
+type ToolState struct {
+	Status        ToolStatus `json:"status"`
+	CurrentUserID *string    `json:"current_user_id,omitempty"`
+}
+
+// StateOf returns the ToolState of t.
+func StateOf(t Tool) ToolState {
+	return ToolState{Status: t.Status, CurrentUserID: t.CurrentUserId}
+}
+
+// Diff returns the names of the fields that differ between s and other.
+func (s ToolState) Diff(other ToolState) []string {
+	fields := []string{}
+	if s.Status != other.Status {
+		fields = append(fields, "status")
+	}
+	if !equalStringPtr(s.CurrentUserID, other.CurrentUserID) {
+		fields = append(fields, "current_user_id")
+	}
+	return fields
+}
+
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// ToolDivergence is a tool whose row disagrees with the state replayed from its events.
+type ToolDivergence struct {
+	ToolID string `json:"tool_id"`
+	Name   string `json:"name"`
+	// Fields names the fields that differ; it is empty when the tool has no events.
+	Fields []string  `json:"fields"`
+	Table  ToolState `json:"table"`
+	// Projected is the state the events lead to, nil when the tool has no events.
+	Projected *ToolState `json:"projected,omitempty"`
+	// Repaired is set once the row has been rewritten to the projected state.
+	Repaired bool `json:"repaired"`
+	// RepairError tells why a repair was attempted but not applied.
+	RepairError string `json:"repair_error,omitempty"`
+}
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestToolState_Diff tests comparing a tool row with its projected state
+func TestToolState_Diff(t *testing.T) {
+	userID, otherID := "user-1", "user-2"
+	out := ToolState{Status: ToolStatusCheckedOut, CurrentUserID: &userID}
+
+	sameUser := userID
+	assert.Empty(t, out.Diff(ToolState{Status: ToolStatusCheckedOut, CurrentUserID: &sameUser}))
+	assert.Equal(t, []string{"current_user_id"}, out.Diff(ToolState{Status: ToolStatusCheckedOut, CurrentUserID: &otherID}))
+	assert.Equal(t, []string{"status", "current_user_id"}, out.Diff(ToolState{Status: ToolStatusInOffice}))
+	assert.Equal(t, []string{"status"}, ToolState{Status: ToolStatusLost}.Diff(ToolState{Status: ToolStatusInOffice}))
+}
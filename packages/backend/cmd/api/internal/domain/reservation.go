@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type ReservationStatus string
+
+const (
+	ReservationStatusActive    ReservationStatus = "ACTIVE"
+	ReservationStatusCancelled ReservationStatus = "CANCELLED"
+	ReservationStatusConverted ReservationStatus = "CONVERTED"
+)
+
+func (s ReservationStatus) IsValid() bool {
+	switch s {
+	case ReservationStatusActive, ReservationStatusCancelled, ReservationStatusConverted:
+		return true
+	default:
+		return false
+	}
+}
+
+// Reservation books a tool for a user over [StartsAt, EndsAt).
+// Only ACTIVE reservations block other bookings and checkouts.
+type Reservation struct {
+	ID        string            `json:"id"`
+	ToolID    string            `json:"tool_id"`
+	UserID    string            `json:"user_id"`
+	StartsAt  time.Time         `json:"starts_at"`
+	EndsAt    time.Time         `json:"ends_at"`
+	Status    ReservationStatus `json:"status"`
+	Notes     string            `json:"notes"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// NewReservation constructs an active Reservation and validates it.
+func NewReservation(toolID, userID string, startsAt, endsAt time.Time, notes string) (Reservation, error) {
+	r := Reservation{
+		ToolID:   toolID,
+		UserID:   userID,
+		StartsAt: startsAt,
+		EndsAt:   endsAt,
+		Status:   ReservationStatusActive,
+		Notes:    notes,
+	}
+	return r, r.Validate()
+}
+
+func (r *Reservation) Validate() error {
+	if err := ValidateUUID(r.ToolID, "tool_id"); err != nil {
+		return err
+	}
+	if err := ValidateUUID(r.UserID, "user_id"); err != nil {
+		return err
+	}
+	if r.StartsAt.IsZero() || r.EndsAt.IsZero() {
+		return fmt.Errorf("%w: starts_at and ends_at are required", ErrValidation)
+	}
+	if !r.EndsAt.After(r.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrValidation)
+	}
+	if !r.Status.IsValid() {
+		return fmt.Errorf("%w: invalid reservation status %s", ErrValidation, r.Status)
+	}
+	return nil
+}
+
+// Overlaps reports whether the reservation intersects [start, end).
+func (r *Reservation) Overlaps(start, end time.Time) bool {
+	return r.StartsAt.Before(end) && start.Before(r.EndsAt)
+}
+
+// IsActive reports whether the reservation still holds its booking.
+func (r *Reservation) IsActive() bool {
+	return r.Status == ReservationStatusActive
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testToolID = "123e4567-e89b-12d3-a456-426614174000"
+	testUserID = "456e7890-e89b-12d3-a456-426614174000"
+)
+
+// TestNewReservation tests reservation creation
+func TestNewReservation(t *testing.T) {
+	start := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+
+	t.Run("Valid reservation is active", func(t *testing.T) {
+		r, err := NewReservation(testToolID, testUserID, start, start.Add(4*time.Hour), "site visit")
+
+		require.NoError(t, err)
+		assert.Equal(t, ReservationStatusActive, r.Status)
+	})
+
+	t.Run("End before start should fail", func(t *testing.T) {
+		_, err := NewReservation(testToolID, testUserID, start, start.Add(-time.Hour), "")
+
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Contains(t, err.Error(), "ends_at must be after starts_at")
+	})
+
+	t.Run("Missing times should fail", func(t *testing.T) {
+		_, err := NewReservation(testToolID, testUserID, time.Time{}, start, "")
+
+		assert.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("Invalid tool ID should fail", func(t *testing.T) {
+		_, err := NewReservation("invalid", testUserID, start, start.Add(time.Hour), "")
+
+		assert.Contains(t, err.Error(), "tool_id must be a valid UUID")
+	})
+}
+
+// TestReservation_Overlaps tests half-open interval overlap
+func TestReservation_Overlaps(t *testing.T) {
+	start := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	r := Reservation{StartsAt: start, EndsAt: start.Add(2 * time.Hour)}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		expected bool
+	}{
+		{"Inside", start.Add(30 * time.Minute), start.Add(time.Hour), true},
+		{"Spanning", start.Add(-time.Hour), start.Add(3 * time.Hour), true},
+		{"Overlapping start", start.Add(-time.Hour), start.Add(time.Minute), true},
+		{"Ends exactly at start", start.Add(-time.Hour), start, false},
+		{"Starts exactly at end", start.Add(2 * time.Hour), start.Add(3 * time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, r.Overlaps(tt.from, tt.to))
+		})
+	}
+}
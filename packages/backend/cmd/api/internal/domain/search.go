@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// SearchHitType names the kind of record a search hit points to.
+type SearchHitType string
+
+const (
+	SearchHitTool  SearchHitType = "tool"
+	SearchHitUser  SearchHitType = "user"
+	SearchHitEvent SearchHitType = "event"
+)
+
+// AllSearchHitTypes lists every searchable record kind.
+var AllSearchHitTypes = []SearchHitType{SearchHitTool, SearchHitUser, SearchHitEvent}
+
+func (t SearchHitType) IsValid() bool {
+	return t == SearchHitTool || t == SearchHitUser || t == SearchHitEvent
+}
+
+// ValidateSearchHitType checks if the provided search type is valid.
+func ValidateSearchHitType(t SearchHitType) error {
+	if !t.IsValid() {
+		return fmt.Errorf("%w: type must be one of %s, %s, %s", ErrValidation, SearchHitTool, SearchHitUser, SearchHitEvent)
+	}
+	return nil
+}
+
+// SearchHit is one search result. Title names the record (a tool or user name, or
+// an event type) and Snippet is the matching text with each match wrapped in
+// <mark></mark>; everything else in it is HTML-escaped. Higher Rank is a better match.
+type SearchHit struct {
+	Type    SearchHitType `json:"type"`
+	ID      string        `json:"id"`
+	Title   string        `json:"title"`
+	Snippet string        `json:"snippet"`
+	Rank    float64       `json:"rank"`
+	// ToolID and UserID link an event hit to the tool and user it concerns.
+	ToolID *string `json:"tool_id,omitempty"`
+	UserID *string `json:"user_id,omitempty"`
+}
+
+// SearchTerms splits a query into the words matched against the search index:
+// runs of letters and digits, lowercased. Everything else separates words, so
+// no query syntax reaches the database.
+func SearchTerms(q string) []string {
+	return strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
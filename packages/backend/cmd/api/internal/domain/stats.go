@@ -0,0 +1,15 @@
+package domain
+
+// ToolCheckouts counts how often a tool was checked out over a period.
+type ToolCheckouts struct {
+	ToolID    string `json:"tool_id"`
+	Name      string `json:"name"`
+	Checkouts int    `json:"checkouts"`
+}
+
+// UserCheckouts counts how many checkouts a user made over a period.
+type UserCheckouts struct {
+	UserID    string `json:"user_id"`
+	Name      string `json:"name"`
+	Checkouts int    `json:"checkouts"`
+}
@@ -29,8 +29,19 @@ type Tool struct {
 	Status           ToolStatus `json:"status"`
 	CurrentUserId    *string    `json:"current_user_id,omitempty"`
 	LastCheckedOutAt *time.Time `json:"last_checked_out_at,omitempty"`
-	CreatedAt        time.Time  `json:"created_at"`
-	UpdatedAt        time.Time  `json:"updated_at"`
+	// DueAt is when the current checkout should come back (nil: open-ended).
+	DueAt *time.Time `json:"due_at,omitempty"`
+	// DefaultLoanDays sets DueAt on checkouts that do not specify one.
+	DefaultLoanDays *int `json:"default_loan_days,omitempty"`
+	// Overdue is computed on read: checked out and past DueAt.
+	Overdue bool `json:"overdue"`
+	// OverdueNotifiedAt records the TOOL_OVERDUE event of the current checkout, so it is emitted once.
+	OverdueNotifiedAt *time.Time `json:"overdue_notified_at,omitempty"`
+	Version           int        `json:"version"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	// DeletedAt is set while the tool is soft-deleted.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 func NewTool(name string, status ToolStatus) (Tool, error) {
@@ -49,6 +60,17 @@ func ValidateToolStatus(status ToolStatus) error {
 
 	return nil
 }
+
+// ValidToolStatuses returns every tool status.
+func ValidToolStatuses() []ToolStatus {
+	return []ToolStatus{
+		ToolStatusInOffice,
+		ToolStatusCheckedOut,
+		ToolStatusMaintenance,
+		ToolStatusLost,
+	}
+}
+
 func (t *Tool) Validate() error {
 	if t.Name == "" {
 		return fmt.Errorf("%w: name is required", ErrValidation)
@@ -56,6 +78,51 @@ func (t *Tool) Validate() error {
 	if err := ValidateToolStatus(t.Status); err != nil {
 		return err
 	}
+	if t.DefaultLoanDays != nil && *t.DefaultLoanDays <= 0 {
+		return fmt.Errorf("%w: default_loan_days must be positive", ErrValidation)
+	}
+
+	return nil
+}
+
+// CheckOut assigns the tool to userID, failing if it is already out or lost.
+// A nil dueAt falls back to the tool's default loan period, if any.
+func (t *Tool) CheckOut(userID string, dueAt *time.Time, now time.Time) error {
+	if t.CurrentUserId != nil {
+		return fmt.Errorf("%w: tool is already checked out", ErrValidation)
+	}
+	if t.Status == ToolStatusLost {
+		return fmt.Errorf("%w: tool is marked as lost", ErrValidation)
+	}
+	if dueAt != nil && !dueAt.After(now) {
+		return fmt.Errorf("%w: due_at must be in the future", ErrValidation)
+	}
+	if dueAt == nil && t.DefaultLoanDays != nil {
+		due := now.AddDate(0, 0, *t.DefaultLoanDays)
+		dueAt = &due
+	}
+	t.Status = ToolStatusCheckedOut
+	t.CurrentUserId = &userID
+	t.DueAt = dueAt
+	t.Overdue = false
+	t.OverdueNotifiedAt = nil
+	return nil
+}
 
+// CheckIn clears the checkout state, including its due date.
+func (t *Tool) CheckIn() error {
+	if t.CurrentUserId == nil {
+		return fmt.Errorf("%w: tool is already checked in", ErrValidation)
+	}
+	t.CurrentUserId = nil
+	t.Status = ToolStatusInOffice
+	t.DueAt = nil
+	t.Overdue = false
+	t.OverdueNotifiedAt = nil
 	return nil
 }
+
+// IsOverdue reports whether the tool is checked out past its due date at now.
+func (t *Tool) IsOverdue(now time.Time) bool {
+	return t.CurrentUserId != nil && t.DueAt != nil && now.After(*t.DueAt)
+}
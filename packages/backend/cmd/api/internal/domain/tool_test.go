@@ -2,6 +2,7 @@ package domain
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -136,3 +137,56 @@ func TestTool_Validate(t *testing.T) {
 		assert.Contains(t, err.Error(), "name is required")
 	})
 }
+
+// TestTool_CheckOut tests checkout state and due date handling
+func TestTool_CheckOut(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	userID := "456e7890-e89b-12d3-a456-426614174000"
+
+	t.Run("Explicit due date is kept", func(t *testing.T) {
+		tool := Tool{Name: "Drill", Status: ToolStatusInOffice}
+		due := now.Add(48 * time.Hour)
+
+		require.NoError(t, tool.CheckOut(userID, &due, now))
+		assert.Equal(t, ToolStatusCheckedOut, tool.Status)
+		require.NotNil(t, tool.DueAt)
+		assert.True(t, due.Equal(*tool.DueAt))
+	})
+
+	t.Run("Default loan period applies without a due date", func(t *testing.T) {
+		days := 7
+		tool := Tool{Name: "Drill", Status: ToolStatusInOffice, DefaultLoanDays: &days}
+
+		require.NoError(t, tool.CheckOut(userID, nil, now))
+		require.NotNil(t, tool.DueAt)
+		assert.True(t, now.AddDate(0, 0, 7).Equal(*tool.DueAt))
+	})
+
+	t.Run("No due date without a default loan period", func(t *testing.T) {
+		tool := Tool{Name: "Drill", Status: ToolStatusInOffice}
+
+		require.NoError(t, tool.CheckOut(userID, nil, now))
+		assert.Nil(t, tool.DueAt)
+	})
+
+	t.Run("Due date in the past should fail", func(t *testing.T) {
+		tool := Tool{Name: "Drill", Status: ToolStatusInOffice}
+		due := now.Add(-time.Hour)
+
+		err := tool.CheckOut(userID, &due, now)
+		assert.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("Overdue after the due date until checked in", func(t *testing.T) {
+		tool := Tool{Name: "Drill", Status: ToolStatusInOffice}
+		due := now.Add(time.Hour)
+		require.NoError(t, tool.CheckOut(userID, &due, now))
+
+		assert.False(t, tool.IsOverdue(now))
+		assert.True(t, tool.IsOverdue(due.Add(time.Minute)))
+
+		require.NoError(t, tool.CheckIn())
+		assert.Nil(t, tool.DueAt)
+		assert.False(t, tool.IsOverdue(due.Add(time.Minute)))
+	})
+}
@@ -19,8 +19,11 @@ type User struct {
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
 	Role      UserRole  `json:"role"`
+	Version   int       `json:"version"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// DeletedAt is set while the user is soft-deleted.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 // NewUser constructs a User with defaults and validates it.
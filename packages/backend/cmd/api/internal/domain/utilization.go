@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// UtilizationGroup selects what a utilization report aggregates by.
+type UtilizationGroup string
+
+const (
+	UtilizationByTool UtilizationGroup = "tool"
+	UtilizationByUser UtilizationGroup = "user"
+)
+
+func (g UtilizationGroup) IsValid() bool {
+	return g == UtilizationByTool || g == UtilizationByUser
+}
+
+// ValidateUtilizationGroup checks if the provided group_by value is valid.
+func ValidateUtilizationGroup(g UtilizationGroup) error {
+	if !g.IsValid() {
+		return fmt.Errorf("%w: group_by must be %s or %s", ErrValidation, UtilizationByTool, UtilizationByUser)
+	}
+	return nil
+}
+
+// StatusChange is an event that moves a tool between statuses, with the names of
+// the tool and user it concerns. It is the input utilization is derived from.
+//
+// A TOOL_UPDATED is a status change only when it changed the status; Status is then
+// the status it set and UserID the holder after it.
+type StatusChange struct {
+	EventID  string
+	Type     EventType
+	ToolID   string
+	ToolName string
+	UserID   *string
+	UserName string
+	Status   ToolStatus
+	At       time.Time
+}
+
+// Utilization summarizes how a tool, or the tools a user borrowed, spent a report window.
+// Durations are in seconds. For a tool, CheckedOutPct is relative to the part of the
+// window the tool existed; for a user, to the whole window, so it exceeds 100 when
+// the user held several tools at once.
+type Utilization struct {
+	ToolID             *string `json:"tool_id,omitempty"`
+	UserID             *string `json:"user_id,omitempty"`
+	Name               string  `json:"name"`
+	TrackedSeconds     int64   `json:"tracked_seconds"`
+	CheckedOutSeconds  int64   `json:"checked_out_seconds"`
+	CheckedOutPct      float64 `json:"checked_out_pct"`
+	Loans              int     `json:"loans"`
+	MeanLoanSeconds    int64   `json:"mean_loan_seconds"`
+	MaintenanceSeconds int64   `json:"maintenance_seconds"`
+	LostSeconds        int64   `json:"lost_seconds"`
+}
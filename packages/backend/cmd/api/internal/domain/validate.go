@@ -3,6 +3,7 @@ package domain
 import (
 	"fmt"
 	"regexp"
+	"strings"
 )
 
 var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
@@ -17,3 +18,17 @@ func ValidateUUID(id string, field string) error {
 	}
 	return nil
 }
+
+// CanonicalUUID returns id in the lowercase form Postgres reads UUIDs back in.
+func CanonicalUUID(id string) string {
+	return strings.ToLower(id)
+}
+
+// CanonicalUUIDPtr is CanonicalUUID for an optional ID.
+func CanonicalUUIDPtr(id *string) *string {
+	if id == nil {
+		return nil
+	}
+	c := CanonicalUUID(*id)
+	return &c
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "fmt"
+
+// AnyVersion skips the optimistic concurrency check (no If-Match was given).
+const AnyVersion = 0
+
+// CheckVersion returns ErrPreconditionFailed when expected is set and differs from actual.
+func CheckVersion(expected, actual int) error {
+	if expected == AnyVersion || expected == actual {
+		return nil
+	}
+	return fmt.Errorf("%w: expected version %d but current version is %d", ErrPreconditionFailed, expected, actual)
+}
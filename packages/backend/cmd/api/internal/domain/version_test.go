@@ -0,0 +1,13 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckVersion(t *testing.T) {
+	assert.NoError(t, CheckVersion(AnyVersion, 7))
+	assert.NoError(t, CheckVersion(7, 7))
+	assert.ErrorIs(t, CheckVersion(6, 7), ErrPreconditionFailed)
+}
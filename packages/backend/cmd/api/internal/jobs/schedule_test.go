@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseSchedule tests cron expression parsing and activation times
+func TestParseSchedule(t *testing.T) {
+	// a Monday
+	base := time.Date(2025, 6, 2, 9, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		spec string
+		want time.Time
+	}{
+		{"every minute", "* * * * *", time.Date(2025, 6, 2, 9, 31, 0, 0, time.UTC)},
+		{"minute step", "*/15 * * * *", time.Date(2025, 6, 2, 9, 45, 0, 0, time.UTC)},
+		{"hourly", "@hourly", time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)},
+		{"daily at 02:30", "30 2 * * *", time.Date(2025, 6, 3, 2, 30, 0, 0, time.UTC)},
+		{"daily descriptor", "@daily", time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC)},
+		{"weekdays at 08:00", "0 8 * * 1-5", time.Date(2025, 6, 3, 8, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "0 0 * * 7", time.Date(2025, 6, 8, 0, 0, 0, 0, time.UTC)},
+		{"first of month", "@monthly", time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"list of hours", "0 6,18 * * *", time.Date(2025, 6, 2, 18, 0, 0, 0, time.UTC)},
+		{"either day field matches", "0 0 15 * 3", time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC)},
+		{"every interval", "@every 90s", base.Add(90 * time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseSchedule(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, s.Next(base))
+		})
+	}
+
+	t.Run("Impossible date never fires", func(t *testing.T) {
+		s, err := ParseSchedule("0 0 30 2 *")
+		require.NoError(t, err)
+		assert.True(t, s.Next(base).IsZero())
+	})
+
+	t.Run("Invalid specs are rejected", func(t *testing.T) {
+		for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@every 10ms", "@every soon"} {
+			_, err := ParseSchedule(spec)
+			assert.Error(t, err, "spec %q should be invalid", spec)
+		}
+	})
+}
@@ -0,0 +1,297 @@
+// Package jobs runs periodic background work inside the API process.
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/logging"
+)
+
+// Func is the work of a job. It should return promptly once ctx is done.
+type Func func(ctx context.Context) error
+
+// Locker guarantees that only one replica runs a job at a time.
+type Locker interface {
+	TryLock(ctx context.Context, key string) (release func(), acquired bool, err error)
+}
+
+// RunStore records job runs.
+type RunStore interface {
+	Start(ctx context.Context, jobName string, trigger domain.JobRunTrigger, startedAt time.Time) (domain.JobRun, error)
+	Finish(ctx context.Context, id string, status domain.JobRunStatus, finishedAt time.Time, errMsg string) (domain.JobRun, error)
+	ListByJob(ctx context.Context, jobName string, limit int) ([]domain.JobRun, error)
+}
+
+// JobInfo describes a registered job for the admin API.
+type JobInfo struct {
+	Name       string          `json:"name"`
+	Schedule   string          `json:"schedule"`
+	Running    bool            `json:"running"`
+	NextRunAt  *time.Time      `json:"next_run_at,omitempty"`
+	RecentRuns []domain.JobRun `json:"recent_runs"`
+}
+
+// recentRunsLimit is how many past runs JobInfo carries.
+const recentRunsLimit = 5
+
+type job struct {
+	name     string
+	spec     string
+	schedule Schedule
+	fn       Func
+
+	mu      sync.Mutex
+	running bool
+	next    time.Time
+}
+
+// tryStart marks the job running in this process; it fails if a run is already in progress.
+func (j *job) tryStart() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.running {
+		return false
+	}
+	j.running = true
+	return true
+}
+
+func (j *job) done() {
+	j.mu.Lock()
+	j.running = false
+	j.mu.Unlock()
+}
+
+// Scheduler is a registry of named jobs that runs each on its schedule.
+// Register jobs first, then Start; Stop cancels and waits for running jobs.
+type Scheduler struct {
+	mu     sync.Mutex
+	jobs   map[string]*job
+	order  []string
+	locker Locker
+	runs   RunStore
+	now    func() time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{jobs: map[string]*job{}, now: time.Now}
+}
+
+// WithLocker makes runs take a cluster-wide lock per job (optional chaining style).
+func (s *Scheduler) WithLocker(l Locker) *Scheduler {
+	s.locker = l
+	return s
+}
+
+// WithRunStore records every run (optional chaining style).
+func (s *Scheduler) WithRunStore(r RunStore) *Scheduler {
+	s.runs = r
+	return s
+}
+
+// Register adds a job under a unique name with a cron spec (see ParseSchedule).
+func (s *Scheduler) Register(name, spec string, fn Func) error {
+	if name == "" {
+		return fmt.Errorf("%w: job name is required", domain.ErrValidation)
+	}
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrValidation, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx != nil {
+		return fmt.Errorf("job %s registered after the scheduler started", name)
+	}
+	if _, ok := s.jobs[name]; ok {
+		return fmt.Errorf("%w: job %s is already registered", domain.ErrConflict, name)
+	}
+	s.jobs[name] = &job{name: name, spec: spec, schedule: schedule, fn: fn}
+	s.order = append(s.order, name)
+	return nil
+}
+
+// Start runs every registered job on its schedule until ctx is done or Stop is called.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx != nil {
+		return
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.ctx = ctx
+	for _, name := range s.order {
+		j := s.jobs[name]
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.loop(ctx, j)
+		}()
+	}
+}
+
+// Stop cancels the scheduler and waits for in-flight runs to return.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// Trigger starts a run of the named job now, in the background.
+// It fails with domain.ErrConflict if the job is already running in this process.
+func (s *Scheduler) Trigger(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", domain.ErrJobNotFound, name)
+	}
+	// checked under s.mu, so no run is added once Stop has cancelled the context
+	if s.ctx == nil || s.ctx.Err() != nil {
+		return fmt.Errorf("%w: scheduler is not running", domain.ErrConflict)
+	}
+	if !j.tryStart() {
+		return fmt.Errorf("%w: job %s is already running", domain.ErrConflict, name)
+	}
+
+	ctx := s.ctx
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer j.done()
+		s.run(ctx, j, domain.JobRunTriggerManual)
+	}()
+	return nil
+}
+
+// Jobs lists the registered jobs with their next activation and recent runs.
+func (s *Scheduler) Jobs(ctx context.Context) ([]JobInfo, error) {
+	s.mu.Lock()
+	jobs := make([]*job, 0, len(s.order))
+	for _, name := range s.order {
+		jobs = append(jobs, s.jobs[name])
+	}
+	s.mu.Unlock()
+
+	infos := make([]JobInfo, 0, len(jobs))
+	for _, j := range jobs {
+		j.mu.Lock()
+		info := JobInfo{Name: j.name, Schedule: j.spec, Running: j.running, RecentRuns: []domain.JobRun{}}
+		if !j.next.IsZero() {
+			next := j.next
+			info.NextRunAt = &next
+		}
+		j.mu.Unlock()
+
+		if s.runs != nil {
+			runs, err := s.runs.ListByJob(ctx, j.name, recentRunsLimit)
+			if err != nil {
+				return nil, err
+			}
+			if runs != nil {
+				info.RecentRuns = runs
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// loop waits for each activation of j and runs it. A run still in progress
+// (e.g. a manual trigger) makes the scheduler skip that activation.
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	for {
+		next := j.schedule.Next(s.now())
+		if next.IsZero() {
+			return
+		}
+		j.mu.Lock()
+		j.next = next
+		j.mu.Unlock()
+
+		timer := time.NewTimer(next.Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if !j.tryStart() {
+			continue
+		}
+		s.run(ctx, j, domain.JobRunTriggerSchedule)
+		j.done()
+	}
+}
+
+// run executes one run of j under its cluster lock and records the outcome.
+// The caller must hold j's running flag.
+func (s *Scheduler) run(ctx context.Context, j *job, trigger domain.JobRunTrigger) {
+	// everything the job logs (including its service and repo calls) is tagged with the job
+	ctx = logging.With(ctx, "job", j.name, "trigger", string(trigger))
+	logger := logging.FromContext(ctx)
+
+	if s.locker != nil {
+		release, acquired, err := s.locker.TryLock(ctx, "jobs:"+j.name)
+		if err != nil {
+			logger.Error("job failed to take lock", "error", err)
+			return
+		}
+		if !acquired {
+			// another replica is running it
+			return
+		}
+		defer release()
+	}
+
+	// the run is recorded even when shutdown cancels ctx mid-run
+	recordCtx := context.WithoutCancel(ctx)
+	var run domain.JobRun
+	if s.runs != nil {
+		var err error
+		run, err = s.runs.Start(recordCtx, j.name, trigger, s.now())
+		if err != nil {
+			logger.Error("job failed to record start", "error", err)
+			return
+		}
+	}
+
+	start := s.now()
+	err := s.call(ctx, j)
+	status, msg := domain.JobRunStatusSucceeded, ""
+	if err != nil {
+		status, msg = domain.JobRunStatusFailed, err.Error()
+		logger.Error("job failed", "error", err, "latency_ms", s.now().Sub(start).Milliseconds())
+	} else {
+		logger.Info("job succeeded", "latency_ms", s.now().Sub(start).Milliseconds())
+	}
+
+	if s.runs != nil {
+		if _, err := s.runs.Finish(recordCtx, run.ID, status, s.now(), msg); err != nil {
+			logger.Error("job failed to record finish", "error", err)
+		}
+	}
+}
+
+// call runs the job function, turning a panic into an error.
+func (s *Scheduler) call(ctx context.Context, j *job) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return j.fn(ctx)
+}
@@ -0,0 +1,186 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// fakeRunStore keeps job runs in memory.
+type fakeRunStore struct {
+	mu   sync.Mutex
+	runs []domain.JobRun
+}
+
+func (f *fakeRunStore) Start(_ context.Context, jobName string, trigger domain.JobRunTrigger, startedAt time.Time) (domain.JobRun, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	run := domain.JobRun{ID: jobName + "-run", JobName: jobName, Trigger: trigger, Status: domain.JobRunStatusRunning, StartedAt: startedAt}
+	f.runs = append(f.runs, run)
+	return run, nil
+}
+
+func (f *fakeRunStore) Finish(_ context.Context, id string, status domain.JobRunStatus, finishedAt time.Time, errMsg string) (domain.JobRun, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range f.runs {
+		if f.runs[i].ID == id {
+			f.runs[i].Status = status
+			f.runs[i].Error = errMsg
+			f.runs[i].FinishedAt = &finishedAt
+			return f.runs[i], nil
+		}
+	}
+	return domain.JobRun{}, errors.New("run not found")
+}
+
+func (f *fakeRunStore) ListByJob(_ context.Context, jobName string, limit int) ([]domain.JobRun, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var runs []domain.JobRun
+	for _, r := range f.runs {
+		if r.JobName == jobName && len(runs) < limit {
+			runs = append(runs, r)
+		}
+	}
+	return runs, nil
+}
+
+// fakeLocker grants or refuses every lock.
+type fakeLocker struct {
+	acquire  bool
+	released int
+}
+
+func (l *fakeLocker) TryLock(context.Context, string) (func(), bool, error) {
+	if !l.acquire {
+		return nil, false, nil
+	}
+	return func() { l.released++ }, true, nil
+}
+
+// TestScheduler tests registration, manual triggering, run recording and shutdown
+func TestScheduler(t *testing.T) {
+	// a schedule that never fires during the test, so only manual runs happen
+	const never = "0 0 1 1 *"
+
+	t.Run("Manual trigger runs the job and records it", func(t *testing.T) {
+		store := &fakeRunStore{}
+		locker := &fakeLocker{acquire: true}
+		s := NewScheduler().WithRunStore(store).WithLocker(locker)
+		ran := make(chan struct{})
+		require.NoError(t, s.Register("sweep", never, func(context.Context) error {
+			close(ran)
+			return nil
+		}))
+
+		s.Start(context.Background())
+		require.NoError(t, s.Trigger("sweep"))
+		<-ran
+		s.Stop()
+
+		infos, err := s.Jobs(context.Background())
+		require.NoError(t, err)
+		require.Len(t, infos, 1)
+		assert.Equal(t, "sweep", infos[0].Name)
+		require.Len(t, infos[0].RecentRuns, 1)
+		assert.Equal(t, domain.JobRunTriggerManual, infos[0].RecentRuns[0].Trigger)
+		assert.Equal(t, domain.JobRunStatusSucceeded, infos[0].RecentRuns[0].Status)
+		assert.Equal(t, 1, locker.released)
+	})
+
+	t.Run("Failures and panics are recorded as failed runs", func(t *testing.T) {
+		store := &fakeRunStore{}
+		s := NewScheduler().WithRunStore(store)
+		require.NoError(t, s.Register("boom", never, func(context.Context) error { panic("boom") }))
+
+		s.Start(context.Background())
+		require.NoError(t, s.Trigger("boom"))
+		s.Stop()
+
+		require.Len(t, store.runs, 1)
+		assert.Equal(t, domain.JobRunStatusFailed, store.runs[0].Status)
+		assert.Contains(t, store.runs[0].Error, "panic: boom")
+	})
+
+	t.Run("Job held by another replica is skipped", func(t *testing.T) {
+		store := &fakeRunStore{}
+		s := NewScheduler().WithRunStore(store).WithLocker(&fakeLocker{acquire: false})
+		called := false
+		require.NoError(t, s.Register("sweep", never, func(context.Context) error {
+			called = true
+			return nil
+		}))
+
+		s.Start(context.Background())
+		require.NoError(t, s.Trigger("sweep"))
+		s.Stop()
+
+		assert.False(t, called)
+		assert.Empty(t, store.runs)
+	})
+
+	t.Run("Trigger errors", func(t *testing.T) {
+		s := NewScheduler()
+		release := make(chan struct{})
+		require.NoError(t, s.Register("slow", never, func(context.Context) error {
+			<-release
+			return nil
+		}))
+
+		assert.ErrorIs(t, s.Trigger("slow"), domain.ErrConflict, "not started")
+
+		s.Start(context.Background())
+		assert.ErrorIs(t, s.Trigger("missing"), domain.ErrJobNotFound)
+		require.NoError(t, s.Trigger("slow"))
+		assert.ErrorIs(t, s.Trigger("slow"), domain.ErrConflict, "already running")
+
+		close(release)
+		s.Stop()
+		assert.ErrorIs(t, s.Trigger("slow"), domain.ErrConflict, "stopped")
+	})
+
+	t.Run("Scheduled runs fire and Stop waits for them", func(t *testing.T) {
+		s := NewScheduler()
+		var mu sync.Mutex
+		runs := 0
+		require.NoError(t, s.Register("tick", "@every 1s", func(ctx context.Context) error {
+			mu.Lock()
+			runs++
+			mu.Unlock()
+			return nil
+		}))
+
+		// the first activation is computed from a second ago, so it is due immediately
+		var clockMu sync.Mutex
+		offset := -time.Second
+		s.now = func() time.Time {
+			clockMu.Lock()
+			defer clockMu.Unlock()
+			now := time.Now().Add(offset)
+			offset = 0
+			return now
+		}
+		s.Start(context.Background())
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return runs > 0
+		}, time.Second, 10*time.Millisecond)
+		s.Stop()
+	})
+
+	t.Run("Invalid registrations", func(t *testing.T) {
+		s := NewScheduler()
+		assert.ErrorIs(t, s.Register("", never, nil), domain.ErrValidation)
+		assert.ErrorIs(t, s.Register("bad", "not a cron", nil), domain.ErrValidation)
+		require.NoError(t, s.Register("dup", never, nil))
+		assert.ErrorIs(t, s.Register("dup", never, nil), domain.ErrConflict)
+	})
+}
@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("JSON output", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := New(&buf, "json", "info")
+		require.NoError(t, err)
+
+		logger.Info("hello", "tool_id", "t1")
+		logger.Debug("hidden")
+
+		var line map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+		assert.Equal(t, "hello", line["msg"])
+		assert.Equal(t, "t1", line["tool_id"])
+	})
+
+	t.Run("Text output at debug level", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := New(&buf, "TEXT", "debug")
+		require.NoError(t, err)
+
+		logger.Debug("hello")
+
+		assert.Contains(t, buf.String(), "level=DEBUG msg=hello")
+	})
+
+	t.Run("Invalid settings", func(t *testing.T) {
+		_, err := New(&bytes.Buffer{}, "xml", "info")
+		assert.ErrorContains(t, err, "invalid log format")
+
+		_, err = New(&bytes.Buffer{}, "json", "loud")
+		assert.ErrorContains(t, err, "invalid log level")
+	})
+}
+
+func TestContext(t *testing.T) {
+	assert.Same(t, slog.Default(), FromContext(context.Background()))
+
+	var buf bytes.Buffer
+	logger, err := New(&buf, "json", "info")
+	require.NoError(t, err)
+
+	ctx := With(NewContext(context.Background(), logger), "request_id", "r1")
+	FromContext(ctx).Info("hello")
+
+	assert.Contains(t, buf.String(), `"request_id":"r1"`)
+}
@@ -0,0 +1,213 @@
+// Package metrics exposes the API's Prometheus metrics: HTTP latency, database
+// pool stats, business counters recorded by the services and gauges read from
+// the database at scrape time.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// namespace prefixes every metric name.
+const namespace = "tooltracker"
+
+// Metrics owns a registry with the API's collectors. It implements service.Metrics.
+type Metrics struct {
+	registry      *prometheus.Registry
+	httpDuration  *prometheus.HistogramVec
+	toolActions   *prometheus.CounterVec
+	eventsWritten *prometheus.CounterVec
+}
+
+// New registers the Go runtime, process, HTTP and business counter collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of HTTP requests by method, route and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		toolActions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tool_actions_total",
+			Help:      "Tool actions handled by the tool service, by action and outcome (success, rejected, error).",
+		}, []string{"action", "outcome"}),
+		eventsWritten: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "events_written_total",
+			Help:      "Events written to the audit log by this process, by event type.",
+		}, []string{"type"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpDuration,
+		m.toolActions,
+		m.eventsWritten,
+	)
+	return m
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDBStats exports the connection pool statistics of db (sql.DB.Stats).
+func (m *Metrics) RegisterDBStats(db *sql.DB) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, namespace))
+}
+
+// RegisterDomain exports the domain gauges read through source on each scrape.
+func (m *Metrics) RegisterDomain(source DomainSource) {
+	m.registry.MustRegister(newDomainCollector(source))
+}
+
+// ObserveHTTP records one request. route is the matched route pattern, not the raw path,
+// to keep the label's cardinality bounded.
+func (m *Metrics) ObserveHTTP(method, route string, status int, d time.Duration) {
+	m.httpDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(d.Seconds())
+}
+
+// ToolAction counts a finished tool action; err is nil on success.
+func (m *Metrics) ToolAction(action string, err error) {
+	m.toolActions.WithLabelValues(action, outcome(err)).Inc()
+}
+
+// EventWritten counts an event stored in the audit log.
+func (m *Metrics) EventWritten(eventType domain.EventType) {
+	m.eventsWritten.WithLabelValues(string(eventType)).Inc()
+}
+
+// outcome separates requests refused by business rules from failures.
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case errors.Is(err, domain.ErrValidation),
+		errors.Is(err, domain.ErrConflict),
+		errors.Is(err, domain.ErrPreconditionFailed),
+		errors.Is(err, domain.ErrToolNotFound),
+		errors.Is(err, domain.ErrUserNotFound):
+		return "rejected"
+	default:
+		return "error"
+	}
+}
+
+// DomainSource reads the current state behind the domain gauges.
+type DomainSource struct {
+	ToolsByStatus func(ctx context.Context) (map[domain.ToolStatus]int, error)
+	Checkouts     func(ctx context.Context) (active, overdue int, err error)
+	EventsByType  func(ctx context.Context) (map[domain.EventType]int, error)
+}
+
+const (
+	// domainQueryTimeout bounds the queries of one scrape.
+	domainQueryTimeout = 5 * time.Second
+	// domainCacheTTL reuses a snapshot across scrapes that arrive close together
+	// (several Prometheus replicas), so the GROUP BY queries run at most this often.
+	domainCacheTTL = 10 * time.Second
+)
+
+var (
+	toolsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "tools"),
+		"Tools by status.", []string{"status"}, nil)
+	activeCheckoutsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "active_checkouts"),
+		"Tools currently checked out.", nil, nil)
+	overdueToolsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "overdue_tools"),
+		"Checked-out tools past their due date.", nil, nil)
+	eventsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "events"),
+		"Events in the audit log by event type.", []string{"type"}, nil)
+	domainScrapeErrorDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "domain_scrape_error"),
+		"1 if reading the domain gauges failed on the last scrape.", nil, nil)
+)
+
+type domainSnapshot struct {
+	toolsByStatus   map[domain.ToolStatus]int
+	active, overdue int
+	eventsByType    map[domain.EventType]int
+}
+
+// domainCollector queries the database when scraped rather than keeping gauges
+// in sync with every write, so the values are right even with several replicas.
+type domainCollector struct {
+	source DomainSource
+	now    func() time.Time
+
+	mu      sync.Mutex
+	cached  domainSnapshot
+	expires time.Time
+}
+
+func newDomainCollector(source DomainSource) *domainCollector {
+	return &domainCollector{source: source, now: time.Now}
+}
+
+func (c *domainCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- toolsDesc
+	ch <- activeCheckoutsDesc
+	ch <- overdueToolsDesc
+	ch <- eventsDesc
+	ch <- domainScrapeErrorDesc
+}
+
+func (c *domainCollector) Collect(ch chan<- prometheus.Metric) {
+	snap, err := c.snapshot()
+	if err != nil {
+		slog.Error("failed to read domain metrics", "error", err)
+		ch <- prometheus.MustNewConstMetric(domainScrapeErrorDesc, prometheus.GaugeValue, 1)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(domainScrapeErrorDesc, prometheus.GaugeValue, 0)
+
+	// every status is exported, so a status that empties out drops to 0 instead of vanishing
+	for _, status := range domain.ValidToolStatuses() {
+		ch <- prometheus.MustNewConstMetric(toolsDesc, prometheus.GaugeValue, float64(snap.toolsByStatus[status]), string(status))
+	}
+	ch <- prometheus.MustNewConstMetric(activeCheckoutsDesc, prometheus.GaugeValue, float64(snap.active))
+	ch <- prometheus.MustNewConstMetric(overdueToolsDesc, prometheus.GaugeValue, float64(snap.overdue))
+	for _, eventType := range domain.ValidEventTypes() {
+		ch <- prometheus.MustNewConstMetric(eventsDesc, prometheus.GaugeValue, float64(snap.eventsByType[eventType]), string(eventType))
+	}
+}
+
+func (c *domainCollector) snapshot() (domainSnapshot, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.now().Before(c.expires) {
+		return c.cached, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), domainQueryTimeout)
+	defer cancel()
+
+	var snap domainSnapshot
+	var err error
+	if snap.toolsByStatus, err = c.source.ToolsByStatus(ctx); err != nil {
+		return domainSnapshot{}, err
+	}
+	if snap.active, snap.overdue, err = c.source.Checkouts(ctx); err != nil {
+		return domainSnapshot{}, err
+	}
+	if snap.eventsByType, err = c.source.EventsByType(ctx); err != nil {
+		return domainSnapshot{}, err
+	}
+
+	c.cached = snap
+	c.expires = c.now().Add(domainCacheTTL)
+	return snap, nil
+}
@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+func TestCounters(t *testing.T) {
+	m := New()
+
+	m.ToolAction("checkout", nil)
+	m.ToolAction("checkout", fmt.Errorf("%w: tool is already checked out", domain.ErrConflict))
+	m.ToolAction("checkout", errors.New("connection reset"))
+	m.EventWritten(domain.EventTypeToolCheckedOut)
+	m.EventWritten(domain.EventTypeToolCheckedOut)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.toolActions.WithLabelValues("checkout", "success")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.toolActions.WithLabelValues("checkout", "rejected")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.toolActions.WithLabelValues("checkout", "error")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.eventsWritten.WithLabelValues(string(domain.EventTypeToolCheckedOut))))
+}
+
+func TestDomainCollector(t *testing.T) {
+	source := func(calls *int, err error) DomainSource {
+		return DomainSource{
+			ToolsByStatus: func(context.Context) (map[domain.ToolStatus]int, error) {
+				*calls++
+				return map[domain.ToolStatus]int{domain.ToolStatusInOffice: 3, domain.ToolStatusCheckedOut: 2}, err
+			},
+			Checkouts: func(context.Context) (int, int, error) { return 2, 1, nil },
+			EventsByType: func(context.Context) (map[domain.EventType]int, error) {
+				return map[domain.EventType]int{domain.EventTypeToolCreated: 5}, nil
+			},
+		}
+	}
+
+	t.Run("Gauges reflect the source", func(t *testing.T) {
+		calls := 0
+		c := newDomainCollector(source(&calls, nil))
+
+		want := `
+# HELP tooltracker_active_checkouts Tools currently checked out.
+# TYPE tooltracker_active_checkouts gauge
+tooltracker_active_checkouts 2
+# HELP tooltracker_overdue_tools Checked-out tools past their due date.
+# TYPE tooltracker_overdue_tools gauge
+tooltracker_overdue_tools 1
+# HELP tooltracker_tools Tools by status.
+# TYPE tooltracker_tools gauge
+tooltracker_tools{status="CHECKED_OUT"} 2
+tooltracker_tools{status="IN_OFFICE"} 3
+tooltracker_tools{status="LOST"} 0
+tooltracker_tools{status="MAINTENANCE"} 0
+`
+		err := testutil.CollectAndCompare(c, strings.NewReader(want),
+			"tooltracker_active_checkouts", "tooltracker_overdue_tools", "tooltracker_tools")
+		require.NoError(t, err)
+	})
+
+	t.Run("Snapshot is cached between scrapes", func(t *testing.T) {
+		calls := 0
+		now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+		c := newDomainCollector(source(&calls, nil))
+		c.now = func() time.Time { return now }
+
+		testutil.CollectAndCount(c)
+		testutil.CollectAndCount(c)
+		assert.Equal(t, 1, calls)
+
+		now = now.Add(domainCacheTTL)
+		testutil.CollectAndCount(c)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Failed read sets the error gauge", func(t *testing.T) {
+		calls := 0
+		c := newDomainCollector(source(&calls, errors.New("db down")))
+
+		want := `
+# HELP tooltracker_domain_scrape_error 1 if reading the domain gauges failed on the last scrape.
+# TYPE tooltracker_domain_scrape_error gauge
+tooltracker_domain_scrape_error 1
+`
+		require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(want)))
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	m := New()
+	m.ObserveHTTP("GET", "/api/tools/:id", 200, 30*time.Millisecond)
+
+	families, err := m.registry.Gather()
+	require.NoError(t, err)
+
+	names := map[string]bool{}
+	for _, f := range families {
+		names[f.GetName()] = true
+	}
+	assert.True(t, names["tooltracker_http_request_duration_seconds"])
+	assert.True(t, names["go_goroutines"])
+	assert.Equal(t, 1, testutil.CollectAndCount(m.httpDuration))
+}
@@ -0,0 +1,43 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// PostgresAdvisoryLocker hands out session-level advisory locks, so only one
+// replica sharing the database runs a given piece of work at a time.
+type PostgresAdvisoryLocker struct {
+	db *sql.DB
+}
+
+func NewPostgresAdvisoryLocker(db *sql.DB) *PostgresAdvisoryLocker {
+	return &PostgresAdvisoryLocker{db: db}
+}
+
+// TryLock takes the advisory lock for key without waiting. When acquired is true the
+// caller must call release, which unlocks and returns the pinned connection to the pool.
+// Advisory locks belong to a session, so the lock and unlock run on one dedicated connection.
+func (l *PostgresAdvisoryLocker) TryLock(ctx context.Context, key string) (release func(), acquired bool, err error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get connection for advisory lock: %w", err)
+	}
+
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("failed to take advisory lock: %w", err)
+	}
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	release = func() {
+		// unlock even if the caller's context is done; closing the session would release it anyway
+		_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, key)
+		conn.Close()
+	}
+	return release, true, nil
+}
@@ -0,0 +1,140 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+type PostgresAPIKeyRepo struct {
+	db DBTX
+}
+
+func NewPostgresAPIKeyRepo(db DBTX) *PostgresAPIKeyRepo {
+	return &PostgresAPIKeyRepo{db: db}
+}
+
+// Helper function to define the column order for api key returns
+func (r *PostgresAPIKeyRepo) apiKeyColumns() string {
+	return "id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at"
+}
+
+// Helper function to scan a row into an APIKey struct
+func (r *PostgresAPIKeyRepo) scanAPIKey(scanner interface {
+	Scan(dest ...any) error
+}) (domain.APIKey, error) {
+	var key domain.APIKey
+	err := scanner.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		&key.Hash,
+		pq.Array(&key.Scopes),
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	)
+	if key.Scopes == nil {
+		key.Scopes = []string{}
+	}
+	return key, err
+}
+
+func (r *PostgresAPIKeyRepo) Create(ctx context.Context, k domain.APIKey) (domain.APIKey, error) {
+	query := `INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING ` + r.apiKeyColumns()
+	row := r.db.QueryRowContext(ctx, query, k.UserID, k.Name, k.Prefix, k.Hash, pq.Array(k.Scopes), k.ExpiresAt)
+	created, err := r.scanAPIKey(row)
+	if err != nil {
+		return domain.APIKey{}, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *PostgresAPIKeyRepo) Get(ctx context.Context, id string) (domain.APIKey, error) {
+	query := `SELECT ` + r.apiKeyColumns() + ` FROM api_keys WHERE id = $1`
+
+	row := r.db.QueryRowContext(ctx, query, id)
+	key, err := r.scanAPIKey(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.APIKey{}, domain.ErrAPIKeyNotFound
+		}
+		return domain.APIKey{}, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return key, nil
+}
+
+func (r *PostgresAPIKeyRepo) GetByPrefix(ctx context.Context, prefix string) (domain.APIKey, error) {
+	query := `SELECT ` + r.apiKeyColumns() + ` FROM api_keys WHERE prefix = $1`
+
+	row := r.db.QueryRowContext(ctx, query, prefix)
+	key, err := r.scanAPIKey(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.APIKey{}, domain.ErrAPIKeyNotFound
+		}
+		return domain.APIKey{}, fmt.Errorf("failed to get api key by prefix: %w", err)
+	}
+
+	return key, nil
+}
+
+func (r *PostgresAPIKeyRepo) ListByUser(ctx context.Context, userID string) ([]domain.APIKey, error) {
+	query := `SELECT ` + r.apiKeyColumns() + ` FROM api_keys WHERE user_id = $1 ORDER BY created_at DESC`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api keys by user: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []domain.APIKey
+	for rows.Next() {
+		key, err := r.scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Revoke marks a key as revoked. Revoking an already revoked key is a no-op.
+func (r *PostgresAPIKeyRepo) Revoke(ctx context.Context, id string) error {
+	query := `UPDATE api_keys SET revoked_at = COALESCE(revoked_at, NOW()) WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresAPIKeyRepo) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	query := `UPDATE api_keys SET last_used_at = $1 WHERE id = $2`
+	if _, err := r.db.ExecContext(ctx, query, at, id); err != nil {
+		return fmt.Errorf("failed to update api key last used: %w", err)
+	}
+	return nil
+}
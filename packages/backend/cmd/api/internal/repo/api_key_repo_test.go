@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// TestPostgresAPIKeyRepo_CRUD tests api key persistence
+func TestPostgresAPIKeyRepo_CRUD(t *testing.T) {
+	db := setupSharedRepoTestDB(t)
+	ctx := context.Background()
+	repo := NewPostgresAPIKeyRepo(db)
+	userID := createTestUser(t, db, "Script Owner", "owner@example.com", domain.UserRoleEmployee)
+
+	newKey := func(prefix string) domain.APIKey {
+		return domain.APIKey{UserID: userID, Name: "script " + prefix, Prefix: prefix, Hash: "hash-" + prefix, Scopes: []string{"tools:read"}}
+	}
+
+	t.Run("Create and Get API key", func(t *testing.T) {
+		created, err := repo.Create(ctx, newKey("aaaa0001"))
+		require.NoError(t, err)
+		assert.NotEmpty(t, created.ID)
+		assert.Equal(t, []string{"tools:read"}, created.Scopes)
+		assert.Nil(t, created.RevokedAt)
+
+		retrieved, err := repo.Get(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, created.Prefix, retrieved.Prefix)
+		assert.Equal(t, "hash-aaaa0001", retrieved.Hash)
+	})
+
+	t.Run("Get by prefix", func(t *testing.T) {
+		created, err := repo.Create(ctx, newKey("aaaa0002"))
+		require.NoError(t, err)
+
+		retrieved, err := repo.GetByPrefix(ctx, "aaaa0002")
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, retrieved.ID)
+
+		_, err = repo.GetByPrefix(ctx, "missing0")
+		assert.ErrorIs(t, err, domain.ErrAPIKeyNotFound)
+	})
+
+	t.Run("Duplicate prefix should fail", func(t *testing.T) {
+		_, err := repo.Create(ctx, newKey("aaaa0003"))
+		require.NoError(t, err)
+
+		_, err = repo.Create(ctx, newKey("aaaa0003"))
+		assert.Error(t, err)
+	})
+
+	t.Run("List by user", func(t *testing.T) {
+		keys, err := repo.ListByUser(ctx, userID)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, len(keys), 3)
+	})
+
+	t.Run("Revoke and touch", func(t *testing.T) {
+		created, err := repo.Create(ctx, newKey("aaaa0004"))
+		require.NoError(t, err)
+
+		usedAt := time.Now().Truncate(time.Microsecond)
+		require.NoError(t, repo.TouchLastUsed(ctx, created.ID, usedAt))
+		require.NoError(t, repo.Revoke(ctx, created.ID))
+
+		retrieved, err := repo.Get(ctx, created.ID)
+		require.NoError(t, err)
+		require.NotNil(t, retrieved.LastUsedAt)
+		assert.True(t, usedAt.Equal(*retrieved.LastUsedAt))
+		assert.NotNil(t, retrieved.RevokedAt)
+	})
+
+	t.Run("Revoke non-existent key", func(t *testing.T) {
+		err := repo.Revoke(ctx, "00000000-0000-0000-0000-000000000000")
+		assert.ErrorIs(t, err, domain.ErrAPIKeyNotFound)
+	})
+}
@@ -0,0 +1,108 @@
+package repo_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/repo"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/service"
+)
+
+// TestToolService_ConcurrentCheckout tests that exactly one of many concurrent
+// checkouts of the same tool succeeds, and that only it is logged
+func TestToolService_ConcurrentCheckout(t *testing.T) {
+	db := repo.SetupSharedRepoTestDB(t)
+	ctx := context.Background()
+
+	toolID := repo.CreateTestTool(t, db, "Contested Drill", domain.ToolStatusInOffice)
+	userID := repo.CreateTestUser(t, db, "Racer", "racer@example.com", domain.UserRoleEmployee)
+
+	svc := service.NewToolService(repo.NewPostgresToolRepo(db)).
+		WithEventLogger(service.NewEventService(repo.NewPostgresEventRepo(db))).
+		WithUnitOfWork(service.NewPostgresUnitOfWork(db))
+
+	const attempts = 10
+	var wg sync.WaitGroup
+	errs := make([]error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = svc.CheckOutTool(ctx, toolID, domain.AnyVersion, userID, userID, "race", service.CheckoutOptions{})
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	}
+	assert.Equal(t, 1, succeeded)
+
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM events WHERE type = $1 AND tool_id = $2", domain.EventTypeToolCheckedOut, toolID).Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "only the winning checkout should be logged")
+}
+
+// TestConcurrentCheckoutAndUserUpdate tests that checkouts logging events about a
+// user do not deadlock with updates of that user, and that the chain stays intact
+func TestConcurrentCheckoutAndUserUpdate(t *testing.T) {
+	db := repo.SetupSharedRepoTestDB(t)
+	ctx := context.Background()
+
+	userID := repo.CreateTestUser(t, db, "Contested User", "contested@example.com", domain.UserRoleEmployee)
+	events := service.NewEventService(repo.NewPostgresEventRepo(db))
+	uow := service.NewPostgresUnitOfWork(db)
+	tools := service.NewToolService(repo.NewPostgresToolRepo(db)).WithEventLogger(events).WithUnitOfWork(uow)
+	users := service.NewUserService(repo.NewPostgresUserRepo(db)).WithEventLogger(events).WithUnitOfWork(uow)
+
+	const workers, rounds = 4, 10
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*workers*rounds)
+	for w := 0; w < workers; w++ {
+		toolID := repo.CreateTestTool(t, db, fmt.Sprintf("Shared Drill %d", w), domain.ToolStatusInOffice)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				if _, err := tools.CheckOutTool(ctx, toolID, domain.AnyVersion, userID, userID, "race", service.CheckoutOptions{}); err != nil {
+					errs <- err
+					continue
+				}
+				if _, err := tools.ReturnTool(ctx, toolID, domain.AnyVersion, userID, "race", service.ReturnOptions{}); err != nil {
+					errs <- err
+				}
+			}
+		}()
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				// changing the email, a unique key, upgrades the row lock of the update
+				email := fmt.Sprintf("contested-%d-%d@example.com", w, i)
+				if _, err := users.UpdateUser(ctx, userID, domain.AnyVersion, "Contested User", email, domain.UserRoleEmployee, userID, "race"); err != nil {
+					errs <- err
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	result, err := service.NewAuditService(repo.NewPostgresEventRepo(db)).VerifyChain(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.Valid, "broken link: %+v", result.BrokenLink)
+	assert.GreaterOrEqual(t, result.EventsChecked, 3*workers*rounds)
+}
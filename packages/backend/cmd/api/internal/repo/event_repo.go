@@ -1,24 +1,41 @@
 package repo
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
 )
 
 type PostgresEventRepo struct {
-	db *sql.DB
+	db DBTX
+	// deferChain and pending are set by WithDeferredChain
+	deferChain bool
+	pending    []domain.Event
 }
 
-func NewPostgresEventRepo(db *sql.DB) *PostgresEventRepo {
+func NewPostgresEventRepo(db DBTX) *PostgresEventRepo {
 	return &PostgresEventRepo{db: db}
 }
 
+// WithDeferredChain makes Create insert events unchained and leave them for
+// ChainPending (optional chaining style). A unit of work uses it so the chain head
+// is the last lock its transaction takes, after every row its events reference,
+// and is held only from then until commit.
+func (r *PostgresEventRepo) WithDeferredChain() *PostgresEventRepo {
+	r.deferChain = true
+	return r
+}
+
 // Helper function to define the column order for event returns
 func (r *PostgresEventRepo) eventColumns() string {
-	return "id, type, tool_id, user_id, actor_id, notes, metadata, created_at"
+	return "id, type, tool_id, user_id, actor_id, notes, metadata, created_at, chain_seq, prev_hash, hash"
 }
 
 // Helper function to scan a row into an Event struct
@@ -26,6 +43,8 @@ func (r *PostgresEventRepo) scanEvent(scanner interface {
 	Scan(dest ...any) error
 }) (domain.Event, error) {
 	var event domain.Event
+	var metadata []byte
+	var prevHash, hash sql.NullString
 	err := scanner.Scan(
 		&event.ID,
 		&event.Type,
@@ -33,36 +52,203 @@ func (r *PostgresEventRepo) scanEvent(scanner interface {
 		&event.UserID,
 		&event.ActorID,
 		&event.Notes,
-		&event.Metadata,
+		&metadata,
 		&event.CreatedAt,
+		&event.ChainSeq,
+		&prevHash,
+		&hash,
 	)
+	if err != nil {
+		return event, err
+	}
+	event.PrevHash, event.Hash = prevHash.String, hash.String
+	event.Metadata, err = scanMetadata(metadata)
 	return event, err
 }
 
-func (r *PostgresEventRepo) Create(eventType domain.EventType, toolID, userID, actorID *string, notes string, metadata *string) (domain.Event, error) {
+// metadataValue marshals m for the JSONB metadata column; nil and empty metadata are stored as NULL.
+func metadataValue(m *domain.EventMetadata) (any, error) {
+	if m == nil || m.IsZero() {
+		return nil, nil
+	}
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event metadata: %w", err)
+	}
+	return string(raw), nil
+}
+
+// scanMetadata decodes the metadata column. Rows written before metadata was typed
+// may hold '{}', which reads as no metadata.
+func scanMetadata(raw []byte) (*domain.EventMetadata, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var m domain.EventMetadata
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, fmt.Errorf("failed to decode event metadata: %w", err)
+	}
+	if m.IsZero() {
+		return nil, nil
+	}
+	return &m, nil
+}
+
+// chainAttempts bounds how often Create retries when other inserts keep advancing
+// the chain head. Every round one of the racing writers wins, so a writer only runs
+// out of attempts behind that many concurrent ones.
+const chainAttempts = 50
+
+// Create appends an event to the audit log, hashed together with the hash of the
+// event before it (see domain.HashEvent).
+//
+// The insert is serialized on the event_chain row: the same statement advances the
+// head, and only if it is still the head the hash was computed against. A writer
+// that lost the race inserts nothing and retries on the new head. With
+// WithDeferredChain the event is inserted unchained instead and ChainPending
+// chains it.
+func (r *PostgresEventRepo) Create(ctx context.Context, eventType domain.EventType, toolID, userID, actorID *string, notes string, metadata *domain.EventMetadata) (domain.Event, error) {
+	// the ID is hashed, so it is chosen here rather than by the column default; the
+	// referenced IDs are stored as they read back
 	event := domain.Event{
+		ID:        uuid.NewString(),
 		Type:      eventType,
-		ToolID:    toolID,
-		UserID:    userID,
-		ActorID:   actorID,
+		ToolID:    domain.CanonicalUUIDPtr(toolID),
+		UserID:    domain.CanonicalUUIDPtr(userID),
+		ActorID:   domain.CanonicalUUIDPtr(actorID),
 		Notes:     notes,
 		Metadata:  metadata,
-		CreatedAt: time.Now(),
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+
+	metadataJSON, err := metadataValue(event.Metadata)
+	if err != nil {
+		return domain.Event{}, err
+	}
+
+	if r.deferChain {
+		query := `INSERT INTO events (id, type, tool_id, user_id, actor_id, notes, metadata, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING ` + r.eventColumns()
+		row := r.db.QueryRowContext(ctx, query, event.ID, event.Type, event.ToolID, event.UserID, event.ActorID, event.Notes, metadataJSON, event.CreatedAt)
+		createdEvent, err := r.scanEvent(row)
+		if err != nil {
+			return domain.Event{}, fmt.Errorf("failed to create event: %w", err)
+		}
+		r.pending = append(r.pending, createdEvent)
+		return createdEvent, nil
+	}
+
+	query := `WITH head AS (
+		UPDATE event_chain SET seq = $9, hash = $11 WHERE id = 1 AND seq = $9 - 1 RETURNING seq
+	)
+	INSERT INTO events (id, type, tool_id, user_id, actor_id, notes, metadata, created_at, chain_seq, prev_hash, hash)
+	SELECT $1::uuid, $2::event_type, $3::uuid, $4::uuid, $5::uuid, $6, $7::jsonb, $8::timestamptz, head.seq, $10, $11 FROM head
+	RETURNING ` + r.eventColumns()
+	for attempt := 0; attempt < chainAttempts; attempt++ {
+		head, err := r.ChainHead(ctx)
+		if err != nil {
+			return domain.Event{}, fmt.Errorf("failed to create event: %w", err)
+		}
+		seq := head.Seq + 1
+		hash, err := domain.HashEvent(event, seq, head.Hash)
+		if err != nil {
+			return domain.Event{}, err
+		}
+
+		row := r.db.QueryRowContext(ctx, query, event.ID, event.Type, event.ToolID, event.UserID, event.ActorID, event.Notes, metadataJSON, event.CreatedAt, seq, head.Hash, hash)
+		createdEvent, err := r.scanEvent(row)
+		if errors.Is(err, sql.ErrNoRows) {
+			// another event took seq first
+			continue
+		}
+		if err != nil {
+			return domain.Event{}, fmt.Errorf("failed to create event: %w", err)
+		}
+		return createdEvent, nil
+	}
+	return domain.Event{}, fmt.Errorf("failed to create event: the chain head moved on %d attempts", chainAttempts)
+}
+
+// ChainPending appends the events Create left unchained to the hash chain, in the
+// order they were created, advances the head and returns the chained events. It
+// locks the head until the transaction ends.
+func (r *PostgresEventRepo) ChainPending(ctx context.Context) ([]domain.Event, error) {
+	if len(r.pending) == 0 {
+		return nil, nil
+	}
+	var head domain.ChainHead
+	err := r.db.QueryRowContext(ctx, `SELECT seq, hash FROM event_chain WHERE id = 1 FOR UPDATE`).Scan(&head.Seq, &head.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock chain head: %w", err)
+	}
+	chained := r.pending
+	for i, e := range chained {
+		seq := head.Seq + 1
+		hash, err := domain.HashEvent(e, seq, head.Hash)
+		if err != nil {
+			return nil, err
+		}
+		_, err = r.db.ExecContext(ctx, `UPDATE events SET chain_seq = $1, prev_hash = $2, hash = $3 WHERE id = $4`, seq, head.Hash, hash, e.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to chain event: %w", err)
+		}
+		chained[i].ChainSeq, chained[i].PrevHash, chained[i].Hash = &seq, head.Hash, hash
+		head = domain.ChainHead{Seq: seq, Hash: hash}
+	}
+	_, err = r.db.ExecContext(ctx, `UPDATE event_chain SET seq = $1, hash = $2 WHERE id = 1`, head.Seq, head.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to advance chain head: %w", err)
 	}
+	r.pending = nil
+	return chained, nil
+}
 
-	query := `INSERT INTO events (type, tool_id, user_id, actor_id, notes, metadata, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING ` + r.eventColumns()
-	row := r.db.QueryRow(query, event.Type, event.ToolID, event.UserID, event.ActorID, event.Notes, event.Metadata, event.CreatedAt)
-	createdEvent, err := r.scanEvent(row)
+// ChainHead returns the head of the audit log's hash chain.
+func (r *PostgresEventRepo) ChainHead(ctx context.Context) (domain.ChainHead, error) {
+	var head domain.ChainHead
+	err := r.db.QueryRowContext(ctx, `SELECT seq, hash FROM event_chain WHERE id = 1`).Scan(&head.Seq, &head.Hash)
 	if err != nil {
-		return domain.Event{}, fmt.Errorf("failed to create event: %w", err)
+		return domain.ChainHead{}, fmt.Errorf("failed to get chain head: %w", err)
 	}
+	return head, nil
+}
+
+// ListChain returns up to limit chained events in chain order, from after afterSeq
+// through throughSeq.
+func (r *PostgresEventRepo) ListChain(ctx context.Context, afterSeq, throughSeq int64, limit int) ([]domain.Event, error) {
+	query := `SELECT ` + r.eventColumns() + ` FROM events WHERE chain_seq > $1 AND chain_seq <= $2 ORDER BY chain_seq LIMIT $3`
+	rows, err := r.db.QueryContext(ctx, query, afterSeq, throughSeq, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event chain: %w", err)
+	}
+	defer rows.Close()
+
+	events := []domain.Event{}
+	for rows.Next() {
+		event, err := r.scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over events: %w", err)
+	}
+	return events, nil
+}
 
-	return createdEvent, nil
+// CountUnchained returns the number of events without a place in the hash chain.
+func (r *PostgresEventRepo) CountUnchained(ctx context.Context) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM events WHERE chain_seq IS NULL`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unchained events: %w", err)
+	}
+	return count, nil
 }
 
-func (r *PostgresEventRepo) List(limit, offset int) ([]domain.Event, error) {
-	query := `SELECT ` + r.eventColumns() + ` FROM events ORDER BY created_at DESC LIMIT $1 OFFSET $2`
-	rows, err := r.db.Query(query, limit, offset)
+func (r *PostgresEventRepo) List(ctx context.Context, limit, offset int) ([]domain.Event, error) {
+	query := `SELECT ` + r.eventColumns() + ` FROM events ORDER BY created_at DESC, id DESC LIMIT $1 OFFSET $2`
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query events: %w", err)
 	}
@@ -84,10 +270,10 @@ func (r *PostgresEventRepo) List(limit, offset int) ([]domain.Event, error) {
 	return events, nil
 }
 
-func (r *PostgresEventRepo) Get(id string) (domain.Event, error) {
+func (r *PostgresEventRepo) Get(ctx context.Context, id string) (domain.Event, error) {
 	query := `SELECT ` + r.eventColumns() + ` FROM events WHERE id = $1`
 
-	row := r.db.QueryRow(query, id)
+	row := r.db.QueryRowContext(ctx, query, id)
 	event, err := r.scanEvent(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -99,9 +285,9 @@ func (r *PostgresEventRepo) Get(id string) (domain.Event, error) {
 	return event, nil
 }
 
-func (r *PostgresEventRepo) ListByType(eventType domain.EventType, limit, offset int) ([]domain.Event, error) {
-	query := `SELECT ` + r.eventColumns() + ` FROM events WHERE type = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`
-	rows, err := r.db.Query(query, eventType, limit, offset)
+func (r *PostgresEventRepo) ListByType(ctx context.Context, eventType domain.EventType, limit, offset int) ([]domain.Event, error) {
+	query := `SELECT ` + r.eventColumns() + ` FROM events WHERE type = $1 ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`
+	rows, err := r.db.QueryContext(ctx, query, eventType, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query events by type: %w", err)
 	}
@@ -123,9 +309,10 @@ func (r *PostgresEventRepo) ListByType(eventType domain.EventType, limit, offset
 	return events, nil
 }
 
-func (r *PostgresEventRepo) ListByTool(toolID string, limit, offset int) ([]domain.Event, error) {
-	query := `SELECT ` + r.eventColumns() + ` FROM events WHERE tool_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`
-	rows, err := r.db.Query(query, toolID, limit, offset)
+// ListByTool returns the events of a tool newest first, after the cursor when one is given.
+func (r *PostgresEventRepo) ListByTool(ctx context.Context, toolID string, after *domain.Cursor, limit, offset int) ([]domain.Event, error) {
+	query, args := pageClause(`SELECT `+r.eventColumns()+` FROM events WHERE tool_id = $1`, []any{toolID}, after, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query events by tool: %w", err)
 	}
@@ -147,9 +334,11 @@ func (r *PostgresEventRepo) ListByTool(toolID string, limit, offset int) ([]doma
 	return events, nil
 }
 
-func (r *PostgresEventRepo) ListByUser(userID string, limit, offset int) ([]domain.Event, error) {
-	query := `SELECT ` + r.eventColumns() + ` FROM events WHERE user_id = $1 OR actor_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`
-	rows, err := r.db.Query(query, userID, limit, offset)
+// ListByUser returns the events a user is the subject or actor of, newest first,
+// after the cursor when one is given.
+func (r *PostgresEventRepo) ListByUser(ctx context.Context, userID string, after *domain.Cursor, limit, offset int) ([]domain.Event, error) {
+	query, args := pageClause(`SELECT `+r.eventColumns()+` FROM events WHERE (user_id = $1 OR actor_id = $1)`, []any{userID}, after, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query events by user: %w", err)
 	}
@@ -176,35 +365,57 @@ type EventFilter struct {
 	Type   *domain.EventType
 	ToolID *string
 	UserID *string
+	// Metadata keeps the events whose metadata has these values, keyed by field; a key
+	// of the form before.<field> or after.<field> looks into a snapshot.
+	Metadata map[string]string
+	// After keeps the events past this cursor; it takes the place of the offset.
+	After *domain.Cursor
 }
 
-func (r *PostgresEventRepo) ListWithFilter(filter EventFilter, limit, offset int) ([]domain.Event, error) {
-	query := `SELECT ` + r.eventColumns() + ` FROM events WHERE 1=1`
+// where returns the WHERE clause selecting the events that match f, ignoring After.
+func (f EventFilter) where() (string, []any) {
+	query := ` WHERE 1=1`
 	args := []any{}
 	argIndex := 1
 
-	if filter.Type != nil {
+	if f.Type != nil {
 		query += fmt.Sprintf(` AND type = $%d`, argIndex)
-		args = append(args, *filter.Type)
+		args = append(args, *f.Type)
 		argIndex++
 	}
 
-	if filter.ToolID != nil {
+	if f.ToolID != nil {
 		query += fmt.Sprintf(` AND tool_id = $%d`, argIndex)
-		args = append(args, *filter.ToolID)
+		args = append(args, *f.ToolID)
 		argIndex++
 	}
 
-	if filter.UserID != nil {
+	if f.UserID != nil {
 		query += fmt.Sprintf(` AND (user_id = $%d OR actor_id = $%d)`, argIndex, argIndex)
-		args = append(args, *filter.UserID)
+		args = append(args, *f.UserID)
 		argIndex++
 	}
 
-	query += fmt.Sprintf(` ORDER BY created_at DESC LIMIT $%d OFFSET $%d`, argIndex, argIndex+1)
-	args = append(args, limit, offset)
+	keys := make([]string, 0, len(f.Metadata))
+	for key := range f.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		// values compare as text, so numbers and timestamps match their JSON form
+		query += fmt.Sprintf(` AND metadata #>> string_to_array($%d, '.') = $%d`, argIndex, argIndex+1)
+		args = append(args, key, f.Metadata[key])
+		argIndex += 2
+	}
+	return query, args
+}
+
+func (r *PostgresEventRepo) ListWithFilter(ctx context.Context, filter EventFilter, limit, offset int) ([]domain.Event, error) {
+	where, args := filter.where()
+	query := `SELECT ` + r.eventColumns() + ` FROM events` + where
+	query, args = pageClause(query, args, filter.After, limit, offset)
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query events with filter: %w", err)
 	}
@@ -226,12 +437,152 @@ func (r *PostgresEventRepo) ListWithFilter(filter EventFilter, limit, offset int
 	return events, nil
 }
 
-func (r *PostgresEventRepo) Count() (int, error) {
+// CountWithFilter returns the number of events matching filter; After is ignored.
+func (r *PostgresEventRepo) CountWithFilter(ctx context.Context, filter EventFilter) (int, error) {
+	where, args := filter.where()
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM events`+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count events with filter: %w", err)
+	}
+	return count, nil
+}
+
+func (r *PostgresEventRepo) Count(ctx context.Context) (int, error) {
 	var count int
 	query := `SELECT COUNT(*) FROM events`
-	err := r.db.QueryRow(query).Scan(&count)
+	err := r.db.QueryRowContext(ctx, query).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count events: %w", err)
 	}
 	return count, nil
 }
+
+// CountByType returns the number of events of each type, optionally only those at or after since.
+func (r *PostgresEventRepo) CountByType(ctx context.Context, since *time.Time) (map[domain.EventType]int, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT type, COUNT(*) FROM events
+		WHERE $1::timestamptz IS NULL OR created_at >= $1
+		GROUP BY type`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count events by type: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[domain.EventType]int{}
+	for rows.Next() {
+		var eventType domain.EventType
+		var count int
+		if err := rows.Scan(&eventType, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan event type count: %w", err)
+		}
+		counts[eventType] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count events by type: %w", err)
+	}
+	return counts, nil
+}
+
+// statusChangeColumns and statusChangeFrom select the events that move tools between
+// statuses: creation, checkout, check-in, maintenance, lost, deletion, restore, and
+// updates whose snapshots show a new status. For an update, the holder is the one
+// after it. Events of tools removed before deletes became soft are left out.
+const (
+	statusChangeColumns = `e.id AS event_id, e.type, t.id AS tool_id, t.name, h.user_id, COALESCE(u.name, ''),
+		COALESCE(e.metadata->'after'->>'status', ''), e.created_at`
+	statusChangeFrom = ` FROM events e
+		JOIN tools t ON t.id = e.tool_id
+		CROSS JOIN LATERAL (SELECT CASE WHEN e.type = 'TOOL_UPDATED'
+			THEN NULLIF(e.metadata->'after'->>'current_user_id', '')::uuid
+			ELSE e.user_id END AS user_id) h
+		LEFT JOIN users u ON u.id = h.user_id
+		WHERE (e.type IN ('TOOL_CREATED', 'TOOL_CHECKED_OUT', 'TOOL_CHECKED_IN', 'TOOL_MAINTENANCE', 'TOOL_LOST', 'TOOL_DELETED', 'TOOL_RESTORED')
+			OR (e.type = 'TOOL_UPDATED' AND e.metadata->'after'->>'status' <> e.metadata->'before'->>'status'))`
+)
+
+// ListStatusesAt returns, for each tool, its last status change before at and its
+// last deletion or restore before at, oldest first. Replayed, they give every
+// tool's status at at without the history before it.
+func (r *PostgresEventRepo) ListStatusesAt(ctx context.Context, at time.Time) ([]domain.StatusChange, error) {
+	return r.listStatusChanges(ctx, `SELECT * FROM (
+		SELECT DISTINCT ON (e.tool_id, e.type IN ('TOOL_DELETED', 'TOOL_RESTORED')) `+statusChangeColumns+statusChangeFrom+`
+			AND e.created_at < $1
+		ORDER BY e.tool_id, e.type IN ('TOOL_DELETED', 'TOOL_RESTORED'), e.created_at DESC, e.id DESC) s
+		ORDER BY s.created_at, s.event_id`, at)
+}
+
+// ListStatusChanges returns up to limit status changes in [from, to), oldest first,
+// after the cursor when one is given.
+func (r *PostgresEventRepo) ListStatusChanges(ctx context.Context, from, to time.Time, after *domain.Cursor, limit int) ([]domain.StatusChange, error) {
+	query := `SELECT ` + statusChangeColumns + statusChangeFrom + ` AND e.created_at >= $1 AND e.created_at < $2`
+	args := []any{from, to}
+	if after != nil {
+		query += ` AND (e.created_at, e.id) > ($3, $4)`
+		args = append(args, after.CreatedAt, after.ID)
+	}
+	query += fmt.Sprintf(` ORDER BY e.created_at, e.id LIMIT $%d`, len(args)+1)
+	args = append(args, limit)
+	return r.listStatusChanges(ctx, query, args...)
+}
+
+func (r *PostgresEventRepo) listStatusChanges(ctx context.Context, query string, args ...any) ([]domain.StatusChange, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list status changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []domain.StatusChange{}
+	for rows.Next() {
+		var sc domain.StatusChange
+		if err := rows.Scan(&sc.EventID, &sc.Type, &sc.ToolID, &sc.ToolName, &sc.UserID, &sc.UserName, &sc.Status, &sc.At); err != nil {
+			return nil, fmt.Errorf("failed to scan status change: %w", err)
+		}
+		changes = append(changes, sc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list status changes: %w", err)
+	}
+	return changes, nil
+}
+
+// ListToolReplay returns up to limit events that concern a tool in the order they
+// were applied, after the given event when one is given. It is the input the tool
+// projection is rebuilt from.
+//
+// Chained events are in chain order, which is commit order; created_at comes from
+// the writer's clock and can disagree with it. Events from before the chain come
+// first, by created_at.
+func (r *PostgresEventRepo) ListToolReplay(ctx context.Context, after *domain.Event, limit int) ([]domain.Event, error) {
+	query := `SELECT ` + r.eventColumns() + ` FROM events WHERE tool_id IS NOT NULL`
+	args := []any{}
+	switch {
+	case after == nil:
+	case after.ChainSeq != nil:
+		query += ` AND chain_seq > $1`
+		args = append(args, *after.ChainSeq)
+	default:
+		query += ` AND (chain_seq IS NOT NULL OR (created_at, id) > ($1, $2))`
+		args = append(args, after.CreatedAt, after.ID)
+	}
+	query += fmt.Sprintf(` ORDER BY chain_seq NULLS FIRST, created_at, id LIMIT $%d`, len(args)+1)
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events for replay: %w", err)
+	}
+	defer rows.Close()
+
+	events := []domain.Event{}
+	for rows.Next() {
+		event, err := r.scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over events: %w", err)
+	}
+	return events, nil
+}
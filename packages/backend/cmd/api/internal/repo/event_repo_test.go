@@ -1,7 +1,12 @@
 package repo
 
 import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -11,6 +16,7 @@ import (
 // TestPostgresEventRepo_CRUD tests all basic CRUD operations
 func TestPostgresEventRepo_CRUD(t *testing.T) {
 	db := setupSharedRepoTestDB(t)
+	ctx := context.Background()
 	repo := NewPostgresEventRepo(db)
 
 	// Create test data
@@ -19,7 +25,7 @@ func TestPostgresEventRepo_CRUD(t *testing.T) {
 	actorID := createTestUser(t, db, "Test Actor", "actor@example.com", domain.UserRoleManager)
 
 	t.Run("Create Event - Tool Created", func(t *testing.T) {
-		event, err := repo.Create(
+		event, err := repo.Create(ctx,
 			domain.EventTypeToolCreated,
 			&toolID,
 			nil,
@@ -40,14 +46,15 @@ func TestPostgresEventRepo_CRUD(t *testing.T) {
 	})
 
 	t.Run("Create Event - Tool Checked Out", func(t *testing.T) {
-		metadata := `{"location": "workshop", "expected_return": "2024-01-15"}`
-		event, err := repo.Create(
+		due := time.Date(2024, 1, 15, 17, 0, 0, 0, time.UTC)
+		metadata := &domain.EventMetadata{DueAt: &due, Condition: domain.ToolConditionGood}
+		event, err := repo.Create(ctx,
 			domain.EventTypeToolCheckedOut,
 			&toolID,
 			&userID,
 			&actorID,
 			"Tool checked out to user",
-			&metadata,
+			metadata,
 		)
 
 		require.NoError(t, err)
@@ -55,12 +62,43 @@ func TestPostgresEventRepo_CRUD(t *testing.T) {
 		assert.Equal(t, &toolID, event.ToolID)
 		assert.Equal(t, &userID, event.UserID)
 		assert.Equal(t, &actorID, event.ActorID)
-		assert.Equal(t, &metadata, event.Metadata)
+		assert.Equal(t, metadata, event.Metadata)
+	})
+
+	t.Run("Create Event - Uppercase IDs", func(t *testing.T) {
+		upperTool, upperUser := strings.ToUpper(toolID), strings.ToUpper(userID)
+		check := func(t *testing.T, id string) {
+			stored, err := repo.Get(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, &toolID, stored.ToolID)
+			assert.Equal(t, &userID, stored.UserID)
+			require.NotNil(t, stored.ChainSeq)
+			hash, err := domain.HashEvent(stored, *stored.ChainSeq, stored.PrevHash)
+			require.NoError(t, err)
+			assert.Equal(t, stored.Hash, hash)
+		}
+
+		event, err := repo.Create(ctx, domain.EventTypeToolCheckedIn, &upperTool, &upperUser, nil, "Returned", nil)
+		require.NoError(t, err)
+		assert.Equal(t, &toolID, event.ToolID)
+		check(t, event.ID)
+
+		var deferred domain.Event
+		err = WithTx(ctx, db, func(tx DBTX) error {
+			events := NewPostgresEventRepo(tx).WithDeferredChain()
+			if deferred, err = events.Create(ctx, domain.EventTypeToolCheckedOut, &upperTool, &upperUser, nil, "Out", nil); err != nil {
+				return err
+			}
+			_, err := events.ChainPending(ctx)
+			return err
+		})
+		require.NoError(t, err)
+		check(t, deferred.ID)
 	})
 
 	t.Run("Get Event", func(t *testing.T) {
 		// Create first
-		created, err := repo.Create(
+		created, err := repo.Create(ctx,
 			domain.EventTypeUserCreated,
 			nil,
 			&userID,
@@ -71,7 +109,7 @@ func TestPostgresEventRepo_CRUD(t *testing.T) {
 		require.NoError(t, err)
 
 		// Get it back
-		retrieved, err := repo.Get(created.ID)
+		retrieved, err := repo.Get(ctx, created.ID)
 		require.NoError(t, err)
 
 		assert.Equal(t, created.ID, retrieved.ID)
@@ -83,18 +121,18 @@ func TestPostgresEventRepo_CRUD(t *testing.T) {
 
 	t.Run("Count Events", func(t *testing.T) {
 		// Get initial count
-		initialCount, err := repo.Count()
+		initialCount, err := repo.Count(ctx)
 		require.NoError(t, err)
 
 		// Add events
-		_, err = repo.Create(domain.EventTypeToolMaintenance, &toolID, nil, &actorID, "Maintenance started", nil)
+		_, err = repo.Create(ctx, domain.EventTypeToolMaintenance, &toolID, nil, &actorID, "Maintenance started", nil)
 		require.NoError(t, err)
 
-		_, err = repo.Create(domain.EventTypeUserUpdated, nil, &userID, &actorID, "User updated", nil)
+		_, err = repo.Create(ctx, domain.EventTypeUserUpdated, nil, &userID, &actorID, "User updated", nil)
 		require.NoError(t, err)
 
 		// Count should increase
-		newCount, err := repo.Count()
+		newCount, err := repo.Count(ctx)
 		require.NoError(t, err)
 		assert.Equal(t, initialCount+2, newCount)
 	})
@@ -103,6 +141,7 @@ func TestPostgresEventRepo_CRUD(t *testing.T) {
 // TestPostgresEventRepo_QueryFeatures tests specialized query operations
 func TestPostgresEventRepo_QueryFeatures(t *testing.T) {
 	db := setupSharedRepoTestDB(t)
+	ctx := context.Background()
 	repo := NewPostgresEventRepo(db)
 
 	// Create test data
@@ -114,17 +153,17 @@ func TestPostgresEventRepo_QueryFeatures(t *testing.T) {
 
 	t.Run("List Events", func(t *testing.T) {
 		// Create test events
-		_, err := repo.Create(domain.EventTypeToolCreated, &tool1ID, nil, &actorID, "Tool 1 created", nil)
+		_, err := repo.Create(ctx, domain.EventTypeToolCreated, &tool1ID, nil, &actorID, "Tool 1 created", nil)
 		require.NoError(t, err)
 
-		_, err = repo.Create(domain.EventTypeToolCreated, &tool2ID, nil, &actorID, "Tool 2 created", nil)
+		_, err = repo.Create(ctx, domain.EventTypeToolCreated, &tool2ID, nil, &actorID, "Tool 2 created", nil)
 		require.NoError(t, err)
 
-		_, err = repo.Create(domain.EventTypeUserCreated, nil, &user1ID, &actorID, "User 1 created", nil)
+		_, err = repo.Create(ctx, domain.EventTypeUserCreated, nil, &user1ID, &actorID, "User 1 created", nil)
 		require.NoError(t, err)
 
 		// List with pagination
-		events, err := repo.List(2, 0)
+		events, err := repo.List(ctx, 2, 0)
 		require.NoError(t, err)
 		assert.Len(t, events, 2)
 
@@ -136,24 +175,24 @@ func TestPostgresEventRepo_QueryFeatures(t *testing.T) {
 
 	t.Run("List by Type", func(t *testing.T) {
 		// Create events of different types
-		_, err := repo.Create(domain.EventTypeToolCheckedOut, &tool1ID, &user1ID, &actorID, "Checkout 1", nil)
+		_, err := repo.Create(ctx, domain.EventTypeToolCheckedOut, &tool1ID, &user1ID, &actorID, "Checkout 1", nil)
 		require.NoError(t, err)
 
-		_, err = repo.Create(domain.EventTypeToolCheckedOut, &tool2ID, &user2ID, &actorID, "Checkout 2", nil)
+		_, err = repo.Create(ctx, domain.EventTypeToolCheckedOut, &tool2ID, &user2ID, &actorID, "Checkout 2", nil)
 		require.NoError(t, err)
 
-		_, err = repo.Create(domain.EventTypeToolCheckedIn, &tool1ID, &user1ID, &actorID, "Checkin 1", nil)
+		_, err = repo.Create(ctx, domain.EventTypeToolCheckedIn, &tool1ID, &user1ID, &actorID, "Checkin 1", nil)
 		require.NoError(t, err)
 
 		// Query by type
-		checkouts, err := repo.ListByType(domain.EventTypeToolCheckedOut, 10, 0)
+		checkouts, err := repo.ListByType(ctx, domain.EventTypeToolCheckedOut, 10, 0)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(checkouts), 2)
 		for _, event := range checkouts {
 			assert.Equal(t, domain.EventTypeToolCheckedOut, event.Type)
 		}
 
-		checkins, err := repo.ListByType(domain.EventTypeToolCheckedIn, 10, 0)
+		checkins, err := repo.ListByType(ctx, domain.EventTypeToolCheckedIn, 10, 0)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(checkins), 1)
 		for _, event := range checkins {
@@ -161,23 +200,107 @@ func TestPostgresEventRepo_QueryFeatures(t *testing.T) {
 		}
 	})
 
+	t.Run("Count by Type", func(t *testing.T) {
+		before := time.Now().Add(-time.Second)
+		_, err := repo.Create(ctx, domain.EventTypeToolLost, &tool2ID, nil, &actorID, "Lost", nil)
+		require.NoError(t, err)
+
+		all, err := repo.CountByType(ctx, nil)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, all[domain.EventTypeToolCheckedOut], 2)
+		assert.GreaterOrEqual(t, all[domain.EventTypeToolLost], 1)
+
+		future := time.Now().Add(time.Hour)
+		none, err := repo.CountByType(ctx, &future)
+		require.NoError(t, err)
+		assert.Empty(t, none)
+
+		recent, err := repo.CountByType(ctx, &before)
+		require.NoError(t, err)
+		assert.Equal(t, 1, recent[domain.EventTypeToolLost])
+	})
+
+	t.Run("List Status Changes", func(t *testing.T) {
+		from := time.Now().Add(-time.Millisecond)
+		_, err := repo.Create(ctx, domain.EventTypeToolCheckedOut, &tool1ID, &user1ID, &actorID, "Out", nil)
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, domain.EventTypeUserUpdated, nil, &user1ID, &actorID, "Not a status change", nil)
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, domain.EventTypeToolCheckedIn, &tool1ID, &user1ID, &actorID, "In", nil)
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, domain.EventTypeToolUpdated, &tool1ID, nil, &actorID, "Renamed", &domain.EventMetadata{
+			Before: json.RawMessage(`{"name":"Tool 1","status":"IN_OFFICE"}`),
+			After:  json.RawMessage(`{"name":"Tool One","status":"IN_OFFICE"}`),
+		})
+		require.NoError(t, err)
+		_, err = repo.Create(ctx, domain.EventTypeToolUpdated, &tool1ID, nil, &actorID, "Lent", &domain.EventMetadata{
+			Before: json.RawMessage(`{"status":"IN_OFFICE","current_user_id":null}`),
+			After:  json.RawMessage(`{"status":"CHECKED_OUT","current_user_id":"` + user1ID + `"}`),
+		})
+		require.NoError(t, err)
+		to := time.Now().Add(time.Second)
+
+		changes, err := repo.ListStatusChanges(ctx, from, to, nil, 10)
+		require.NoError(t, err)
+		require.Len(t, changes, 3)
+		assert.Equal(t, domain.EventTypeToolCheckedOut, changes[0].Type)
+		assert.Equal(t, domain.EventTypeToolCheckedIn, changes[1].Type)
+		assert.Equal(t, tool1ID, changes[1].ToolID)
+		assert.Equal(t, "Tool 1", changes[1].ToolName)
+		assert.Equal(t, "User 1", changes[1].UserName)
+		// an update is a status change only when it changed the status
+		lent := changes[2]
+		assert.Equal(t, domain.EventTypeToolUpdated, lent.Type)
+		assert.Equal(t, domain.ToolStatusCheckedOut, lent.Status)
+		assert.Equal(t, &user1ID, lent.UserID)
+		assert.Equal(t, "User 1", lent.UserName)
+
+		first, err := repo.ListStatusChanges(ctx, from, to, nil, 1)
+		require.NoError(t, err)
+		require.Len(t, first, 1)
+		rest, err := repo.ListStatusChanges(ctx, from, to, &domain.Cursor{CreatedAt: first[0].At, ID: first[0].EventID}, 10)
+		require.NoError(t, err)
+		assert.Equal(t, changes[1:], rest)
+
+		none, err := repo.ListStatusChanges(ctx, from.Add(-time.Hour), from, nil, 10)
+		require.NoError(t, err)
+		for _, c := range none {
+			assert.True(t, c.At.Before(from))
+		}
+
+		// the tool's status at to is the last change, not every change before it
+		statuses, err := repo.ListStatusesAt(ctx, to)
+		require.NoError(t, err)
+		var tool1 []domain.StatusChange
+		for i, c := range statuses {
+			if i > 0 {
+				assert.False(t, c.At.Before(statuses[i-1].At), "statuses must be oldest first")
+			}
+			if c.ToolID == tool1ID {
+				tool1 = append(tool1, c)
+			}
+		}
+		require.Len(t, tool1, 1)
+		assert.Equal(t, lent, tool1[0])
+	})
+
 	t.Run("List by Tool", func(t *testing.T) {
 		// Create events for specific tool
-		_, err := repo.Create(domain.EventTypeToolCreated, &tool1ID, nil, &actorID, "Tool created", nil)
+		_, err := repo.Create(ctx, domain.EventTypeToolCreated, &tool1ID, nil, &actorID, "Tool created", nil)
 		require.NoError(t, err)
 
-		_, err = repo.Create(domain.EventTypeToolCheckedOut, &tool1ID, &user1ID, &actorID, "Tool checked out", nil)
+		_, err = repo.Create(ctx, domain.EventTypeToolCheckedOut, &tool1ID, &user1ID, &actorID, "Tool checked out", nil)
 		require.NoError(t, err)
 
-		_, err = repo.Create(domain.EventTypeToolCheckedIn, &tool1ID, &user1ID, &actorID, "Tool checked in", nil)
+		_, err = repo.Create(ctx, domain.EventTypeToolCheckedIn, &tool1ID, &user1ID, &actorID, "Tool checked in", nil)
 		require.NoError(t, err)
 
 		// Create event for different tool
-		_, err = repo.Create(domain.EventTypeToolCreated, &tool2ID, nil, &actorID, "Other tool created", nil)
+		_, err = repo.Create(ctx, domain.EventTypeToolCreated, &tool2ID, nil, &actorID, "Other tool created", nil)
 		require.NoError(t, err)
 
 		// Query events for tool1
-		tool1Events, err := repo.ListByTool(tool1ID, 10, 0)
+		tool1Events, err := repo.ListByTool(ctx, tool1ID, nil, 10, 0)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(tool1Events), 3)
 		for _, event := range tool1Events {
@@ -185,23 +308,53 @@ func TestPostgresEventRepo_QueryFeatures(t *testing.T) {
 		}
 	})
 
+	t.Run("List Tool Replay", func(t *testing.T) {
+		_, err := repo.Create(ctx, domain.EventTypeUserCreated, nil, &user1ID, &actorID, "Not a tool event", nil)
+		require.NoError(t, err)
+		// inserted directly, as events logged before the chain existed were; it is
+		// newer than the chained events but replays before them
+		unchained := createTestEvent(t, db, domain.EventTypeToolUpdated, &tool1ID, nil, &actorID, "Unchained")
+
+		first, err := repo.ListToolReplay(ctx, nil, 2)
+		require.NoError(t, err)
+		require.Len(t, first, 2)
+		rest, err := repo.ListToolReplay(ctx, &first[1], 1000)
+		require.NoError(t, err)
+
+		all := append(first, rest...)
+		var lastSeq int64
+		seen := map[string]bool{}
+		for i, event := range all {
+			assert.NotNil(t, event.ToolID)
+			assert.False(t, seen[event.ID], "events must not repeat")
+			seen[event.ID] = true
+			if event.ChainSeq == nil {
+				assert.Nil(t, all[max(i-1, 0)].ChainSeq, "unchained events must come first")
+				continue
+			}
+			assert.Greater(t, *event.ChainSeq, lastSeq, "chained events must be in chain order")
+			lastSeq = *event.ChainSeq
+		}
+		assert.True(t, seen[unchained])
+	})
+
 	t.Run("List by User", func(t *testing.T) {
 		// Create events where user1 is involved (as user or actor)
-		_, err := repo.Create(domain.EventTypeUserCreated, nil, &user1ID, &actorID, "User created", nil)
+		_, err := repo.Create(ctx, domain.EventTypeUserCreated, nil, &user1ID, &actorID, "User created", nil)
 		require.NoError(t, err)
 
-		_, err = repo.Create(domain.EventTypeToolCheckedOut, &tool1ID, &user1ID, &actorID, "User checked out tool", nil)
+		_, err = repo.Create(ctx, domain.EventTypeToolCheckedOut, &tool1ID, &user1ID, &actorID, "User checked out tool", nil)
 		require.NoError(t, err)
 
-		_, err = repo.Create(domain.EventTypeToolUpdated, &tool1ID, nil, &user1ID, "User updated tool", nil)
+		_, err = repo.Create(ctx, domain.EventTypeToolUpdated, &tool1ID, nil, &user1ID, "User updated tool", nil)
 		require.NoError(t, err)
 
 		// Create event for different user
-		_, err = repo.Create(domain.EventTypeUserCreated, nil, &user2ID, &actorID, "Other user created", nil)
+		_, err = repo.Create(ctx, domain.EventTypeUserCreated, nil, &user2ID, &actorID, "Other user created", nil)
 		require.NoError(t, err)
 
 		// Query events for user1 (should include events where they are user_id or actor_id)
-		user1Events, err := repo.ListByUser(user1ID, 10, 0)
+		user1Events, err := repo.ListByUser(ctx, user1ID, nil, 10, 0)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(user1Events), 3)
 		for _, event := range user1Events {
@@ -213,30 +366,63 @@ func TestPostgresEventRepo_QueryFeatures(t *testing.T) {
 		}
 	})
 
+	t.Run("Filter Events By Metadata", func(t *testing.T) {
+		cost := int64(4200)
+		_, err := repo.Create(ctx, domain.EventTypeToolMaintenance, &tool1ID, nil, &actorID, "Metadata test 1",
+			&domain.EventMetadata{Vendor: "Acme Repairs", CostCents: &cost})
+		require.NoError(t, err)
+
+		_, err = repo.Create(ctx, domain.EventTypeToolMaintenance, &tool2ID, nil, &actorID, "Metadata test 2",
+			&domain.EventMetadata{Vendor: "Other Repairs"})
+		require.NoError(t, err)
+
+		vendorFilter := EventFilter{Metadata: map[string]string{"vendor": "Acme Repairs"}}
+		events, err := repo.ListWithFilter(ctx, vendorFilter, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "Metadata test 1", events[0].Notes)
+		assert.Equal(t, &cost, events[0].Metadata.CostCents)
+
+		count, err := repo.CountWithFilter(ctx, EventFilter{Metadata: map[string]string{"vendor": "Acme Repairs", "cost_cents": "4200"}})
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		count, err = repo.CountWithFilter(ctx, EventFilter{Metadata: map[string]string{"vendor": "Nobody"}})
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+
 	t.Run("List with Filter", func(t *testing.T) {
 		// Create various events
-		_, err := repo.Create(domain.EventTypeToolCheckedOut, &tool1ID, &user1ID, &actorID, "Filter test 1", nil)
+		_, err := repo.Create(ctx, domain.EventTypeToolCheckedOut, &tool1ID, &user1ID, &actorID, "Filter test 1", nil)
 		require.NoError(t, err)
 
-		_, err = repo.Create(domain.EventTypeToolCheckedIn, &tool1ID, &user1ID, &actorID, "Filter test 2", nil)
+		_, err = repo.Create(ctx, domain.EventTypeToolCheckedIn, &tool1ID, &user1ID, &actorID, "Filter test 2", nil)
 		require.NoError(t, err)
 
-		_, err = repo.Create(domain.EventTypeToolCheckedOut, &tool2ID, &user2ID, &actorID, "Filter test 3", nil)
+		_, err = repo.Create(ctx, domain.EventTypeToolCheckedOut, &tool2ID, &user2ID, &actorID, "Filter test 3", nil)
 		require.NoError(t, err)
 
 		// Filter by type only
 		eventType := domain.EventTypeToolCheckedOut
 		typeFilter := EventFilter{Type: &eventType}
-		checkoutEvents, err := repo.ListWithFilter(typeFilter, 10, 0)
+		checkoutEvents, err := repo.ListWithFilter(ctx, typeFilter, 10, 0)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(checkoutEvents), 2)
 		for _, event := range checkoutEvents {
 			assert.Equal(t, domain.EventTypeToolCheckedOut, event.Type)
 		}
 
+		checkoutCount, err := repo.CountWithFilter(ctx, typeFilter)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, checkoutCount, len(checkoutEvents))
+		allCount, err := repo.CountWithFilter(ctx, EventFilter{})
+		require.NoError(t, err)
+		assert.Greater(t, allCount, checkoutCount)
+
 		// Filter by tool only
 		toolFilter := EventFilter{ToolID: &tool1ID}
-		tool1Events, err := repo.ListWithFilter(toolFilter, 10, 0)
+		tool1Events, err := repo.ListWithFilter(ctx, toolFilter, 10, 0)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(tool1Events), 2)
 		for _, event := range tool1Events {
@@ -245,7 +431,7 @@ func TestPostgresEventRepo_QueryFeatures(t *testing.T) {
 
 		// Filter by user only
 		userFilter := EventFilter{UserID: &user1ID}
-		user1Events, err := repo.ListWithFilter(userFilter, 10, 0)
+		user1Events, err := repo.ListWithFilter(ctx, userFilter, 10, 0)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(user1Events), 2)
 
@@ -255,7 +441,7 @@ func TestPostgresEventRepo_QueryFeatures(t *testing.T) {
 			ToolID: &tool1ID,
 			UserID: &user1ID,
 		}
-		filteredEvents, err := repo.ListWithFilter(combinedFilter, 10, 0)
+		filteredEvents, err := repo.ListWithFilter(ctx, combinedFilter, 10, 0)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(filteredEvents), 1)
 		for _, event := range filteredEvents {
@@ -269,10 +455,10 @@ func TestPostgresEventRepo_QueryFeatures(t *testing.T) {
 	})
 
 	t.Run("UUID Primary Keys", func(t *testing.T) {
-		event1, err := repo.Create(domain.EventTypeToolCreated, &tool1ID, nil, &actorID, "Event 1", nil)
+		event1, err := repo.Create(ctx, domain.EventTypeToolCreated, &tool1ID, nil, &actorID, "Event 1", nil)
 		require.NoError(t, err)
 
-		event2, err := repo.Create(domain.EventTypeToolCreated, &tool2ID, nil, &actorID, "Event 2", nil)
+		event2, err := repo.Create(ctx, domain.EventTypeToolCreated, &tool2ID, nil, &actorID, "Event 2", nil)
 		require.NoError(t, err)
 
 		// UUIDs should be different
@@ -284,19 +470,110 @@ func TestPostgresEventRepo_QueryFeatures(t *testing.T) {
 	})
 }
 
+// TestPostgresEventRepo_HashChain tests chaining created events by hash
+func TestPostgresEventRepo_HashChain(t *testing.T) {
+	db := setupSharedRepoTestDB(t)
+	ctx := context.Background()
+	repo := NewPostgresEventRepo(db)
+
+	toolID := createTestTool(t, db, "Chained Tool", domain.ToolStatusInOffice)
+	actorID := createTestUser(t, db, "Chain Actor", "chain@example.com", domain.UserRoleAdmin)
+	// inserted directly, as events logged before the chain existed were
+	createTestEvent(t, db, domain.EventTypeToolCreated, &toolID, nil, &actorID, "Unchained")
+
+	t.Run("Created events link to the previous one", func(t *testing.T) {
+		due := time.Date(2024, 1, 15, 17, 0, 0, 0, time.UTC)
+		first, err := repo.Create(ctx, domain.EventTypeToolCheckedOut, &toolID, &actorID, &actorID, "Out",
+			&domain.EventMetadata{DueAt: &due, Condition: domain.ToolConditionGood})
+		require.NoError(t, err)
+		second, err := repo.Create(ctx, domain.EventTypeToolCheckedIn, &toolID, &actorID, &actorID, "In", nil)
+		require.NoError(t, err)
+
+		require.NotNil(t, first.ChainSeq)
+		require.NotNil(t, second.ChainSeq)
+		assert.Equal(t, *first.ChainSeq+1, *second.ChainSeq)
+		assert.Equal(t, first.Hash, second.PrevHash)
+
+		// the stored row, as read back, hashes to the stored hash
+		stored, err := repo.Get(ctx, first.ID)
+		require.NoError(t, err)
+		hash, err := domain.HashEvent(stored, *stored.ChainSeq, stored.PrevHash)
+		require.NoError(t, err)
+		assert.Equal(t, stored.Hash, hash)
+
+		head, err := repo.ChainHead(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, domain.ChainHead{Seq: *second.ChainSeq, Hash: second.Hash}, head)
+	})
+
+	t.Run("Concurrent creates keep the chain contiguous", func(t *testing.T) {
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := repo.Create(ctx, domain.EventTypeToolUpdated, &toolID, nil, &actorID, "Concurrent", nil)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		head, err := repo.ChainHead(ctx)
+		require.NoError(t, err)
+		chain, err := repo.ListChain(ctx, 0, head.Seq, 100)
+		require.NoError(t, err)
+		require.Len(t, chain, int(head.Seq))
+		prevHash := ""
+		for i, event := range chain {
+			assert.Equal(t, int64(i+1), *event.ChainSeq)
+			assert.Equal(t, prevHash, event.PrevHash)
+			prevHash = event.Hash
+		}
+		assert.Equal(t, head.Hash, prevHash)
+	})
+
+	t.Run("Count Unchained", func(t *testing.T) {
+		count, err := repo.CountUnchained(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("Hard deletes cannot rewrite chained events", func(t *testing.T) {
+		_, err := db.ExecContext(ctx, "DELETE FROM users WHERE id = $1", actorID)
+		assert.Error(t, err)
+		_, err = db.ExecContext(ctx, "DELETE FROM tools WHERE id = $1", toolID)
+		assert.Error(t, err)
+
+		head, err := repo.ChainHead(ctx)
+		require.NoError(t, err)
+		chain, err := repo.ListChain(ctx, 0, head.Seq, 100)
+		require.NoError(t, err)
+		for _, event := range chain {
+			assert.Equal(t, &toolID, event.ToolID)
+			assert.Equal(t, &actorID, event.ActorID)
+		}
+	})
+}
+
 // TestPostgresEventRepo_ErrorCases tests error handling
 func TestPostgresEventRepo_ErrorCases(t *testing.T) {
 	db := setupSharedRepoTestDB(t)
+	ctx := context.Background()
 	repo := NewPostgresEventRepo(db)
 
 	t.Run("Get Non-existent Event", func(t *testing.T) {
-		_, err := repo.Get("00000000-0000-0000-0000-000000000000")
+		_, err := repo.Get(ctx, "00000000-0000-0000-0000-000000000000")
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "event not found")
 	})
 
 	t.Run("Get Invalid UUID", func(t *testing.T) {
-		_, err := repo.Get("invalid-uuid")
+		_, err := repo.Get(ctx, "invalid-uuid")
 		assert.Error(t, err)
 	})
 
@@ -305,7 +582,7 @@ func TestPostgresEventRepo_ErrorCases(t *testing.T) {
 
 		// This might fail due to foreign key constraints if they exist
 		// The exact behavior depends on your database schema
-		_, err := repo.Create(
+		_, err := repo.Create(ctx,
 			domain.EventTypeToolCheckedOut,
 			&nonExistentID, // Non-existent tool
 			&nonExistentID, // Non-existent user
@@ -321,12 +598,12 @@ func TestPostgresEventRepo_ErrorCases(t *testing.T) {
 	t.Run("Empty Filter Returns All Events", func(t *testing.T) {
 		// Create a test event
 		userID := createTestUser(t, db, "Filter User", "filter@example.com", domain.UserRoleEmployee)
-		_, err := repo.Create(domain.EventTypeUserCreated, nil, &userID, &userID, "Filter test", nil)
+		_, err := repo.Create(ctx, domain.EventTypeUserCreated, nil, &userID, &userID, "Filter test", nil)
 		require.NoError(t, err)
 
 		// Empty filter should return events
 		emptyFilter := EventFilter{}
-		events, err := repo.ListWithFilter(emptyFilter, 10, 0)
+		events, err := repo.ListWithFilter(ctx, emptyFilter, 10, 0)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(events), 1)
 	})
@@ -0,0 +1,8 @@
+package repo
+
+// Exported for tests in package repo_test, which exercise the services on top of the repos.
+var (
+	SetupSharedRepoTestDB = setupSharedRepoTestDB
+	CreateTestTool        = createTestTool
+	CreateTestUser        = createTestUser
+)
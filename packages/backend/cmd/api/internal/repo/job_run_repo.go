@@ -0,0 +1,94 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+type PostgresJobRunRepo struct {
+	db DBTX
+}
+
+func NewPostgresJobRunRepo(db DBTX) *PostgresJobRunRepo {
+	return &PostgresJobRunRepo{db: db}
+}
+
+// Helper function to define the column order for job run returns
+func (r *PostgresJobRunRepo) jobRunColumns() string {
+	return "id, job_name, trigger, status, error, started_at, finished_at, duration_ms"
+}
+
+// Helper function to scan a row into a JobRun struct
+func (r *PostgresJobRunRepo) scanJobRun(scanner interface {
+	Scan(dest ...any) error
+}) (domain.JobRun, error) {
+	var run domain.JobRun
+	err := scanner.Scan(
+		&run.ID,
+		&run.JobName,
+		&run.Trigger,
+		&run.Status,
+		&run.Error,
+		&run.StartedAt,
+		&run.FinishedAt,
+		&run.DurationMs,
+	)
+	return run, err
+}
+
+// Start records a RUNNING job run.
+func (r *PostgresJobRunRepo) Start(ctx context.Context, jobName string, trigger domain.JobRunTrigger, startedAt time.Time) (domain.JobRun, error) {
+	query := `INSERT INTO job_runs (job_name, trigger, started_at) VALUES ($1, $2, $3) RETURNING ` + r.jobRunColumns()
+	run, err := r.scanJobRun(r.db.QueryRowContext(ctx, query, jobName, trigger, startedAt))
+	if err != nil {
+		return domain.JobRun{}, fmt.Errorf("failed to start job run: %w", err)
+	}
+
+	return run, nil
+}
+
+// Finish records the outcome of a job run; the duration is derived from its start time.
+func (r *PostgresJobRunRepo) Finish(ctx context.Context, id string, status domain.JobRunStatus, finishedAt time.Time, errMsg string) (domain.JobRun, error) {
+	query := `UPDATE job_runs
+		SET status = $1, error = $2, finished_at = $3,
+			duration_ms = (EXTRACT(EPOCH FROM ($3 - started_at)) * 1000)::BIGINT
+		WHERE id = $4 RETURNING ` + r.jobRunColumns()
+	run, err := r.scanJobRun(r.db.QueryRowContext(ctx, query, status, errMsg, finishedAt, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.JobRun{}, fmt.Errorf("job run %s not found", id)
+		}
+		return domain.JobRun{}, fmt.Errorf("failed to finish job run: %w", err)
+	}
+
+	return run, nil
+}
+
+// ListByJob returns the most recent runs of a job, newest first.
+func (r *PostgresJobRunRepo) ListByJob(ctx context.Context, jobName string, limit int) ([]domain.JobRun, error) {
+	query := `SELECT ` + r.jobRunColumns() + ` FROM job_runs WHERE job_name = $1 ORDER BY started_at DESC LIMIT $2`
+	rows, err := r.db.QueryContext(ctx, query, jobName, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query job runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []domain.JobRun
+	for rows.Next() {
+		run, err := r.scanJobRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over job runs: %w", err)
+	}
+
+	return runs, nil
+}
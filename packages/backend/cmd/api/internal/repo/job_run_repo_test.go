@@ -0,0 +1,58 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// TestPostgresJobRunRepo tests job run recording
+func TestPostgresJobRunRepo(t *testing.T) {
+	db := setupSharedRepoTestDB(t)
+	ctx := context.Background()
+	repo := NewPostgresJobRunRepo(db)
+
+	startedAt := time.Now().Add(-2 * time.Second).Truncate(time.Microsecond)
+	run, err := repo.Start(ctx, "overdue-sweep", domain.JobRunTriggerManual, startedAt)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobRunStatusRunning, run.Status)
+	assert.Nil(t, run.FinishedAt)
+
+	finished, err := repo.Finish(ctx, run.ID, domain.JobRunStatusFailed, startedAt.Add(1500*time.Millisecond), "boom")
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobRunStatusFailed, finished.Status)
+	assert.Equal(t, "boom", finished.Error)
+	require.NotNil(t, finished.DurationMs)
+	assert.Equal(t, int64(1500), *finished.DurationMs)
+
+	runs, err := repo.ListByJob(ctx, "overdue-sweep", 5)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, run.ID, runs[0].ID)
+}
+
+// TestPostgresAdvisoryLocker tests that a job lock is exclusive until released
+func TestPostgresAdvisoryLocker(t *testing.T) {
+	db := setupSharedRepoTestDB(t)
+	ctx := context.Background()
+	locker := NewPostgresAdvisoryLocker(db)
+
+	release, acquired, err := locker.TryLock(ctx, "jobs:test")
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	_, acquired, err = locker.TryLock(ctx, "jobs:test")
+	require.NoError(t, err)
+	assert.False(t, acquired, "second session must not get the lock")
+
+	release()
+
+	release, acquired, err = locker.TryLock(ctx, "jobs:test")
+	require.NoError(t, err)
+	assert.True(t, acquired)
+	release()
+}
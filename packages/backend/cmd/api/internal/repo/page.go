@@ -0,0 +1,77 @@
+package repo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// defaultOrder lists rows newest first, with id as the tie-breaker the keyset needs.
+const defaultOrder = `created_at DESC, id DESC`
+
+// pageClause completes a query whose WHERE clause is open: it keeps the rows after
+// the cursor (when set), orders newest first with id as the tie-breaker, and adds
+// LIMIT/OFFSET. Placeholders continue from len(args).
+func pageClause(query string, args []any, after *domain.Cursor, limit, offset int) (string, []any) {
+	return orderedPageClause(query, args, after, defaultOrder, limit, offset)
+}
+
+// orderedPageClause is pageClause with an ORDER BY of the caller's choosing. The
+// cursor only makes sense with defaultOrder.
+func orderedPageClause(query string, args []any, after *domain.Cursor, orderBy string, limit, offset int) (string, []any) {
+	n := len(args) + 1
+	if after != nil {
+		query += fmt.Sprintf(` AND (created_at, id) < ($%d, $%d)`, n, n+1)
+		args = append(args, after.CreatedAt, after.ID)
+		n += 2
+	}
+	query += fmt.Sprintf(` ORDER BY %s LIMIT $%d OFFSET $%d`, orderBy, n, n+1)
+	return query, append(args, limit, offset)
+}
+
+// SortKey orders a list by one field, ascending unless Desc is set.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// parseSort parses a sort parameter such as "name,-created_at" (a leading "-" sorts
+// descending). Only the fields in columns are accepted.
+func parseSort(raw string, columns map[string]string) ([]SortKey, error) {
+	var keys []SortKey
+	seen := map[string]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key := SortKey{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := columns[key.Field]; !ok {
+			return nil, fmt.Errorf("%w: sort: unknown field %q", domain.ErrValidation, key.Field)
+		}
+		if seen[key.Field] {
+			return nil, fmt.Errorf("%w: sort: field %q given twice", domain.ErrValidation, key.Field)
+		}
+		seen[key.Field] = true
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// orderBy renders keys as an ORDER BY list over columns, nulls last, followed by
+// defaultOrder so that pages are stable. Column names come from the whitelist only.
+func orderBy(keys []SortKey, columns map[string]string) string {
+	if len(keys) == 0 {
+		return defaultOrder
+	}
+	parts := make([]string, 0, len(keys)+1)
+	for _, k := range keys {
+		dir := "ASC"
+		if k.Desc {
+			dir = "DESC"
+		}
+		parts = append(parts, columns[k.Field]+" "+dir+" NULLS LAST")
+	}
+	return strings.Join(append(parts, defaultOrder), ", ")
+}
@@ -0,0 +1,74 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/logging"
+)
+
+// maxLoggedQueryLength truncates statements in query logs.
+const maxLoggedQueryLength = 200
+
+// loggedDB logs every statement at debug level with its latency, through the
+// logger on the statement's context, so query logs carry the caller's request_id.
+type loggedDB struct {
+	db DBTX
+}
+
+// LogQueries wraps db so its statements are logged (at debug level) with their latency.
+func LogQueries(db DBTX) DBTX {
+	if _, ok := db.(loggedDB); ok {
+		return db
+	}
+	return loggedDB{db: db}
+}
+
+func (l loggedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	res, err := l.db.ExecContext(ctx, query, args...)
+	logQuery(ctx, "exec", query, start, err)
+	return res, err
+}
+
+func (l loggedDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := l.db.QueryContext(ctx, query, args...)
+	logQuery(ctx, "query", query, start, err)
+	return rows, err
+}
+
+func (l loggedDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	start := time.Now()
+	row := l.db.QueryRowContext(ctx, query, args...)
+	logQuery(ctx, "query_row", query, start, row.Err())
+	return row
+}
+
+func logQuery(ctx context.Context, op, query string, start time.Time, err error) {
+	logger := logging.FromContext(ctx)
+	if !logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	attrs := []any{
+		"op", op,
+		"query", compactQuery(query),
+		"latency_ms", time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		attrs = append(attrs, "error", err.Error())
+	}
+	logger.DebugContext(ctx, "db query", attrs...)
+}
+
+// compactQuery collapses a statement's whitespace onto one line and truncates it.
+func compactQuery(query string) string {
+	q := strings.Join(strings.Fields(query), " ")
+	if len(q) > maxLoggedQueryLength {
+		q = q[:maxLoggedQueryLength] + "..."
+	}
+	return q
+}
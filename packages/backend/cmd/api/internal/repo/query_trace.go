@@ -0,0 +1,59 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedDB records a span per statement as a child of the span on the
+// statement's context. Only the statement text is recorded, never its arguments.
+type tracedDB struct {
+	db DBTX
+}
+
+// TraceQueries wraps db so each of its statements gets a span.
+func TraceQueries(db DBTX) DBTX {
+	if _, ok := db.(tracedDB); ok {
+		return db
+	}
+	return tracedDB{db: db}
+}
+
+func (t tracedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	res, err := t.db.ExecContext(ctx, query, args...)
+	tracing.End(span, &err)
+	return res, err
+}
+
+// QueryContext's span ends when the query returns, so it does not cover reading the rows.
+func (t tracedDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	rows, err := t.db.QueryContext(ctx, query, args...)
+	tracing.End(span, &err)
+	return rows, err
+}
+
+func (t tracedDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := startQuerySpan(ctx, query)
+	row := t.db.QueryRowContext(ctx, query, args...)
+	err := row.Err() // the statement's own failure; sql.ErrNoRows only surfaces from Scan
+	tracing.End(span, &err)
+	return row
+}
+
+// startQuerySpan names the span after the statement's operation (SELECT, UPDATE, ...).
+func startQuerySpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	q := compactQuery(query)
+	op := strings.ToUpper(strings.SplitN(q, " ", 2)[0])
+	return tracing.StartClient(ctx, "db "+op,
+		semconv.DBSystemNamePostgreSQL,
+		semconv.DBOperationName(op),
+		semconv.DBQueryText(q),
+	)
+}
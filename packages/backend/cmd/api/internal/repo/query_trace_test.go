@@ -0,0 +1,74 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// stubDB answers ExecContext with err and fails the other calls.
+type stubDB struct {
+	err error
+}
+
+func (s stubDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return nil, s.err
+}
+
+func (s stubDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s stubDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	panic("not implemented")
+}
+
+func TestTraceQueries(t *testing.T) {
+	t.Run("Statement span is a client child of the caller's span", func(t *testing.T) {
+		rec := tracing.RecordSpans(t)
+		db := TraceQueries(stubDB{})
+
+		ctx, parent := tracing.Start(context.Background(), "ToolService.applyAndSave")
+		_, err := db.ExecContext(ctx, "\n\t\tupdate tools\n\t\tSET name = $1 WHERE id = $2", "secret name", "id")
+		parent.End()
+		require.NoError(t, err)
+
+		span := tracing.SpanNamed(rec, "db UPDATE")
+		require.NotNil(t, span)
+		assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Contains(t, span.Attributes(), attribute.String("db.query.text", "update tools SET name = $1 WHERE id = $2"))
+		for _, a := range span.Attributes() {
+			assert.NotContains(t, a.Value.Emit(), "secret name", "arguments are never recorded")
+		}
+	})
+
+	t.Run("Failed statement fails its span", func(t *testing.T) {
+		rec := tracing.RecordSpans(t)
+		db := TraceQueries(stubDB{err: errors.New("deadlock detected")})
+
+		_, err := db.ExecContext(context.Background(), "DELETE FROM tools WHERE id = $1", "id")
+		require.Error(t, err)
+
+		span := tracing.SpanNamed(rec, "db DELETE")
+		require.NotNil(t, span)
+		assert.Equal(t, codes.Error, span.Status().Code)
+	})
+
+	t.Run("Wrapping twice records one span", func(t *testing.T) {
+		rec := tracing.RecordSpans(t)
+		db := TraceQueries(TraceQueries(stubDB{}))
+
+		_, _ = db.ExecContext(context.Background(), "SELECT 1")
+
+		assert.Len(t, rec.Ended(), 1)
+	})
+}
@@ -0,0 +1,158 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+type PostgresReservationRepo struct {
+	db DBTX
+}
+
+func NewPostgresReservationRepo(db DBTX) *PostgresReservationRepo {
+	return &PostgresReservationRepo{db: db}
+}
+
+// Helper function to define the column order for reservation returns
+func (r *PostgresReservationRepo) reservationColumns() string {
+	return "id, tool_id, user_id, starts_at, ends_at, status, notes, created_at, updated_at"
+}
+
+// Helper function to scan a row into a Reservation struct
+func (r *PostgresReservationRepo) scanReservation(scanner interface {
+	Scan(dest ...any) error
+}) (domain.Reservation, error) {
+	var res domain.Reservation
+	err := scanner.Scan(
+		&res.ID,
+		&res.ToolID,
+		&res.UserID,
+		&res.StartsAt,
+		&res.EndsAt,
+		&res.Status,
+		&res.Notes,
+		&res.CreatedAt,
+		&res.UpdatedAt,
+	)
+	return res, err
+}
+
+// Helper function to collect reservation rows
+func (r *PostgresReservationRepo) scanReservations(rows *sql.Rows) ([]domain.Reservation, error) {
+	defer rows.Close()
+
+	var reservations []domain.Reservation
+	for rows.Next() {
+		res, err := r.scanReservation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reservation: %w", err)
+		}
+		reservations = append(reservations, res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over reservations: %w", err)
+	}
+
+	return reservations, nil
+}
+
+func (r *PostgresReservationRepo) Create(ctx context.Context, res domain.Reservation) (domain.Reservation, error) {
+	query := `INSERT INTO reservations (tool_id, user_id, starts_at, ends_at, status, notes) VALUES ($1, $2, $3, $4, $5, $6) RETURNING ` + r.reservationColumns()
+	row := r.db.QueryRowContext(ctx, query, res.ToolID, res.UserID, res.StartsAt, res.EndsAt, res.Status, res.Notes)
+	created, err := r.scanReservation(row)
+	if err != nil {
+		return domain.Reservation{}, fmt.Errorf("failed to create reservation: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *PostgresReservationRepo) Get(ctx context.Context, id string) (domain.Reservation, error) {
+	query := `SELECT ` + r.reservationColumns() + ` FROM reservations WHERE id = $1`
+
+	row := r.db.QueryRowContext(ctx, query, id)
+	res, err := r.scanReservation(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Reservation{}, domain.ErrReservationNotFound
+		}
+		return domain.Reservation{}, fmt.Errorf("failed to get reservation: %w", err)
+	}
+
+	return res, nil
+}
+
+// GetForUpdate loads a reservation and locks its row until the surrounding transaction ends.
+func (r *PostgresReservationRepo) GetForUpdate(ctx context.Context, id string) (domain.Reservation, error) {
+	query := `SELECT ` + r.reservationColumns() + ` FROM reservations WHERE id = $1 FOR NO KEY UPDATE`
+
+	row := r.db.QueryRowContext(ctx, query, id)
+	res, err := r.scanReservation(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Reservation{}, domain.ErrReservationNotFound
+		}
+		return domain.Reservation{}, fmt.Errorf("failed to get reservation for update: %w", err)
+	}
+
+	return res, nil
+}
+
+// ListByTool returns a tool's reservations (any status) that intersect [from, to).
+// A nil bound leaves that side of the window open.
+func (r *PostgresReservationRepo) ListByTool(ctx context.Context, toolID string, from, to *time.Time) ([]domain.Reservation, error) {
+	query := `SELECT ` + r.reservationColumns() + ` FROM reservations
+		WHERE tool_id = $1
+		AND ($2::timestamptz IS NULL OR ends_at > $2)
+		AND ($3::timestamptz IS NULL OR starts_at < $3)
+		ORDER BY starts_at`
+	rows, err := r.db.QueryContext(ctx, query, toolID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reservations by tool: %w", err)
+	}
+
+	return r.scanReservations(rows)
+}
+
+// ListActiveOverlapping returns the tool's active reservations that intersect [start, end).
+func (r *PostgresReservationRepo) ListActiveOverlapping(ctx context.Context, toolID string, start, end time.Time) ([]domain.Reservation, error) {
+	query := `SELECT ` + r.reservationColumns() + ` FROM reservations
+		WHERE tool_id = $1 AND status = 'ACTIVE' AND starts_at < $3 AND ends_at > $2
+		ORDER BY starts_at`
+	rows, err := r.db.QueryContext(ctx, query, toolID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query overlapping reservations: %w", err)
+	}
+
+	return r.scanReservations(rows)
+}
+
+func (r *PostgresReservationRepo) ListByUser(ctx context.Context, userID string, limit, offset int) ([]domain.Reservation, error) {
+	query := `SELECT ` + r.reservationColumns() + ` FROM reservations WHERE user_id = $1 ORDER BY starts_at DESC LIMIT $2 OFFSET $3`
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reservations by user: %w", err)
+	}
+
+	return r.scanReservations(rows)
+}
+
+func (r *PostgresReservationRepo) UpdateStatus(ctx context.Context, id string, status domain.ReservationStatus) (domain.Reservation, error) {
+	query := `UPDATE reservations SET status = $1 WHERE id = $2 RETURNING ` + r.reservationColumns()
+
+	row := r.db.QueryRowContext(ctx, query, status, id)
+	res, err := r.scanReservation(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Reservation{}, domain.ErrReservationNotFound
+		}
+		return domain.Reservation{}, fmt.Errorf("failed to update reservation status: %w", err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// TestPostgresReservationRepo_CRUD tests reservation persistence and overlap queries
+func TestPostgresReservationRepo_CRUD(t *testing.T) {
+	db := setupSharedRepoTestDB(t)
+	ctx := context.Background()
+	repo := NewPostgresReservationRepo(db)
+	toolID := createTestTool(t, db, "Reserved Drill", domain.ToolStatusInOffice)
+	userID := createTestUser(t, db, "Planner", "planner@example.com", domain.UserRoleEmployee)
+
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Microsecond)
+	newReservation := func(start, end time.Time) domain.Reservation {
+		res, err := domain.NewReservation(toolID, userID, start, end, "site work")
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("Create and Get reservation", func(t *testing.T) {
+		created, err := repo.Create(ctx, newReservation(base, base.Add(2*time.Hour)))
+		require.NoError(t, err)
+		assert.NotEmpty(t, created.ID)
+		assert.Equal(t, domain.ReservationStatusActive, created.Status)
+
+		retrieved, err := repo.Get(ctx, created.ID)
+		require.NoError(t, err)
+		assert.True(t, base.Equal(retrieved.StartsAt))
+		assert.Equal(t, "site work", retrieved.Notes)
+	})
+
+	t.Run("Get non-existent reservation", func(t *testing.T) {
+		_, err := repo.Get(ctx, "00000000-0000-0000-0000-000000000000")
+		assert.ErrorIs(t, err, domain.ErrReservationNotFound)
+	})
+
+	t.Run("End before start is rejected by the database", func(t *testing.T) {
+		res := newReservation(base, base.Add(time.Hour))
+		res.EndsAt = base.Add(-time.Hour)
+		_, err := repo.Create(ctx, res)
+		assert.Error(t, err)
+	})
+
+	t.Run("Overlap query only returns active overlapping reservations", func(t *testing.T) {
+		start := base.Add(48 * time.Hour)
+		active, err := repo.Create(ctx, newReservation(start, start.Add(4*time.Hour)))
+		require.NoError(t, err)
+		cancelled, err := repo.Create(ctx, newReservation(start, start.Add(4*time.Hour)))
+		require.NoError(t, err)
+		_, err = repo.UpdateStatus(ctx, cancelled.ID, domain.ReservationStatusCancelled)
+		require.NoError(t, err)
+
+		overlapping, err := repo.ListActiveOverlapping(ctx, toolID, start.Add(time.Hour), start.Add(2*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, overlapping, 1)
+		assert.Equal(t, active.ID, overlapping[0].ID)
+
+		// windows are half-open: touching the end is not an overlap
+		adjacent, err := repo.ListActiveOverlapping(ctx, toolID, start.Add(4*time.Hour), start.Add(5*time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, adjacent)
+	})
+
+	t.Run("List by tool within window and by user", func(t *testing.T) {
+		from := base.Add(47 * time.Hour)
+		to := base.Add(49 * time.Hour)
+		calendar, err := repo.ListByTool(ctx, toolID, &from, &to)
+		require.NoError(t, err)
+		assert.Len(t, calendar, 2) // active and cancelled
+
+		all, err := repo.ListByTool(ctx, toolID, nil, nil)
+		require.NoError(t, err)
+		assert.Len(t, all, 3)
+
+		mine, err := repo.ListByUser(ctx, userID, 10, 0)
+		require.NoError(t, err)
+		assert.Len(t, mine, 3)
+	})
+
+	t.Run("Update status of non-existent reservation", func(t *testing.T) {
+		_, err := repo.UpdateStatus(ctx, "00000000-0000-0000-0000-000000000000", domain.ReservationStatusCancelled)
+		assert.ErrorIs(t, err, domain.ErrReservationNotFound)
+	})
+}
@@ -0,0 +1,116 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// Snippets are highlighted by ts_headline with these control characters rather
+// than HTML, so the text around them can be escaped before they become <mark> tags.
+const (
+	markStart = "\x02"
+	markStop  = "\x03"
+)
+
+// searchSources holds one SELECT per hit type. Each one matches the $1 tsquery
+// against the table's search_vector (migration 013), skipping soft-deleted rows, and
+// returns the columns scanned by scanHits; $2 and $3 are the ts_headline options for
+// short and long text.
+var searchSources = map[domain.SearchHitType]string{
+	domain.SearchHitTool: `SELECT 'tool', t.id::text, t.name,
+		ts_headline('english', t.name, q.query, $2),
+		ts_rank(t.search_vector, q.query), NULL::text, NULL::text, t.created_at
+		FROM tools t, q WHERE t.search_vector @@ q.query AND t.deleted_at IS NULL`,
+	domain.SearchHitUser: `SELECT 'user', u.id::text, u.name,
+		ts_headline('english', u.name || ' <' || u.email || '>', q.query, $2),
+		ts_rank(u.search_vector, q.query), NULL::text, NULL::text, u.created_at
+		FROM users u, q WHERE u.search_vector @@ q.query AND u.deleted_at IS NULL`,
+	domain.SearchHitEvent: `SELECT 'event', e.id::text, e.type::text,
+		ts_headline('english', e.notes, q.query, $3),
+		ts_rank(e.search_vector, q.query), e.tool_id::text, e.user_id::text, e.created_at
+		FROM events e, q WHERE e.search_vector @@ q.query`,
+}
+
+type PostgresSearchRepo struct {
+	db DBTX
+}
+
+func NewPostgresSearchRepo(db DBTX) *PostgresSearchRepo {
+	return &PostgresSearchRepo{db: db}
+}
+
+// Search returns the records of the given types whose text matches every term,
+// best match first. Each term also matches longer words it starts, so a partly
+// typed query already finds results.
+func (r *PostgresSearchRepo) Search(ctx context.Context, terms []string, types []domain.SearchHitType, limit int) ([]domain.SearchHit, error) {
+	if len(terms) == 0 || len(types) == 0 {
+		return []domain.SearchHit{}, nil
+	}
+
+	sources := make([]string, 0, len(types))
+	for _, t := range types {
+		src, ok := searchSources[t]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown search type %q", domain.ErrValidation, t)
+		}
+		sources = append(sources, src)
+	}
+
+	query := `WITH q AS (SELECT to_tsquery('english', $1) AS query)
+		SELECT type, id, title, snippet, rank, tool_id, user_id FROM (` +
+		strings.Join(sources, " UNION ALL ") +
+		`) AS hits (type, id, title, snippet, rank, tool_id, user_id, created_at)
+		ORDER BY rank DESC, created_at DESC, id LIMIT $4`
+
+	rows, err := r.db.QueryContext(ctx, query,
+		prefixQuery(terms),
+		"HighlightAll=true, StartSel="+markStart+", StopSel="+markStop,
+		"MaxWords=20, MinWords=5, MaxFragments=2, FragmentDelimiter=\" … \", StartSel="+markStart+", StopSel="+markStop,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	return scanHits(rows)
+}
+
+func scanHits(rows *sql.Rows) ([]domain.SearchHit, error) {
+	defer rows.Close()
+
+	hits := []domain.SearchHit{}
+	for rows.Next() {
+		var hit domain.SearchHit
+		if err := rows.Scan(&hit.Type, &hit.ID, &hit.Title, &hit.Snippet, &hit.Rank, &hit.ToolID, &hit.UserID); err != nil {
+			return nil, fmt.Errorf("failed to scan search hit: %w", err)
+		}
+		hit.Snippet = markSnippet(hit.Snippet)
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over search hits: %w", err)
+	}
+
+	return hits, nil
+}
+
+// prefixQuery builds a tsquery that requires every term, each as a prefix.
+// Terms must be plain words (see domain.SearchTerms) so they carry no tsquery syntax.
+func prefixQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = term + ":*"
+	}
+	return strings.Join(parts, " & ")
+}
+
+// markSnippet escapes a ts_headline result for HTML and turns its highlight
+// markers into <mark> tags.
+func markSnippet(s string) string {
+	return strings.NewReplacer(markStart, "<mark>", markStop, "</mark>").Replace(html.EscapeString(s))
+}
@@ -0,0 +1,88 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// TestPostgresSearchRepo_Search tests ranked full-text search across tools, users and events
+func TestPostgresSearchRepo_Search(t *testing.T) {
+	db := setupSharedRepoTestDB(t)
+	ctx := context.Background()
+	repo := NewPostgresSearchRepo(db)
+
+	drillID := createTestTool(t, db, "Hilti Hammer Drill", domain.ToolStatusInOffice)
+	createTestTool(t, db, "Circular Saw", domain.ToolStatusInOffice)
+	annID := createTestUser(t, db, "Ann Drillsmith", "ann.drillsmith@example.com", domain.UserRoleEmployee)
+	createTestUser(t, db, "Bob Builder", "bob@example.com", domain.UserRoleEmployee)
+	eventID := createTestEvent(t, db, domain.EventTypeToolMaintenance, &drillID, &annID, nil, "Drill chuck <loose>, sent for repair")
+
+	t.Run("Matches every type", func(t *testing.T) {
+		hits, err := repo.Search(ctx, []string{"drill"}, domain.AllSearchHitTypes, 10)
+		require.NoError(t, err)
+
+		byType := map[domain.SearchHitType]domain.SearchHit{}
+		for _, h := range hits {
+			byType[h.Type] = h
+		}
+		require.Len(t, byType, 3)
+		assert.Equal(t, drillID, byType[domain.SearchHitTool].ID)
+		assert.Equal(t, "Hilti Hammer <mark>Drill</mark>", byType[domain.SearchHitTool].Snippet)
+		assert.Equal(t, annID, byType[domain.SearchHitUser].ID)
+		assert.Equal(t, eventID, byType[domain.SearchHitEvent].ID)
+		assert.Equal(t, string(domain.EventTypeToolMaintenance), byType[domain.SearchHitEvent].Title)
+		assert.Equal(t, drillID, *byType[domain.SearchHitEvent].ToolID)
+		// the note is escaped, only the highlight is markup
+		assert.Contains(t, byType[domain.SearchHitEvent].Snippet, "<mark>Drill</mark> chuck &lt;loose&gt;")
+
+		for i := 1; i < len(hits); i++ {
+			assert.GreaterOrEqual(t, hits[i-1].Rank, hits[i].Rank)
+		}
+	})
+
+	t.Run("Prefix match for type-ahead", func(t *testing.T) {
+		hits, err := repo.Search(ctx, []string{"circ"}, domain.AllSearchHitTypes, 10)
+		require.NoError(t, err)
+		require.Len(t, hits, 1)
+		assert.Equal(t, "Circular Saw", hits[0].Title)
+	})
+
+	t.Run("Every term must match", func(t *testing.T) {
+		hits, err := repo.Search(ctx, []string{"hammer", "saw"}, domain.AllSearchHitTypes, 10)
+		require.NoError(t, err)
+		assert.Empty(t, hits)
+	})
+
+	t.Run("Email parts match", func(t *testing.T) {
+		hits, err := repo.Search(ctx, []string{"bob"}, []domain.SearchHitType{domain.SearchHitUser}, 10)
+		require.NoError(t, err)
+		require.Len(t, hits, 1)
+		assert.Equal(t, "Bob Builder", hits[0].Title)
+	})
+
+	t.Run("Restricted to the given types", func(t *testing.T) {
+		hits, err := repo.Search(ctx, []string{"drill"}, []domain.SearchHitType{domain.SearchHitTool}, 10)
+		require.NoError(t, err)
+		require.Len(t, hits, 1)
+		assert.Equal(t, domain.SearchHitTool, hits[0].Type)
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		hits, err := repo.Search(ctx, []string{"drill"}, domain.AllSearchHitTypes, 2)
+		require.NoError(t, err)
+		assert.Len(t, hits, 2)
+	})
+}
+
+func TestPrefixQuery(t *testing.T) {
+	assert.Equal(t, "hilti:*", prefixQuery([]string{"hilti"}))
+	assert.Equal(t, "hammer:* & dri:*", prefixQuery([]string{"hammer", "dri"}))
+}
+
+func TestMarkSnippet(t *testing.T) {
+	assert.Equal(t, "<mark>Drill</mark> &lt;b&gt; &amp; saw", markSnippet(markStart+"Drill"+markStop+" <b> & saw"))
+}
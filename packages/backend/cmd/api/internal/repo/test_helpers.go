@@ -3,6 +3,7 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"log/slog"
 	"sync"
 	"testing"
 	"time"
@@ -55,7 +56,7 @@ func setupSharedRepoTestDB(t *testing.T) *sql.DB {
 		}
 		require.NoError(t, pingErr, "Failed to connect to shared test database")
 
-		err = database.RunMigrations(sharedTestDB)
+		err = database.RunMigrations(sharedTestDB, slog.Default())
 		require.NoError(t, err, "Failed to run migrations on shared test database")
 	})
 
@@ -67,7 +68,7 @@ func setupSharedRepoTestDB(t *testing.T) *sql.DB {
 // cleanupSharedTestData removes all test data while preserving schema
 func cleanupSharedTestData(t *testing.T, db *sql.DB) {
 	// Delete in reverse order of dependencies
-	tables := []string{"events", "tools", "users"}
+	tables := []string{"job_runs", "api_keys", "events", "reservations", "tools", "users"}
 	for _, table := range tables {
 		// Skip system user (id = 1) if it exists
 		query := "DELETE FROM " + table
@@ -77,6 +78,9 @@ func cleanupSharedTestData(t *testing.T, db *sql.DB) {
 		_, err := db.Exec(query)
 		require.NoError(t, err, "Failed to clean up table: "+table)
 	}
+	// the events are gone, so the hash chain starts over
+	_, err := db.Exec("UPDATE event_chain SET seq = 0, hash = ''")
+	require.NoError(t, err, "Failed to reset the event chain")
 }
 
 // Helper functions for creating test data across all repo tests
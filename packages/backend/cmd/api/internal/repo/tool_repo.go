@@ -1,23 +1,28 @@
 package repo
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/lib/pq"
 	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
 )
 
 type PostgresToolRepo struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewPostgresToolRepo(db *sql.DB) *PostgresToolRepo {
+func NewPostgresToolRepo(db DBTX) *PostgresToolRepo {
 	return &PostgresToolRepo{db: db}
 }
 
 // Helper function to define the column order for tool returns
 func (r *PostgresToolRepo) toolColumns() string {
-	return "id, name, status, current_user_id, last_checked_out_at, created_at, updated_at"
+	return "id, name, status, current_user_id, last_checked_out_at, due_at, default_loan_days, " +
+		"COALESCE(current_user_id IS NOT NULL AND due_at < NOW(), FALSE) AS overdue, overdue_notified_at, version, created_at, updated_at, deleted_at"
 }
 
 // Helper function to scan a row into a Tool struct
@@ -31,13 +36,19 @@ func (r *PostgresToolRepo) scanTool(scanner interface {
 		&tool.Status,
 		&tool.CurrentUserId,
 		&tool.LastCheckedOutAt,
+		&tool.DueAt,
+		&tool.DefaultLoanDays,
+		&tool.Overdue,
+		&tool.OverdueNotifiedAt,
+		&tool.Version,
 		&tool.CreatedAt,
 		&tool.UpdatedAt,
+		&tool.DeletedAt,
 	)
 	return tool, err
 }
 
-func (r *PostgresToolRepo) Create(name string, status domain.ToolStatus) (domain.Tool, error) {
+func (r *PostgresToolRepo) Create(ctx context.Context, name string, status domain.ToolStatus) (domain.Tool, error) {
 
 	tool, err := domain.NewTool(name, status)
 	if err != nil {
@@ -45,7 +56,7 @@ func (r *PostgresToolRepo) Create(name string, status domain.ToolStatus) (domain
 	}
 
 	query := `INSERT INTO tools (name, status) VALUES ($1, $2) RETURNING ` + r.toolColumns()
-	row := r.db.QueryRow(query, tool.Name, tool.Status)
+	row := r.db.QueryRowContext(ctx, query, tool.Name, tool.Status)
 	createdTool, err := r.scanTool(row)
 	if err != nil {
 		return domain.Tool{}, fmt.Errorf("failed to create tool: %w", err)
@@ -54,9 +65,11 @@ func (r *PostgresToolRepo) Create(name string, status domain.ToolStatus) (domain
 	return createdTool, nil
 }
 
-func (r *PostgresToolRepo) List(limit, offset int) ([]domain.Tool, error) {
-	query := `SELECT ` + r.toolColumns() + ` FROM tools ORDER BY created_at DESC LIMIT $1 OFFSET $2`
-	rows, err := r.db.Query(query, limit, offset)
+// List returns tools newest first, after the cursor when one is given. Like every
+// query here unless stated otherwise, it leaves out soft-deleted tools.
+func (r *PostgresToolRepo) List(ctx context.Context, after *domain.Cursor, limit, offset int) ([]domain.Tool, error) {
+	query, args := pageClause(`SELECT `+r.toolColumns()+` FROM tools WHERE deleted_at IS NULL`, nil, after, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tools: %w", err)
 	}
@@ -79,10 +92,157 @@ func (r *PostgresToolRepo) List(limit, offset int) ([]domain.Tool, error) {
 	return tools, nil
 }
 
-func (r *PostgresToolRepo) Get(id string) (domain.Tool, error) {
+// toolSortColumns whitelists the fields tools can be sorted by.
+var toolSortColumns = map[string]string{
+	"name":                "name",
+	"status":              "status",
+	"created_at":          "created_at",
+	"updated_at":          "updated_at",
+	"last_checked_out_at": "last_checked_out_at",
+	"due_at":              "due_at",
+}
+
+// ParseToolSort parses a sort parameter such as "status,-due_at" over the fields in
+// toolSortColumns.
+func ParseToolSort(raw string) ([]SortKey, error) {
+	return parseSort(raw, toolSortColumns)
+}
+
+// ToolFilter represents filtering and sorting options for tools
+type ToolFilter struct {
+	// Statuses keeps tools in any of these statuses.
+	Statuses      []domain.ToolStatus
+	CurrentUserID *string
+	// NameContains matches a substring of the name, ignoring case.
+	NameContains     *string
+	CheckedOutBefore *time.Time
+	CheckedOutAfter  *time.Time
+	CreatedBefore    *time.Time
+	CreatedAfter     *time.Time
+	// Overdue keeps tools that are (true) or are not (false) overdue at Now.
+	Overdue *bool
+	Now     time.Time
+	// IncludeDeleted also keeps soft-deleted tools.
+	IncludeDeleted bool
+	// Sort orders the list; empty means newest first.
+	Sort []SortKey
+	// After keeps the tools past this cursor; it takes the place of the offset and
+	// requires the default sort.
+	After *domain.Cursor
+}
+
+// where returns the WHERE clause selecting the tools that match f, ignoring After and Sort.
+func (f ToolFilter) where() (string, []any) {
+	query := ` WHERE 1=1`
+	args := []any{}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if !f.IncludeDeleted {
+		query += ` AND deleted_at IS NULL`
+	}
+	if len(f.Statuses) > 0 {
+		statuses := make([]string, len(f.Statuses))
+		for i, st := range f.Statuses {
+			statuses[i] = string(st)
+		}
+		query += ` AND status = ANY(` + arg(pq.Array(statuses)) + `::tool_status[])`
+	}
+	if f.CurrentUserID != nil {
+		query += ` AND current_user_id = ` + arg(*f.CurrentUserID)
+	}
+	if f.NameContains != nil {
+		query += ` AND name ILIKE ` + arg("%"+escapeLike(*f.NameContains)+"%") + ` ESCAPE '\'`
+	}
+	if f.CheckedOutBefore != nil {
+		query += ` AND last_checked_out_at < ` + arg(*f.CheckedOutBefore)
+	}
+	if f.CheckedOutAfter != nil {
+		query += ` AND last_checked_out_at >= ` + arg(*f.CheckedOutAfter)
+	}
+	if f.CreatedBefore != nil {
+		query += ` AND created_at < ` + arg(*f.CreatedBefore)
+	}
+	if f.CreatedAfter != nil {
+		query += ` AND created_at >= ` + arg(*f.CreatedAfter)
+	}
+	if f.Overdue != nil {
+		n := arg(f.Now)
+		if *f.Overdue {
+			query += ` AND current_user_id IS NOT NULL AND due_at < ` + n
+		} else {
+			query += ` AND (current_user_id IS NULL OR due_at IS NULL OR due_at >= ` + n + `)`
+		}
+	}
+	return query, args
+}
+
+// escapeLike escapes the LIKE wildcards in s so that it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// ListWithFilter returns the tools matching filter in its sort order (newest first by default).
+func (r *PostgresToolRepo) ListWithFilter(ctx context.Context, filter ToolFilter, limit, offset int) ([]domain.Tool, error) {
+	if filter.After != nil && len(filter.Sort) > 0 {
+		return nil, fmt.Errorf("%w: cursor cannot be combined with sort", domain.ErrValidation)
+	}
+	where, args := filter.where()
+	query, args := orderedPageClause(`SELECT `+r.toolColumns()+` FROM tools`+where, args, filter.After, orderBy(filter.Sort, toolSortColumns), limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tools with filter: %w", err)
+	}
+	defer rows.Close()
+
+	var tools []domain.Tool
+	for rows.Next() {
+		tool, err := r.scanTool(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tool: %w", err)
+		}
+		tools = append(tools, tool)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over tools: %w", err)
+	}
+
+	return tools, nil
+}
+
+// CountWithFilter returns the number of tools matching filter; After and Sort are ignored.
+func (r *PostgresToolRepo) CountWithFilter(ctx context.Context, filter ToolFilter) (int, error) {
+	where, args := filter.where()
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM tools`+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count tools with filter: %w", err)
+	}
+	return count, nil
+}
+
+func (r *PostgresToolRepo) Get(ctx context.Context, id string) (domain.Tool, error) {
+	query := `SELECT ` + r.toolColumns() + ` FROM tools WHERE id = $1 AND deleted_at IS NULL`
+
+	row := r.db.QueryRowContext(ctx, query, id)
+	tool, err := r.scanTool(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Tool{}, domain.ErrToolNotFound
+		}
+		return domain.Tool{}, fmt.Errorf("failed to get tool: %w", err)
+	}
+
+	return tool, nil
+}
+
+// GetIncludingDeleted loads a tool whether or not it is soft-deleted.
+func (r *PostgresToolRepo) GetIncludingDeleted(ctx context.Context, id string) (domain.Tool, error) {
 	query := `SELECT ` + r.toolColumns() + ` FROM tools WHERE id = $1`
 
-	row := r.db.QueryRow(query, id)
+	row := r.db.QueryRowContext(ctx, query, id)
 	tool, err := r.scanTool(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -94,10 +254,29 @@ func (r *PostgresToolRepo) Get(id string) (domain.Tool, error) {
 	return tool, nil
 }
 
-func (r *PostgresToolRepo) Update(t domain.Tool) (domain.Tool, error) {
-	query := `UPDATE tools SET name = $1, status = $2, current_user_id = $3 WHERE id = $4 RETURNING ` + r.toolColumns()
+// GetForUpdate loads a tool and locks its row until the surrounding transaction ends.
+// The lock leaves the key-share locks foreign keys take alone, so other transactions
+// can still log events that reference the row.
+func (r *PostgresToolRepo) GetForUpdate(ctx context.Context, id string) (domain.Tool, error) {
+	query := `SELECT ` + r.toolColumns() + ` FROM tools WHERE id = $1 AND deleted_at IS NULL FOR NO KEY UPDATE`
 
-	row := r.db.QueryRow(query, t.Name, t.Status, t.CurrentUserId, t.ID)
+	row := r.db.QueryRowContext(ctx, query, id)
+	tool, err := r.scanTool(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Tool{}, domain.ErrToolNotFound
+		}
+		return domain.Tool{}, fmt.Errorf("failed to get tool for update: %w", err)
+	}
+
+	return tool, nil
+}
+
+func (r *PostgresToolRepo) Update(ctx context.Context, t domain.Tool) (domain.Tool, error) {
+	query := `UPDATE tools SET name = $1, status = $2, current_user_id = $3, due_at = $4, default_loan_days = $5, overdue_notified_at = $6
+		WHERE id = $7 AND deleted_at IS NULL RETURNING ` + r.toolColumns()
+
+	row := r.db.QueryRowContext(ctx, query, t.Name, t.Status, t.CurrentUserId, t.DueAt, t.DefaultLoanDays, t.OverdueNotifiedAt, t.ID)
 	tool, err := r.scanTool(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -109,9 +288,10 @@ func (r *PostgresToolRepo) Update(t domain.Tool) (domain.Tool, error) {
 	return tool, nil
 }
 
-func (r *PostgresToolRepo) Delete(id string) error {
-	query := `DELETE FROM tools WHERE id = $1`
-	result, err := r.db.Exec(query, id)
+// Delete soft-deletes a tool: the row stays, so its events keep their tool_id.
+func (r *PostgresToolRepo) Delete(ctx context.Context, id string) error {
+	query := `UPDATE tools SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete tool: %w", err)
 	}
@@ -128,9 +308,26 @@ func (r *PostgresToolRepo) Delete(id string) error {
 	return nil
 }
 
-func (r *PostgresToolRepo) ListByStatus(status domain.ToolStatus, limit, offset int) ([]domain.Tool, error) {
-	query := `SELECT ` + r.toolColumns() + ` FROM tools WHERE status = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`
-	rows, err := r.db.Query(query, status, limit, offset)
+// Restore undoes the soft delete of a tool. It fails with ErrToolNotFound unless the
+// tool exists and is deleted.
+func (r *PostgresToolRepo) Restore(ctx context.Context, id string) (domain.Tool, error) {
+	query := `UPDATE tools SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING ` + r.toolColumns()
+
+	row := r.db.QueryRowContext(ctx, query, id)
+	tool, err := r.scanTool(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Tool{}, domain.ErrToolNotFound
+		}
+		return domain.Tool{}, fmt.Errorf("failed to restore tool: %w", err)
+	}
+
+	return tool, nil
+}
+
+func (r *PostgresToolRepo) ListByStatus(ctx context.Context, status domain.ToolStatus, limit, offset int) ([]domain.Tool, error) {
+	query := `SELECT ` + r.toolColumns() + ` FROM tools WHERE status = $1 AND deleted_at IS NULL ORDER BY created_at DESC LIMIT $2 OFFSET $3`
+	rows, err := r.db.QueryContext(ctx, query, status, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tools by status: %w", err)
 	}
@@ -152,9 +349,9 @@ func (r *PostgresToolRepo) ListByStatus(status domain.ToolStatus, limit, offset
 	return tools, nil
 }
 
-func (r *PostgresToolRepo) ListByUser(userID string, limit, offset int) ([]domain.Tool, error) {
-	query := `SELECT ` + r.toolColumns() + ` FROM tools WHERE current_user_id = $1 ORDER BY last_checked_out_at DESC LIMIT $2 OFFSET $3`
-	rows, err := r.db.Query(query, userID, limit, offset)
+func (r *PostgresToolRepo) ListByUser(ctx context.Context, userID string, limit, offset int) ([]domain.Tool, error) {
+	query := `SELECT ` + r.toolColumns() + ` FROM tools WHERE current_user_id = $1 AND deleted_at IS NULL ORDER BY last_checked_out_at DESC LIMIT $2 OFFSET $3`
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tools by user: %w", err)
 	}
@@ -176,12 +373,115 @@ func (r *PostgresToolRepo) ListByUser(userID string, limit, offset int) ([]domai
 	return tools, nil
 }
 
-func (r *PostgresToolRepo) Count() (int, error) {
+// ListOverdue returns checked-out tools whose due date is before now, most overdue first.
+func (r *PostgresToolRepo) ListOverdue(ctx context.Context, now time.Time, limit, offset int) ([]domain.Tool, error) {
+	query := `SELECT ` + r.toolColumns() + ` FROM tools
+		WHERE current_user_id IS NOT NULL AND due_at < $1 AND deleted_at IS NULL
+		ORDER BY due_at LIMIT $2 OFFSET $3`
+	return r.listOverdue(ctx, query, now, limit, offset)
+}
+
+// ListOverdueUnnotified returns overdue tools whose TOOL_OVERDUE event has not been emitted yet.
+func (r *PostgresToolRepo) ListOverdueUnnotified(ctx context.Context, now time.Time, limit int) ([]domain.Tool, error) {
+	query := `SELECT ` + r.toolColumns() + ` FROM tools
+		WHERE current_user_id IS NOT NULL AND due_at < $1 AND overdue_notified_at IS NULL AND deleted_at IS NULL
+		ORDER BY due_at LIMIT $2`
+	return r.listOverdue(ctx, query, now, limit)
+}
+
+func (r *PostgresToolRepo) listOverdue(ctx context.Context, query string, args ...any) ([]domain.Tool, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query overdue tools: %w", err)
+	}
+	defer rows.Close()
+
+	var tools []domain.Tool
+	for rows.Next() {
+		tool, err := r.scanTool(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tool: %w", err)
+		}
+		tools = append(tools, tool)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over tools: %w", err)
+	}
+
+	return tools, nil
+}
+
+func (r *PostgresToolRepo) Count(ctx context.Context) (int, error) {
 	var count int
-	query := `SELECT COUNT(*) FROM tools`
-	err := r.db.QueryRow(query).Scan(&count)
+	query := `SELECT COUNT(*) FROM tools WHERE deleted_at IS NULL`
+	err := r.db.QueryRowContext(ctx, query).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count tools: %w", err)
 	}
 	return count, nil
 }
+
+// CountByStatus returns the number of tools in each status; statuses without tools are absent.
+func (r *PostgresToolRepo) CountByStatus(ctx context.Context) (map[domain.ToolStatus]int, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT status, COUNT(*) FROM tools WHERE deleted_at IS NULL GROUP BY status`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tools by status: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[domain.ToolStatus]int{}
+	for rows.Next() {
+		var status domain.ToolStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan tool status count: %w", err)
+		}
+		counts[status] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count tools by status: %w", err)
+	}
+	return counts, nil
+}
+
+// CountCheckouts returns how many tools are checked out and how many of those are past their due date at now.
+func (r *PostgresToolRepo) CountCheckouts(ctx context.Context, now time.Time) (active, overdue int, err error) {
+	query := `SELECT
+			COUNT(*) FILTER (WHERE current_user_id IS NOT NULL),
+			COUNT(*) FILTER (WHERE current_user_id IS NOT NULL AND due_at < $1)
+		FROM tools WHERE deleted_at IS NULL`
+	if err := r.db.QueryRowContext(ctx, query, now).Scan(&active, &overdue); err != nil {
+		return 0, 0, fmt.Errorf("failed to count checkouts: %w", err)
+	}
+	return active, overdue, nil
+}
+
+// MostCheckedOut returns the tools checked out most often since since, busiest first.
+// Deleted tools are left out.
+func (r *PostgresToolRepo) MostCheckedOut(ctx context.Context, since time.Time, limit int) ([]domain.ToolCheckouts, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT t.id, t.name, COUNT(*) AS checkouts
+		FROM events e
+		JOIN tools t ON t.id = e.tool_id
+		WHERE e.type = 'TOOL_CHECKED_OUT' AND e.created_at >= $1 AND t.deleted_at IS NULL
+		GROUP BY t.id, t.name
+		ORDER BY checkouts DESC, t.name
+		LIMIT $2`, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list most checked out tools: %w", err)
+	}
+	defer rows.Close()
+
+	tools := []domain.ToolCheckouts{}
+	for rows.Next() {
+		var tc domain.ToolCheckouts
+		if err := rows.Scan(&tc.ToolID, &tc.Name, &tc.Checkouts); err != nil {
+			return nil, fmt.Errorf("failed to scan tool checkouts: %w", err)
+		}
+		tools = append(tools, tc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list most checked out tools: %w", err)
+	}
+	return tools, nil
+}
@@ -1,8 +1,10 @@
 package repo
 
 import (
+	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -22,10 +24,11 @@ func TestMain(m *testing.M) {
 // TestPostgresToolRepo_CRUD tests all basic CRUD operations
 func TestPostgresToolRepo_CRUD(t *testing.T) {
 	db := setupSharedRepoTestDB(t)
+	ctx := context.Background()
 	repo := NewPostgresToolRepo(db)
 
 	t.Run("Create Tool", func(t *testing.T) {
-		tool, err := repo.Create("Test Hammer", domain.ToolStatusInOffice)
+		tool, err := repo.Create(ctx, "Test Hammer", domain.ToolStatusInOffice)
 
 		require.NoError(t, err)
 		assert.NotNil(t, tool.ID)
@@ -38,11 +41,11 @@ func TestPostgresToolRepo_CRUD(t *testing.T) {
 
 	t.Run("Get Tool", func(t *testing.T) {
 		// Create first
-		created, err := repo.Create("Test Drill", domain.ToolStatusInOffice)
+		created, err := repo.Create(ctx, "Test Drill", domain.ToolStatusInOffice)
 		require.NoError(t, err)
 
 		// Get it back
-		retrieved, err := repo.Get(*created.ID)
+		retrieved, err := repo.Get(ctx, *created.ID)
 		require.NoError(t, err)
 
 		assert.Equal(t, created.ID, retrieved.ID)
@@ -52,7 +55,7 @@ func TestPostgresToolRepo_CRUD(t *testing.T) {
 
 	t.Run("Update Tool", func(t *testing.T) {
 		// Create first
-		created, err := repo.Create("Original Name", domain.ToolStatusInOffice)
+		created, err := repo.Create(ctx, "Original Name", domain.ToolStatusInOffice)
 		require.NoError(t, err)
 
 		originalUpdatedAt := created.UpdatedAt
@@ -61,43 +64,70 @@ func TestPostgresToolRepo_CRUD(t *testing.T) {
 		created.Name = "Updated Name"
 		created.Status = domain.ToolStatusMaintenance
 
-		updated, err := repo.Update(created)
+		updated, err := repo.Update(ctx, created)
 		require.NoError(t, err)
 
 		assert.Equal(t, "Updated Name", updated.Name)
 		assert.Equal(t, domain.ToolStatusMaintenance, updated.Status)
 		// Database trigger should update the timestamp
 		assert.True(t, updated.UpdatedAt.After(originalUpdatedAt))
+		// and bump the version
+		assert.Equal(t, created.Version+1, updated.Version)
 	})
 
 	t.Run("Delete Tool", func(t *testing.T) {
 		// Create first
-		created, err := repo.Create("To Delete", domain.ToolStatusInOffice)
+		created, err := repo.Create(ctx, "To Delete", domain.ToolStatusInOffice)
 		require.NoError(t, err)
 
 		// Delete it
-		err = repo.Delete(*created.ID)
+		err = repo.Delete(ctx, *created.ID)
 		require.NoError(t, err)
 
 		// Should not be found
-		_, err = repo.Get(*created.ID)
+		_, err = repo.Get(ctx, *created.ID)
 		assert.Error(t, err)
 	})
 
+	t.Run("Restore Tool", func(t *testing.T) {
+		created, err := repo.Create(ctx, "To Restore", domain.ToolStatusInOffice)
+		require.NoError(t, err)
+		require.NoError(t, repo.Delete(ctx, *created.ID))
+
+		// The row is kept and stamped
+		deleted, err := repo.GetIncludingDeleted(ctx, *created.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, deleted.DeletedAt)
+
+		// Deleting twice finds nothing
+		assert.ErrorIs(t, repo.Delete(ctx, *created.ID), domain.ErrToolNotFound)
+
+		restored, err := repo.Restore(ctx, *created.ID)
+		require.NoError(t, err)
+		assert.Nil(t, restored.DeletedAt)
+
+		_, err = repo.Get(ctx, *created.ID)
+		assert.NoError(t, err)
+
+		// Only deleted tools can be restored
+		_, err = repo.Restore(ctx, *created.ID)
+		assert.ErrorIs(t, err, domain.ErrToolNotFound)
+	})
+
 	t.Run("Count Tools", func(t *testing.T) {
 		// Get initial count
-		initialCount, err := repo.Count()
+		initialCount, err := repo.Count(ctx)
 		require.NoError(t, err)
 
 		// Add tools
-		_, err = repo.Create("Tool 1", domain.ToolStatusInOffice)
+		_, err = repo.Create(ctx, "Tool 1", domain.ToolStatusInOffice)
 		require.NoError(t, err)
 
-		_, err = repo.Create("Tool 2", domain.ToolStatusMaintenance)
+		_, err = repo.Create(ctx, "Tool 2", domain.ToolStatusMaintenance)
 		require.NoError(t, err)
 
 		// Count should increase
-		newCount, err := repo.Count()
+		newCount, err := repo.Count(ctx)
 		require.NoError(t, err)
 		assert.Equal(t, initialCount+2, newCount)
 	})
@@ -106,13 +136,14 @@ func TestPostgresToolRepo_CRUD(t *testing.T) {
 // TestPostgresToolRepo_PostgreSQLFeatures tests database-specific features
 func TestPostgresToolRepo_PostgreSQLFeatures(t *testing.T) {
 	db := setupSharedRepoTestDB(t)
+	ctx := context.Background()
 	repo := NewPostgresToolRepo(db)
 
 	t.Run("UUID Primary Keys", func(t *testing.T) {
-		tool1, err := repo.Create("Tool 1", domain.ToolStatusInOffice)
+		tool1, err := repo.Create(ctx, "Tool 1", domain.ToolStatusInOffice)
 		require.NoError(t, err)
 
-		tool2, err := repo.Create("Tool 2", domain.ToolStatusInOffice)
+		tool2, err := repo.Create(ctx, "Tool 2", domain.ToolStatusInOffice)
 		require.NoError(t, err)
 
 		// UUIDs should be different
@@ -128,7 +159,7 @@ func TestPostgresToolRepo_PostgreSQLFeatures(t *testing.T) {
 		userID := createTestUser(t, db, "Test User", "test@example.com", domain.UserRoleEmployee)
 
 		// Create tool
-		tool, err := repo.Create("Trigger Test", domain.ToolStatusInOffice)
+		tool, err := repo.Create(ctx, "Trigger Test", domain.ToolStatusInOffice)
 		require.NoError(t, err)
 		assert.Nil(t, tool.LastCheckedOutAt)
 
@@ -136,7 +167,7 @@ func TestPostgresToolRepo_PostgreSQLFeatures(t *testing.T) {
 		tool.CurrentUserId = &userID
 		tool.Status = domain.ToolStatusCheckedOut
 
-		updated, err := repo.Update(tool)
+		updated, err := repo.Update(ctx, tool)
 		require.NoError(t, err)
 
 		// Database trigger should set LastCheckedOutAt
@@ -150,36 +181,36 @@ func TestPostgresToolRepo_PostgreSQLFeatures(t *testing.T) {
 		user2ID := createTestUser(t, db, "User 2", "user2@example.com", domain.UserRoleEmployee)
 
 		// Create tools checked out to different users
-		tool1, err := repo.Create("User1 Tool 1", domain.ToolStatusInOffice)
+		tool1, err := repo.Create(ctx, "User1 Tool 1", domain.ToolStatusInOffice)
 		require.NoError(t, err)
 		tool1.CurrentUserId = &user1ID
 		tool1.Status = domain.ToolStatusCheckedOut
-		_, err = repo.Update(tool1)
+		_, err = repo.Update(ctx, tool1)
 		require.NoError(t, err)
 
-		tool2, err := repo.Create("User1 Tool 2", domain.ToolStatusInOffice)
+		tool2, err := repo.Create(ctx, "User1 Tool 2", domain.ToolStatusInOffice)
 		require.NoError(t, err)
 		tool2.CurrentUserId = &user1ID
 		tool2.Status = domain.ToolStatusCheckedOut
-		_, err = repo.Update(tool2)
+		_, err = repo.Update(ctx, tool2)
 		require.NoError(t, err)
 
-		tool3, err := repo.Create("User2 Tool", domain.ToolStatusInOffice)
+		tool3, err := repo.Create(ctx, "User2 Tool", domain.ToolStatusInOffice)
 		require.NoError(t, err)
 		tool3.CurrentUserId = &user2ID
 		tool3.Status = domain.ToolStatusCheckedOut
-		_, err = repo.Update(tool3)
+		_, err = repo.Update(ctx, tool3)
 		require.NoError(t, err)
 
 		// Test ListByUser
-		user1Tools, err := repo.ListByUser(user1ID, 10, 0)
+		user1Tools, err := repo.ListByUser(ctx, user1ID, 10, 0)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(user1Tools), 2)
 		for _, tool := range user1Tools {
 			assert.Equal(t, &user1ID, tool.CurrentUserId)
 		}
 
-		user2Tools, err := repo.ListByUser(user2ID, 10, 0)
+		user2Tools, err := repo.ListByUser(ctx, user2ID, 10, 0)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(user2Tools), 1)
 		for _, tool := range user2Tools {
@@ -189,39 +220,241 @@ func TestPostgresToolRepo_PostgreSQLFeatures(t *testing.T) {
 
 	t.Run("List by Status", func(t *testing.T) {
 		// Create tools with different statuses
-		_, err := repo.Create("Available 1", domain.ToolStatusInOffice)
+		_, err := repo.Create(ctx, "Available 1", domain.ToolStatusInOffice)
 		require.NoError(t, err)
 
-		_, err = repo.Create("Available 2", domain.ToolStatusInOffice)
+		_, err = repo.Create(ctx, "Available 2", domain.ToolStatusInOffice)
 		require.NoError(t, err)
 
-		_, err = repo.Create("Maintenance Tool", domain.ToolStatusMaintenance)
+		_, err = repo.Create(ctx, "Maintenance Tool", domain.ToolStatusMaintenance)
 		require.NoError(t, err)
 
 		// Query by status
-		available, err := repo.ListByStatus(domain.ToolStatusInOffice, 10, 0)
+		available, err := repo.ListByStatus(ctx, domain.ToolStatusInOffice, 10, 0)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(available), 2)
 
-		maintenance, err := repo.ListByStatus(domain.ToolStatusMaintenance, 10, 0)
+		maintenance, err := repo.ListByStatus(ctx, domain.ToolStatusMaintenance, 10, 0)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(maintenance), 1)
 	})
+
+	t.Run("Due dates and overdue listing", func(t *testing.T) {
+		userID := createTestUser(t, db, "Borrower", "borrower@example.com", domain.UserRoleEmployee)
+		now := time.Now()
+
+		tool, err := repo.Create(ctx, "Late Saw", domain.ToolStatusInOffice)
+		require.NoError(t, err)
+		assert.False(t, tool.Overdue)
+
+		pastDue := now.Add(-time.Hour)
+		tool.CurrentUserId = &userID
+		tool.Status = domain.ToolStatusCheckedOut
+		tool.DueAt = &pastDue
+		late, err := repo.Update(ctx, tool)
+		require.NoError(t, err)
+		assert.True(t, late.Overdue)
+
+		onTime, err := repo.Create(ctx, "On Time Saw", domain.ToolStatusInOffice)
+		require.NoError(t, err)
+		future := now.Add(time.Hour)
+		onTime.CurrentUserId = &userID
+		onTime.Status = domain.ToolStatusCheckedOut
+		onTime.DueAt = &future
+		_, err = repo.Update(ctx, onTime)
+		require.NoError(t, err)
+
+		overdue, err := repo.ListOverdue(ctx, now, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, overdue, 1)
+		assert.Equal(t, late.ID, overdue[0].ID)
+
+		unnotified, err := repo.ListOverdueUnnotified(ctx, now, 10)
+		require.NoError(t, err)
+		assert.Len(t, unnotified, 1)
+
+		late.OverdueNotifiedAt = &now
+		_, err = repo.Update(ctx, late)
+		require.NoError(t, err)
+
+		unnotified, err = repo.ListOverdueUnnotified(ctx, now, 10)
+		require.NoError(t, err)
+		assert.Empty(t, unnotified)
+
+		active, overdueCount, err := repo.CountCheckouts(ctx, now)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, active, 2)
+		assert.Equal(t, 1, overdueCount)
+
+		byStatus, err := repo.CountByStatus(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, active, byStatus[domain.ToolStatusCheckedOut])
+	})
+
+	t.Run("Most Checked Out", func(t *testing.T) {
+		events := NewPostgresEventRepo(db)
+		userID := createTestUser(t, db, "Frequent User", "frequent@example.com", domain.UserRoleEmployee)
+		since := time.Now().Add(-time.Second)
+		popular, err := repo.Create(ctx, "Popular Ladder", domain.ToolStatusInOffice)
+		require.NoError(t, err)
+		rare, err := repo.Create(ctx, "Rare Lathe", domain.ToolStatusInOffice)
+		require.NoError(t, err)
+		for _, id := range []string{*popular.ID, *popular.ID, *popular.ID, *rare.ID} {
+			_, err := events.Create(ctx, domain.EventTypeToolCheckedOut, &id, &userID, &userID, "", nil)
+			require.NoError(t, err)
+		}
+
+		top, err := repo.MostCheckedOut(ctx, since, 5)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.ToolCheckouts{
+			{ToolID: *popular.ID, Name: "Popular Ladder", Checkouts: 3},
+			{ToolID: *rare.ID, Name: "Rare Lathe", Checkouts: 1},
+		}, top)
+	})
+}
+
+// TestPostgresToolRepo_ListWithFilter tests filtering and sorting tools
+func TestPostgresToolRepo_ListWithFilter(t *testing.T) {
+	db := setupSharedRepoTestDB(t)
+	ctx := context.Background()
+	repo := NewPostgresToolRepo(db)
+
+	now := time.Now()
+	ann := createTestUser(t, db, "Ann", "ann@example.com", domain.UserRoleEmployee)
+	bob := createTestUser(t, db, "Bob", "bob@example.com", domain.UserRoleEmployee)
+	checkOut := func(name, userID string, outAt, dueAt time.Time) domain.Tool {
+		tool, err := repo.Create(ctx, name, domain.ToolStatusInOffice)
+		require.NoError(t, err)
+		tool.Status = domain.ToolStatusCheckedOut
+		tool.CurrentUserId = &userID
+		tool.DueAt = &dueAt
+		tool, err = repo.Update(ctx, tool)
+		require.NoError(t, err)
+		// the checkout trigger stamps NOW(); backdate it
+		_, err = db.ExecContext(ctx, `UPDATE tools SET last_checked_out_at = $1 WHERE id = $2`, outAt, *tool.ID)
+		require.NoError(t, err)
+		return tool
+	}
+	lateDrill := checkOut("Cordless Drill", ann, now.Add(-72*time.Hour), now.Add(-time.Hour))
+	saw := checkOut("Circular Saw", bob, now.Add(-2*time.Hour), now.Add(48*time.Hour))
+	press, err := repo.Create(ctx, "Drill Press", domain.ToolStatusMaintenance)
+	require.NoError(t, err)
+	odd, err := repo.Create(ctx, "100% Ruler_v2", domain.ToolStatusInOffice)
+	require.NoError(t, err)
+
+	ids := func(tools []domain.Tool) []string {
+		out := make([]string, len(tools))
+		for i, tool := range tools {
+			out[i] = *tool.ID
+		}
+		return out
+	}
+	list := func(f ToolFilter) []string {
+		t.Helper()
+		tools, err := repo.ListWithFilter(ctx, f, 50, 0)
+		require.NoError(t, err)
+		count, err := repo.CountWithFilter(ctx, f)
+		require.NoError(t, err)
+		assert.Equal(t, len(tools), count)
+		return ids(tools)
+	}
+	ptr := func(s string) *string { return &s }
+	yes, no := true, false
+	before := now.Add(-24 * time.Hour)
+
+	t.Run("No filter lists newest first", func(t *testing.T) {
+		assert.Equal(t, []string{*odd.ID, *press.ID, *saw.ID, *lateDrill.ID}, list(ToolFilter{}))
+	})
+
+	t.Run("Statuses", func(t *testing.T) {
+		got := list(ToolFilter{Statuses: []domain.ToolStatus{domain.ToolStatusMaintenance, domain.ToolStatusInOffice}})
+		assert.ElementsMatch(t, []string{*press.ID, *odd.ID}, got)
+	})
+
+	t.Run("Current user", func(t *testing.T) {
+		assert.Equal(t, []string{*saw.ID}, list(ToolFilter{CurrentUserID: &bob}))
+	})
+
+	t.Run("Name contains ignores case and matches wildcards literally", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{*lateDrill.ID, *press.ID}, list(ToolFilter{NameContains: ptr("drill")}))
+		assert.Equal(t, []string{*odd.ID}, list(ToolFilter{NameContains: ptr("0% r")}))
+		assert.Equal(t, []string{*odd.ID}, list(ToolFilter{NameContains: ptr("_v")}))
+		assert.Equal(t, []string{*odd.ID}, list(ToolFilter{NameContains: ptr("%")}))
+	})
+
+	t.Run("Checked out and created windows", func(t *testing.T) {
+		assert.Equal(t, []string{*lateDrill.ID}, list(ToolFilter{CheckedOutBefore: &before}))
+		assert.Equal(t, []string{*saw.ID}, list(ToolFilter{CheckedOutAfter: &before}))
+		future := now.Add(time.Hour)
+		assert.Len(t, list(ToolFilter{CreatedBefore: &future, CreatedAfter: &before}), 4)
+		assert.Empty(t, list(ToolFilter{CreatedAfter: &future}))
+	})
+
+	t.Run("Overdue", func(t *testing.T) {
+		assert.Equal(t, []string{*lateDrill.ID}, list(ToolFilter{Overdue: &yes, Now: now}))
+		assert.ElementsMatch(t, []string{*saw.ID, *press.ID, *odd.ID}, list(ToolFilter{Overdue: &no, Now: now}))
+	})
+
+	t.Run("Filters combine", func(t *testing.T) {
+		got := list(ToolFilter{Statuses: []domain.ToolStatus{domain.ToolStatusCheckedOut}, NameContains: ptr("saw"), CurrentUserID: &ann})
+		assert.Empty(t, got)
+	})
+
+	t.Run("Sort", func(t *testing.T) {
+		byName, err := ParseToolSort("name")
+		require.NoError(t, err)
+		assert.Equal(t, []string{*odd.ID, *saw.ID, *lateDrill.ID, *press.ID}, list(ToolFilter{Sort: byName}))
+
+		// tools never checked out sort last either way
+		byDue, err := ParseToolSort("-due_at,name")
+		require.NoError(t, err)
+		assert.Equal(t, []string{*saw.ID, *lateDrill.ID, *odd.ID, *press.ID}, list(ToolFilter{Sort: byDue}))
+
+		page, err := repo.ListWithFilter(ctx, ToolFilter{Sort: byName}, 2, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{*lateDrill.ID, *press.ID}, ids(page))
+	})
+
+	t.Run("Cursor requires the default sort", func(t *testing.T) {
+		byName, err := ParseToolSort("name")
+		require.NoError(t, err)
+		_, err = repo.ListWithFilter(ctx, ToolFilter{Sort: byName, After: &domain.Cursor{CreatedAt: now, ID: *saw.ID}}, 10, 0)
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+}
+
+// TestParseToolSort tests the sort whitelist
+func TestParseToolSort(t *testing.T) {
+	keys, err := ParseToolSort(" status , -due_at ")
+	require.NoError(t, err)
+	assert.Equal(t, []SortKey{{Field: "status"}, {Field: "due_at", Desc: true}}, keys)
+	assert.Equal(t, "status ASC NULLS LAST, due_at DESC NULLS LAST, created_at DESC, id DESC", orderBy(keys, toolSortColumns))
+
+	keys, err = ParseToolSort("")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+	assert.Equal(t, defaultOrder, orderBy(keys, toolSortColumns))
+
+	for _, raw := range []string{"id; DROP TABLE tools", "current_user_id", "name,-name", "--name"} {
+		_, err := ParseToolSort(raw)
+		assert.ErrorIs(t, err, domain.ErrValidation, raw)
+	}
 }
 
 // TestPostgresToolRepo_ErrorCases tests error handling
 func TestPostgresToolRepo_ErrorCases(t *testing.T) {
 	db := setupSharedRepoTestDB(t)
+	ctx := context.Background()
 	repo := NewPostgresToolRepo(db)
 
 	t.Run("Get Non-existent Tool", func(t *testing.T) {
-		_, err := repo.Get("00000000-0000-0000-0000-000000000000")
+		_, err := repo.Get(ctx, "00000000-0000-0000-0000-000000000000")
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "tool not found")
 	})
 
 	t.Run("Get Invalid UUID", func(t *testing.T) {
-		_, err := repo.Get("invalid-uuid")
+		_, err := repo.Get(ctx, "invalid-uuid")
 		assert.Error(t, err)
 	})
 
@@ -231,14 +464,14 @@ func TestPostgresToolRepo_ErrorCases(t *testing.T) {
 			Name:   "Non-existent Tool",
 			Status: domain.ToolStatusInOffice,
 		}
-		_, err := repo.Update(nonExistentTool)
+		_, err := repo.Update(ctx, nonExistentTool)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "tool not found")
 	})
 
 	t.Run("Delete Non-existent Tool", func(t *testing.T) {
 		// This should error because the tool doesn't exist
-		err := repo.Delete("00000000-0000-0000-0000-000000000000")
+		err := repo.Delete(ctx, "00000000-0000-0000-0000-000000000000")
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "tool not found")
 	})
@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// TestWithTx tests commit and rollback of a transaction
+func TestWithTx(t *testing.T) {
+	db := setupSharedRepoTestDB(t)
+	ctx := context.Background()
+
+	t.Run("Commits when fn succeeds", func(t *testing.T) {
+		var toolID string
+		err := WithTx(ctx, db, func(tx DBTX) error {
+			tool, err := NewPostgresToolRepo(tx).Create(ctx, "Committed Hammer", domain.ToolStatusInOffice)
+			if err != nil {
+				return err
+			}
+			toolID = *tool.ID
+			return nil
+		})
+		require.NoError(t, err)
+
+		_, err = NewPostgresToolRepo(db).Get(ctx, toolID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Rolls back when fn fails", func(t *testing.T) {
+		var toolID string
+		err := WithTx(ctx, db, func(tx DBTX) error {
+			tool, err := NewPostgresToolRepo(tx).Create(ctx, "Rolled Back Hammer", domain.ToolStatusInOffice)
+			if err != nil {
+				return err
+			}
+			toolID = *tool.ID
+			return assert.AnError
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+
+		_, err = NewPostgresToolRepo(db).Get(ctx, toolID)
+		assert.ErrorIs(t, err, domain.ErrToolNotFound)
+	})
+}
@@ -0,0 +1,51 @@
+package repo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/repo"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/service"
+)
+
+// eventCounter records the events a unit of work reports as written.
+type eventCounter struct {
+	events []domain.EventType
+}
+
+func (c *eventCounter) ToolAction(string, error) {}
+
+func (c *eventCounter) EventWritten(eventType domain.EventType) {
+	c.events = append(c.events, eventType)
+}
+
+// TestPostgresUnitOfWork_Metrics tests that only committed events are counted
+func TestPostgresUnitOfWork_Metrics(t *testing.T) {
+	db := repo.SetupSharedRepoTestDB(t)
+	ctx := context.Background()
+	toolID := repo.CreateTestTool(t, db, "Counted Drill", domain.ToolStatusInOffice)
+
+	counter := &eventCounter{}
+	uow := service.NewPostgresUnitOfWork(db).WithMetrics(counter)
+	logEvent := func(tx service.TxRepos) error {
+		_, err := tx.Events.Create(ctx, domain.EventTypeToolUpdated, &toolID, nil, nil, "", nil)
+		return err
+	}
+
+	err := uow.Do(ctx, func(tx service.TxRepos) error {
+		if err := logEvent(tx); err != nil {
+			return err
+		}
+		return errors.New("rolled back")
+	})
+	require.Error(t, err)
+	assert.Empty(t, counter.events)
+
+	err = uow.Do(ctx, logEvent)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.EventType{domain.EventTypeToolUpdated}, counter.events)
+}
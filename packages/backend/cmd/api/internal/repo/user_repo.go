@@ -1,6 +1,7 @@
 package repo
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -9,16 +10,16 @@ import (
 )
 
 type PostgresUserRepo struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewPostgresUserRepo(db *sql.DB) *PostgresUserRepo {
+func NewPostgresUserRepo(db DBTX) *PostgresUserRepo {
 	return &PostgresUserRepo{db: db}
 }
 
 // Helper function to define the column order for user returns
 func (r *PostgresUserRepo) userColumns() string {
-	return "id, name, email, role, created_at, updated_at"
+	return "id, name, email, role, version, created_at, updated_at, deleted_at"
 }
 
 // Helper function to scan a row into a User struct
@@ -31,13 +32,15 @@ func (r *PostgresUserRepo) scanUser(scanner interface {
 		&user.Name,
 		&user.Email,
 		&user.Role,
+		&user.Version,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.DeletedAt,
 	)
 	return user, err
 }
 
-func (r *PostgresUserRepo) Create(name string, email string, role domain.UserRole) (domain.User, error) {
+func (r *PostgresUserRepo) Create(ctx context.Context, name string, email string, role domain.UserRole) (domain.User, error) {
 	user := domain.User{
 		Name:      name,
 		Email:     email,
@@ -47,7 +50,7 @@ func (r *PostgresUserRepo) Create(name string, email string, role domain.UserRol
 	}
 
 	query := `INSERT INTO users (name, email, role, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) RETURNING ` + r.userColumns()
-	row := r.db.QueryRow(query, user.Name, user.Email, user.Role, user.CreatedAt, user.UpdatedAt)
+	row := r.db.QueryRowContext(ctx, query, user.Name, user.Email, user.Role, user.CreatedAt, user.UpdatedAt)
 	createdUser, err := r.scanUser(row)
 	if err != nil {
 		return domain.User{}, fmt.Errorf("failed to create user: %w", err)
@@ -56,9 +59,11 @@ func (r *PostgresUserRepo) Create(name string, email string, role domain.UserRol
 	return createdUser, nil
 }
 
-func (r *PostgresUserRepo) List(limit, offset int) ([]domain.User, error) {
-	query := `SELECT ` + r.userColumns() + ` FROM users ORDER BY created_at DESC LIMIT $1 OFFSET $2`
-	rows, err := r.db.Query(query, limit, offset)
+// List returns users newest first, after the cursor when one is given. Like every
+// query here unless stated otherwise, it leaves out soft-deleted users.
+func (r *PostgresUserRepo) List(ctx context.Context, after *domain.Cursor, limit, offset int) ([]domain.User, error) {
+	query, args := pageClause(`SELECT `+r.userColumns()+` FROM users WHERE deleted_at IS NULL`, nil, after, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query users: %w", err)
 	}
@@ -80,10 +85,85 @@ func (r *PostgresUserRepo) List(limit, offset int) ([]domain.User, error) {
 	return users, nil
 }
 
-func (r *PostgresUserRepo) Get(id string) (domain.User, error) {
+// UserFilter represents filtering options for users
+type UserFilter struct {
+	Role *domain.UserRole
+	// IncludeDeleted also keeps soft-deleted users.
+	IncludeDeleted bool
+	// After keeps the users past this cursor; it takes the place of the offset.
+	After *domain.Cursor
+}
+
+// where returns the WHERE clause selecting the users that match f, ignoring After.
+func (f UserFilter) where() (string, []any) {
+	query := ` WHERE 1=1`
+	args := []any{}
+	if !f.IncludeDeleted {
+		query += ` AND deleted_at IS NULL`
+	}
+	if f.Role != nil {
+		args = append(args, *f.Role)
+		query += fmt.Sprintf(` AND role = $%d`, len(args))
+	}
+	return query, args
+}
+
+// ListWithFilter returns the users matching filter newest first.
+func (r *PostgresUserRepo) ListWithFilter(ctx context.Context, filter UserFilter, limit, offset int) ([]domain.User, error) {
+	where, args := filter.where()
+	query, args := pageClause(`SELECT `+r.userColumns()+` FROM users`+where, args, filter.After, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users with filter: %w", err)
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		user, err := r.scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over users: %w", err)
+	}
+
+	return users, nil
+}
+
+// CountWithFilter returns the number of users matching filter; After is ignored.
+func (r *PostgresUserRepo) CountWithFilter(ctx context.Context, filter UserFilter) (int, error) {
+	where, args := filter.where()
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users`+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count users with filter: %w", err)
+	}
+	return count, nil
+}
+
+func (r *PostgresUserRepo) Get(ctx context.Context, id string) (domain.User, error) {
+	query := `SELECT ` + r.userColumns() + ` FROM users WHERE id = $1 AND deleted_at IS NULL`
+
+	row := r.db.QueryRowContext(ctx, query, id)
+	user, err := r.scanUser(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.User{}, domain.ErrUserNotFound
+		}
+		return domain.User{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetIncludingDeleted loads a user whether or not it is soft-deleted.
+func (r *PostgresUserRepo) GetIncludingDeleted(ctx context.Context, id string) (domain.User, error) {
 	query := `SELECT ` + r.userColumns() + ` FROM users WHERE id = $1`
 
-	row := r.db.QueryRow(query, id)
+	row := r.db.QueryRowContext(ctx, query, id)
 	user, err := r.scanUser(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -95,10 +175,28 @@ func (r *PostgresUserRepo) Get(id string) (domain.User, error) {
 	return user, nil
 }
 
-func (r *PostgresUserRepo) GetByEmail(email string) (domain.User, error) {
-	query := `SELECT ` + r.userColumns() + ` FROM users WHERE email = $1`
+// GetForUpdate loads a user and locks its row until the surrounding transaction ends.
+// The lock leaves the key-share locks foreign keys take alone, so other transactions
+// can still log events that reference the row.
+func (r *PostgresUserRepo) GetForUpdate(ctx context.Context, id string) (domain.User, error) {
+	query := `SELECT ` + r.userColumns() + ` FROM users WHERE id = $1 AND deleted_at IS NULL FOR NO KEY UPDATE`
+
+	row := r.db.QueryRowContext(ctx, query, id)
+	user, err := r.scanUser(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.User{}, domain.ErrUserNotFound
+		}
+		return domain.User{}, fmt.Errorf("failed to get user for update: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *PostgresUserRepo) GetByEmail(ctx context.Context, email string) (domain.User, error) {
+	query := `SELECT ` + r.userColumns() + ` FROM users WHERE email = $1 AND deleted_at IS NULL`
 
-	row := r.db.QueryRow(query, email)
+	row := r.db.QueryRowContext(ctx, query, email)
 	user, err := r.scanUser(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -110,10 +208,10 @@ func (r *PostgresUserRepo) GetByEmail(email string) (domain.User, error) {
 	return user, nil
 }
 
-func (r *PostgresUserRepo) Update(id string, name string, email string, role domain.UserRole) (domain.User, error) {
-	query := `UPDATE users SET name = $1, email = $2, role = $3, updated_at = $4 WHERE id = $5 RETURNING ` + r.userColumns()
+func (r *PostgresUserRepo) Update(ctx context.Context, id string, name string, email string, role domain.UserRole) (domain.User, error) {
+	query := `UPDATE users SET name = $1, email = $2, role = $3, updated_at = $4 WHERE id = $5 AND deleted_at IS NULL RETURNING ` + r.userColumns()
 
-	row := r.db.QueryRow(query, name, email, role, time.Now(), id)
+	row := r.db.QueryRowContext(ctx, query, name, email, role, time.Now(), id)
 	user, err := r.scanUser(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -125,9 +223,10 @@ func (r *PostgresUserRepo) Update(id string, name string, email string, role dom
 	return user, nil
 }
 
-func (r *PostgresUserRepo) Delete(id string) error {
-	query := `DELETE FROM users WHERE id = $1`
-	result, err := r.db.Exec(query, id)
+// Delete soft-deletes a user: the row stays, so events keep their user_id.
+func (r *PostgresUserRepo) Delete(ctx context.Context, id string) error {
+	query := `UPDATE users SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
@@ -144,9 +243,27 @@ func (r *PostgresUserRepo) Delete(id string) error {
 	return nil
 }
 
-func (r *PostgresUserRepo) ListByRole(role domain.UserRole, limit, offset int) ([]domain.User, error) {
-	query := `SELECT ` + r.userColumns() + ` FROM users WHERE role = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`
-	rows, err := r.db.Query(query, role, limit, offset)
+// Restore undoes the soft delete of a user. It fails with ErrUserNotFound unless the
+// user exists and is deleted.
+func (r *PostgresUserRepo) Restore(ctx context.Context, id string) (domain.User, error) {
+	query := `UPDATE users SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING ` + r.userColumns()
+
+	row := r.db.QueryRowContext(ctx, query, id)
+	user, err := r.scanUser(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.User{}, domain.ErrUserNotFound
+		}
+		return domain.User{}, fmt.Errorf("failed to restore user: %w", err)
+	}
+
+	return user, nil
+}
+
+// ListByRole returns the users with a role newest first, after the cursor when one is given.
+func (r *PostgresUserRepo) ListByRole(ctx context.Context, role domain.UserRole, after *domain.Cursor, limit, offset int) ([]domain.User, error) {
+	query, args := pageClause(`SELECT `+r.userColumns()+` FROM users WHERE role = $1 AND deleted_at IS NULL`, []any{role}, after, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query users by role: %w", err)
 	}
@@ -168,12 +285,64 @@ func (r *PostgresUserRepo) ListByRole(role domain.UserRole, limit, offset int) (
 	return users, nil
 }
 
-func (r *PostgresUserRepo) Count() (int, error) {
+func (r *PostgresUserRepo) Count(ctx context.Context) (int, error) {
 	var count int
-	query := `SELECT COUNT(*) FROM users`
-	err := r.db.QueryRow(query).Scan(&count)
+	query := `SELECT COUNT(*) FROM users WHERE deleted_at IS NULL`
+	err := r.db.QueryRowContext(ctx, query).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count users: %w", err)
 	}
 	return count, nil
 }
+
+// CountByRole returns the number of users with each role; roles without users are absent.
+func (r *PostgresUserRepo) CountByRole(ctx context.Context) (map[domain.UserRole]int, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT role, COUNT(*) FROM users WHERE deleted_at IS NULL GROUP BY role`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users by role: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[domain.UserRole]int{}
+	for rows.Next() {
+		var role domain.UserRole
+		var count int
+		if err := rows.Scan(&role, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan user role count: %w", err)
+		}
+		counts[role] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count users by role: %w", err)
+	}
+	return counts, nil
+}
+
+// TopBorrowers returns the users who checked tools out most often since since, busiest first.
+// Deleted users are left out.
+func (r *PostgresUserRepo) TopBorrowers(ctx context.Context, since time.Time, limit int) ([]domain.UserCheckouts, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT u.id, u.name, COUNT(*) AS checkouts
+		FROM events e
+		JOIN users u ON u.id = e.user_id
+		WHERE e.type = 'TOOL_CHECKED_OUT' AND e.created_at >= $1 AND u.deleted_at IS NULL
+		GROUP BY u.id, u.name
+		ORDER BY checkouts DESC, u.name
+		LIMIT $2`, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list top borrowers: %w", err)
+	}
+	defer rows.Close()
+
+	users := []domain.UserCheckouts{}
+	for rows.Next() {
+		var uc domain.UserCheckouts
+		if err := rows.Scan(&uc.UserID, &uc.Name, &uc.Checkouts); err != nil {
+			return nil, fmt.Errorf("failed to scan user checkouts: %w", err)
+		}
+		users = append(users, uc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list top borrowers: %w", err)
+	}
+	return users, nil
+}
@@ -1,7 +1,9 @@
 package repo
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -11,10 +13,11 @@ import (
 // TestPostgresUserRepo_CRUD tests all basic CRUD operations
 func TestPostgresUserRepo_CRUD(t *testing.T) {
 	db := setupSharedRepoTestDB(t)
+	ctx := context.Background()
 	repo := NewPostgresUserRepo(db)
 
 	t.Run("Create User", func(t *testing.T) {
-		user, err := repo.Create("John Doe", "john@example.com", domain.UserRoleEmployee)
+		user, err := repo.Create(ctx, "John Doe", "john@example.com", domain.UserRoleEmployee)
 
 		require.NoError(t, err)
 		assert.NotEmpty(t, user.ID)
@@ -27,11 +30,11 @@ func TestPostgresUserRepo_CRUD(t *testing.T) {
 
 	t.Run("Get User", func(t *testing.T) {
 		// Create first
-		created, err := repo.Create("Jane Smith", "jane@example.com", domain.UserRoleManager)
+		created, err := repo.Create(ctx, "Jane Smith", "jane@example.com", domain.UserRoleManager)
 		require.NoError(t, err)
 
 		// Get it back
-		retrieved, err := repo.Get(created.ID)
+		retrieved, err := repo.Get(ctx, created.ID)
 		require.NoError(t, err)
 
 		assert.Equal(t, created.ID, retrieved.ID)
@@ -42,11 +45,11 @@ func TestPostgresUserRepo_CRUD(t *testing.T) {
 
 	t.Run("Get User by Email", func(t *testing.T) {
 		// Create first
-		created, err := repo.Create("Bob Wilson", "bob@example.com", domain.UserRoleAdmin)
+		created, err := repo.Create(ctx, "Bob Wilson", "bob@example.com", domain.UserRoleAdmin)
 		require.NoError(t, err)
 
 		// Get by email
-		retrieved, err := repo.GetByEmail("bob@example.com")
+		retrieved, err := repo.GetByEmail(ctx, "bob@example.com")
 		require.NoError(t, err)
 
 		assert.Equal(t, created.ID, retrieved.ID)
@@ -56,50 +59,72 @@ func TestPostgresUserRepo_CRUD(t *testing.T) {
 
 	t.Run("Update User", func(t *testing.T) {
 		// Create first
-		created, err := repo.Create("Alice Brown", "alice@example.com", domain.UserRoleEmployee)
+		created, err := repo.Create(ctx, "Alice Brown", "alice@example.com", domain.UserRoleEmployee)
 		require.NoError(t, err)
 
 		originalUpdatedAt := created.UpdatedAt
 
 		// Update it
-		updated, err := repo.Update(created.ID, "Alice Johnson", "alice.johnson@example.com", domain.UserRoleManager)
+		updated, err := repo.Update(ctx, created.ID, "Alice Johnson", "alice.johnson@example.com", domain.UserRoleManager)
 		require.NoError(t, err)
 
 		assert.Equal(t, "Alice Johnson", updated.Name)
 		assert.Equal(t, "alice.johnson@example.com", updated.Email)
 		assert.Equal(t, domain.UserRoleManager, updated.Role)
 		assert.True(t, updated.UpdatedAt.After(originalUpdatedAt))
+		assert.Equal(t, created.Version+1, updated.Version)
 	})
 
 	t.Run("Delete User", func(t *testing.T) {
 		// Create first
-		created, err := repo.Create("Charlie Davis", "charlie@example.com", domain.UserRoleEmployee)
+		created, err := repo.Create(ctx, "Charlie Davis", "charlie@example.com", domain.UserRoleEmployee)
 		require.NoError(t, err)
 
 		// Delete it
-		err = repo.Delete(created.ID)
+		err = repo.Delete(ctx, created.ID)
 		require.NoError(t, err)
 
 		// Should not be found
-		_, err = repo.Get(created.ID)
+		_, err = repo.Get(ctx, created.ID)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "user not found")
 	})
 
+	t.Run("Restore User", func(t *testing.T) {
+		created, err := repo.Create(ctx, "Dana Evans", "dana@example.com", domain.UserRoleEmployee)
+		require.NoError(t, err)
+		require.NoError(t, repo.Delete(ctx, created.ID))
+
+		deleted, err := repo.GetIncludingDeleted(ctx, created.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, deleted.DeletedAt)
+
+		// A deleted user's email is free again
+		_, err = repo.GetByEmail(ctx, "dana@example.com")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+		restored, err := repo.Restore(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Nil(t, restored.DeletedAt)
+
+		_, err = repo.Get(ctx, created.ID)
+		assert.NoError(t, err)
+	})
+
 	t.Run("Count Users", func(t *testing.T) {
 		// Get initial count
-		initialCount, err := repo.Count()
+		initialCount, err := repo.Count(ctx)
 		require.NoError(t, err)
 
 		// Add users
-		_, err = repo.Create("User 1", "user1@example.com", domain.UserRoleEmployee)
+		_, err = repo.Create(ctx, "User 1", "user1@example.com", domain.UserRoleEmployee)
 		require.NoError(t, err)
 
-		_, err = repo.Create("User 2", "user2@example.com", domain.UserRoleAdmin)
+		_, err = repo.Create(ctx, "User 2", "user2@example.com", domain.UserRoleAdmin)
 		require.NoError(t, err)
 
 		// Count should increase
-		newCount, err := repo.Count()
+		newCount, err := repo.Count(ctx)
 		require.NoError(t, err)
 		assert.Equal(t, initialCount+2, newCount)
 	})
@@ -108,26 +133,27 @@ func TestPostgresUserRepo_CRUD(t *testing.T) {
 // TestPostgresUserRepo_QueryFeatures tests specialized query operations
 func TestPostgresUserRepo_QueryFeatures(t *testing.T) {
 	db := setupSharedRepoTestDB(t)
+	ctx := context.Background()
 	repo := NewPostgresUserRepo(db)
 
 	t.Run("List Users", func(t *testing.T) {
 		// Create test users
-		_, err := repo.Create("User A", "usera@example.com", domain.UserRoleEmployee)
+		_, err := repo.Create(ctx, "User A", "usera@example.com", domain.UserRoleEmployee)
 		require.NoError(t, err)
 
-		_, err = repo.Create("User B", "userb@example.com", domain.UserRoleManager)
+		_, err = repo.Create(ctx, "User B", "userb@example.com", domain.UserRoleManager)
 		require.NoError(t, err)
 
-		_, err = repo.Create("User C", "userc@example.com", domain.UserRoleAdmin)
+		_, err = repo.Create(ctx, "User C", "userc@example.com", domain.UserRoleAdmin)
 		require.NoError(t, err)
 
 		// List with pagination
-		users, err := repo.List(2, 0)
+		users, err := repo.List(ctx, nil, 2, 0)
 		require.NoError(t, err)
 		assert.Len(t, users, 2)
 
 		// List next page
-		moreUsers, err := repo.List(2, 2)
+		moreUsers, err := repo.List(ctx, nil, 2, 2)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(moreUsers), 1)
 
@@ -135,38 +161,49 @@ func TestPostgresUserRepo_QueryFeatures(t *testing.T) {
 		if len(users) >= 2 {
 			assert.True(t, users[0].CreatedAt.After(users[1].CreatedAt) || users[0].CreatedAt.Equal(users[1].CreatedAt))
 		}
+
+		// The page after the last user matches the next offset page
+		after := &domain.Cursor{CreatedAt: users[1].CreatedAt, ID: users[1].ID}
+		keyset, err := repo.List(ctx, after, 2, 0)
+		require.NoError(t, err)
+		require.NotEmpty(t, keyset)
+		assert.Equal(t, moreUsers[0].ID, keyset[0].ID)
+		for _, u := range keyset {
+			assert.NotEqual(t, users[0].ID, u.ID)
+			assert.NotEqual(t, users[1].ID, u.ID)
+		}
 	})
 
 	t.Run("List by Role", func(t *testing.T) {
 		// Create users with different roles
-		_, err := repo.Create("Employee 1", "emp1@example.com", domain.UserRoleEmployee)
+		_, err := repo.Create(ctx, "Employee 1", "emp1@example.com", domain.UserRoleEmployee)
 		require.NoError(t, err)
 
-		_, err = repo.Create("Employee 2", "emp2@example.com", domain.UserRoleEmployee)
+		_, err = repo.Create(ctx, "Employee 2", "emp2@example.com", domain.UserRoleEmployee)
 		require.NoError(t, err)
 
-		_, err = repo.Create("Manager 1", "mgr1@example.com", domain.UserRoleManager)
+		_, err = repo.Create(ctx, "Manager 1", "mgr1@example.com", domain.UserRoleManager)
 		require.NoError(t, err)
 
-		_, err = repo.Create("Admin 1", "admin1@example.com", domain.UserRoleAdmin)
+		_, err = repo.Create(ctx, "Admin 1", "admin1@example.com", domain.UserRoleAdmin)
 		require.NoError(t, err)
 
 		// Query by role
-		employees, err := repo.ListByRole(domain.UserRoleEmployee, 10, 0)
+		employees, err := repo.ListByRole(ctx, domain.UserRoleEmployee, nil, 10, 0)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(employees), 2)
 		for _, user := range employees {
 			assert.Equal(t, domain.UserRoleEmployee, user.Role)
 		}
 
-		managers, err := repo.ListByRole(domain.UserRoleManager, 10, 0)
+		managers, err := repo.ListByRole(ctx, domain.UserRoleManager, nil, 10, 0)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(managers), 1)
 		for _, user := range managers {
 			assert.Equal(t, domain.UserRoleManager, user.Role)
 		}
 
-		admins, err := repo.ListByRole(domain.UserRoleAdmin, 10, 0)
+		admins, err := repo.ListByRole(ctx, domain.UserRoleAdmin, nil, 10, 0)
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(admins), 1)
 		for _, user := range admins {
@@ -174,11 +211,51 @@ func TestPostgresUserRepo_QueryFeatures(t *testing.T) {
 		}
 	})
 
+	t.Run("Count by Role", func(t *testing.T) {
+		counts, err := repo.CountByRole(ctx)
+		require.NoError(t, err)
+
+		total, err := repo.Count(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, total, counts[domain.UserRoleEmployee]+counts[domain.UserRoleManager]+counts[domain.UserRoleAdmin])
+		assert.GreaterOrEqual(t, counts[domain.UserRoleManager], 2)
+	})
+
+	t.Run("Top Borrowers", func(t *testing.T) {
+		events := NewPostgresEventRepo(db)
+		since := time.Now().Add(-time.Second)
+		heavyID := createTestUser(t, db, "Heavy Borrower", "heavy@example.com", domain.UserRoleEmployee)
+		lightID := createTestUser(t, db, "Light Borrower", "light@example.com", domain.UserRoleEmployee)
+		toolID := createTestTool(t, db, "Borrowed Drill", domain.ToolStatusInOffice)
+		for _, userID := range []string{heavyID, heavyID, lightID} {
+			_, err := events.Create(ctx, domain.EventTypeToolCheckedOut, &toolID, &userID, &userID, "", nil)
+			require.NoError(t, err)
+		}
+		// check-ins are not borrowing
+		_, err := events.Create(ctx, domain.EventTypeToolCheckedIn, &toolID, &lightID, &lightID, "", nil)
+		require.NoError(t, err)
+
+		top, err := repo.TopBorrowers(ctx, since, 5)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.UserCheckouts{
+			{UserID: heavyID, Name: "Heavy Borrower", Checkouts: 2},
+			{UserID: lightID, Name: "Light Borrower", Checkouts: 1},
+		}, top)
+
+		top, err = repo.TopBorrowers(ctx, since, 1)
+		require.NoError(t, err)
+		assert.Len(t, top, 1)
+
+		none, err := repo.TopBorrowers(ctx, time.Now().Add(time.Hour), 5)
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+
 	t.Run("UUID Primary Keys", func(t *testing.T) {
-		user1, err := repo.Create("User 1", "uuid1@example.com", domain.UserRoleEmployee)
+		user1, err := repo.Create(ctx, "User 1", "uuid1@example.com", domain.UserRoleEmployee)
 		require.NoError(t, err)
 
-		user2, err := repo.Create("User 2", "uuid2@example.com", domain.UserRoleEmployee)
+		user2, err := repo.Create(ctx, "User 2", "uuid2@example.com", domain.UserRoleEmployee)
 		require.NoError(t, err)
 
 		// UUIDs should be different
@@ -193,44 +270,45 @@ func TestPostgresUserRepo_QueryFeatures(t *testing.T) {
 // TestPostgresUserRepo_ErrorCases tests error handling
 func TestPostgresUserRepo_ErrorCases(t *testing.T) {
 	db := setupSharedRepoTestDB(t)
+	ctx := context.Background()
 	repo := NewPostgresUserRepo(db)
 
 	t.Run("Get Non-existent User", func(t *testing.T) {
-		_, err := repo.Get("00000000-0000-0000-0000-000000000000")
+		_, err := repo.Get(ctx, "00000000-0000-0000-0000-000000000000")
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "user not found")
 	})
 
 	t.Run("Get Invalid UUID", func(t *testing.T) {
-		_, err := repo.Get("invalid-uuid")
+		_, err := repo.Get(ctx, "invalid-uuid")
 		assert.Error(t, err)
 	})
 
 	t.Run("Get by Non-existent Email", func(t *testing.T) {
-		_, err := repo.GetByEmail("nonexistent@example.com")
+		_, err := repo.GetByEmail(ctx, "nonexistent@example.com")
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "user not found")
 	})
 
 	t.Run("Update Non-existent User", func(t *testing.T) {
-		_, err := repo.Update("00000000-0000-0000-0000-000000000000", "Updated Name", "updated@example.com", domain.UserRoleEmployee)
+		_, err := repo.Update(ctx, "00000000-0000-0000-0000-000000000000", "Updated Name", "updated@example.com", domain.UserRoleEmployee)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "user not found")
 	})
 
 	t.Run("Delete Non-existent User", func(t *testing.T) {
-		err := repo.Delete("00000000-0000-0000-0000-000000000000")
+		err := repo.Delete(ctx, "00000000-0000-0000-0000-000000000000")
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "user not found")
 	})
 
 	t.Run("Create User with Duplicate Email", func(t *testing.T) {
 		// Create first user
-		_, err := repo.Create("First User", "duplicate@example.com", domain.UserRoleEmployee)
+		_, err := repo.Create(ctx, "First User", "duplicate@example.com", domain.UserRoleEmployee)
 		require.NoError(t, err)
 
 		// Try to create another user with same email (should fail due to unique constraint)
-		_, err = repo.Create("Second User", "duplicate@example.com", domain.UserRoleEmployee)
+		_, err = repo.Create(ctx, "Second User", "duplicate@example.com", domain.UserRoleEmployee)
 		assert.Error(t, err)
 		// The exact error message will depend on the database constraint
 	})
@@ -1,9 +1,14 @@
 package server
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/service"
 )
 
 // Admin stats response
@@ -22,45 +27,76 @@ type StatsResponse struct {
 		Managers  int `json:"managers"`
 		Admins    int `json:"admins"`
 	} `json:"users_by_role"`
+	ActiveCheckouts int `json:"active_checkouts"`
+	OverdueTools    int `json:"overdue_tools"`
+	// The activity figures below cover the last WindowDays days, starting at Since.
+	WindowDays    int                    `json:"window_days"`
+	Since         time.Time              `json:"since"`
+	EventsByType  map[string]int         `json:"events_by_type"`
+	TopBorrowers  []domain.UserCheckouts `json:"top_borrowers"`
+	MostUsedTools []domain.ToolCheckouts `json:"most_used_tools"`
+	// GeneratedAt is when the snapshot was computed; it is cached for a short while.
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+func newStatsResponse(stats service.Stats) StatsResponse {
+	resp := StatsResponse{
+		TotalTools:      stats.TotalTools,
+		TotalUsers:      stats.TotalUsers,
+		TotalEvents:     stats.TotalEvents,
+		ActiveCheckouts: stats.ActiveCheckouts,
+		OverdueTools:    stats.OverdueTools,
+		WindowDays:      int(stats.Window / (24 * time.Hour)),
+		Since:           stats.Since,
+		EventsByType:    map[string]int{},
+		TopBorrowers:    stats.TopBorrowers,
+		MostUsedTools:   stats.MostUsedTools,
+		GeneratedAt:     stats.GeneratedAt,
+	}
+	resp.ToolsByStatus.InOffice = stats.ToolsByStatus[domain.ToolStatusInOffice]
+	resp.ToolsByStatus.CheckedOut = stats.ToolsByStatus[domain.ToolStatusCheckedOut]
+	resp.ToolsByStatus.Maintenance = stats.ToolsByStatus[domain.ToolStatusMaintenance]
+	resp.ToolsByStatus.Lost = stats.ToolsByStatus[domain.ToolStatusLost]
+	resp.UsersByRole.Employees = stats.UsersByRole[domain.UserRoleEmployee]
+	resp.UsersByRole.Managers = stats.UsersByRole[domain.UserRoleManager]
+	resp.UsersByRole.Admins = stats.UsersByRole[domain.UserRoleAdmin]
+	// every type is listed, so a type without events in the window reads 0
+	for _, eventType := range domain.ValidEventTypes() {
+		resp.EventsByType[string(eventType)] = stats.EventsByType[eventType]
+	}
+	return resp
 }
 
 // GetStats godoc
 // @Summary Get system statistics
-// @Description Get comprehensive statistics about tools, users, and events
+// @Description Get tool, user and event totals with their breakdowns, current and overdue checkouts, and the events, top borrowers and most used tools of the last `days` days. The snapshot is cached for up to 30 seconds.
 // @Tags admin
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Param days query int false "Activity window in days (1-365)" default(30)
 // @Success 200 {object} StatsResponse
+// @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /admin/stats [get]
 func (s *Server) getStats(c *gin.Context) {
-	var stats StatsResponse
-
-	// Get total counts
-	toolCount, err := s.toolService.GetToolCount()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get tool count"})
+	if !requireService(c, s.statsService != nil, "stats") {
 		return
 	}
-	stats.TotalTools = toolCount
-
-	userCount, err := s.userService.GetUserCount()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user count"})
+	maxDays := int(service.MaxStatsWindow / (24 * time.Hour))
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(int(service.DefaultStatsWindow/(24*time.Hour)))))
+	if err != nil || days < 1 || days > maxDays {
+		respondDomainError(c, validationErr("days", fmt.Sprintf("must be an integer between 1 and %d", maxDays)))
 		return
 	}
-	stats.TotalUsers = userCount
 
-	eventCount, err := s.eventService.GetEventCount()
+	stats, err := s.statsService.Stats(c.Request.Context(), time.Duration(days)*24*time.Hour)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get event count"})
+		respondDomainError(c, err)
 		return
 	}
-	stats.TotalEvents = eventCount
 
-	// TODO rest of the stats 0 for now
-
-	c.JSON(http.StatusOK, stats)
+	c.JSON(http.StatusOK, newStatsResponse(stats))
 }
 
 // GetAuditLog godoc
@@ -69,19 +105,133 @@ func (s *Server) getStats(c *gin.Context) {
 // @Tags admin
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Success 200 {object} map[string]interface{}
 // @Failure 500 {object} map[string]string
 // @Router /admin/audit [get]
 func (s *Server) getAuditLog(c *gin.Context) {
 	// Get recent audit events (last 100)
-	events, err := s.eventService.ListEvents(100, 0, nil, nil, nil)
+	page, err := s.eventService.ListEvents(c.Request.Context(), domain.PageRequest{Limit: 100}, nil, nil, nil, nil)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get audit log"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"audit_log": events,
-		"total":     len(events),
+		"audit_log": page.Items,
+		"total":     len(page.Items),
 	})
 }
+
+// VerifyAuditChain godoc
+// @Summary Verify the audit log's hash chain
+// @Description Walk the hash chain of the event log from the first chained event up to the current head, recomputing each event's hash, and report the first broken link: a missing event, an event whose prev_hash is not its predecessor's hash, or one whose contents no longer match its hash. Events logged before the chain existed are counted as unchained_events.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.ChainVerification
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/audit/verify [get]
+func (s *Server) verifyAuditChain(c *gin.Context) {
+	if !requireService(c, s.auditService != nil, "audit chain") {
+		return
+	}
+	result, err := s.auditService.VerifyChain(c.Request.Context())
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExportAuditHead godoc
+// @Summary Export the signed head of the audit log's hash chain
+// @Description Get the sequence number and hash of the newest chained event, signed with the configured Ed25519 key. Storing the export outside the database anchors the chain: if the event at that seq later carries a different hash, the log was rewritten, even when every hash after it was recomputed. The signature covers payload exactly.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.SignedChainHead
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string "audit.signing_key is not set"
+// @Router /admin/audit/head [get]
+func (s *Server) exportAuditHead(c *gin.Context) {
+	if !requireService(c, s.auditService != nil, "audit chain") {
+		return
+	}
+	head, err := s.auditService.ExportChainHead(c.Request.Context())
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, head)
+}
+
+// ProjectionReportResponse compares the tools table with the state replayed from the event log.
+type ProjectionReportResponse struct {
+	ToolsChecked   int `json:"tools_checked"`
+	EventsReplayed int `json:"events_replayed"`
+	// Divergences lists the tools whose row disagrees with their events, or that have no events.
+	Divergences []domain.ToolDivergence `json:"divergences"`
+	// Repaired counts the divergences rewritten to the projected state.
+	Repaired    int       `json:"repaired"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+func newProjectionReportResponse(report service.ProjectionReport) ProjectionReportResponse {
+	return ProjectionReportResponse{
+		ToolsChecked:   report.ToolsChecked,
+		EventsReplayed: report.EventsReplayed,
+		Divergences:    report.Divergences,
+		Repaired:       report.Repaired,
+		GeneratedAt:    report.GeneratedAt,
+	}
+}
+
+// GetToolProjection godoc
+// @Summary Check tools against the event log
+// @Description Replay every tool event in order to rebuild each tool's status and current user, and list the tools whose row differs from that projection (or that have no events). Soft-deleted tools are not checked. Nothing is changed.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} ProjectionReportResponse
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/projection/tools [get]
+func (s *Server) getToolProjection(c *gin.Context) {
+	s.checkToolProjection(c, false)
+}
+
+// RepairToolProjection godoc
+// @Summary Repair tools from the event log
+// @Description Run the same check as GET /admin/projection/tools and rewrite each diverging tool to the projected status and current user. Every repair logs a TOOL_UPDATED event. A tool changed since it was checked is skipped and reports repair_error.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} ProjectionReportResponse
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/projection/tools/repair [post]
+func (s *Server) repairToolProjection(c *gin.Context) {
+	s.checkToolProjection(c, true)
+}
+
+func (s *Server) checkToolProjection(c *gin.Context, repair bool) {
+	if !requireService(c, s.projectionService != nil, "tool projection") {
+		return
+	}
+	report, err := s.projectionService.CheckTools(c.Request.Context(), repair, GetActorID(c))
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newProjectionReportResponse(report))
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/service"
+)
+
+func TestGetStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Days out of range", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/stats", NewServer(nil, nil, nil).WithStats(service.NewStatsService(nil, nil, nil)).getStats)
+
+		for _, days := range []string{"0", "366", "week"} {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats?days="+days, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, days)
+			assert.Contains(t, w.Body.String(), "days must be an integer between 1 and 365")
+		}
+	})
+
+	t.Run("Response lists every status, role and event type", func(t *testing.T) {
+		now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+		stats := service.Stats{
+			TotalTools:    6,
+			ToolsByStatus: map[domain.ToolStatus]int{domain.ToolStatusInOffice: 4, domain.ToolStatusLost: 2},
+			UsersByRole:   map[domain.UserRole]int{domain.UserRoleManager: 1},
+			Window:        7 * 24 * time.Hour,
+			Since:         now.Add(-7 * 24 * time.Hour),
+			EventsByType:  map[domain.EventType]int{domain.EventTypeToolCheckedOut: 3},
+			GeneratedAt:   now,
+		}
+
+		resp := newStatsResponse(stats)
+
+		assert.Equal(t, 4, resp.ToolsByStatus.InOffice)
+		assert.Equal(t, 2, resp.ToolsByStatus.Lost)
+		assert.Equal(t, 1, resp.UsersByRole.Managers)
+		assert.Equal(t, 7, resp.WindowDays)
+		assert.Len(t, resp.EventsByType, len(domain.ValidEventTypes()))
+		assert.Equal(t, 3, resp.EventsByType[string(domain.EventTypeToolCheckedOut)])
+		assert.Equal(t, 0, resp.EventsByType[string(domain.EventTypeToolLost)])
+	})
+}
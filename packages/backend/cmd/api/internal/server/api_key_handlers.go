@@ -0,0 +1,151 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreateAPIKeyResponse carries the plaintext key, which is only ever returned once.
+type CreateAPIKeyResponse struct {
+	APIKey domain.APIKey `json:"api_key"`
+	Key    string        `json:"key"`
+}
+
+// CreateAPIKey godoc
+// @Summary Create a personal API key
+// @Description Issue a new API key for the calling user; no one can create a key for another user. The plaintext key is only returned in this response.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param api_key body CreateAPIKeyRequest true "API key data"
+// @Success 201 {object} CreateAPIKeyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/{id}/api-keys [post]
+func (s *Server) createAPIKey(c *gin.Context) {
+	if !requireService(c, s.apiKeyService != nil, "api keys") {
+		return
+	}
+	userID := c.Param("id")
+	// A key authenticates as its user, so one issued by anyone else, admins included,
+	// would let them act as that user.
+	if u, ok := GetCurrentUser(c); !ok || u.ID != userID {
+		respondDomainError(c, fmt.Errorf("%w: api keys can only be created for yourself", domain.ErrForbidden))
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	for _, scope := range req.Scopes {
+		if !KnownPermission(Permission(scope)) {
+			respondDomainError(c, validationErr("scopes", fmt.Sprintf("contains unknown scope %q", scope)))
+			return
+		}
+	}
+	// A scoped key may only mint keys with a subset of its own scopes.
+	if caller, ok := GetCurrentAPIKey(c); ok && len(caller.Scopes) > 0 {
+		if len(req.Scopes) == 0 {
+			respondDomainError(c, fmt.Errorf("%w: scoped api keys cannot create unscoped keys", domain.ErrForbidden))
+			return
+		}
+		for _, scope := range req.Scopes {
+			if !caller.HasScope(scope) {
+				respondDomainError(c, fmt.Errorf("%w: scope %q exceeds the calling key's scopes", domain.ErrForbidden, scope))
+				return
+			}
+		}
+	}
+
+	if _, err := s.userService.GetUser(c.Request.Context(), userID); err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	key, plaintext, err := s.apiKeyService.CreateAPIKey(c.Request.Context(), userID, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreateAPIKeyResponse{APIKey: key, Key: plaintext})
+}
+
+// ListAPIKeys godoc
+// @Summary List a user's API keys
+// @Description List API keys for a user, including revoked and expired keys
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} map[string][]domain.APIKey
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /users/{id}/api-keys [get]
+func (s *Server) listAPIKeys(c *gin.Context) {
+	if !requireService(c, s.apiKeyService != nil, "api keys") {
+		return
+	}
+	userID := c.Param("id")
+	if err := s.authorizeSelfOr(c, userID, PermUsersWrite); err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	keys, err := s.apiKeyService.ListAPIKeys(c.Request.Context(), userID)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"api_keys": keys})
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Revoke one of a user's API keys. Revoked keys can no longer authenticate.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param keyId path string true "API key ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/{id}/api-keys/{keyId} [delete]
+func (s *Server) revokeAPIKey(c *gin.Context) {
+	if !requireService(c, s.apiKeyService != nil, "api keys") {
+		return
+	}
+	userID := c.Param("id")
+	if err := s.authorizeSelfOr(c, userID, PermUsersWrite); err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	if err := s.apiKeyService.RevokeAPIKey(c.Request.Context(), userID, c.Param("keyId")); err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/service"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	admin := domain.User{ID: testUserID, Role: domain.UserRoleAdmin}
+	s := NewServer(nil, nil, nil).WithAPIKeys(service.NewAPIKeyService(nil))
+	r := gin.New()
+	r.POST("/users/:id/api-keys", func(c *gin.Context) { c.Set(currentUserKey, admin) }, s.createAPIKey)
+
+	t.Run("Not even an admin can create a key for another user", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/123e4567-e89b-12d3-a456-426614174000/api-keys", strings.NewReader(`{"name":"ci"}`)))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "only be created for yourself")
+	})
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"crypto/rsa"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/service"
+)
+
+// AuthConfig controls how requests under /api are authenticated.
+type AuthConfig struct {
+	// Anonymous skips authentication entirely and attributes every action to
+	// SystemUserID. Intended for local development only.
+	Anonymous bool
+	// HMACSecret verifies HS256 tokens. HS256 is rejected when empty.
+	HMACSecret []byte
+	// RSAKeys verifies RS256 tokens, keyed by the token's "kid" header.
+	// A key stored under "" is used for tokens that carry no kid.
+	RSAKeys map[string]*rsa.PublicKey
+	// Issuer and Audience are checked against the iss/aud claims when set.
+	Issuer   string
+	Audience string
+}
+
+// validMethods lists the signing algorithms this config can verify.
+func (a AuthConfig) validMethods() []string {
+	var methods []string
+	if len(a.HMACSecret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(a.RSAKeys) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	return methods
+}
+
+// keyFunc resolves the verification key for a parsed (unverified) token.
+func (a AuthConfig) keyFunc(t *jwt.Token) (any, error) {
+	switch t.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return a.HMACSecret, nil
+	case *jwt.SigningMethodRSA:
+		kid, _ := t.Header["kid"].(string)
+		key, ok := a.RSAKeys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", t.Method.Alg())
+	}
+}
+
+// parseToken verifies the raw token and returns its subject claim.
+func (a AuthConfig) parseToken(raw string) (string, error) {
+	methods := a.validMethods()
+	if len(methods) == 0 {
+		return "", errors.New("no signing keys configured")
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+	}
+	if a.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(a.Issuer))
+	}
+	if a.Audience != "" {
+		opts = append(opts, jwt.WithAudience(a.Audience))
+	}
+
+	var claims jwt.RegisteredClaims
+	if _, err := jwt.ParseWithClaims(raw, &claims, a.keyFunc, opts...); err != nil {
+		return "", err
+	}
+	if claims.Subject == "" {
+		return "", errors.New("token has no subject")
+	}
+	return claims.Subject, nil
+}
+
+// ParseRSAKeySet parses a comma-separated list of "kid=path" pairs into a key set.
+// An entry without "kid=" is stored under the empty key id.
+func ParseRSAKeySet(spec string) (map[string]*rsa.PublicKey, error) {
+	keys := map[string]*rsa.PublicKey{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kid, path := "", entry
+		if i := strings.Index(entry, "="); i >= 0 {
+			kid, path = entry[:i], entry[i+1:]
+		}
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read RSA key %q: %w", kid, err)
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RSA key %q: %w", kid, err)
+		}
+		keys[kid] = key
+	}
+	return keys, nil
+}
+
+// authenticate validates the bearer token on each request and stores the
+// resolved domain.User in the context under currentUserKey. Tokens starting
+// with domain.APIKeyPrefix are treated as personal API keys instead of JWTs.
+func (s *Server) authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.auth.Anonymous {
+			c.Next()
+			return
+		}
+
+		raw, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok {
+			abortWithError(c, fmt.Errorf("%w: missing bearer token", domain.ErrUnauthorized))
+			return
+		}
+
+		var subject string
+		if strings.HasPrefix(raw, domain.APIKeyPrefix) && s.apiKeyService != nil {
+			key, err := s.apiKeyService.Authenticate(c.Request.Context(), raw)
+			if err != nil {
+				abortWithError(c, err)
+				return
+			}
+			c.Set(currentAPIKeyKey, key)
+			// also carry the key on the request context, so logged events record it
+			c.Request = c.Request.WithContext(service.ContextWithAPIKey(c.Request.Context(), key))
+			subject = key.UserID
+		} else {
+			var err error
+			subject, err = s.auth.parseToken(raw)
+			if err != nil {
+				abortWithError(c, fmt.Errorf("%w: invalid token", domain.ErrUnauthorized))
+				return
+			}
+		}
+
+		user, err := s.userService.GetUser(c.Request.Context(), subject)
+		if err != nil {
+			if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrValidation) {
+				err = fmt.Errorf("%w: unknown subject", domain.ErrUnauthorized)
+			}
+			abortWithError(c, err)
+			return
+		}
+
+		c.Set(currentUserKey, user)
+		c.Next()
+	}
+}
+
+// authenticateMetrics checks the bearer token of /metrics scrapes against the
+// configured metrics token; without one the endpoint is open.
+func (s *Server) authenticateMetrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.metricsToken == "" {
+			c.Next()
+			return
+		}
+		raw, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok || subtle.ConstantTimeCompare([]byte(raw), []byte(s.metricsToken)) != 1 {
+			abortWithError(c, fmt.Errorf("%w: invalid metrics token", domain.ErrUnauthorized))
+			return
+		}
+		c.Next()
+	}
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>" header.
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
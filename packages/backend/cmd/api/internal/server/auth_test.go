@@ -0,0 +1,151 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/service"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/service/mocks"
+)
+
+const (
+	testUserID = "456e7890-e89b-12d3-a456-426614174000"
+	testSecret = "test-secret"
+)
+
+// newAuthTestEngine wires the auth middleware in front of a handler that echoes the actor.
+func newAuthTestEngine(t *testing.T, cfg AuthConfig) (*gin.Engine, *mocks.MockUserRepo) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	userRepo := mocks.NewMockUserRepo(ctrl)
+
+	s := NewServer(nil, service.NewUserService(userRepo), nil).WithAuth(cfg)
+	r := gin.New()
+	r.GET("/api/whoami", s.authenticate(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"actor_id": GetActorID(c)})
+	})
+	return r, userRepo
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, key any, kid string, claims jwt.RegisteredClaims) string {
+	tok := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		tok.Header["kid"] = kid
+	}
+	raw, err := tok.SignedString(key)
+	require.NoError(t, err)
+	return raw
+}
+
+func validClaims() jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		Subject:   testUserID,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+}
+
+func doWhoAmI(r *gin.Engine, authHeader string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/whoami", nil)
+	if authHeader != "" {
+		req.Header.Set("Authorization", authHeader)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthenticate(t *testing.T) {
+	user := domain.User{ID: testUserID, Name: "Alice", Email: "alice@example.com", Role: domain.UserRoleEmployee}
+
+	t.Run("Valid HS256 token sets current user", func(t *testing.T) {
+		r, userRepo := newAuthTestEngine(t, AuthConfig{HMACSecret: []byte(testSecret)})
+		userRepo.EXPECT().Get(gomock.Any(), testUserID).Return(user, nil)
+
+		token := signToken(t, jwt.SigningMethodHS256, []byte(testSecret), "", validClaims())
+		w := doWhoAmI(r, "Bearer "+token)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), testUserID)
+	})
+
+	t.Run("Valid RS256 token is matched by kid", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		r, userRepo := newAuthTestEngine(t, AuthConfig{RSAKeys: map[string]*rsa.PublicKey{"k1": &key.PublicKey}})
+		userRepo.EXPECT().Get(gomock.Any(), testUserID).Return(user, nil)
+
+		token := signToken(t, jwt.SigningMethodRS256, key, "k1", validClaims())
+		w := doWhoAmI(r, "Bearer "+token)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Missing header is rejected", func(t *testing.T) {
+		r, _ := newAuthTestEngine(t, AuthConfig{HMACSecret: []byte(testSecret)})
+
+		w := doWhoAmI(r, "")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"unauthorized"`)
+	})
+
+	t.Run("Wrong signature is rejected", func(t *testing.T) {
+		r, _ := newAuthTestEngine(t, AuthConfig{HMACSecret: []byte(testSecret)})
+
+		token := signToken(t, jwt.SigningMethodHS256, []byte("other-secret"), "", validClaims())
+		w := doWhoAmI(r, "Bearer "+token)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Expired token is rejected", func(t *testing.T) {
+		r, _ := newAuthTestEngine(t, AuthConfig{HMACSecret: []byte(testSecret)})
+
+		claims := validClaims()
+		claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+		token := signToken(t, jwt.SigningMethodHS256, []byte(testSecret), "", claims)
+		w := doWhoAmI(r, "Bearer "+token)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("HS256 token is rejected when only RSA keys are configured", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		r, _ := newAuthTestEngine(t, AuthConfig{RSAKeys: map[string]*rsa.PublicKey{"": &key.PublicKey}})
+
+		token := signToken(t, jwt.SigningMethodHS256, []byte(testSecret), "", validClaims())
+		w := doWhoAmI(r, "Bearer "+token)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Unknown subject is rejected", func(t *testing.T) {
+		r, userRepo := newAuthTestEngine(t, AuthConfig{HMACSecret: []byte(testSecret)})
+		userRepo.EXPECT().Get(gomock.Any(), testUserID).Return(domain.User{}, domain.ErrUserNotFound)
+
+		token := signToken(t, jwt.SigningMethodHS256, []byte(testSecret), "", validClaims())
+		w := doWhoAmI(r, "Bearer "+token)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Anonymous mode falls back to the system user", func(t *testing.T) {
+		r, _ := newAuthTestEngine(t, AuthConfig{Anonymous: true})
+
+		w := doWhoAmI(r, "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), SystemUserID)
+	})
+}
@@ -1,18 +1,45 @@
 package server
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
 
 // SystemUserID is the fallback actor used when no authenticated user is present.
 // This ID is seeded into the database by a migration.
 const SystemUserID = "00000000-0000-0000-0000-000000000001"
 
+// Context keys the auth middleware stores the caller under.
+const (
+	currentUserKey   = "currentUser"
+	currentAPIKeyKey = "currentAPIKey"
+)
+
+// GetCurrentUser returns the authenticated user, if any.
+func GetCurrentUser(c *gin.Context) (domain.User, bool) {
+	if v, ok := c.Get(currentUserKey); ok {
+		if u, ok := v.(domain.User); ok && u.ID != "" {
+			return u, true
+		}
+	}
+	return domain.User{}, false
+}
+
 // GetActorID extracts the actor identifier from the request context.
-// Later, auth middleware should set a "currentUser" UUID string in the context.
+// Falls back to SystemUserID when running in anonymous mode.
 func GetActorID(c *gin.Context) string {
-	if v, ok := c.Get("currentUser"); ok {
-		if s, ok := v.(string); ok && s != "" {
-			return s
-		}
+	if u, ok := GetCurrentUser(c); ok {
+		return u.ID
 	}
 	return SystemUserID
 }
+
+// GetCurrentAPIKey returns the API key the request was authenticated with, if any.
+func GetCurrentAPIKey(c *gin.Context) (domain.APIKey, bool) {
+	if v, ok := c.Get(currentAPIKeyKey); ok {
+		if k, ok := v.(domain.APIKey); ok {
+			return k, true
+		}
+	}
+	return domain.APIKey{}, false
+}
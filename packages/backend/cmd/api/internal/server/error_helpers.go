@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -23,6 +24,18 @@ func respondDomainError(c *gin.Context, err error) {
 	case errors.Is(err, domain.ErrValidation):
 		status = http.StatusBadRequest
 		body = apiError{Code: "validation_error", Message: err.Error()}
+	case errors.Is(err, domain.ErrUnauthorized):
+		status = http.StatusUnauthorized
+		body = apiError{Code: "unauthorized", Message: err.Error()}
+	case errors.Is(err, domain.ErrForbidden):
+		status = http.StatusForbidden
+		body = apiError{Code: "forbidden", Message: err.Error()}
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		status = http.StatusPreconditionFailed
+		body = apiError{Code: "precondition_failed", Message: err.Error()}
+	case errors.Is(err, domain.ErrPreconditionRequired):
+		status = http.StatusPreconditionRequired
+		body = apiError{Code: "precondition_required", Message: err.Error()}
 	case errors.Is(err, domain.ErrConflict):
 		status = http.StatusConflict
 		body = apiError{Code: "conflict", Message: err.Error()}
@@ -32,14 +45,35 @@ func respondDomainError(c *gin.Context, err error) {
 	case errors.Is(err, domain.ErrUserNotFound):
 		status = http.StatusNotFound
 		body = apiError{Code: "user_not_found", Message: err.Error()}
+	case errors.Is(err, domain.ErrAPIKeyNotFound):
+		status = http.StatusNotFound
+		body = apiError{Code: "api_key_not_found", Message: err.Error()}
+	case errors.Is(err, domain.ErrReservationNotFound):
+		status = http.StatusNotFound
+		body = apiError{Code: "reservation_not_found", Message: err.Error()}
+	case errors.Is(err, domain.ErrJobNotFound):
+		status = http.StatusNotFound
+		body = apiError{Code: "job_not_found", Message: err.Error()}
 	case errors.Is(err, domain.ErrEventNotFound):
 		status = http.StatusNotFound
 		body = apiError{Code: "event_not_found", Message: err.Error()}
+	case errors.Is(err, domain.ErrNotConfigured):
+		status = http.StatusServiceUnavailable
+		body = apiError{Code: "not_configured", Message: err.Error()}
+	case errors.Is(err, context.DeadlineExceeded) || requestTimedOut(c):
+		status = http.StatusGatewayTimeout
+		body = apiError{Code: "timeout", Message: "request timed out"}
 	}
 
 	c.JSON(status, gin.H{"error": body})
 }
 
+// requestTimedOut reports whether the request's deadline has passed; drivers do not
+// always surface the context error itself when a query is cancelled.
+func requestTimedOut(c *gin.Context) bool {
+	return c.Request != nil && errors.Is(c.Request.Context().Err(), context.DeadlineExceeded)
+}
+
 // validationErr wraps domain.ErrValidation with a contextual message (field + detail).
 func validationErr(field, msg string) error {
 	cleanField := strings.TrimSpace(field)
@@ -48,3 +82,19 @@ func validationErr(field, msg string) error {
 	}
 	return fmt.Errorf("%w: %s %s", domain.ErrValidation, cleanField, msg)
 }
+
+// requireService responds 503 when the service behind a route was not passed to its
+// With* option, so a partly wired server fails the request instead of panicking.
+// Handlers return when it reports false.
+func requireService(c *gin.Context, configured bool, name string) bool {
+	if !configured {
+		respondDomainError(c, fmt.Errorf("%w: %s is not available", domain.ErrNotConfigured, name))
+	}
+	return configured
+}
+
+// abortWithError writes the error response and stops the handler chain (for middleware).
+func abortWithError(c *gin.Context, err error) {
+	respondDomainError(c, err)
+	c.Abort()
+}
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// setETag exposes a resource version as a strong ETag, e.g. "3".
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatchVersion returns the version the client expects from the If-Match header.
+// "*" and (unless If-Match is required) a missing header yield domain.AnyVersion.
+// Anything that is not a single ETag we issued can never match, so it fails the precondition.
+func (s *Server) ifMatchVersion(c *gin.Context) (int, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		if s.requireIfMatch {
+			return 0, fmt.Errorf("%w: If-Match header is required", domain.ErrPreconditionRequired)
+		}
+		return domain.AnyVersion, nil
+	}
+	if header == "*" {
+		return domain.AnyVersion, nil
+	}
+
+	unquoted, err := strconv.Unquote(header)
+	if err != nil {
+		return 0, fmt.Errorf("%w: If-Match must be a single ETag", domain.ErrPreconditionFailed)
+	}
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("%w: If-Match does not match any version", domain.ErrPreconditionFailed)
+	}
+	return version, nil
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		require bool
+		want    int
+		wantErr error
+	}{
+		{"missing header allowed", "", false, domain.AnyVersion, nil},
+		{"missing header required", "", true, 0, domain.ErrPreconditionRequired},
+		{"wildcard", "*", true, domain.AnyVersion, nil},
+		{"quoted version", `"3"`, true, 3, nil},
+		{"unquoted version", "3", false, 0, domain.ErrPreconditionFailed},
+		{"weak etag", `W/"3"`, false, 0, domain.ErrPreconditionFailed},
+		{"not a version", `"abc"`, false, 0, domain.ErrPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPut, "/api/tools/x", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("If-Match", tt.header)
+			}
+
+			got, err := NewServer(nil, nil, nil).WithRequireIfMatch(tt.require).ifMatchVersion(c)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSetETag(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	setETag(c, 4)
+
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+}
@@ -3,28 +3,44 @@ package server
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
 )
 
+// EventListResponse is one page of events, newest first.
+type EventListResponse struct {
+	Items []domain.Event `json:"items"`
+	ListMeta
+}
+
+func newEventListResponse(page domain.Page[domain.Event]) EventListResponse {
+	return EventListResponse{Items: page.Items, ListMeta: newListMeta(page)}
+}
+
 // ListEvents godoc
 // @Summary List all events
-// @Description Get a list of events with pagination and optional filtering
+// @Description Get a list of events with pagination and optional filtering. Metadata fields filter as metadata.<key>=value, e.g. metadata.vendor=Acme or metadata.condition=DAMAGED; keys are api_key_id, api_key_prefix, due_at, condition, vendor, cost_cents and reservation_id, or before.<field> and after.<field> to look into the tool or user snapshots (e.g. metadata.after.status=LOST).
 // @Tags events
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param limit query int false "Limit" default(50)
-// @Param offset query int false "Offset" default(0)
+// @Param cursor query string false "Cursor from next_cursor of the previous page. Best-effort: a row written by a transaction still open when the page was read can be skipped"
+// @Param offset query int false "Offset (deprecated, use cursor)" default(0)
 // @Param type query string false "Filter by event type"
 // @Param tool_id query string false "Filter by tool ID"
 // @Param user_id query string false "Filter by user ID"
-// @Success 200 {object} map[string][]domain.Event
+// @Param metadata.condition query string false "Filter by a metadata field (any metadata.<key> is accepted, see the description)"
+// @Success 200 {object} EventListResponse
+// @Header 200 {integer} X-Total-Count "Number of rows matching the filters"
 // @Failure 400 {object} map[string]string
 // @Router /events [get]
 func (s *Server) listEvents(c *gin.Context) {
 	limitStr := c.DefaultQuery("limit", "50")
 	offsetStr := c.DefaultQuery("offset", "0")
+	cursor := c.Query("cursor")
 	eventType := c.Query("type")
 	toolID := c.Query("tool_id")
 	userID := c.Query("user_id")
@@ -34,6 +50,7 @@ func (s *Server) listEvents(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
 		return
 	}
+	limit = s.capLimit(limit)
 
 	offset, err := strconv.Atoi(offsetStr)
 	if err != nil {
@@ -47,6 +64,13 @@ func (s *Server) listEvents(c *gin.Context) {
 		return
 	}
 
+	metadata := map[string]string{}
+	for key, values := range c.Request.URL.Query() {
+		if field, ok := strings.CutPrefix(key, "metadata."); ok && len(values) > 0 {
+			metadata[field] = values[0]
+		}
+	}
+
 	var eventTypePtr *string
 	var toolIDPtr *string
 	var userIDPtr *string
@@ -61,13 +85,15 @@ func (s *Server) listEvents(c *gin.Context) {
 		userIDPtr = &userID
 	}
 
-	events, err := s.eventService.ListEvents(limit, offset, eventTypePtr, toolIDPtr, userIDPtr)
+	req := domain.PageRequest{Limit: limit, Offset: offset, Cursor: cursor}
+	page, err := s.eventService.ListEvents(c.Request.Context(), req, eventTypePtr, toolIDPtr, userIDPtr, metadata)
 	if err != nil {
 		respondDomainError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"events": events})
+	setTotalCount(c, page.Total)
+	c.JSON(http.StatusOK, newEventListResponse(page))
 }
 
 // GetEvent godoc
@@ -76,6 +102,7 @@ func (s *Server) listEvents(c *gin.Context) {
 // @Tags events
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Event ID"
 // @Success 200 {object} domain.Event
 // @Failure 404 {object} map[string]string
@@ -83,7 +110,7 @@ func (s *Server) listEvents(c *gin.Context) {
 func (s *Server) getEvent(c *gin.Context) {
 	id := c.Param("id")
 
-	event, err := s.eventService.GetEvent(id)
+	event, err := s.eventService.GetEvent(c.Request.Context(), id)
 	if err != nil {
 		respondDomainError(c, err)
 		return
@@ -92,48 +119,96 @@ func (s *Server) getEvent(c *gin.Context) {
 	c.JSON(http.StatusOK, event)
 }
 
+// GetEventDiff godoc
+// @Summary Get what an event changed
+// @Description List the fields an event changed on its tool or user, from the snapshots recorded with it. Creates show every field coming from null and deletes every field going to null. Events that record no snapshot (overdue and reservation events, and older events) have no changes.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} domain.EventDiff
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /events/{id}/diff [get]
+func (s *Server) getEventDiff(c *gin.Context) {
+	diff, err := s.eventService.GetEventDiff(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
 // GetToolHistory godoc
 // @Summary Get tool history
-// @Description Get the complete event history for a specific tool
+// @Description Get the event history for a specific tool, newest first
 // @Tags tools
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Tool ID"
-// @Success 200 {object} map[string][]domain.Event
+// @Param limit query int false "Limit" default(1000)
+// @Param cursor query string false "Cursor from next_cursor of the previous page. Best-effort: a row written by a transaction still open when the page was read can be skipped"
+// @Success 200 {object} EventListResponse
+// @Header 200 {integer} X-Total-Count "Number of rows matching the filters"
+// @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /tools/{id}/history [get]
 func (s *Server) getToolHistory(c *gin.Context) {
 	toolID := c.Param("id")
 
-	events, err := s.eventService.GetToolHistory(toolID)
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "1000"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+
+	req := domain.PageRequest{Limit: limit, Cursor: c.Query("cursor")}
+	page, err := s.eventService.GetToolHistory(c.Request.Context(), toolID, req)
 	if err != nil {
 		respondDomainError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"events": events})
+	setTotalCount(c, page.Total)
+	c.JSON(http.StatusOK, newEventListResponse(page))
 }
 
 // GetUserActivity godoc
 // @Summary Get user activity
-// @Description Get the complete activity history for a specific user
+// @Description Get the activity history for a specific user, newest first
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "User ID"
-// @Success 200 {object} map[string][]domain.Event
+// @Param limit query int false "Limit" default(1000)
+// @Param cursor query string false "Cursor from next_cursor of the previous page. Best-effort: a row written by a transaction still open when the page was read can be skipped"
+// @Success 200 {object} EventListResponse
+// @Header 200 {integer} X-Total-Count "Number of rows matching the filters"
+// @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /users/{id}/activity [get]
 func (s *Server) getUserActivity(c *gin.Context) {
 	userID := c.Param("id")
 
-	events, err := s.eventService.GetUserActivity(userID)
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "1000"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+
+	req := domain.PageRequest{Limit: limit, Cursor: c.Query("cursor")}
+	page, err := s.eventService.GetUserActivity(c.Request.Context(), userID, req)
 	if err != nil {
 		respondDomainError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"events": events})
+	setTotalCount(c, page.Total)
+	c.JSON(http.StatusOK, newEventListResponse(page))
 }
 
 // GetUserTools godoc
@@ -142,6 +217,7 @@ func (s *Server) getUserActivity(c *gin.Context) {
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "User ID"
 // @Success 200 {object} map[string][]string
 // @Failure 500 {object} map[string]string
@@ -149,7 +225,7 @@ func (s *Server) getUserActivity(c *gin.Context) {
 func (s *Server) getUserTools(c *gin.Context) {
 	userID := c.Param("id")
 
-	checkedOutTools, err := s.toolService.ListToolsByUser(userID, 100, 0)
+	checkedOutTools, err := s.toolService.ListToolsByUser(c.Request.Context(), userID, 100, 0)
 	if err != nil {
 		respondDomainError(c, err)
 		return
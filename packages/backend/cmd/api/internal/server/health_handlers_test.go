@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	probe := func(s *Server, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		s.SetupRoutes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("Liveness ignores readiness checks", func(t *testing.T) {
+		s := NewServer(nil, nil, nil).WithReadiness(func(context.Context) error { return errors.New("db down") })
+
+		w := probe(s, "/healthz")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Ready when every check passes", func(t *testing.T) {
+		calls := 0
+		check := func(ctx context.Context) error {
+			_, ok := ctx.Deadline()
+			assert.True(t, ok)
+			calls++
+			return nil
+		}
+		s := NewServer(nil, nil, nil).WithReadiness(check).WithReadiness(check)
+
+		w := probe(s, "/readyz")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Failing check makes it unavailable", func(t *testing.T) {
+		s := NewServer(nil, nil, nil).WithReadiness(func(context.Context) error { return errors.New("2 pending migration(s)") })
+
+		w := probe(s, "/readyz")
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), "pending migration")
+	})
+
+	t.Run("Draining makes it unavailable", func(t *testing.T) {
+		s := NewServer(nil, nil, nil)
+		s.Drain()
+
+		assert.Equal(t, http.StatusServiceUnavailable, probe(s, "/readyz").Code)
+		assert.Equal(t, http.StatusOK, probe(s, "/healthz").Code)
+	})
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListJobs godoc
+// @Summary List background jobs
+// @Description List the registered background jobs with their schedule, next run and recent runs
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string][]jobs.JobInfo
+// @Failure 403 {object} map[string]string
+// @Router /admin/jobs [get]
+func (s *Server) listJobs(c *gin.Context) {
+	if !requireService(c, s.scheduler != nil, "job scheduler") {
+		return
+	}
+
+	infos, err := s.scheduler.Jobs(c.Request.Context())
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"jobs": infos})
+}
+
+// TriggerJob godoc
+// @Summary Run a background job now
+// @Description Start a run of the named job in the background. The run shows up in GET /admin/jobs.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Job name"
+// @Success 202 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/jobs/{name}/run [post]
+func (s *Server) triggerJob(c *gin.Context) {
+	if !requireService(c, s.scheduler != nil, "job scheduler") {
+		return
+	}
+
+	name := c.Param("name")
+
+	if err := s.scheduler.Trigger(name); err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Job triggered", "job": name})
+}
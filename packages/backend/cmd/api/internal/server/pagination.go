@@ -0,0 +1,43 @@
+package server
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// TotalCountHeader carries the number of rows matching a list request, for pagers.
+const TotalCountHeader = "X-Total-Count"
+
+// ListMeta describes the page a list response holds. Offset is set when the page was
+// requested by offset and Cursor when it was requested by cursor; NextCursor is set
+// when HasMore is.
+type ListMeta struct {
+	Total      int    `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     *int   `json:"offset,omitempty"`
+	Cursor     string `json:"cursor,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+func newListMeta[T any](page domain.Page[T]) ListMeta {
+	meta := ListMeta{
+		Total:      page.Total,
+		Limit:      page.Limit,
+		Cursor:     page.Cursor,
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+	}
+	if page.Cursor == "" {
+		offset := page.Offset
+		meta.Offset = &offset
+	}
+	return meta
+}
+
+// setTotalCount exposes the total of a list in the X-Total-Count header.
+func setTotalCount(c *gin.Context, total int) {
+	c.Header(TotalCountHeader, strconv.Itoa(total))
+}
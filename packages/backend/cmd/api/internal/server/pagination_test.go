@@ -0,0 +1,30 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+func TestNewListMeta(t *testing.T) {
+	t.Run("Offset page", func(t *testing.T) {
+		page := domain.Page[domain.Tool]{Items: []domain.Tool{}, Total: 42, Limit: 10, Offset: 0}
+
+		body, err := json.Marshal(ToolListResponse{Items: page.Items, ListMeta: newListMeta(page)})
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"items":[],"total":42,"limit":10,"offset":0,"has_more":false}`, string(body))
+	})
+
+	t.Run("Cursor page", func(t *testing.T) {
+		page := domain.Page[domain.Tool]{Items: []domain.Tool{}, Total: 42, Limit: 10, Cursor: "abc", NextCursor: "def", HasMore: true}
+
+		body, err := json.Marshal(ToolListResponse{Items: page.Items, ListMeta: newListMeta(page)})
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"items":[],"total":42,"limit":10,"cursor":"abc","next_cursor":"def","has_more":true}`, string(body))
+	})
+}
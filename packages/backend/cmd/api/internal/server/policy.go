@@ -0,0 +1,177 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// Permission names an action a role may perform.
+type Permission string
+
+const (
+	PermToolsRead        Permission = "tools:read"
+	PermToolsWrite       Permission = "tools:write"
+	PermToolsCheckout    Permission = "tools:checkout"
+	PermToolsCheckoutAny Permission = "tools:checkout:any"
+	PermToolsStatus      Permission = "tools:status"
+	PermUsersRead        Permission = "users:read"
+	PermUsersWrite       Permission = "users:write"
+	PermEventsRead       Permission = "events:read"
+	PermAPIKeysManage    Permission = "api_keys:manage"
+	PermReportsRead      Permission = "reports:read"
+	PermAdmin            Permission = "admin"
+)
+
+// rolePermissions is the policy table: which permissions each role holds.
+// Employees may only check tools out and in for themselves; managers may act
+// on behalf of others, change tool status and read reports; admins manage users
+// and /admin.
+var rolePermissions = map[domain.UserRole][]Permission{
+	domain.UserRoleEmployee: {
+		PermToolsRead, PermToolsCheckout, PermUsersRead, PermEventsRead, PermAPIKeysManage,
+	},
+	domain.UserRoleManager: {
+		PermToolsRead, PermToolsCheckout, PermUsersRead, PermEventsRead, PermAPIKeysManage,
+		PermToolsCheckoutAny, PermToolsStatus, PermToolsWrite, PermReportsRead,
+	},
+	domain.UserRoleAdmin: {
+		PermToolsRead, PermToolsCheckout, PermUsersRead, PermEventsRead, PermAPIKeysManage,
+		PermToolsCheckoutAny, PermToolsStatus, PermToolsWrite, PermReportsRead,
+		PermUsersWrite, PermAdmin,
+	},
+}
+
+// routePolicies maps every /api route, as registered with gin, to the permission it requires.
+// Routes missing from this table are denied.
+var routePolicies = map[string]Permission{
+	"GET /api/tools":                          PermToolsRead,
+	"POST /api/tools":                         PermToolsWrite,
+	"GET /api/tools/overdue":                  PermToolsRead,
+	"GET /api/tools/:id":                      PermToolsRead,
+	"PUT /api/tools/:id":                      PermToolsWrite,
+	"DELETE /api/tools/:id":                   PermToolsWrite,
+	"POST /api/tools/:id/restore":             PermToolsWrite,
+	"POST /api/tools/:id/checkout":            PermToolsCheckout,
+	"POST /api/tools/:id/checkin":             PermToolsCheckout,
+	"POST /api/tools/:id/maintenance":         PermToolsStatus,
+	"POST /api/tools/:id/lost":                PermToolsStatus,
+	"GET /api/tools/:id/history":              PermToolsRead,
+	"GET /api/tools/:id/reservations":         PermToolsRead,
+	"POST /api/tools/:id/reservations":        PermToolsCheckout,
+	"GET /api/users":                          PermUsersRead,
+	"POST /api/users":                         PermUsersWrite,
+	"GET /api/users/:id":                      PermUsersRead,
+	"PUT /api/users/:id":                      PermUsersWrite,
+	"DELETE /api/users/:id":                   PermUsersWrite,
+	"POST /api/users/:id/restore":             PermUsersWrite,
+	"GET /api/users/:id/activity":             PermUsersRead,
+	"GET /api/users/:id/tools":                PermUsersRead,
+	"GET /api/users/:id/reservations":         PermUsersRead,
+	"GET /api/users/:id/api-keys":             PermAPIKeysManage,
+	"POST /api/users/:id/api-keys":            PermAPIKeysManage,
+	"DELETE /api/users/:id/api-keys/:keyId":   PermAPIKeysManage,
+	"GET /api/reservations/:id":               PermToolsRead,
+	"POST /api/reservations/:id/cancel":       PermToolsCheckout,
+	"POST /api/reservations/:id/checkout":     PermToolsCheckout,
+	"GET /api/events":                         PermEventsRead,
+	"GET /api/events/:id":                     PermEventsRead,
+	"GET /api/events/:id/diff":                PermEventsRead,
+	"GET /api/reports/utilization":            PermReportsRead,
+	"GET /api/search":                         PermToolsRead,
+	"GET /api/admin/stats":                    PermAdmin,
+	"GET /api/admin/audit":                    PermAdmin,
+	"GET /api/admin/audit/verify":             PermAdmin,
+	"GET /api/admin/audit/head":               PermAdmin,
+	"GET /api/admin/jobs":                     PermAdmin,
+	"POST /api/admin/jobs/:name/run":          PermAdmin,
+	"GET /api/admin/projection/tools":         PermAdmin,
+	"POST /api/admin/projection/tools/repair": PermAdmin,
+}
+
+// RoleHasPermission reports whether the policy table grants perm to role.
+func RoleHasPermission(role domain.UserRole, perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// KnownPermission reports whether p names a permission; API key scopes must be known permissions.
+func KnownPermission(p Permission) bool {
+	for _, perms := range rolePermissions {
+		for _, known := range perms {
+			if known == p {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// can reports whether the caller holds perm. Anonymous mode allows everything.
+// Requests made with an API key are further limited to the key's scopes.
+func (s *Server) can(c *gin.Context, perm Permission) bool {
+	if s.auth.Anonymous {
+		return true
+	}
+	u, ok := GetCurrentUser(c)
+	if !ok || !RoleHasPermission(u.Role, perm) {
+		return false
+	}
+	if key, ok := GetCurrentAPIKey(c); ok && !key.HasScope(string(perm)) {
+		return false
+	}
+	return true
+}
+
+// authorize enforces routePolicies. It must run after authenticate.
+func (s *Server) authorize() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		perm, ok := routePolicies[c.Request.Method+" "+c.FullPath()]
+		if !ok || !s.can(c, perm) {
+			abortWithError(c, fmt.Errorf("%w: insufficient permissions", domain.ErrForbidden))
+			return
+		}
+		c.Next()
+	}
+}
+
+// includeDeleted reads the include_deleted query parameter. Only admins may see
+// soft-deleted records.
+func (s *Server) includeDeleted(c *gin.Context) (bool, error) {
+	raw := c.Query("include_deleted")
+	if raw == "" {
+		return false, nil
+	}
+	include, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, validationErr("include_deleted", "must be true or false")
+	}
+	if include && !s.can(c, PermAdmin) {
+		return false, fmt.Errorf("%w: include_deleted requires admin", domain.ErrForbidden)
+	}
+	return include, nil
+}
+
+// authorizeOnBehalfOf allows acting for userID when it is the caller,
+// or when the caller may act on behalf of others.
+func (s *Server) authorizeOnBehalfOf(c *gin.Context, userID string) error {
+	return s.authorizeSelfOr(c, userID, PermToolsCheckoutAny)
+}
+
+// authorizeSelfOr allows access to userID's resources when userID is the caller,
+// or when the caller holds perm.
+func (s *Server) authorizeSelfOr(c *gin.Context, userID string, perm Permission) error {
+	if s.can(c, perm) {
+		return nil
+	}
+	if u, ok := GetCurrentUser(c); ok && u.ID == userID {
+		return nil
+	}
+	return fmt.Errorf("%w: cannot act on behalf of another user", domain.ErrForbidden)
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// newPolicyTestEngine registers a stub handler for every route in routePolicies,
+// behind a fake authenticator that injects the given user.
+func newPolicyTestEngine(s *Server, user *domain.User) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if user != nil {
+			c.Set(currentUserKey, *user)
+		}
+	}, s.authorize())
+	for route := range routePolicies {
+		method, path, _ := strings.Cut(route, " ")
+		r.Handle(method, path, func(c *gin.Context) { c.Status(http.StatusOK) })
+	}
+	return r
+}
+
+func TestRoutePolicies_CoverEveryRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := NewServer(nil, nil, nil).SetupRoutes()
+
+	for _, route := range r.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/") {
+			continue
+		}
+		key := route.Method + " " + route.Path
+		_, ok := routePolicies[key]
+		assert.True(t, ok, "route %s has no policy", key)
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	employee := domain.User{ID: testUserID, Role: domain.UserRoleEmployee}
+	manager := domain.User{ID: testUserID, Role: domain.UserRoleManager}
+	admin := domain.User{ID: testUserID, Role: domain.UserRoleAdmin}
+
+	tests := []struct {
+		name   string
+		user   *domain.User
+		method string
+		path   string
+		want   int
+	}{
+		{"employee lists tools", &employee, http.MethodGet, "/api/tools", http.StatusOK},
+		{"employee checks out", &employee, http.MethodPost, "/api/tools/x/checkout", http.StatusOK},
+		{"employee checks in", &employee, http.MethodPost, "/api/tools/x/checkin", http.StatusOK},
+		{"employee cannot create tools", &employee, http.MethodPost, "/api/tools", http.StatusForbidden},
+		{"employee cannot update tools", &employee, http.MethodPut, "/api/tools/x", http.StatusForbidden},
+		{"employee cannot send to maintenance", &employee, http.MethodPost, "/api/tools/x/maintenance", http.StatusForbidden},
+		{"employee cannot mark lost", &employee, http.MethodPost, "/api/tools/x/lost", http.StatusForbidden},
+		{"employee cannot delete users", &employee, http.MethodDelete, "/api/users/x", http.StatusForbidden},
+		{"employee cannot read stats", &employee, http.MethodGet, "/api/admin/stats", http.StatusForbidden},
+		{"employee cannot read reports", &employee, http.MethodGet, "/api/reports/utilization", http.StatusForbidden},
+		{"manager sends to maintenance", &manager, http.MethodPost, "/api/tools/x/maintenance", http.StatusOK},
+		{"manager updates tools", &manager, http.MethodPut, "/api/tools/x", http.StatusOK},
+		{"manager reads reports", &manager, http.MethodGet, "/api/reports/utilization", http.StatusOK},
+		{"manager cannot create users", &manager, http.MethodPost, "/api/users", http.StatusForbidden},
+		{"manager cannot delete users", &manager, http.MethodDelete, "/api/users/x", http.StatusForbidden},
+		{"manager cannot read audit", &manager, http.MethodGet, "/api/admin/audit", http.StatusForbidden},
+		{"admin deletes users", &admin, http.MethodDelete, "/api/users/x", http.StatusOK},
+		{"manager restores tools", &manager, http.MethodPost, "/api/tools/x/restore", http.StatusOK},
+		{"manager cannot restore users", &manager, http.MethodPost, "/api/users/x/restore", http.StatusForbidden},
+		{"admin restores users", &admin, http.MethodPost, "/api/users/x/restore", http.StatusOK},
+		{"admin reads stats", &admin, http.MethodGet, "/api/admin/stats", http.StatusOK},
+		{"admin reads audit", &admin, http.MethodGet, "/api/admin/audit", http.StatusOK},
+		{"employee searches", &employee, http.MethodGet, "/api/search", http.StatusOK},
+		{"no user is denied", nil, http.MethodGet, "/api/tools", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newPolicyTestEngine(NewServer(nil, nil, nil), tt.user)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.want, w.Code)
+			if tt.want == http.StatusForbidden {
+				assert.Contains(t, w.Body.String(), `"code":"forbidden"`)
+			}
+		})
+	}
+}
+
+func TestAuthorize_AnonymousModeAllowsEverything(t *testing.T) {
+	s := NewServer(nil, nil, nil).WithAuth(AuthConfig{Anonymous: true})
+	r := newPolicyTestEngine(s, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/users/x", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthorizeOnBehalfOf(t *testing.T) {
+	const otherUserID = "789e0123-e89b-12d3-a456-426614174000"
+
+	tests := []struct {
+		name    string
+		role    domain.UserRole
+		target  string
+		allowed bool
+	}{
+		{"employee for self", domain.UserRoleEmployee, testUserID, true},
+		{"employee for other", domain.UserRoleEmployee, otherUserID, false},
+		{"manager for other", domain.UserRoleManager, otherUserID, true},
+		{"admin for other", domain.UserRoleAdmin, otherUserID, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Set(currentUserKey, domain.User{ID: testUserID, Role: tt.role})
+
+			err := NewServer(nil, nil, nil).authorizeOnBehalfOf(c, tt.target)
+
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, domain.ErrForbidden))
+			}
+		})
+	}
+}
+
+func TestCan_APIKeyScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    domain.UserRole
+		scopes  []string
+		perm    Permission
+		allowed bool
+	}{
+		{"unscoped key inherits role", domain.UserRoleManager, []string{}, PermToolsStatus, true},
+		{"scoped key within scope", domain.UserRoleManager, []string{"tools:read"}, PermToolsRead, true},
+		{"scoped key outside scope", domain.UserRoleManager, []string{"tools:read"}, PermToolsStatus, false},
+		{"scope cannot exceed role", domain.UserRoleEmployee, []string{"admin"}, PermAdmin, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Set(currentUserKey, domain.User{ID: testUserID, Role: tt.role})
+			c.Set(currentAPIKeyKey, domain.APIKey{UserID: testUserID, Scopes: tt.scopes})
+
+			assert.Equal(t, tt.allowed, NewServer(nil, nil, nil).can(c, tt.perm))
+		})
+	}
+}
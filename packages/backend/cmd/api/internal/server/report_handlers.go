@@ -0,0 +1,61 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// UtilizationResponse is a utilization report over [from, to), busiest first.
+type UtilizationResponse struct {
+	From    time.Time               `json:"from"`
+	To      time.Time               `json:"to"`
+	GroupBy domain.UtilizationGroup `json:"group_by"`
+	Rows    []domain.Utilization    `json:"rows"`
+}
+
+// GetUtilization godoc
+// @Summary Tool utilization report
+// @Description Derive per-tool (or per-borrower) usage from the event history over [from, to): share of time checked out, number of loans started, mean loan duration, and time in maintenance or lost. Durations are in seconds.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Window start (RFC3339), default 30 days before to"
+// @Param to query string false "Window end (RFC3339), default now"
+// @Param group_by query string false "Aggregate by tool or user" Enums(tool, user) default(tool)
+// @Success 200 {object} UtilizationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /reports/utilization [get]
+func (s *Server) getUtilization(c *gin.Context) {
+	if !requireService(c, s.utilizationService != nil, "utilization report") {
+		return
+	}
+	from, err := parseTimeQuery(c, "from")
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+	to, err := parseTimeQuery(c, "to")
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+	groupBy := domain.UtilizationGroup(c.DefaultQuery("group_by", string(domain.UtilizationByTool)))
+
+	report, err := s.utilizationService.Utilization(c.Request.Context(), from, to, groupBy)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, UtilizationResponse{
+		From:    report.From,
+		To:      report.To,
+		GroupBy: report.GroupBy,
+		Rows:    report.Rows,
+	})
+}
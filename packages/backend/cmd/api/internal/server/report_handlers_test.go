@@ -0,0 +1,28 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/service"
+)
+
+func TestGetUtilization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Window bounds must be RFC3339", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/utilization", NewServer(nil, nil, nil).WithReports(service.NewUtilizationService(nil)).getUtilization)
+
+		for _, query := range []string{"from=yesterday", "to=2025-06-01"} {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/utilization?"+query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+			assert.Contains(t, w.Body.String(), "must be an RFC3339 timestamp")
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/logging"
+)
+
+// RequestIDHeader carries the request ID in both directions: a caller (or proxy)
+// may supply one, and every response echoes the ID that was used.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds caller-supplied IDs so they cannot bloat every log line.
+const maxRequestIDLength = 128
+
+// requestID adopts the caller's X-Request-ID or generates one, echoes it on the
+// response and puts a logger tagged with it on the request context.
+func (s *Server) requestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+
+		logger := s.log().With("request_id", id)
+		c.Request = c.Request.WithContext(logging.NewContext(c.Request.Context(), logger))
+		c.Next()
+	}
+}
+
+// validRequestID accepts non-empty, bounded IDs of printable ASCII without spaces.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r <= ' ' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// accessLog writes one line per request with its outcome, latency and caller.
+// It replaces gin.Logger so access logs share the format and request_id of everything else.
+func (s *Server) accessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		attrs := []any{
+			"method", c.Request.Method,
+			"route", route,
+			"path", c.Request.URL.Path,
+			"status", status,
+			"latency_ms", time.Since(start).Milliseconds(),
+			"client_ip", c.ClientIP(),
+		}
+		if u, ok := GetCurrentUser(c); ok {
+			attrs = append(attrs, "actor_id", u.ID)
+		}
+		if k, ok := GetCurrentAPIKey(c); ok {
+			attrs = append(attrs, "api_key_id", k.ID)
+		}
+		if id, ok := toolIDParam(c); ok {
+			attrs = append(attrs, "tool_id", id)
+		}
+
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		logging.FromContext(c.Request.Context()).Log(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// toolIDParam returns the :id of a /api/tools/:id route, which names a tool.
+func toolIDParam(c *gin.Context) (string, bool) {
+	if !strings.HasPrefix(c.FullPath(), "/api/tools/:id") {
+		return "", false
+	}
+	return c.Param("id"), true
+}
+
+// instrument records each request's latency by route and status.
+func (s *Server) instrument() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		s.metrics.ObserveHTTP(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
+
+// recovery turns a panic into a 500 and logs it with the request's logger.
+func (s *Server) recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, p any) {
+		logging.FromContext(c.Request.Context()).Error("panic recovered",
+			"error", fmt.Sprint(p),
+			"stack", string(debug.Stack()),
+		)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": apiError{Code: "internal_error", Message: "internal error"}})
+	})
+}
+
+// log returns the configured logger, or slog.Default() when none was set.
+func (s *Server) log() *slog.Logger {
+	if s.logger != nil {
+		return s.logger
+	}
+	return slog.Default()
+}
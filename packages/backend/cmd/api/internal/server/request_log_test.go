@@ -0,0 +1,149 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/logging"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/metrics"
+)
+
+func TestRequestLogging(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	setup := func(t *testing.T) (*gin.Engine, *bytes.Buffer) {
+		var buf bytes.Buffer
+		logger, err := logging.New(&buf, logging.FormatJSON, "info")
+		require.NoError(t, err)
+		s := NewServer(nil, nil, nil).WithLogger(logger)
+
+		r := gin.New()
+		r.Use(s.requestID(), s.accessLog(), s.recovery())
+		r.GET("/api/tools/:id", func(c *gin.Context) {
+			logging.FromContext(c.Request.Context()).Info("handler")
+			c.Status(http.StatusOK)
+		})
+		r.GET("/panic", func(c *gin.Context) { panic("boom") })
+		return r, &buf
+	}
+
+	// lines decodes the JSON log lines written so far.
+	lines := func(t *testing.T, buf *bytes.Buffer) []map[string]any {
+		var out []map[string]any
+		for _, raw := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var line map[string]any
+			require.NoError(t, json.Unmarshal([]byte(raw), &line))
+			out = append(out, line)
+		}
+		return out
+	}
+
+	t.Run("Generates an ID and tags every line with it", func(t *testing.T) {
+		r, buf := setup(t)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tools/t1", nil))
+
+		id := w.Header().Get(RequestIDHeader)
+		assert.Len(t, id, 32)
+		logged := lines(t, buf)
+		require.Len(t, logged, 2)
+		assert.Equal(t, "handler", logged[0]["msg"])
+		assert.Equal(t, id, logged[0]["request_id"])
+		assert.Equal(t, "request", logged[1]["msg"])
+		assert.Equal(t, id, logged[1]["request_id"])
+		assert.Equal(t, "/api/tools/:id", logged[1]["route"])
+		assert.Equal(t, "t1", logged[1]["tool_id"])
+		assert.EqualValues(t, http.StatusOK, logged[1]["status"])
+		assert.Contains(t, logged[1], "latency_ms")
+	})
+
+	t.Run("Adopts the caller's ID", func(t *testing.T) {
+		r, buf := setup(t)
+		req := httptest.NewRequest(http.MethodGet, "/api/tools/t1", nil)
+		req.Header.Set(RequestIDHeader, "upstream-123")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, "upstream-123", w.Header().Get(RequestIDHeader))
+		assert.Equal(t, "upstream-123", lines(t, buf)[1]["request_id"])
+	})
+
+	t.Run("Replaces an unusable ID", func(t *testing.T) {
+		r, _ := setup(t)
+		req := httptest.NewRequest(http.MethodGet, "/api/tools/t1", nil)
+		req.Header.Set(RequestIDHeader, "has spaces\tand tabs")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Len(t, w.Header().Get(RequestIDHeader), 32)
+	})
+
+	t.Run("Panics are logged and answered with 500", func(t *testing.T) {
+		r, buf := setup(t)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		logged := lines(t, buf)
+		require.Len(t, logged, 2)
+		assert.Equal(t, "panic recovered", logged[0]["msg"])
+		assert.Equal(t, "boom", logged[0]["error"])
+		assert.Equal(t, "ERROR", logged[1]["level"])
+	})
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Requests are observed by route pattern", func(t *testing.T) {
+		r := NewServer(nil, nil, nil).WithMetrics(metrics.New()).SetupRoutes()
+
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nowhere", nil))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		assert.Contains(t, body, `tooltracker_http_request_duration_seconds_count{method="GET",route="/healthz",status="200"} 1`)
+		assert.Contains(t, body, `route="unmatched",status="404"`)
+		assert.NotContains(t, body, "/nowhere")
+	})
+
+	t.Run("Token is required when set", func(t *testing.T) {
+		r := NewServer(nil, nil, nil).WithMetrics(metrics.New()).WithMetricsToken("scrape-secret").SetupRoutes()
+
+		for header, want := range map[string]int{
+			"":                     http.StatusUnauthorized,
+			"Bearer wrong":         http.StatusUnauthorized,
+			"Bearer scrape-secret": http.StatusOK,
+		} {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if header != "" {
+				req.Header.Set("Authorization", header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, want, w.Code, header)
+		}
+	})
+
+	t.Run("Not served without metrics", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewServer(nil, nil, nil).SetupRoutes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,271 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreateReservationRequest struct {
+	UserID   string    `json:"user_id" binding:"required"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+	Notes    string    `json:"notes"`
+}
+
+type ReservationActionRequest struct {
+	Notes string `json:"notes"`
+}
+
+// CreateReservation godoc
+// @Summary Reserve a tool
+// @Description Book a tool for a user over [starts_at, ends_at). Fails with 409 when the window overlaps another active reservation, or when the tool is checked out to someone else and the window has already started.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tool ID"
+// @Param reservation body CreateReservationRequest true "Reservation data"
+// @Success 201 {object} domain.Reservation
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /tools/{id}/reservations [post]
+func (s *Server) createReservation(c *gin.Context) {
+	if !requireService(c, s.reservationService != nil, "reservations") {
+		return
+	}
+	toolID := c.Param("id")
+	var req CreateReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondDomainError(c, validationErr("", err.Error()))
+		return
+	}
+
+	if err := s.authorizeOnBehalfOf(c, req.UserID); err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	actor := GetActorID(c)
+	res, err := s.reservationService.CreateReservation(c.Request.Context(), toolID, req.UserID, req.StartsAt, req.EndsAt, actor, req.Notes)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+// ListToolReservations godoc
+// @Summary Get a tool's booking calendar
+// @Description List reservations of a tool (any status) overlapping the optional [from, to) window, ordered by start
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tool ID"
+// @Param from query string false "Window start (RFC3339)"
+// @Param to query string false "Window end (RFC3339)"
+// @Success 200 {object} map[string][]domain.Reservation
+// @Failure 400 {object} map[string]string
+// @Router /tools/{id}/reservations [get]
+func (s *Server) listToolReservations(c *gin.Context) {
+	if !requireService(c, s.reservationService != nil, "reservations") {
+		return
+	}
+	toolID := c.Param("id")
+
+	from, err := parseTimeQuery(c, "from")
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+	to, err := parseTimeQuery(c, "to")
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	reservations, err := s.reservationService.ListToolReservations(c.Request.Context(), toolID, from, to)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reservations": reservations})
+}
+
+// ListUserReservations godoc
+// @Summary Get a user's reservations
+// @Description List reservations held by a user, most recent start first
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} map[string][]domain.Reservation
+// @Failure 400 {object} map[string]string
+// @Router /users/{id}/reservations [get]
+func (s *Server) listUserReservations(c *gin.Context) {
+	if !requireService(c, s.reservationService != nil, "reservations") {
+		return
+	}
+	userID := c.Param("id")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		respondDomainError(c, validationErr("limit", "must be an integer"))
+		return
+	}
+	limit = s.capLimit(limit)
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		respondDomainError(c, validationErr("offset", "must be an integer"))
+		return
+	}
+
+	reservations, err := s.reservationService.ListUserReservations(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reservations": reservations})
+}
+
+// GetReservation godoc
+// @Summary Get a reservation
+// @Description Get a single reservation by ID
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Reservation ID"
+// @Success 200 {object} domain.Reservation
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /reservations/{id} [get]
+func (s *Server) getReservation(c *gin.Context) {
+	if !requireService(c, s.reservationService != nil, "reservations") {
+		return
+	}
+	res, err := s.reservationService.GetReservation(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// CancelReservation godoc
+// @Summary Cancel a reservation
+// @Description Cancel an active reservation. Only its holder or a manager may cancel it.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Reservation ID"
+// @Param cancel body ReservationActionRequest false "Optional notes"
+// @Success 200 {object} domain.Reservation
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /reservations/{id}/cancel [post]
+func (s *Server) cancelReservation(c *gin.Context) {
+	if !requireService(c, s.reservationService != nil, "reservations") {
+		return
+	}
+	id := c.Param("id")
+	req, ok := s.bindReservationAction(c, id)
+	if !ok {
+		return
+	}
+
+	actor := GetActorID(c)
+	res, err := s.reservationService.CancelReservation(c.Request.Context(), id, actor, req.Notes)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// ConvertReservation godoc
+// @Summary Check out a reserved tool
+// @Description Convert an active reservation into a checkout of its tool to the reservation's holder. Only its holder or a manager may convert it.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Reservation ID"
+// @Param checkout body ReservationActionRequest false "Optional notes"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /reservations/{id}/checkout [post]
+func (s *Server) convertReservation(c *gin.Context) {
+	if !requireService(c, s.reservationService != nil, "reservations") {
+		return
+	}
+	id := c.Param("id")
+	req, ok := s.bindReservationAction(c, id)
+	if !ok {
+		return
+	}
+
+	actor := GetActorID(c)
+	tool, err := s.reservationService.ConvertToCheckout(c.Request.Context(), id, actor, req.Notes)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	setETag(c, tool.Version)
+	c.JSON(http.StatusOK, gin.H{"message": "Tool checked out successfully", "tool": tool})
+}
+
+// bindReservationAction reads the optional action body and checks that the caller
+// may act for the reservation's holder. It writes the error response itself.
+func (s *Server) bindReservationAction(c *gin.Context, id string) (ReservationActionRequest, bool) {
+	var req ReservationActionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondDomainError(c, validationErr("", err.Error()))
+			return req, false
+		}
+	}
+
+	res, err := s.reservationService.GetReservation(c.Request.Context(), id)
+	if err != nil {
+		respondDomainError(c, err)
+		return req, false
+	}
+	if err := s.authorizeOnBehalfOf(c, res.UserID); err != nil {
+		respondDomainError(c, err)
+		return req, false
+	}
+	return req, true
+}
+
+// parseTimeQuery parses an optional RFC3339 query parameter.
+func parseTimeQuery(c *gin.Context, name string) (*time.Time, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, validationErr(name, "must be an RFC3339 timestamp")
+	}
+	return &t, nil
+}
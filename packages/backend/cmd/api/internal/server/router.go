@@ -1,19 +1,60 @@
 package server
 
 import (
-	"net/http"
+	"context"
+	"log/slog"
+	"slices"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/jobs"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/metrics"
 	"github.com/wassaaa/tool-tracker/cmd/api/internal/service"
 )
 
 type Server struct {
-	toolService  *service.ToolService
-	userService  *service.UserService
-	eventService *service.EventService
+	toolService   *service.ToolService
+	userService   *service.UserService
+	eventService  *service.EventService
+	apiKeyService *service.APIKeyService
+	// reservationService backs the booking endpoints.
+	reservationService *service.ReservationService
+	// statsService backs /admin/stats.
+	statsService *service.StatsService
+	// utilizationService backs /reports/utilization.
+	utilizationService *service.UtilizationService
+	// searchService backs /search.
+	searchService *service.SearchService
+	// projectionService backs /admin/projection.
+	projectionService *service.ProjectionService
+	// auditService backs /admin/audit/verify and /admin/audit/head.
+	auditService *service.AuditService
+	// scheduler backs the /admin/jobs endpoints.
+	scheduler *jobs.Scheduler
+	auth      AuthConfig
+	// requireIfMatch rejects writes without an If-Match header (428) instead of applying them unconditionally.
+	requireIfMatch bool
+	// requestTimeout bounds the context (and so every query) of each /api request; 0 disables it.
+	requestTimeout time.Duration
+	// readiness checks back /readyz; draining fails it while the process shuts down.
+	readiness []ReadinessCheck
+	draining  atomic.Bool
+	// corsOrigins are the browser origins allowed to call the API; "*" allows any, none disables CORS.
+	corsOrigins []string
+	// swagger serves the API docs under /swagger.
+	swagger bool
+	// maxLimit caps the limit query parameter of list endpoints; 0 leaves it uncapped.
+	maxLimit int
+	// logger is the base of every request's logger; nil means slog.Default().
+	logger *slog.Logger
+	// metrics, when set, records request latency and is served on /metrics.
+	metrics *metrics.Metrics
+	// metricsToken, when set, is the bearer token /metrics requires.
+	metricsToken string
 }
 
 func NewServer(
@@ -28,34 +69,197 @@ func NewServer(
 	}
 }
 
+// WithAuth sets the authentication config used by the /api middleware (optional chaining style).
+func (s *Server) WithAuth(cfg AuthConfig) *Server {
+	s.auth = cfg
+	return s
+}
+
+// WithAPIKeys enables personal API key authentication (optional chaining style).
+func (s *Server) WithAPIKeys(svc *service.APIKeyService) *Server {
+	s.apiKeyService = svc
+	return s
+}
+
+// WithReservations enables the reservation and booking calendar endpoints (optional chaining style).
+func (s *Server) WithReservations(svc *service.ReservationService) *Server {
+	s.reservationService = svc
+	return s
+}
+
+// WithStats backs the /admin/stats endpoint (optional chaining style).
+func (s *Server) WithStats(svc *service.StatsService) *Server {
+	s.statsService = svc
+	return s
+}
+
+// WithReports backs the /reports endpoints (optional chaining style).
+func (s *Server) WithReports(svc *service.UtilizationService) *Server {
+	s.utilizationService = svc
+	return s
+}
+
+// WithSearch backs the /search endpoint (optional chaining style).
+func (s *Server) WithSearch(svc *service.SearchService) *Server {
+	s.searchService = svc
+	return s
+}
+
+// WithProjection backs the /admin/projection endpoints (optional chaining style).
+func (s *Server) WithProjection(svc *service.ProjectionService) *Server {
+	s.projectionService = svc
+	return s
+}
+
+// WithAudit backs the audit chain endpoints under /admin/audit (optional chaining style).
+func (s *Server) WithAudit(svc *service.AuditService) *Server {
+	s.auditService = svc
+	return s
+}
+
+// WithJobs exposes the background job scheduler under /admin/jobs (optional chaining style).
+func (s *Server) WithJobs(scheduler *jobs.Scheduler) *Server {
+	s.scheduler = scheduler
+	return s
+}
+
+// WithRequireIfMatch controls whether writes must carry an If-Match header (optional chaining style).
+func (s *Server) WithRequireIfMatch(require bool) *Server {
+	s.requireIfMatch = require
+	return s
+}
+
+// WithRequestTimeout sets the per-request deadline for /api routes (optional chaining style).
+func (s *Server) WithRequestTimeout(d time.Duration) *Server {
+	s.requestTimeout = d
+	return s
+}
+
+// WithReadiness adds a check that /readyz runs on every probe (optional chaining style).
+func (s *Server) WithReadiness(check ReadinessCheck) *Server {
+	s.readiness = append(s.readiness, check)
+	return s
+}
+
+// Drain makes /readyz fail so load balancers stop routing new traffic before shutdown.
+func (s *Server) Drain() {
+	s.draining.Store(true)
+}
+
+// WithCORS allows cross-origin requests from the given origins (optional chaining style).
+func (s *Server) WithCORS(origins []string) *Server {
+	s.corsOrigins = origins
+	return s
+}
+
+// WithSwagger controls whether the API docs are served under /swagger (optional chaining style).
+func (s *Server) WithSwagger(enabled bool) *Server {
+	s.swagger = enabled
+	return s
+}
+
+// WithMaxLimit caps the page size of list endpoints (optional chaining style).
+func (s *Server) WithMaxLimit(n int) *Server {
+	s.maxLimit = n
+	return s
+}
+
+// WithLogger sets the logger request logs are written to (optional chaining style).
+func (s *Server) WithLogger(logger *slog.Logger) *Server {
+	s.logger = logger
+	return s
+}
+
+// WithMetrics instruments requests and serves the Prometheus metrics on /metrics (optional chaining style).
+func (s *Server) WithMetrics(m *metrics.Metrics) *Server {
+	s.metrics = m
+	return s
+}
+
+// WithMetricsToken requires "Authorization: Bearer <token>" on /metrics (optional chaining style).
+func (s *Server) WithMetricsToken(token string) *Server {
+	s.metricsToken = token
+	return s
+}
+
+// capLimit clamps a requested page size to the configured maximum.
+func (s *Server) capLimit(limit int) int {
+	if s.maxLimit > 0 && limit > s.maxLimit {
+		return s.maxLimit
+	}
+	return limit
+}
+
+// cors builds the CORS middleware, or returns nil when no origin is allowed.
+func (s *Server) cors() gin.HandlerFunc {
+	if len(s.corsOrigins) == 0 {
+		return nil
+	}
+	config := cors.DefaultConfig()
+	if slices.Contains(s.corsOrigins, "*") {
+		config.AllowAllOrigins = true
+	} else {
+		config.AllowOrigins = s.corsOrigins
+	}
+	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "Accept", "If-Match", RequestIDHeader}
+	config.ExposeHeaders = []string{"ETag", RequestIDHeader, TotalCountHeader}
+	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+	return cors.New(config)
+}
+
+// timeout attaches the configured deadline to the request context. Services and
+// repos receive that context, so slow queries are cancelled once it expires.
+func (s *Server) timeout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.requestTimeout <= 0 {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), s.requestTimeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
 func (s *Server) SetupRoutes() *gin.Engine {
 	r := gin.New()
-	r.Use(gin.Logger(), gin.Recovery())
+	r.Use(s.requestID(), s.trace(), s.accessLog(), s.recovery())
+	if s.metrics != nil {
+		r.Use(s.instrument())
+	}
 
 	// CORS middleware
-	config := cors.DefaultConfig()
-	config.AllowAllOrigins = true
-	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "Accept"}
-	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	r.Use(cors.New(config))
+	if mw := s.cors(); mw != nil {
+		r.Use(mw)
+	}
 
 	// Swagger documentation
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	if s.swagger {
+		r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	}
+
+	// Probes
+	r.GET("/healthz", s.healthz)
+	r.GET("/readyz", s.readyz)
 
-	r.GET("/ping", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"message": "pong"})
-	})
+	// Prometheus scrape endpoint
+	if s.metrics != nil {
+		r.GET("/metrics", s.authenticateMetrics(), gin.WrapH(s.metrics.Handler()))
+	}
 
-	api := r.Group("/api")
+	api := r.Group("/api", s.timeout(), s.authenticate(), s.authorize())
 	{
 		// Tools (CRUD)
 		tools := api.Group("/tools")
 		{
 			tools.GET("", s.listTools)
 			tools.POST("", s.createTool)
+			tools.GET("/overdue", s.listOverdueTools)
 			tools.GET("/:id", s.getTool)
 			tools.PUT("/:id", s.updateTool)
 			tools.DELETE("/:id", s.deleteTool)
+			tools.POST("/:id/restore", s.restoreTool)
 
 			// Tool Actions (Events)
 			tools.POST("/:id/checkout", s.checkoutTool)
@@ -65,6 +269,10 @@ func (s *Server) SetupRoutes() *gin.Engine {
 
 			// Tool History
 			tools.GET("/:id/history", s.getToolHistory)
+
+			// Booking calendar
+			tools.GET("/:id/reservations", s.listToolReservations)
+			tools.POST("/:id/reservations", s.createReservation)
 		}
 
 		// Users (CRUD)
@@ -75,10 +283,25 @@ func (s *Server) SetupRoutes() *gin.Engine {
 			users.GET("/:id", s.getUser)
 			users.PUT("/:id", s.updateUser)
 			users.DELETE("/:id", s.deleteUser)
+			users.POST("/:id/restore", s.restoreUser)
 
 			// User Activity
 			users.GET("/:id/activity", s.getUserActivity)
 			users.GET("/:id/tools", s.getUserTools)
+			users.GET("/:id/reservations", s.listUserReservations)
+
+			// Personal API keys
+			users.GET("/:id/api-keys", s.listAPIKeys)
+			users.POST("/:id/api-keys", s.createAPIKey)
+			users.DELETE("/:id/api-keys/:keyId", s.revokeAPIKey)
+		}
+
+		// Reservations
+		reservations := api.Group("/reservations")
+		{
+			reservations.GET("/:id", s.getReservation)
+			reservations.POST("/:id/cancel", s.cancelReservation)
+			reservations.POST("/:id/checkout", s.convertReservation)
 		}
 
 		// Events/Audit Log
@@ -86,13 +309,29 @@ func (s *Server) SetupRoutes() *gin.Engine {
 		{
 			events.GET("", s.listEvents)
 			events.GET("/:id", s.getEvent)
+			events.GET("/:id/diff", s.getEventDiff)
 		}
 
+		// Reports
+		reports := api.Group("/reports")
+		{
+			reports.GET("/utilization", s.getUtilization)
+		}
+
+		// Search
+		api.GET("/search", s.search)
+
 		// Admin routes
 		admin := api.Group("/admin")
 		{
 			admin.GET("/stats", s.getStats)
 			admin.GET("/audit", s.getAuditLog)
+			admin.GET("/audit/verify", s.verifyAuditChain)
+			admin.GET("/audit/head", s.exportAuditHead)
+			admin.GET("/jobs", s.listJobs)
+			admin.POST("/jobs/:name/run", s.triggerJob)
+			admin.GET("/projection/tools", s.getToolProjection)
+			admin.POST("/projection/tools/repair", s.repairToolProjection)
 		}
 	}
 	return r
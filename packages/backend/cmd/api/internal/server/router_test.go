@@ -0,0 +1,121 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Attaches a deadline to the request context", func(t *testing.T) {
+		r := gin.New()
+		r.Use(NewServer(nil, nil, nil).WithRequestTimeout(time.Minute).timeout())
+		r.GET("/", func(c *gin.Context) {
+			_, ok := c.Request.Context().Deadline()
+			assert.True(t, ok)
+			c.Status(http.StatusOK)
+		})
+
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+
+	t.Run("Expired deadline maps to 504", func(t *testing.T) {
+		r := gin.New()
+		r.Use(NewServer(nil, nil, nil).WithRequestTimeout(time.Millisecond).timeout())
+		r.GET("/", func(c *gin.Context) {
+			<-c.Request.Context().Done()
+			respondDomainError(c, fmt.Errorf("failed to list tools: %w", c.Request.Context().Err()))
+		})
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"timeout"`)
+	})
+
+	t.Run("Zero disables the deadline", func(t *testing.T) {
+		r := gin.New()
+		r.Use(NewServer(nil, nil, nil).timeout())
+		r.GET("/", func(c *gin.Context) {
+			_, ok := c.Request.Context().Deadline()
+			assert.False(t, ok)
+			c.Status(http.StatusOK)
+		})
+
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	preflight := func(s *Server, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/healthz", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		w := httptest.NewRecorder()
+		s.SetupRoutes().ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Disabled without origins", func(t *testing.T) {
+		w := preflight(NewServer(nil, nil, nil), "https://a.example.com")
+
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("Allows only listed origins", func(t *testing.T) {
+		s := NewServer(nil, nil, nil).WithCORS([]string{"https://a.example.com"})
+
+		assert.Equal(t, "https://a.example.com", preflight(s, "https://a.example.com").Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, preflight(s, "https://evil.example.com").Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("Wildcard allows any origin", func(t *testing.T) {
+		s := NewServer(nil, nil, nil).WithCORS([]string{"*"})
+
+		assert.Equal(t, "*", preflight(s, "https://evil.example.com").Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+// TestRoutesWithoutServices tests that the routes of optional services answer 503
+// instead of panicking when the service was not wired
+func TestRoutesWithoutServices(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := NewServer(nil, nil, nil).WithAuth(AuthConfig{Anonymous: true}).SetupRoutes()
+
+	for _, route := range []struct{ method, path string }{
+		{http.MethodGet, "/api/admin/stats"},
+		{http.MethodGet, "/api/reports/utilization"},
+		{http.MethodGet, "/api/search?q=drill"},
+		{http.MethodGet, "/api/admin/projection/tools"},
+		{http.MethodPost, "/api/admin/projection/tools/repair"},
+		{http.MethodGet, "/api/admin/audit/verify"},
+		{http.MethodGet, "/api/admin/audit/head"},
+		{http.MethodGet, "/api/tools/x/reservations"},
+		{http.MethodPost, "/api/tools/x/reservations"},
+		{http.MethodGet, "/api/users/x/reservations"},
+		{http.MethodGet, "/api/reservations/x"},
+		{http.MethodPost, "/api/reservations/x/cancel"},
+		{http.MethodPost, "/api/reservations/x/checkout"},
+		{http.MethodGet, "/api/users/x/api-keys"},
+		{http.MethodPost, "/api/users/x/api-keys"},
+		{http.MethodDelete, "/api/users/x/api-keys/y"},
+		{http.MethodGet, "/api/admin/jobs"},
+		{http.MethodPost, "/api/admin/jobs/x/run"},
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(route.method, route.path, nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code, route.path)
+		assert.Contains(t, w.Body.String(), "not_configured", route.path)
+	}
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// SearchResponse lists search hits, best match first.
+type SearchResponse struct {
+	Query string             `json:"query"`
+	Hits  []domain.SearchHit `json:"hits"`
+}
+
+// searchPermissions is the permission needed to see each kind of hit.
+var searchPermissions = map[domain.SearchHitType]Permission{
+	domain.SearchHitTool:  PermToolsRead,
+	domain.SearchHitUser:  PermUsersRead,
+	domain.SearchHitEvent: PermEventsRead,
+}
+
+// Search godoc
+// @Summary Search tools, users and events
+// @Description Full-text search over tool names, user names and emails, and event notes. Every word of q must match, and words match as prefixes so partly typed queries work (type-ahead). Hits are ranked best first; snippet is HTML with the matched words wrapped in <mark>. Only the kinds of records the caller may read are searched.
+// @Tags search
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search text"
+// @Param type query []string false "Kinds of records to search (repeat or comma-separate), default all the caller may read" collectionFormat(multi) Enums(tool, user, event)
+// @Param limit query int false "Maximum number of hits" default(20) maximum(50)
+// @Success 200 {object} SearchResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /search [get]
+func (s *Server) search(c *gin.Context) {
+	if !requireService(c, s.searchService != nil, "search") {
+		return
+	}
+	q := c.Query("q")
+	if strings.TrimSpace(q) == "" {
+		respondDomainError(c, validationErr("q", "is required"))
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+
+	requested := domain.AllSearchHitTypes
+	if raw := c.QueryArray("type"); len(raw) > 0 {
+		requested = nil
+		for _, v := range raw {
+			for _, part := range strings.Split(v, ",") {
+				if part = strings.TrimSpace(part); part != "" {
+					requested = append(requested, domain.SearchHitType(part))
+				}
+			}
+		}
+	}
+	// never nil, so a caller who may read none of the requested kinds gets no hits
+	types := []domain.SearchHitType{}
+	for _, t := range requested {
+		if err := domain.ValidateSearchHitType(t); err != nil {
+			respondDomainError(c, err)
+			return
+		}
+		if s.can(c, searchPermissions[t]) {
+			types = append(types, t)
+		}
+	}
+
+	hits, err := s.searchService.Search(c.Request.Context(), q, types, s.capLimit(limit))
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SearchResponse{Query: q, Hits: hits})
+}
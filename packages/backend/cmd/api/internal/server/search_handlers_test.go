@@ -0,0 +1,71 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/service"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/service/mocks"
+)
+
+// newSearchTestEngine serves /search as user, optionally through an API key with the given scopes.
+func newSearchTestEngine(t *testing.T, user domain.User, scopes []string) (*gin.Engine, *mocks.MockSearchRepo) {
+	gin.SetMode(gin.TestMode)
+	searchRepo := mocks.NewMockSearchRepo(gomock.NewController(t))
+	s := NewServer(nil, nil, nil).WithSearch(service.NewSearchService(searchRepo))
+
+	r := gin.New()
+	r.GET("/search", func(c *gin.Context) {
+		c.Set(currentUserKey, user)
+		if scopes != nil {
+			c.Set(currentAPIKeyKey, domain.APIKey{UserID: user.ID, Scopes: scopes})
+		}
+	}, s.search)
+	return r, searchRepo
+}
+
+func TestSearch(t *testing.T) {
+	employee := domain.User{ID: testUserID, Role: domain.UserRoleEmployee}
+
+	t.Run("Searches every type the caller may read", func(t *testing.T) {
+		r, searchRepo := newSearchTestEngine(t, employee, nil)
+		searchRepo.EXPECT().
+			Search(gomock.Any(), []string{"drill"}, domain.AllSearchHitTypes, 20).
+			Return([]domain.SearchHit{{Type: domain.SearchHitTool, ID: "t1", Title: "Drill", Snippet: "<mark>Drill</mark>"}}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?q=drill", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"title":"Drill"`)
+	})
+
+	t.Run("API key scopes narrow the types", func(t *testing.T) {
+		r, searchRepo := newSearchTestEngine(t, employee, []string{string(PermToolsRead), string(PermEventsRead)})
+		searchRepo.EXPECT().
+			Search(gomock.Any(), []string{"drill"}, []domain.SearchHitType{domain.SearchHitTool}, 20).
+			Return([]domain.SearchHit{}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?q=drill&type=tool,user", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"query":"drill","hits":[]}`, w.Body.String())
+	})
+
+	t.Run("Bad parameters", func(t *testing.T) {
+		r, _ := newSearchTestEngine(t, employee, nil)
+
+		for _, query := range []string{"", "q=%20", "q=drill&type=reservation", "q=drill&limit=many"} {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?"+query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+}
@@ -1,9 +1,13 @@
 package server
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/service"
 )
 
 // Request payloads for tool actions.
@@ -11,6 +15,12 @@ import (
 type CheckoutToolRequest struct {
 	UserID string `json:"user_id" binding:"required"`
 	Notes  string `json:"notes"`
+	// DueAt is when the tool should come back; omitted, the tool's default loan period applies.
+	DueAt *time.Time `json:"due_at"`
+	// OverrideReservation checks out despite another user's active reservation (managers only).
+	OverrideReservation bool `json:"override_reservation"`
+	// Condition is the state the tool leaves in: GOOD, FAIR, POOR or DAMAGED.
+	Condition domain.ToolCondition `json:"condition"`
 }
 
 type CheckinToolRequest struct {
@@ -21,6 +31,9 @@ type CheckinToolRequest struct {
 type MaintenanceRequest struct {
 	UserID string `json:"user_id" binding:"required"`
 	Notes  string `json:"notes"`
+	// Vendor and CostCents (the quoted cost in cents) describe the repair; both are optional.
+	Vendor    string `json:"vendor"`
+	CostCents *int64 `json:"cost_cents"`
 }
 
 type MarkLostRequest struct {
@@ -30,15 +43,21 @@ type MarkLostRequest struct {
 
 // CheckoutTool godoc
 // @Summary Check out a tool to a user
-// @Description Check out a tool to a specific user with optional notes
+// @Description Check out a tool to a specific user with optional notes and due date. Refused with 409 while another user's reservation is active, unless a manager sets override_reservation.
 // @Tags tools
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Tool ID"
+// @Param If-Match header string false "ETag from a previous read"
 // @Param checkout body CheckoutToolRequest true "Checkout data"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /tools/{id}/checkout [post]
 func (s *Server) checkoutTool(c *gin.Context) {
 	toolID := c.Param("id")
@@ -48,13 +67,31 @@ func (s *Server) checkoutTool(c *gin.Context) {
 		return
 	}
 
+	if err := s.authorizeOnBehalfOf(c, req.UserID); err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	if req.OverrideReservation && !s.can(c, PermToolsCheckoutAny) {
+		respondDomainError(c, fmt.Errorf("%w: only managers can override reservations", domain.ErrForbidden))
+		return
+	}
+
+	version, err := s.ifMatchVersion(c)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
 	actor := GetActorID(c)
-	updatedTool, err := s.toolService.CheckOutTool(toolID, req.UserID, actor, req.Notes)
+	opts := service.CheckoutOptions{DueAt: req.DueAt, OverrideReservations: req.OverrideReservation, Condition: req.Condition}
+	updatedTool, err := s.toolService.CheckOutTool(c.Request.Context(), toolID, version, req.UserID, actor, req.Notes, opts)
 	if err != nil {
 		respondDomainError(c, err)
 		return
 	}
 
+	setETag(c, updatedTool.Version)
 	c.JSON(http.StatusOK, gin.H{"message": "Tool checked out successfully", "tool": updatedTool})
 }
 
@@ -64,11 +101,16 @@ func (s *Server) checkoutTool(c *gin.Context) {
 // @Tags tools
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Tool ID"
+// @Param If-Match header string false "ETag from a previous read"
 // @Param checkin body CheckinToolRequest true "Checkin data"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /tools/{id}/checkin [post]
 func (s *Server) checkinTool(c *gin.Context) {
 	toolID := c.Param("id")
@@ -78,13 +120,23 @@ func (s *Server) checkinTool(c *gin.Context) {
 		return
 	}
 
+	version, err := s.ifMatchVersion(c)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
 	actor := GetActorID(c)
-	updatedTool, err := s.toolService.ReturnTool(toolID, actor, req.Notes)
+	// Only the current holder may check a tool in unless the caller can act for others.
+	updatedTool, err := s.toolService.ReturnTool(c.Request.Context(), toolID, version, actor, req.Notes, service.ReturnOptions{
+		AuthorizeHolder: func(holderID string) error { return s.authorizeOnBehalfOf(c, holderID) },
+	})
 	if err != nil {
 		respondDomainError(c, err)
 		return
 	}
 
+	setETag(c, updatedTool.Version)
 	c.JSON(http.StatusOK, gin.H{"message": "Tool checked in successfully", "tool": updatedTool})
 }
 
@@ -94,11 +146,15 @@ func (s *Server) checkinTool(c *gin.Context) {
 // @Tags tools
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Tool ID"
+// @Param If-Match header string false "ETag from a previous read"
 // @Param maintenance body MaintenanceRequest true "Maintenance data"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /tools/{id}/maintenance [post]
 func (s *Server) sendToMaintenance(c *gin.Context) {
 	toolID := c.Param("id")
@@ -108,13 +164,20 @@ func (s *Server) sendToMaintenance(c *gin.Context) {
 		return
 	}
 
+	version, err := s.ifMatchVersion(c)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
 	actor := GetActorID(c)
-	updatedTool, err := s.toolService.SendToMaintenance(toolID, actor, req.Notes)
+	updatedTool, err := s.toolService.SendToMaintenance(c.Request.Context(), toolID, version, actor, req.Notes, service.MaintenanceOptions{Vendor: req.Vendor, CostCents: req.CostCents})
 	if err != nil {
 		respondDomainError(c, err)
 		return
 	}
 
+	setETag(c, updatedTool.Version)
 	c.JSON(http.StatusOK, gin.H{"message": "Tool sent to maintenance", "tool": updatedTool})
 }
 
@@ -124,11 +187,15 @@ func (s *Server) sendToMaintenance(c *gin.Context) {
 // @Tags tools
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Tool ID"
+// @Param If-Match header string false "ETag from a previous read"
 // @Param lost body MarkLostRequest true "Lost data"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /tools/{id}/lost [post]
 func (s *Server) markAsLost(c *gin.Context) {
 	toolID := c.Param("id")
@@ -138,12 +205,19 @@ func (s *Server) markAsLost(c *gin.Context) {
 		return
 	}
 
+	version, err := s.ifMatchVersion(c)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
 	actor := GetActorID(c)
-	updatedTool, err := s.toolService.MarkLost(toolID, actor, req.Notes)
+	updatedTool, err := s.toolService.MarkLost(c.Request.Context(), toolID, version, actor, req.Notes)
 	if err != nil {
 		respondDomainError(c, err)
 		return
 	}
 
+	setETag(c, updatedTool.Version)
 	c.JSON(http.StatusOK, gin.H{"message": "Tool marked as lost", "tool": updatedTool})
 }
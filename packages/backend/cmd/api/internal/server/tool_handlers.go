@@ -3,9 +3,12 @@ package server
 import (
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/service"
 )
 
 type CreateToolRequest struct {
@@ -16,6 +19,8 @@ type CreateToolRequest struct {
 type UpdateToolRequest struct {
 	Name   string            `json:"name" binding:"required"`
 	Status domain.ToolStatus `json:"status"`
+	// DefaultLoanDays sets the due date of checkouts that do not give one; omit to clear it.
+	DefaultLoanDays *int `json:"default_loan_days"`
 }
 
 // CreateTool godoc
@@ -24,6 +29,7 @@ type UpdateToolRequest struct {
 // @Tags tools
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param tool body CreateToolRequest true "Tool data"
 // @Success 201 {object} domain.Tool
 // @Failure 400 {object} map[string]string
@@ -36,36 +42,58 @@ func (s *Server) createTool(c *gin.Context) {
 	}
 
 	actor := GetActorID(c)
-	tool, err := s.toolService.CreateTool(req.Name, req.Status, actor, "")
+	tool, err := s.toolService.CreateTool(c.Request.Context(), req.Name, req.Status, actor, "")
 	if err != nil {
 		respondDomainError(c, err)
 		return
 	}
 
+	setETag(c, tool.Version)
 	c.JSON(http.StatusCreated, tool)
 }
 
+// ToolListResponse is one page of tools, newest first.
+type ToolListResponse struct {
+	Items []domain.Tool `json:"items"`
+	ListMeta
+}
+
 // ListTools godoc
 // @Summary List all tools
-// @Description Get a list of tools with pagination and optional status filtering
+// @Description Get a list of tools with pagination, filtering and sorting. Filters combine with AND. A sorted list pages by offset; cursors follow the default newest-first order only.
 // @Tags tools
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param limit query int false "Limit" default(10)
-// @Param offset query int false "Offset" default(0)
-// @Param status query string false "Filter by status"
-// @Success 200 {object} map[string][]domain.Tool
+// @Param cursor query string false "Cursor from next_cursor of the previous page. Best-effort: a row written by a transaction still open when the page was read can be skipped"
+// @Param offset query int false "Offset (deprecated, use cursor)" default(0)
+// @Param status query []string false "Filter by status; repeat or comma-separate for several" collectionFormat(multi)
+// @Param current_user_id query string false "Filter by the user holding the tool"
+// @Param name query string false "Filter by name containing this text, ignoring case"
+// @Param checked_out_before query string false "Last checked out before (RFC3339)"
+// @Param checked_out_after query string false "Last checked out at or after (RFC3339)"
+// @Param created_before query string false "Created before (RFC3339)"
+// @Param created_after query string false "Created at or after (RFC3339)"
+// @Param overdue query bool false "Only overdue (true) or not overdue (false) tools"
+// @Param sort query string false "Comma-separated fields, prefixed with - for descending: name, status, created_at, updated_at, last_checked_out_at, due_at"
+// @Param include_deleted query bool false "Also list soft-deleted tools (admin only)"
+// @Success 200 {object} ToolListResponse
+// @Header 200 {integer} X-Total-Count "Number of rows matching the filters"
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Router /tools [get]
 func (s *Server) listTools(c *gin.Context) {
 	limitStr := c.DefaultQuery("limit", "10")
 	offsetStr := c.DefaultQuery("offset", "0")
+	cursor := c.Query("cursor")
 
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
 		return
 	}
+	limit = s.capLimit(limit)
 
 	offset, err := strconv.Atoi(offsetStr)
 	if err != nil {
@@ -73,9 +101,95 @@ func (s *Server) listTools(c *gin.Context) {
 		return
 	}
 
-	tools, err := s.toolService.ListTools(limit, offset)
+	query, err := toolQuery(c)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+	query.IncludeDeleted, err = s.includeDeleted(c)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	page, err := s.toolService.ListTools(c.Request.Context(), query, domain.PageRequest{Limit: limit, Offset: offset, Cursor: cursor})
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	setTotalCount(c, page.Total)
+	c.JSON(http.StatusOK, ToolListResponse{Items: page.Items, ListMeta: newListMeta(page)})
+}
+
+// toolQuery reads the filter and sort parameters of GET /tools.
+func toolQuery(c *gin.Context) (service.ToolQuery, error) {
+	q := service.ToolQuery{
+		CurrentUserID: c.Query("current_user_id"),
+		Name:          c.Query("name"),
+		Sort:          c.Query("sort"),
+	}
+	for _, raw := range c.QueryArray("status") {
+		for _, status := range strings.Split(raw, ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				q.Statuses = append(q.Statuses, status)
+			}
+		}
+	}
+	for _, param := range []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"checked_out_before", &q.CheckedOutBefore},
+		{"checked_out_after", &q.CheckedOutAfter},
+		{"created_before", &q.CreatedBefore},
+		{"created_after", &q.CreatedAfter},
+	} {
+		t, err := parseTimeQuery(c, param.name)
+		if err != nil {
+			return service.ToolQuery{}, err
+		}
+		*param.dst = t
+	}
+	if raw := c.Query("overdue"); raw != "" {
+		overdue, err := strconv.ParseBool(raw)
+		if err != nil {
+			return service.ToolQuery{}, validationErr("overdue", "must be true or false")
+		}
+		q.Overdue = &overdue
+	}
+	return q, nil
+}
+
+// ListOverdueTools godoc
+// @Summary List overdue tools
+// @Description Get checked-out tools that are past their due date, most overdue first
+// @Tags tools
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} map[string][]domain.Tool
+// @Failure 400 {object} map[string]string
+// @Router /tools/overdue [get]
+func (s *Server) listOverdueTools(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+	limit = s.capLimit(limit)
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list tools"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset parameter"})
+		return
+	}
+
+	tools, err := s.toolService.ListOverdueTools(c.Request.Context(), limit, offset)
+	if err != nil {
+		respondDomainError(c, err)
 		return
 	}
 
@@ -88,33 +202,52 @@ func (s *Server) listTools(c *gin.Context) {
 // @Tags tools
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Tool ID"
+// @Param include_deleted query bool false "Also find a soft-deleted tool (admin only)"
 // @Success 200 {object} domain.Tool
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /tools/{id} [get]
 func (s *Server) getTool(c *gin.Context) {
 	id := c.Param("id")
 
-	tool, err := s.toolService.GetTool(id)
+	includeDeleted, err := s.includeDeleted(c)
 	if err != nil {
 		respondDomainError(c, err)
 		return
 	}
 
+	var tool domain.Tool
+	if includeDeleted {
+		tool, err = s.toolService.GetToolIncludingDeleted(c.Request.Context(), id)
+	} else {
+		tool, err = s.toolService.GetTool(c.Request.Context(), id)
+	}
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	setETag(c, tool.Version)
 	c.JSON(http.StatusOK, tool)
 }
 
 // UpdateTool godoc
 // @Summary Update a tool
-// @Description Update a tool's name and status
+// @Description Update a tool's name, status and default loan period
 // @Tags tools
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Tool ID"
+// @Param If-Match header string false "ETag from a previous read"
 // @Param tool body UpdateToolRequest true "Updated tool data"
 // @Success 200 {object} domain.Tool
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /tools/{id} [put]
 func (s *Server) updateTool(c *gin.Context) {
 	id := c.Param("id")
@@ -125,32 +258,49 @@ func (s *Server) updateTool(c *gin.Context) {
 		return
 	}
 
+	version, err := s.ifMatchVersion(c)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
 	actor := GetActorID(c)
-	tool, err := s.toolService.UpdateTool(id, req.Name, req.Status, actor, "")
+	tool, err := s.toolService.UpdateTool(c.Request.Context(), id, version, req.Name, req.Status, req.DefaultLoanDays, actor, "")
 	if err != nil {
 		respondDomainError(c, err)
 		return
 	}
 
+	setETag(c, tool.Version)
 	c.JSON(http.StatusOK, tool)
 }
 
 // DeleteTool godoc
 // @Summary Delete a tool
-// @Description Delete a tool from the system
+// @Description Soft-delete a tool: it disappears from listings but keeps its history and can be restored
 // @Tags tools
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "Tool ID"
+// @Param If-Match header string false "ETag from a previous read"
 // @Success 204 "No Content"
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /tools/{id} [delete]
 func (s *Server) deleteTool(c *gin.Context) {
 	id := c.Param("id")
 
+	version, err := s.ifMatchVersion(c)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
 	actor := GetActorID(c)
-	err := s.toolService.DeleteTool(id, actor, "")
+	err = s.toolService.DeleteTool(c.Request.Context(), id, version, actor, "")
 	if err != nil {
 		respondDomainError(c, err)
 		return
@@ -159,4 +309,40 @@ func (s *Server) deleteTool(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// RestoreTool godoc
+// @Summary Restore a deleted tool
+// @Description Undo the soft delete of a tool; it comes back as it was when deleted
+// @Tags tools
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tool ID"
+// @Param If-Match header string false "ETag from a previous read"
+// @Success 200 {object} domain.Tool
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Router /tools/{id}/restore [post]
+func (s *Server) restoreTool(c *gin.Context) {
+	id := c.Param("id")
+
+	version, err := s.ifMatchVersion(c)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	actor := GetActorID(c)
+	tool, err := s.toolService.RestoreTool(c.Request.Context(), id, version, actor, "")
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	setETag(c, tool.Version)
+	c.JSON(http.StatusOK, tool)
+}
+
 // tool_handlers now rely on shared respondDomainError in error_helpers.go
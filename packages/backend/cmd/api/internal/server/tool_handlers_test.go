@@ -0,0 +1,40 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	parse := func(query string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/tools?"+query, nil)
+		return c
+	}
+
+	t.Run("Statuses repeat or comma-separate", func(t *testing.T) {
+		q, err := toolQuery(parse("status=IN_OFFICE,LOST&status=MAINTENANCE&overdue=true&sort=-due_at&created_after=2025-06-01T00:00:00Z"))
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"IN_OFFICE", "LOST", "MAINTENANCE"}, q.Statuses)
+		require.NotNil(t, q.Overdue)
+		assert.True(t, *q.Overdue)
+		assert.Equal(t, "-due_at", q.Sort)
+		require.NotNil(t, q.CreatedAfter)
+		assert.Nil(t, q.CreatedBefore)
+	})
+
+	t.Run("Bad values are rejected", func(t *testing.T) {
+		_, err := toolQuery(parse("overdue=maybe"))
+		assert.ErrorContains(t, err, "overdue must be true or false")
+
+		_, err = toolQuery(parse("checked_out_before=yesterday"))
+		assert.ErrorContains(t, err, "checked_out_before must be an RFC3339 timestamp")
+	})
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/logging"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestTracing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+
+	const toolID = "123e4567-e89b-12d3-a456-426614174000"
+	setup := func(t *testing.T, status int) (*gin.Engine, *bytes.Buffer) {
+		var buf bytes.Buffer
+		logger, err := logging.New(&buf, logging.FormatJSON, "info")
+		require.NoError(t, err)
+		s := NewServer(nil, nil, nil).WithLogger(logger)
+
+		r := gin.New()
+		r.Use(s.requestID(), s.trace(), s.accessLog())
+		r.POST("/api/tools/:id/checkout", func(c *gin.Context) {
+			_, span := tracing.Start(c.Request.Context(), "ToolService.CheckOutTool")
+			span.End()
+			c.Status(status)
+		})
+		return r, &buf
+	}
+
+	t.Run("Request span is the parent of service spans", func(t *testing.T) {
+		rec := tracing.RecordSpans(t)
+		r, logs := setup(t, http.StatusOK)
+
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/tools/"+toolID+"/checkout", nil))
+
+		req := tracing.SpanNamed(rec, "POST /api/tools/:id/checkout")
+		svc := tracing.SpanNamed(rec, "ToolService.CheckOutTool")
+		require.NotNil(t, req)
+		require.NotNil(t, svc)
+		assert.Equal(t, req.SpanContext().SpanID(), svc.Parent().SpanID())
+		assert.Contains(t, req.Attributes(), attribute.String("http.route", "/api/tools/:id/checkout"))
+		assert.Contains(t, req.Attributes(), attribute.Int("http.response.status_code", http.StatusOK))
+		assert.Contains(t, req.Attributes(), attribute.String("tool_id", toolID))
+		assert.Contains(t, logs.String(), `"trace_id":"`+req.SpanContext().TraceID().String()+`"`)
+	})
+
+	t.Run("Caller's trace is continued", func(t *testing.T) {
+		rec := tracing.RecordSpans(t)
+		r, _ := setup(t, http.StatusOK)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/tools/"+toolID+"/checkout", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+
+		span := tracing.SpanNamed(rec, "POST /api/tools/:id/checkout")
+		require.NotNil(t, span)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+	})
+
+	t.Run("Only server errors fail the span", func(t *testing.T) {
+		rec := tracing.RecordSpans(t)
+		conflict, _ := setup(t, http.StatusConflict)
+		failure, _ := setup(t, http.StatusInternalServerError)
+
+		conflict.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/tools/"+toolID+"/checkout", nil))
+		failure.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/tools/"+toolID+"/checkout", nil))
+
+		var statuses []codes.Code
+		for _, span := range rec.Ended() {
+			if span.Name() == "POST /api/tools/:id/checkout" {
+				statuses = append(statuses, span.Status().Code)
+			}
+		}
+		assert.Equal(t, []codes.Code{codes.Unset, codes.Error}, statuses)
+	})
+}
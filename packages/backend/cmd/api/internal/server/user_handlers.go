@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/service"
 )
 
 type CreateUserRequest struct {
@@ -26,6 +27,7 @@ type UpdateUserRequest struct {
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param user body CreateUserRequest true "User data"
 // @Success 201 {object} domain.User
 // @Failure 400 {object} map[string]string
@@ -38,30 +40,43 @@ func (s *Server) createUser(c *gin.Context) {
 	}
 
 	actor := GetActorID(c)
-	user, err := s.userService.CreateUser(req.Name, req.Email, req.Role, actor, "")
+	user, err := s.userService.CreateUser(c.Request.Context(), req.Name, req.Email, req.Role, actor, "")
 	if err != nil {
 		respondDomainError(c, err)
 		return
 	}
 
+	setETag(c, user.Version)
 	c.JSON(http.StatusCreated, user)
 }
 
+// UserListResponse is one page of users, newest first.
+type UserListResponse struct {
+	Items []domain.User `json:"items"`
+	ListMeta
+}
+
 // ListUsers godoc
 // @Summary List all users
 // @Description Get a list of users with pagination and optional role filtering
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param limit query int false "Limit" default(10)
-// @Param offset query int false "Offset" default(0)
+// @Param cursor query string false "Cursor from next_cursor of the previous page. Best-effort: a row written by a transaction still open when the page was read can be skipped"
+// @Param offset query int false "Offset (deprecated, use cursor)" default(0)
 // @Param role query string false "Filter by role (EMPLOYEE, ADMIN, MANAGER)"
-// @Success 200 {object} map[string][]domain.User
+// @Param include_deleted query bool false "Also list soft-deleted users (admin only)"
+// @Success 200 {object} UserListResponse
+// @Header 200 {integer} X-Total-Count "Number of rows matching the filters"
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Router /users [get]
 func (s *Server) listUsers(c *gin.Context) {
 	limitStr := c.DefaultQuery("limit", "10")
 	offsetStr := c.DefaultQuery("offset", "0")
+	cursor := c.Query("cursor")
 	roleFilter := c.Query("role") // Optional role filter
 
 	limit, err := strconv.Atoi(limitStr)
@@ -69,6 +84,7 @@ func (s *Server) listUsers(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
 		return
 	}
+	limit = s.capLimit(limit)
 
 	offset, err := strconv.Atoi(offsetStr)
 	if err != nil {
@@ -82,19 +98,21 @@ func (s *Server) listUsers(c *gin.Context) {
 		return
 	}
 
-	var users []domain.User
-	if roleFilter != "" {
-		users, err = s.userService.ListUsersByRole(domain.UserRole(roleFilter), limit, offset)
-	} else {
-		users, err = s.userService.ListUsers(limit, offset)
+	includeDeleted, err := s.includeDeleted(c)
+	if err != nil {
+		respondDomainError(c, err)
+		return
 	}
 
+	query := service.UserQuery{Role: domain.UserRole(roleFilter), IncludeDeleted: includeDeleted}
+	page, err := s.userService.ListUsers(c.Request.Context(), query, domain.PageRequest{Limit: limit, Offset: offset, Cursor: cursor})
 	if err != nil {
 		respondDomainError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"users": users})
+	setTotalCount(c, page.Total)
+	c.JSON(http.StatusOK, UserListResponse{Items: page.Items, ListMeta: newListMeta(page)})
 }
 
 // GetUser godoc
@@ -103,19 +121,34 @@ func (s *Server) listUsers(c *gin.Context) {
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "User ID"
+// @Param include_deleted query bool false "Also find a soft-deleted user (admin only)"
 // @Success 200 {object} domain.User
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /users/{id} [get]
 func (s *Server) getUser(c *gin.Context) {
 	id := c.Param("id")
 
-	user, err := s.userService.GetUser(id)
+	includeDeleted, err := s.includeDeleted(c)
 	if err != nil {
 		respondDomainError(c, err)
 		return
 	}
 
+	var user domain.User
+	if includeDeleted {
+		user, err = s.userService.GetUserIncludingDeleted(c.Request.Context(), id)
+	} else {
+		user, err = s.userService.GetUser(c.Request.Context(), id)
+	}
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	setETag(c, user.Version)
 	c.JSON(http.StatusOK, user)
 }
 
@@ -125,11 +158,15 @@ func (s *Server) getUser(c *gin.Context) {
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "User ID"
+// @Param If-Match header string false "ETag from a previous read"
 // @Param user body UpdateUserRequest true "Updated user data"
 // @Success 200 {object} domain.User
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /users/{id} [put]
 func (s *Server) updateUser(c *gin.Context) {
 	id := c.Param("id")
@@ -140,32 +177,49 @@ func (s *Server) updateUser(c *gin.Context) {
 		return
 	}
 
+	version, err := s.ifMatchVersion(c)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
 	actor := GetActorID(c)
-	user, err := s.userService.UpdateUser(id, req.Name, req.Email, req.Role, actor, "")
+	user, err := s.userService.UpdateUser(c.Request.Context(), id, version, req.Name, req.Email, req.Role, actor, "")
 	if err != nil {
 		respondDomainError(c, err)
 		return
 	}
 
+	setETag(c, user.Version)
 	c.JSON(http.StatusOK, user)
 }
 
 // DeleteUser godoc
 // @Summary Delete a user
-// @Description Delete a user from the system
+// @Description Soft-delete a user: they disappear from listings and can no longer sign in, but keep their history and can be restored
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "User ID"
+// @Param If-Match header string false "ETag from a previous read"
 // @Success 204 "No Content"
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Router /users/{id} [delete]
 func (s *Server) deleteUser(c *gin.Context) {
 	id := c.Param("id")
 
+	version, err := s.ifMatchVersion(c)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
 	actor := GetActorID(c)
-	err := s.userService.DeleteUser(id, actor, "")
+	err = s.userService.DeleteUser(c.Request.Context(), id, version, actor, "")
 	if err != nil {
 		respondDomainError(c, err)
 		return
@@ -174,5 +228,41 @@ func (s *Server) deleteUser(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// RestoreUser godoc
+// @Summary Restore a deleted user
+// @Description Undo the soft delete of a user. Fails with 409 when a live user has taken their email meanwhile.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param If-Match header string false "ETag from a previous read"
+// @Success 200 {object} domain.User
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Router /users/{id}/restore [post]
+func (s *Server) restoreUser(c *gin.Context) {
+	id := c.Param("id")
+
+	version, err := s.ifMatchVersion(c)
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	actor := GetActorID(c)
+	user, err := s.userService.RestoreUser(c.Request.Context(), id, version, actor, "")
+	if err != nil {
+		respondDomainError(c, err)
+		return
+	}
+
+	setETag(c, user.Version)
+	c.JSON(http.StatusOK, user)
+}
+
 // Helper function to validate user role
 // role validation now handled by domain validation & service layer
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+//go:generate mockgen -source=api_key_service.go -destination=mocks/mock_api_key_interfaces.go -package=mocks
+
+type APIKeyRepo interface {
+	Create(ctx context.Context, k domain.APIKey) (domain.APIKey, error)
+	Get(ctx context.Context, id string) (domain.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (domain.APIKey, error)
+	ListByUser(ctx context.Context, userID string) ([]domain.APIKey, error)
+	Revoke(ctx context.Context, id string) error
+	TouchLastUsed(ctx context.Context, id string, at time.Time) error
+}
+
+type APIKeyService struct {
+	Repo APIKeyRepo
+	now  func() time.Time
+}
+
+func NewAPIKeyService(r APIKeyRepo) *APIKeyService {
+	return &APIKeyService{Repo: r, now: time.Now}
+}
+
+// CreateAPIKey issues a new key for userID and returns it along with the
+// plaintext secret, which is not stored and cannot be recovered later.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, userID, name string, scopes []string, expiresAt *time.Time) (domain.APIKey, string, error) {
+	k, err := domain.NewAPIKey(userID, name, scopes, expiresAt)
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+
+	prefix, err := randomToken(4, hex.EncodeToString)
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+	secret, err := randomToken(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+	plaintext := domain.APIKeyPrefix + prefix + "_" + secret
+
+	k.Prefix = prefix
+	k.Hash = hashAPIKey(plaintext)
+
+	created, err := s.Repo.Create(ctx, k)
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+	return created, plaintext, nil
+}
+
+func (s *APIKeyService) ListAPIKeys(ctx context.Context, userID string) ([]domain.APIKey, error) {
+	if err := domain.ValidateUUID(userID, "user_id"); err != nil {
+		return nil, err
+	}
+	return s.Repo.ListByUser(ctx, userID)
+}
+
+// RevokeAPIKey revokes keyID, which must belong to userID.
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, userID, keyID string) error {
+	if err := domain.ValidateUUID(userID, "user_id"); err != nil {
+		return err
+	}
+	if err := domain.ValidateUUID(keyID, "key_id"); err != nil {
+		return err
+	}
+	k, err := s.Repo.Get(ctx, keyID)
+	if err != nil {
+		return err
+	}
+	if k.UserID != userID {
+		return domain.ErrAPIKeyNotFound
+	}
+	return s.Repo.Revoke(ctx, keyID)
+}
+
+// Authenticate resolves a plaintext key to its active APIKey and records the use.
+// Every failure is reported as domain.ErrUnauthorized so callers cannot probe for keys.
+func (s *APIKeyService) Authenticate(ctx context.Context, plaintext string) (domain.APIKey, error) {
+	rest, ok := strings.CutPrefix(plaintext, domain.APIKeyPrefix)
+	if !ok {
+		return domain.APIKey{}, fmt.Errorf("%w: malformed api key", domain.ErrUnauthorized)
+	}
+	prefix, _, ok := strings.Cut(rest, "_")
+	if !ok || prefix == "" {
+		return domain.APIKey{}, fmt.Errorf("%w: malformed api key", domain.ErrUnauthorized)
+	}
+
+	k, err := s.Repo.GetByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			return domain.APIKey{}, fmt.Errorf("%w: invalid api key", domain.ErrUnauthorized)
+		}
+		return domain.APIKey{}, err
+	}
+	if subtle.ConstantTimeCompare([]byte(k.Hash), []byte(hashAPIKey(plaintext))) != 1 {
+		return domain.APIKey{}, fmt.Errorf("%w: invalid api key", domain.ErrUnauthorized)
+	}
+
+	now := s.now()
+	if !k.IsActive(now) {
+		return domain.APIKey{}, fmt.Errorf("%w: api key is revoked or expired", domain.ErrUnauthorized)
+	}
+	if err := s.Repo.TouchLastUsed(ctx, k.ID, now); err != nil {
+		return domain.APIKey{}, err
+	}
+	k.LastUsedAt = &now
+	return k, nil
+}
+
+// hashAPIKey returns the hex SHA-256 of the full plaintext key.
+// Keys carry 256 bits of entropy, so a fast unsalted hash is sufficient.
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomToken(n int, encode func([]byte) string) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	return encode(b), nil
+}
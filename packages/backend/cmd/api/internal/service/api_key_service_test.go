@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// TestAPIKeyService_CreateAPIKey tests key issuance
+func TestAPIKeyService_CreateAPIKey(t *testing.T) {
+	t.Run("Successful key creation stores only the hash", func(t *testing.T) {
+		mocks := SetupAPIKeyServiceMocks(t)
+		defer mocks.Teardown()
+
+		var stored domain.APIKey
+		mocks.MockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, k domain.APIKey) (domain.APIKey, error) {
+			stored = k
+			k.ID = TestKeyID
+			return k, nil
+		})
+
+		key, plaintext, err := mocks.Service.CreateAPIKey(context.Background(), TestUserID, "warehouse script", []string{"tools:read"}, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, TestKeyID, key.ID)
+		assert.True(t, strings.HasPrefix(plaintext, domain.APIKeyPrefix+stored.Prefix+"_"))
+		assert.Equal(t, hashAPIKey(plaintext), stored.Hash)
+		assert.NotContains(t, stored.Hash, plaintext)
+		assert.Equal(t, []string{"tools:read"}, stored.Scopes)
+	})
+
+	t.Run("Invalid input should fail before hitting the repo", func(t *testing.T) {
+		mocks := SetupAPIKeyServiceMocks(t)
+		defer mocks.Teardown()
+
+		_, _, err := mocks.Service.CreateAPIKey(context.Background(), TestUserID, "", nil, nil)
+
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+}
+
+// TestAPIKeyService_Authenticate tests key verification
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	const plaintext = domain.APIKeyPrefix + "abcd1234_secret"
+	now := time.Now()
+
+	activeKey := func() domain.APIKey {
+		return domain.APIKey{ID: TestKeyID, UserID: TestUserID, Prefix: "abcd1234", Hash: hashAPIKey(plaintext)}
+	}
+
+	t.Run("Valid key records last use", func(t *testing.T) {
+		mocks := SetupAPIKeyServiceMocks(t)
+		defer mocks.Teardown()
+		mocks.Service.now = func() time.Time { return now }
+
+		mocks.MockRepo.EXPECT().GetByPrefix(gomock.Any(), "abcd1234").Return(activeKey(), nil)
+		mocks.MockRepo.EXPECT().TouchLastUsed(gomock.Any(), TestKeyID, now).Return(nil)
+
+		key, err := mocks.Service.Authenticate(context.Background(), plaintext)
+
+		require.NoError(t, err)
+		assert.Equal(t, TestUserID, key.UserID)
+		require.NotNil(t, key.LastUsedAt)
+		assert.Equal(t, now, *key.LastUsedAt)
+	})
+
+	t.Run("Wrong secret is unauthorized", func(t *testing.T) {
+		mocks := SetupAPIKeyServiceMocks(t)
+		defer mocks.Teardown()
+
+		mocks.MockRepo.EXPECT().GetByPrefix(gomock.Any(), "abcd1234").Return(activeKey(), nil)
+
+		_, err := mocks.Service.Authenticate(context.Background(), domain.APIKeyPrefix+"abcd1234_wrong")
+
+		assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	})
+
+	t.Run("Unknown prefix is unauthorized", func(t *testing.T) {
+		mocks := SetupAPIKeyServiceMocks(t)
+		defer mocks.Teardown()
+
+		mocks.MockRepo.EXPECT().GetByPrefix(gomock.Any(), "abcd1234").Return(domain.APIKey{}, domain.ErrAPIKeyNotFound)
+
+		_, err := mocks.Service.Authenticate(context.Background(), plaintext)
+
+		assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	})
+
+	t.Run("Revoked key is unauthorized", func(t *testing.T) {
+		mocks := SetupAPIKeyServiceMocks(t)
+		defer mocks.Teardown()
+
+		revoked := activeKey()
+		revokedAt := now.Add(-time.Minute)
+		revoked.RevokedAt = &revokedAt
+		mocks.MockRepo.EXPECT().GetByPrefix(gomock.Any(), "abcd1234").Return(revoked, nil)
+
+		_, err := mocks.Service.Authenticate(context.Background(), plaintext)
+
+		assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	})
+
+	t.Run("Malformed key is unauthorized", func(t *testing.T) {
+		mocks := SetupAPIKeyServiceMocks(t)
+		defer mocks.Teardown()
+
+		_, err := mocks.Service.Authenticate(context.Background(), "not-a-key")
+
+		assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	})
+}
+
+// TestAPIKeyService_RevokeAPIKey tests revocation ownership checks
+func TestAPIKeyService_RevokeAPIKey(t *testing.T) {
+	t.Run("Owner can revoke", func(t *testing.T) {
+		mocks := SetupAPIKeyServiceMocks(t)
+		defer mocks.Teardown()
+
+		mocks.MockRepo.EXPECT().Get(gomock.Any(), TestKeyID).Return(domain.APIKey{ID: TestKeyID, UserID: TestUserID}, nil)
+		mocks.MockRepo.EXPECT().Revoke(gomock.Any(), TestKeyID).Return(nil)
+
+		err := mocks.Service.RevokeAPIKey(context.Background(), TestUserID, TestKeyID)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("Key of another user is not found", func(t *testing.T) {
+		mocks := SetupAPIKeyServiceMocks(t)
+		defer mocks.Teardown()
+
+		mocks.MockRepo.EXPECT().Get(gomock.Any(), TestKeyID).Return(domain.APIKey{ID: TestKeyID, UserID: TestActorID}, nil)
+
+		err := mocks.Service.RevokeAPIKey(context.Background(), TestUserID, TestKeyID)
+
+		assert.ErrorIs(t, err, domain.ErrAPIKeyNotFound)
+	})
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// chainTestEvents returns n tool events chained from seq 1, with the head after the last.
+func chainTestEvents(t *testing.T, n int) ([]domain.Event, domain.ChainHead) {
+	t.Helper()
+	toolID := TestToolID
+	var head domain.ChainHead
+	events := make([]domain.Event, 0, n)
+	for i := 1; i <= n; i++ {
+		e := CreateTestEvent(fmt.Sprintf("e%d", i), domain.EventTypeToolUpdated, &toolID, nil, nil, "")
+		seq := int64(i)
+		hash, err := domain.HashEvent(e, seq, head.Hash)
+		require.NoError(t, err)
+		e.ChainSeq, e.PrevHash, e.Hash = &seq, head.Hash, hash
+		events = append(events, e)
+		head = domain.ChainHead{Seq: seq, Hash: hash}
+	}
+	return events, head
+}
+
+// TestAuditService_VerifyChain tests walking the audit log's hash chain
+func TestAuditService_VerifyChain(t *testing.T) {
+	now := time.Date(2025, 6, 9, 17, 0, 0, 0, time.UTC)
+
+	verify := func(t *testing.T, events []domain.Event, head domain.ChainHead) domain.ChainVerification {
+		mocks := SetupAuditServiceMocks(t, now)
+		defer mocks.Teardown()
+		mocks.MockEvents.EXPECT().ChainHead(gomock.Any()).Return(head, nil)
+		mocks.MockEvents.EXPECT().CountUnchained(gomock.Any()).Return(2, nil)
+		mocks.MockEvents.EXPECT().ListChain(gomock.Any(), int64(0), head.Seq, auditBatch).Return(events, nil)
+		mocks.MockEvents.EXPECT().ListChain(gomock.Any(), gomock.Any(), head.Seq, auditBatch).Return([]domain.Event{}, nil).AnyTimes()
+
+		result, err := mocks.Service.VerifyChain(context.Background())
+
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("Intact chain", func(t *testing.T) {
+		events, head := chainTestEvents(t, 3)
+
+		result := verify(t, events, head)
+
+		assert.True(t, result.Valid)
+		assert.Nil(t, result.BrokenLink)
+		assert.Equal(t, 3, result.EventsChecked)
+		assert.Equal(t, head, result.Head)
+		assert.Equal(t, 2, result.UnchainedEvents)
+		assert.Equal(t, now, result.VerifiedAt)
+	})
+
+	t.Run("Edited event", func(t *testing.T) {
+		events, head := chainTestEvents(t, 3)
+		events[1].Notes = "edited"
+
+		result := verify(t, events, head)
+
+		assert.False(t, result.Valid)
+		assert.Equal(t, &domain.BrokenLink{Seq: 2, EventID: "e2", Reason: "contents do not match the stored hash"}, result.BrokenLink)
+		assert.Equal(t, 1, result.EventsChecked)
+	})
+
+	t.Run("Rehashed event breaks the next link", func(t *testing.T) {
+		events, head := chainTestEvents(t, 3)
+		events[1].Notes = "edited"
+		hash, err := domain.HashEvent(events[1], 2, events[1].PrevHash)
+		require.NoError(t, err)
+		events[1].Hash = hash
+
+		result := verify(t, events, head)
+
+		assert.Equal(t, &domain.BrokenLink{Seq: 3, EventID: "e3", Reason: "prev_hash does not match the hash of the event before it"}, result.BrokenLink)
+	})
+
+	t.Run("Deleted event", func(t *testing.T) {
+		events, head := chainTestEvents(t, 3)
+
+		result := verify(t, []domain.Event{events[0], events[2]}, head)
+
+		assert.Equal(t, &domain.BrokenLink{Seq: 2, Reason: "event is missing"}, result.BrokenLink)
+	})
+
+	t.Run("Deleted newest events", func(t *testing.T) {
+		events, head := chainTestEvents(t, 3)
+
+		result := verify(t, events[:1], head)
+
+		assert.False(t, result.Valid)
+		assert.Equal(t, int64(2), result.BrokenLink.Seq)
+		assert.Contains(t, result.BrokenLink.Reason, "the chain ends at 1 but its head is at 3")
+	})
+
+	t.Run("Head does not match the last event", func(t *testing.T) {
+		events, head := chainTestEvents(t, 3)
+		head.Hash = "forged"
+
+		result := verify(t, events, head)
+
+		assert.Equal(t, &domain.BrokenLink{Seq: 3, Reason: "the chain head does not match the hash of the last event"}, result.BrokenLink)
+	})
+
+	t.Run("Empty chain", func(t *testing.T) {
+		mocks := SetupAuditServiceMocks(t, now)
+		defer mocks.Teardown()
+		mocks.MockEvents.EXPECT().ChainHead(gomock.Any()).Return(domain.ChainHead{}, nil)
+		mocks.MockEvents.EXPECT().CountUnchained(gomock.Any()).Return(0, nil)
+
+		result, err := mocks.Service.VerifyChain(context.Background())
+
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, 0, result.EventsChecked)
+	})
+
+	t.Run("Repository error should propagate", func(t *testing.T) {
+		mocks := SetupAuditServiceMocks(t, now)
+		defer mocks.Teardown()
+		mocks.MockEvents.EXPECT().ChainHead(gomock.Any()).Return(domain.ChainHead{}, assert.AnError)
+
+		_, err := mocks.Service.VerifyChain(context.Background())
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+// TestAuditService_ExportChainHead tests signing the chain head for export
+func TestAuditService_ExportChainHead(t *testing.T) {
+	now := time.Date(2025, 6, 9, 17, 0, 0, 0, time.UTC)
+	head := domain.ChainHead{Seq: 7, Hash: "abc"}
+
+	t.Run("Signed with the configured key", func(t *testing.T) {
+		mocks := SetupAuditServiceMocks(t, now)
+		defer mocks.Teardown()
+		key := ed25519.NewKeyFromSeed([]byte(strings.Repeat("k", ed25519.SeedSize)))
+		mocks.Service.WithSigningKey(key)
+		mocks.MockEvents.EXPECT().ChainHead(gomock.Any()).Return(head, nil)
+
+		signed, err := mocks.Service.ExportChainHead(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, head, signed.ChainHead)
+		assert.Equal(t, now, signed.ExportedAt)
+		signature, err := base64.StdEncoding.DecodeString(signed.Signature)
+		require.NoError(t, err)
+		assert.True(t, ed25519.Verify(key.Public().(ed25519.PublicKey), []byte(signed.Payload), signature))
+	})
+
+	t.Run("No signing key", func(t *testing.T) {
+		mocks := SetupAuditServiceMocks(t, now)
+		defer mocks.Teardown()
+
+		_, err := mocks.Service.ExportChainHead(context.Background())
+
+		assert.ErrorIs(t, err, domain.ErrNotConfigured)
+	})
+}
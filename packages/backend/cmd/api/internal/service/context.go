@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+type apiKeyContextKey struct{}
+
+// ContextWithAPIKey returns a copy of ctx carrying the API key the request
+// authenticated with. Events logged under it record the key in their metadata.
+func ContextWithAPIKey(ctx context.Context, k domain.APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, k)
+}
+
+// APIKeyFromContext returns the API key stored by ContextWithAPIKey, if any.
+func APIKeyFromContext(ctx context.Context) (domain.APIKey, bool) {
+	k, ok := ctx.Value(apiKeyContextKey{}).(domain.APIKey)
+	return k, ok
+}
+
+// eventMetadata completes the metadata of an event logged under ctx with the request's
+// API key, if any. It returns nil when there is nothing to record.
+func eventMetadata(ctx context.Context, m domain.EventMetadata) *domain.EventMetadata {
+	if k, ok := APIKeyFromContext(ctx); ok {
+		m.APIKeyID = k.ID
+		m.APIKeyPrefix = k.Prefix
+	}
+	if m.IsZero() {
+		return nil
+	}
+	return &m
+}
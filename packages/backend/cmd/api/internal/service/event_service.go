@@ -1,56 +1,93 @@
 package service
 
 import (
+	"context"
+	"time"
+
 	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
 	"github.com/wassaaa/tool-tracker/cmd/api/internal/repo"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/tracing"
 )
 
 //go:generate mockgen -source=event_service.go -destination=mocks/mock_event_interfaces.go -package=mocks
 
 type EventRepo interface {
-	Create(eventType domain.EventType, toolID *string, userID *string, actorID *string, notes string, metadata *string) (domain.Event, error)
-	List(limit, offset int) ([]domain.Event, error)
-	Get(id string) (domain.Event, error)
-	ListByType(eventType domain.EventType, limit, offset int) ([]domain.Event, error)
-	ListByTool(toolID string, limit, offset int) ([]domain.Event, error)
-	ListByUser(userID string, limit, offset int) ([]domain.Event, error)
-	ListWithFilter(filter repo.EventFilter, limit, offset int) ([]domain.Event, error)
-	Count() (int, error)
+	Create(ctx context.Context, eventType domain.EventType, toolID *string, userID *string, actorID *string, notes string, metadata *domain.EventMetadata) (domain.Event, error)
+	List(ctx context.Context, limit, offset int) ([]domain.Event, error)
+	Get(ctx context.Context, id string) (domain.Event, error)
+	ListByType(ctx context.Context, eventType domain.EventType, limit, offset int) ([]domain.Event, error)
+	ListByTool(ctx context.Context, toolID string, after *domain.Cursor, limit, offset int) ([]domain.Event, error)
+	ListByUser(ctx context.Context, userID string, after *domain.Cursor, limit, offset int) ([]domain.Event, error)
+	ListWithFilter(ctx context.Context, filter repo.EventFilter, limit, offset int) ([]domain.Event, error)
+	CountWithFilter(ctx context.Context, filter repo.EventFilter) (int, error)
+	Count(ctx context.Context) (int, error)
+	CountByType(ctx context.Context, since *time.Time) (map[domain.EventType]int, error)
+	ListStatusesAt(ctx context.Context, at time.Time) ([]domain.StatusChange, error)
+	ListStatusChanges(ctx context.Context, from, to time.Time, after *domain.Cursor, limit int) ([]domain.StatusChange, error)
+	ListToolReplay(ctx context.Context, after *domain.Event, limit int) ([]domain.Event, error)
+	ChainHead(ctx context.Context) (domain.ChainHead, error)
+	ListChain(ctx context.Context, afterSeq, throughSeq int64, limit int) ([]domain.Event, error)
+	CountUnchained(ctx context.Context) (int, error)
 }
 
 type EventService struct {
-	Repo EventRepo
+	Repo    EventRepo
+	metrics Metrics
 }
 
 func NewEventService(r EventRepo) *EventService {
 	return &EventService{Repo: r}
 }
 
-func (s *EventService) CreateEvent(eventType domain.EventType, toolID *string, userID *string, actorID *string, notes string, metadata *string) (domain.Event, error) {
+// WithMetrics counts every event written outside a unit of work, by type (optional
+// chaining style). A unit of work counts its events itself once it commits.
+func (s *EventService) WithMetrics(m Metrics) *EventService {
+	s.metrics = m
+	return s
+}
+
+// WithRepo returns a copy of the service that writes through r.
+// ToolService and UserService use it to log inside a unit of work, which counts
+// the events only if it commits, so the copy counts nothing.
+func (s *EventService) WithRepo(r EventRepo) EventLogger {
+	return &EventService{Repo: r}
+}
+
+func (s *EventService) CreateEvent(ctx context.Context, eventType domain.EventType, toolID *string, userID *string, actorID *string, notes string, metadata *domain.EventMetadata) (_ domain.Event, opErr error) {
+	ctx, span := tracing.Start(ctx, "EventService.CreateEvent", tracing.EventType(eventType))
+	defer tracing.End(span, &opErr)
+	if toolID != nil {
+		span.SetAttributes(tracing.ToolID(*toolID))
+	}
+	if userID != nil {
+		span.SetAttributes(tracing.UserID(*userID))
+	}
+
 	evt, err := domain.NewEvent(eventType, toolID, userID, actorID, notes, metadata)
 	if err != nil {
 		return domain.Event{}, err
 	}
-	return s.Repo.Create(evt.Type, evt.ToolID, evt.UserID, evt.ActorID, evt.Notes, evt.Metadata)
-}
-
-func (s *EventService) ListEvents(limit, offset int, eventType *string, toolID *string, userID *string) ([]domain.Event, error) {
-	if limit <= 0 {
-		limit = 50
-	}
-	if limit > 500 {
-		limit = 500
+	created, err := s.Repo.Create(ctx, evt.Type, evt.ToolID, evt.UserID, evt.ActorID, evt.Notes, evt.Metadata)
+	if err != nil {
+		return domain.Event{}, err
 	}
-	if offset < 0 {
-		offset = 0
+	if s.metrics != nil {
+		s.metrics.EventWritten(created.Type)
 	}
+	return created, nil
+}
+
+// ListEvents returns one page of events matching the optional filters, newest first.
+// metadata filters on metadata fields, keyed as domain.ValidateMetadataFilterKey accepts.
+func (s *EventService) ListEvents(ctx context.Context, req domain.PageRequest, eventType *string, toolID *string, userID *string, metadata map[string]string) (domain.Page[domain.Event], error) {
+	req = clampPage(req, 50, 500)
 
 	// Build filter
 	filter := repo.EventFilter{}
 	if eventType != nil && *eventType != "" {
 		et := domain.EventType(*eventType)
 		if err := domain.ValidateEventType(et); err != nil {
-			return nil, err
+			return domain.Page[domain.Event]{}, err
 		}
 		filter.Type = &et
 	}
@@ -60,36 +97,61 @@ func (s *EventService) ListEvents(limit, offset int, eventType *string, toolID *
 	if userID != nil && *userID != "" {
 		filter.UserID = userID
 	}
+	for key := range metadata {
+		if err := domain.ValidateMetadataFilterKey(key); err != nil {
+			return domain.Page[domain.Event]{}, err
+		}
+	}
+	if len(metadata) > 0 {
+		filter.Metadata = metadata
+	}
 
-	return s.Repo.ListWithFilter(filter, limit, offset)
+	return fetchPage(req, eventCursor, func(after *domain.Cursor, limit, offset int) ([]domain.Event, error) {
+		filter.After = after
+		return s.Repo.ListWithFilter(ctx, filter, limit, offset)
+	}, func() (int, error) {
+		return s.Repo.CountWithFilter(ctx, repo.EventFilter{Type: filter.Type, ToolID: filter.ToolID, UserID: filter.UserID, Metadata: filter.Metadata})
+	})
 }
 
-func (s *EventService) GetEvent(id string) (domain.Event, error) {
+func (s *EventService) GetEvent(ctx context.Context, id string) (domain.Event, error) {
 	if err := domain.ValidateUUID(id, "event_id"); err != nil {
 		return domain.Event{}, err
 	}
-	return s.Repo.Get(id)
+	return s.Repo.Get(ctx, id)
 }
 
-func (s *EventService) GetToolHistory(toolID string) ([]domain.Event, error) {
+// GetToolHistory returns one page of a tool's events, newest first.
+func (s *EventService) GetToolHistory(ctx context.Context, toolID string, req domain.PageRequest) (domain.Page[domain.Event], error) {
 	if err := domain.ValidateUUID(toolID, "tool_id"); err != nil {
-		return nil, err
+		return domain.Page[domain.Event]{}, err
 	}
 
-	// Get all events for this tool (higher limit for history)
-	return s.Repo.ListByTool(toolID, 1000, 0)
+	// higher limit for history; unpaged callers get the whole of a typical history
+	req = clampPage(req, 1000, 1000)
+	return fetchPage(req, eventCursor, func(after *domain.Cursor, limit, offset int) ([]domain.Event, error) {
+		return s.Repo.ListByTool(ctx, toolID, after, limit, offset)
+	}, func() (int, error) {
+		return s.Repo.CountWithFilter(ctx, repo.EventFilter{ToolID: &toolID})
+	})
 }
 
-func (s *EventService) GetUserActivity(userID string) ([]domain.Event, error) {
+// GetUserActivity returns one page of the events a user is the subject or actor of, newest first.
+func (s *EventService) GetUserActivity(ctx context.Context, userID string, req domain.PageRequest) (domain.Page[domain.Event], error) {
 	if err := domain.ValidateUUID(userID, "user_id"); err != nil {
-		return nil, err
+		return domain.Page[domain.Event]{}, err
 	}
 
-	// Get all events for this user (higher limit for activity)
-	return s.Repo.ListByUser(userID, 1000, 0)
+	// higher limit for activity
+	req = clampPage(req, 1000, 1000)
+	return fetchPage(req, eventCursor, func(after *domain.Cursor, limit, offset int) ([]domain.Event, error) {
+		return s.Repo.ListByUser(ctx, userID, after, limit, offset)
+	}, func() (int, error) {
+		return s.Repo.CountWithFilter(ctx, repo.EventFilter{UserID: &userID})
+	})
 }
 
-func (s *EventService) GetEventsByType(eventType domain.EventType, limit, offset int) ([]domain.Event, error) {
+func (s *EventService) GetEventsByType(ctx context.Context, eventType domain.EventType, limit, offset int) ([]domain.Event, error) {
 	if err := domain.ValidateEventType(eventType); err != nil {
 		return nil, err
 	}
@@ -104,63 +166,171 @@ func (s *EventService) GetEventsByType(eventType domain.EventType, limit, offset
 		offset = 0
 	}
 
-	return s.Repo.ListByType(eventType, limit, offset)
+	return s.Repo.ListByType(ctx, eventType, limit, offset)
 }
 
-func (s *EventService) GetEventCount() (int, error) {
-	return s.Repo.Count()
+func (s *EventService) GetEventCount(ctx context.Context) (int, error) {
+	return s.Repo.Count(ctx)
 }
 
-// Helper methods for specific event creation
+// CountEventsByType returns the number of events of each type, optionally only those at or after since.
+func (s *EventService) CountEventsByType(ctx context.Context, since *time.Time) (map[domain.EventType]int, error) {
+	return s.Repo.CountByType(ctx, since)
+}
+
+// GetEventDiff returns the fields an event changed on its tool or user: the changes an
+// update recorded, or else the difference of its snapshots. Events that record neither
+// (overdue and reservation events, and those logged before snapshots were kept) have no changes.
+func (s *EventService) GetEventDiff(ctx context.Context, id string) (_ domain.EventDiff, opErr error) {
+	ctx, span := tracing.Start(ctx, "EventService.GetEventDiff")
+	defer tracing.End(span, &opErr)
+
+	evt, err := s.GetEvent(ctx, id)
+	if err != nil {
+		return domain.EventDiff{}, err
+	}
+	changes := []domain.FieldChange{}
+	if m := evt.Metadata; m != nil && m.Changes != nil {
+		changes = m.Changes
+	} else if m != nil {
+		if changes, err = m.Diff(); err != nil {
+			return domain.EventDiff{}, err
+		}
+	}
+	return domain.EventDiff{
+		EventID:   evt.ID,
+		Type:      evt.Type,
+		ToolID:    evt.ToolID,
+		UserID:    evt.UserID,
+		CreatedAt: evt.CreatedAt,
+		Changes:   changes,
+	}, nil
+}
+
+// snapshots is the metadata of an event that took a tool or user from before to after.
+// A nil side is left out: there is nothing before a create and nothing after a delete.
+func snapshots(before, after any) domain.EventMetadata {
+	var m domain.EventMetadata
+	if before != nil {
+		m.Before = domain.Snapshot(before)
+	}
+	if after != nil {
+		m.After = domain.Snapshot(after)
+	}
+	return m
+}
+
+// updateSnapshots is snapshots plus the field diff an update event carries.
+func updateSnapshots(before, after any) domain.EventMetadata {
+	m := snapshots(before, after)
+	// snapshots of a Tool or User always decode, so the diff cannot fail
+	m.Changes, _ = m.Diff()
+	return m
+}
+
+// Helper methods for specific event creation. Those that change a tool or user
+// record its state before and after in the event metadata.
 // Tool CRUD logs (actor-aware)
-func (s *EventService) LogToolCreated(toolID string, actorID string, notes string) error {
-	_, err := s.CreateEvent(domain.EventTypeToolCreated, &toolID, nil, &actorID, notes, nil)
+func (s *EventService) LogToolCreated(ctx context.Context, tool domain.Tool, actorID string, notes string) error {
+	_, err := s.CreateEvent(ctx, domain.EventTypeToolCreated, tool.ID, nil, &actorID, notes, eventMetadata(ctx, snapshots(nil, tool)))
 	return err
 }
 
-func (s *EventService) LogToolUpdated(toolID string, actorID string, notes string) error {
-	_, err := s.CreateEvent(domain.EventTypeToolUpdated, &toolID, nil, &actorID, notes, nil)
+func (s *EventService) LogToolUpdated(ctx context.Context, before, after domain.Tool, actorID string, notes string) error {
+	_, err := s.CreateEvent(ctx, domain.EventTypeToolUpdated, after.ID, nil, &actorID, notes, eventMetadata(ctx, updateSnapshots(before, after)))
 	return err
 }
 
-func (s *EventService) LogToolDeleted(toolID string, actorID string, notes string) error {
-	_, err := s.CreateEvent(domain.EventTypeToolDeleted, &toolID, nil, &actorID, notes, nil)
+func (s *EventService) LogToolDeleted(ctx context.Context, tool domain.Tool, actorID string, notes string) error {
+	_, err := s.CreateEvent(ctx, domain.EventTypeToolDeleted, tool.ID, nil, &actorID, notes, eventMetadata(ctx, snapshots(tool, nil)))
 	return err
 }
 
-func (s *EventService) LogToolCheckedOut(toolID string, userID string, actorID string, notes string) error {
-	_, err := s.CreateEvent(domain.EventTypeToolCheckedOut, &toolID, &userID, &actorID, notes, nil)
+func (s *EventService) LogToolRestored(ctx context.Context, before, after domain.Tool, actorID string, notes string) error {
+	_, err := s.CreateEvent(ctx, domain.EventTypeToolRestored, after.ID, nil, &actorID, notes, eventMetadata(ctx, snapshots(before, after)))
+	return err
+}
+
+// LogToolCheckedOut records a checkout; its due date and the tool's condition, if
+// known, go in the event metadata.
+func (s *EventService) LogToolCheckedOut(ctx context.Context, before, after domain.Tool, userID string, actorID string, notes string, condition domain.ToolCondition) error {
+	m := snapshots(before, after)
+	m.Condition = condition
+	if after.DueAt != nil {
+		dueAt := after.DueAt.UTC()
+		m.DueAt = &dueAt
+	}
+	_, err := s.CreateEvent(ctx, domain.EventTypeToolCheckedOut, after.ID, &userID, &actorID, notes, eventMetadata(ctx, m))
 	return err
 }
 
 // Tool action logs
-func (s *EventService) LogToolCheckedIn(toolID string, userID string, actorID string, notes string) error {
-	_, err := s.CreateEvent(domain.EventTypeToolCheckedIn, &toolID, &userID, &actorID, notes, nil)
+func (s *EventService) LogToolCheckedIn(ctx context.Context, before, after domain.Tool, userID string, actorID string, notes string) error {
+	_, err := s.CreateEvent(ctx, domain.EventTypeToolCheckedIn, after.ID, &userID, &actorID, notes, eventMetadata(ctx, snapshots(before, after)))
 	return err
 }
 
-func (s *EventService) LogToolMaintenance(toolID string, userID string, notes string) error {
-	_, err := s.CreateEvent(domain.EventTypeToolMaintenance, &toolID, &userID, nil, notes, nil)
+// LogToolMaintenance records a tool going to maintenance, with the repair details if any.
+// The event's user is the tool's holder at the time, if any.
+func (s *EventService) LogToolMaintenance(ctx context.Context, before, after domain.Tool, actorID string, notes string, vendor string, costCents *int64) error {
+	m := snapshots(before, after)
+	m.Vendor = vendor
+	m.CostCents = costCents
+	_, err := s.CreateEvent(ctx, domain.EventTypeToolMaintenance, after.ID, before.CurrentUserId, &actorID, notes, eventMetadata(ctx, m))
 	return err
 }
 
-func (s *EventService) LogToolLost(toolID string, userID string, notes string) error {
-	_, err := s.CreateEvent(domain.EventTypeToolLost, &toolID, &userID, nil, notes, nil)
+// LogToolLost records a tool being marked lost; the event's user is its holder at the time, if any.
+func (s *EventService) LogToolLost(ctx context.Context, before, after domain.Tool, actorID string, notes string) error {
+	_, err := s.CreateEvent(ctx, domain.EventTypeToolLost, after.ID, before.CurrentUserId, &actorID, notes, eventMetadata(ctx, snapshots(before, after)))
+	return err
+}
+
+// LogToolOverdue records that a checkout passed its due date. It is emitted by the
+// overdue sweeper, so there is no actor.
+func (s *EventService) LogToolOverdue(ctx context.Context, toolID string, userID string, dueAt time.Time) error {
+	dueAt = dueAt.UTC()
+	metadata := eventMetadata(ctx, domain.EventMetadata{DueAt: &dueAt})
+	_, err := s.CreateEvent(ctx, domain.EventTypeToolOverdue, &toolID, &userID, nil, "", metadata)
 	return err
 }
 
 // User CRUD logs
-func (s *EventService) LogUserCreated(userID string, actorID string, notes string) error {
-	_, err := s.CreateEvent(domain.EventTypeUserCreated, nil, &userID, &actorID, notes, nil)
+func (s *EventService) LogUserCreated(ctx context.Context, user domain.User, actorID string, notes string) error {
+	_, err := s.CreateEvent(ctx, domain.EventTypeUserCreated, nil, &user.ID, &actorID, notes, eventMetadata(ctx, snapshots(nil, user)))
+	return err
+}
+
+func (s *EventService) LogUserUpdated(ctx context.Context, before, after domain.User, actorID string, notes string) error {
+	_, err := s.CreateEvent(ctx, domain.EventTypeUserUpdated, nil, &after.ID, &actorID, notes, eventMetadata(ctx, updateSnapshots(before, after)))
+	return err
+}
+
+func (s *EventService) LogUserDeleted(ctx context.Context, user domain.User, actorID string, notes string) error {
+	_, err := s.CreateEvent(ctx, domain.EventTypeUserDeleted, nil, &user.ID, &actorID, notes, eventMetadata(ctx, snapshots(user, nil)))
+	return err
+}
+
+func (s *EventService) LogUserRestored(ctx context.Context, before, after domain.User, actorID string, notes string) error {
+	_, err := s.CreateEvent(ctx, domain.EventTypeUserRestored, nil, &after.ID, &actorID, notes, eventMetadata(ctx, snapshots(before, after)))
+	return err
+}
+
+// Reservation logs; the reservation ID is recorded in the event metadata
+func (s *EventService) LogReservationCreated(ctx context.Context, reservationID, toolID, userID, actorID, notes string) error {
+	metadata := eventMetadata(ctx, domain.EventMetadata{ReservationID: reservationID})
+	_, err := s.CreateEvent(ctx, domain.EventTypeReservationCreated, &toolID, &userID, &actorID, notes, metadata)
 	return err
 }
 
-func (s *EventService) LogUserUpdated(userID string, actorID string, notes string) error {
-	_, err := s.CreateEvent(domain.EventTypeUserUpdated, nil, &userID, &actorID, notes, nil)
+func (s *EventService) LogReservationCancelled(ctx context.Context, reservationID, toolID, userID, actorID, notes string) error {
+	metadata := eventMetadata(ctx, domain.EventMetadata{ReservationID: reservationID})
+	_, err := s.CreateEvent(ctx, domain.EventTypeReservationCancelled, &toolID, &userID, &actorID, notes, metadata)
 	return err
 }
 
-func (s *EventService) LogUserDeleted(userID string, actorID string, notes string) error {
-	_, err := s.CreateEvent(domain.EventTypeUserDeleted, nil, &userID, &actorID, notes, nil)
+func (s *EventService) LogReservationConverted(ctx context.Context, reservationID, toolID, userID, actorID, notes string) error {
+	metadata := eventMetadata(ctx, domain.EventMetadata{ReservationID: reservationID})
+	_, err := s.CreateEvent(ctx, domain.EventTypeReservationConverted, &toolID, &userID, &actorID, notes, metadata)
 	return err
 }
@@ -1,7 +1,11 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"github.com/golang/mock/gomock"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -21,17 +25,17 @@ func TestEventService_CreateEvent(t *testing.T) {
 		createdEvent := CreateTestEvent(TestEventID, domain.EventTypeToolCheckedOut, &toolID, &userID, &actorID, "Tool checked out for project")
 
 		// Set expectations
-		mocks.MockRepo.EXPECT().Create(
+		mocks.MockRepo.EXPECT().Create(gomock.Any(),
 			domain.EventTypeToolCheckedOut,
 			&toolID,
 			&userID,
 			&actorID,
 			"Tool checked out for project",
-			(*string)(nil),
+			(*domain.EventMetadata)(nil),
 		).Return(createdEvent, nil)
 
 		// Execute
-		result, err := mocks.Service.CreateEvent(
+		result, err := mocks.Service.CreateEvent(context.Background(),
 			domain.EventTypeToolCheckedOut,
 			&toolID,
 			&userID,
@@ -52,16 +56,16 @@ func TestEventService_CreateEvent(t *testing.T) {
 		toolID := TestToolID
 		actorID := TestActorID
 		repoError := assert.AnError
-		mocks.MockRepo.EXPECT().Create(
+		mocks.MockRepo.EXPECT().Create(gomock.Any(),
 			domain.EventTypeToolCreated,
 			&toolID,
 			(*string)(nil),
 			&actorID,
 			"Tool created",
-			(*string)(nil),
+			(*domain.EventMetadata)(nil),
 		).Return(domain.Event{}, repoError)
 
-		_, err := mocks.Service.CreateEvent(
+		_, err := mocks.Service.CreateEvent(context.Background(),
 			domain.EventTypeToolCreated,
 			&toolID,
 			nil,
@@ -89,12 +93,13 @@ func TestEventService_ListEvents(t *testing.T) {
 		}
 
 		expectedFilter := repo.EventFilter{}
-		mocks.MockRepo.EXPECT().ListWithFilter(expectedFilter, 50, 0).Return(expectedEvents, nil)
+		mocks.MockRepo.EXPECT().ListWithFilter(gomock.Any(), expectedFilter, 51, 0).Return(expectedEvents, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), expectedFilter).Return(2, nil)
 
-		result, err := mocks.Service.ListEvents(50, 0, nil, nil, nil)
+		result, err := mocks.Service.ListEvents(context.Background(), domain.PageRequest{Limit: 50}, nil, nil, nil, nil)
 
 		require.NoError(t, err)
-		assert.Equal(t, expectedEvents, result)
+		assert.Equal(t, expectedEvents, result.Items)
 	})
 
 	t.Run("List events with event type filter", func(t *testing.T) {
@@ -112,12 +117,13 @@ func TestEventService_ListEvents(t *testing.T) {
 		expectedFilter := repo.EventFilter{
 			Type: &eventTypeFilter,
 		}
-		mocks.MockRepo.EXPECT().ListWithFilter(expectedFilter, 50, 0).Return(expectedEvents, nil)
+		mocks.MockRepo.EXPECT().ListWithFilter(gomock.Any(), expectedFilter, 51, 0).Return(expectedEvents, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), expectedFilter).Return(2, nil)
 
-		result, err := mocks.Service.ListEvents(50, 0, &eventTypeStr, nil, nil)
+		result, err := mocks.Service.ListEvents(context.Background(), domain.PageRequest{Limit: 50}, &eventTypeStr, nil, nil, nil)
 
 		require.NoError(t, err)
-		assert.Equal(t, expectedEvents, result)
+		assert.Equal(t, expectedEvents, result.Items)
 	})
 
 	t.Run("List events with tool ID filter", func(t *testing.T) {
@@ -133,12 +139,13 @@ func TestEventService_ListEvents(t *testing.T) {
 		expectedFilter := repo.EventFilter{
 			ToolID: &toolID,
 		}
-		mocks.MockRepo.EXPECT().ListWithFilter(expectedFilter, 50, 0).Return(expectedEvents, nil)
+		mocks.MockRepo.EXPECT().ListWithFilter(gomock.Any(), expectedFilter, 51, 0).Return(expectedEvents, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), expectedFilter).Return(2, nil)
 
-		result, err := mocks.Service.ListEvents(50, 0, nil, &toolID, nil)
+		result, err := mocks.Service.ListEvents(context.Background(), domain.PageRequest{Limit: 50}, nil, &toolID, nil, nil)
 
 		require.NoError(t, err)
-		assert.Equal(t, expectedEvents, result)
+		assert.Equal(t, expectedEvents, result.Items)
 	})
 
 	t.Run("Default limit applied when zero", func(t *testing.T) {
@@ -146,9 +153,10 @@ func TestEventService_ListEvents(t *testing.T) {
 		defer mocks.Teardown()
 
 		expectedFilter := repo.EventFilter{}
-		mocks.MockRepo.EXPECT().ListWithFilter(expectedFilter, 50, 0).Return([]domain.Event{}, nil)
+		mocks.MockRepo.EXPECT().ListWithFilter(gomock.Any(), expectedFilter, 51, 0).Return([]domain.Event{}, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), expectedFilter).Return(2, nil)
 
-		_, err := mocks.Service.ListEvents(0, 0, nil, nil, nil)
+		_, err := mocks.Service.ListEvents(context.Background(), domain.PageRequest{}, nil, nil, nil, nil)
 
 		require.NoError(t, err)
 	})
@@ -158,20 +166,65 @@ func TestEventService_ListEvents(t *testing.T) {
 		defer mocks.Teardown()
 
 		expectedFilter := repo.EventFilter{}
-		mocks.MockRepo.EXPECT().ListWithFilter(expectedFilter, 500, 0).Return([]domain.Event{}, nil)
+		mocks.MockRepo.EXPECT().ListWithFilter(gomock.Any(), expectedFilter, 501, 0).Return([]domain.Event{}, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), expectedFilter).Return(2, nil)
+
+		_, err := mocks.Service.ListEvents(context.Background(), domain.PageRequest{Limit: 1000}, nil, nil, nil, nil)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("Cursor is passed to the filter", func(t *testing.T) {
+		mocks := SetupEventServiceMocks(t)
+		defer mocks.Teardown()
 
-		_, err := mocks.Service.ListEvents(1000, 0, nil, nil, nil)
+		cursor := domain.Cursor{CreatedAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), ID: TestEventID}
+		toolID := TestToolID
+		expectedFilter := repo.EventFilter{ToolID: &toolID, After: &cursor}
+		mocks.MockRepo.EXPECT().ListWithFilter(gomock.Any(), expectedFilter, 51, 0).Return([]domain.Event{}, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), repo.EventFilter{ToolID: &toolID}).Return(2, nil)
+
+		result, err := mocks.Service.ListEvents(context.Background(), domain.PageRequest{Offset: 100, Cursor: cursor.Encode()}, nil, &toolID, nil, nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Items)
+		assert.Equal(t, 2, result.Total)
+		assert.Equal(t, 0, result.Offset)
+		assert.Equal(t, cursor.Encode(), result.Cursor)
+		assert.False(t, result.HasMore)
+	})
+
+	t.Run("List events with metadata filter", func(t *testing.T) {
+		mocks := SetupEventServiceMocks(t)
+		defer mocks.Teardown()
+
+		metadata := map[string]string{"vendor": "Acme Repairs", "after.status": "MAINTENANCE"}
+		expectedFilter := repo.EventFilter{Metadata: metadata}
+		mocks.MockRepo.EXPECT().ListWithFilter(gomock.Any(), expectedFilter, 51, 0).Return([]domain.Event{}, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), expectedFilter).Return(0, nil)
+
+		_, err := mocks.Service.ListEvents(context.Background(), domain.PageRequest{Limit: 50}, nil, nil, nil, metadata)
 
 		require.NoError(t, err)
 	})
 
+	t.Run("Unknown metadata key should fail", func(t *testing.T) {
+		mocks := SetupEventServiceMocks(t)
+		defer mocks.Teardown()
+
+		_, err := mocks.Service.ListEvents(context.Background(), domain.PageRequest{Limit: 50}, nil, nil, nil, map[string]string{"notes": "x"})
+
+		assert.ErrorIs(t, err, domain.ErrValidation)
+		assert.Contains(t, err.Error(), "cannot filter on metadata key notes")
+	})
+
 	t.Run("Invalid event type should fail", func(t *testing.T) {
 		mocks := SetupEventServiceMocks(t)
 		defer mocks.Teardown()
 
 		invalidEventType := "invalid_event_type"
 
-		_, err := mocks.Service.ListEvents(50, 0, &invalidEventType, nil, nil)
+		_, err := mocks.Service.ListEvents(context.Background(), domain.PageRequest{Limit: 50}, &invalidEventType, nil, nil, nil)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "invalid event type")
@@ -186,9 +239,9 @@ func TestEventService_GetEvent(t *testing.T) {
 
 		expectedEvent := CreateTestEvent(TestEventID, domain.EventTypeToolCreated, nil, nil, nil, "Event retrieved")
 
-		mocks.MockRepo.EXPECT().Get(TestEventID).Return(expectedEvent, nil)
+		mocks.MockRepo.EXPECT().Get(gomock.Any(), TestEventID).Return(expectedEvent, nil)
 
-		result, err := mocks.Service.GetEvent(TestEventID)
+		result, err := mocks.Service.GetEvent(context.Background(), TestEventID)
 
 		require.NoError(t, err)
 		assert.Equal(t, expectedEvent, result)
@@ -198,7 +251,7 @@ func TestEventService_GetEvent(t *testing.T) {
 		mocks := SetupEventServiceMocks(t)
 		defer mocks.Teardown()
 
-		_, err := mocks.Service.GetEvent(InvalidUUID)
+		_, err := mocks.Service.GetEvent(context.Background(), InvalidUUID)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "event_id must be a valid UUID")
@@ -217,19 +270,20 @@ func TestEventService_GetToolHistory(t *testing.T) {
 			CreateTestEvent("event2", domain.EventTypeToolCheckedOut, &toolID, nil, nil, "Tool checked out"),
 		}
 
-		mocks.MockRepo.EXPECT().ListByTool(TestToolID, 1000, 0).Return(expectedEvents, nil)
+		mocks.MockRepo.EXPECT().ListByTool(gomock.Any(), TestToolID, nil, 1001, 0).Return(expectedEvents, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), repo.EventFilter{ToolID: &toolID}).Return(2, nil)
 
-		result, err := mocks.Service.GetToolHistory(TestToolID)
+		result, err := mocks.Service.GetToolHistory(context.Background(), TestToolID, domain.PageRequest{})
 
 		require.NoError(t, err)
-		assert.Equal(t, expectedEvents, result)
+		assert.Equal(t, expectedEvents, result.Items)
 	})
 
 	t.Run("Invalid tool ID should fail", func(t *testing.T) {
 		mocks := SetupEventServiceMocks(t)
 		defer mocks.Teardown()
 
-		_, err := mocks.Service.GetToolHistory(InvalidUUID)
+		_, err := mocks.Service.GetToolHistory(context.Background(), InvalidUUID, domain.PageRequest{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "tool_id must be a valid UUID")
@@ -248,19 +302,20 @@ func TestEventService_GetUserActivity(t *testing.T) {
 			CreateTestEvent("event2", domain.EventTypeToolCheckedIn, nil, &userID, nil, "User checked in tool"),
 		}
 
-		mocks.MockRepo.EXPECT().ListByUser(TestUserID, 1000, 0).Return(expectedEvents, nil)
+		mocks.MockRepo.EXPECT().ListByUser(gomock.Any(), TestUserID, nil, 1001, 0).Return(expectedEvents, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), repo.EventFilter{UserID: &userID}).Return(2, nil)
 
-		result, err := mocks.Service.GetUserActivity(TestUserID)
+		result, err := mocks.Service.GetUserActivity(context.Background(), TestUserID, domain.PageRequest{})
 
 		require.NoError(t, err)
-		assert.Equal(t, expectedEvents, result)
+		assert.Equal(t, expectedEvents, result.Items)
 	})
 
 	t.Run("Invalid user ID should fail", func(t *testing.T) {
 		mocks := SetupEventServiceMocks(t)
 		defer mocks.Teardown()
 
-		_, err := mocks.Service.GetUserActivity(InvalidUUID)
+		_, err := mocks.Service.GetUserActivity(context.Background(), InvalidUUID, domain.PageRequest{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "user_id must be a valid UUID")
@@ -277,16 +332,17 @@ func TestEventService_LogToolCreated(t *testing.T) {
 		actorID := TestActorID
 		createdEvent := CreateTestEvent(TestEventID, domain.EventTypeToolCreated, &toolID, nil, &actorID, "Tool created via API")
 
-		mocks.MockRepo.EXPECT().Create(
+		mocks.MockRepo.EXPECT().Create(gomock.Any(),
 			domain.EventTypeToolCreated,
 			&toolID,
 			(*string)(nil),
 			&actorID,
 			"Tool created via API",
-			(*string)(nil),
+			gomock.Any(),
 		).Return(createdEvent, nil)
 
-		err := mocks.Service.LogToolCreated(TestToolID, TestActorID, "Tool created via API")
+		tool := CreateTestTool(TestToolID, "Test Hammer", domain.ToolStatusInOffice)
+		err := mocks.Service.LogToolCreated(context.Background(), tool, TestActorID, "Tool created via API")
 
 		require.NoError(t, err)
 	})
@@ -303,21 +359,173 @@ func TestEventService_LogToolCheckedOut(t *testing.T) {
 		actorID := TestActorID
 		createdEvent := CreateTestEvent(TestEventID, domain.EventTypeToolCheckedOut, &toolID, &userID, &actorID, "Tool checked out for project")
 
-		mocks.MockRepo.EXPECT().Create(
+		mocks.MockRepo.EXPECT().Create(gomock.Any(),
 			domain.EventTypeToolCheckedOut,
 			&toolID,
 			&userID,
 			&actorID,
 			"Tool checked out for project",
-			(*string)(nil),
+			gomock.Any(),
 		).Return(createdEvent, nil)
 
-		err := mocks.Service.LogToolCheckedOut(TestToolID, TestUserID, TestActorID, "Tool checked out for project")
+		before, after := checkoutSnapshots(nil)
+		err := mocks.Service.LogToolCheckedOut(context.Background(), before, after, TestUserID, TestActorID, "Tool checked out for project", "")
+
+		require.NoError(t, err)
+	})
+
+	t.Run("Due date is recorded in metadata", func(t *testing.T) {
+		mocks := SetupEventServiceMocks(t)
+		defer mocks.Teardown()
+
+		dueAt := time.Date(2025, 6, 9, 19, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+		var metadata *domain.EventMetadata
+
+		mocks.MockRepo.EXPECT().Create(gomock.Any(), domain.EventTypeToolCheckedOut, gomock.Any(), gomock.Any(), gomock.Any(), "", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ domain.EventType, _, _, _ *string, _ string, m *domain.EventMetadata) (domain.Event, error) {
+				metadata = m
+				return domain.Event{}, nil
+			})
+
+		before, after := checkoutSnapshots(&dueAt)
+		err := mocks.Service.LogToolCheckedOut(context.Background(), before, after, TestUserID, TestActorID, "", domain.ToolConditionFair)
+
+		require.NoError(t, err)
+		require.NotNil(t, metadata)
+		parsed := *metadata
+		require.NotNil(t, parsed.DueAt)
+		assert.Equal(t, "2025-06-09T17:00:00Z", parsed.DueAt.Format(time.RFC3339))
+		assert.Equal(t, domain.ToolConditionFair, parsed.Condition)
+	})
+}
+
+// checkoutSnapshots returns a tool before and after being checked out to TestUserID.
+func checkoutSnapshots(dueAt *time.Time) (domain.Tool, domain.Tool) {
+	before := CreateTestTool(TestToolID, "Test Hammer", domain.ToolStatusInOffice)
+	after := before
+	after.Status = domain.ToolStatusCheckedOut
+	userID := TestUserID
+	after.CurrentUserId = &userID
+	after.DueAt = dueAt
+	return before, after
+}
+
+// TestEventService_Snapshots tests that events changing a tool or user record its state
+func TestEventService_Snapshots(t *testing.T) {
+	capture := func(mocks *EventServiceMocks, metadata **domain.EventMetadata) {
+		mocks.MockRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ domain.EventType, _, _, _ *string, _ string, m *domain.EventMetadata) (domain.Event, error) {
+				*metadata = m
+				return domain.Event{}, nil
+			})
+	}
+
+	t.Run("Checkout records the tool before and after", func(t *testing.T) {
+		mocks := SetupEventServiceMocks(t)
+		defer mocks.Teardown()
+		var metadata *domain.EventMetadata
+		capture(mocks, &metadata)
+
+		before, after := checkoutSnapshots(nil)
+		require.NoError(t, mocks.Service.LogToolCheckedOut(context.Background(), before, after, TestUserID, TestActorID, "", domain.ToolConditionFair))
+
+		require.NotNil(t, metadata)
+		parsed := *metadata
+		var gotBefore, gotAfter domain.Tool
+		require.NoError(t, json.Unmarshal(parsed.Before, &gotBefore))
+		require.NoError(t, json.Unmarshal(parsed.After, &gotAfter))
+		assert.Equal(t, domain.ToolStatusInOffice, gotBefore.Status)
+		assert.Equal(t, domain.ToolStatusCheckedOut, gotAfter.Status)
+		assert.Equal(t, "Test Hammer", gotAfter.Name)
+	})
+
+	t.Run("Delete records only the state before", func(t *testing.T) {
+		mocks := SetupEventServiceMocks(t)
+		defer mocks.Teardown()
+		var metadata *domain.EventMetadata
+		capture(mocks, &metadata)
+
+		user := CreateTestUser(TestUserID, "John Doe", "john@example.com", domain.UserRoleEmployee)
+		require.NoError(t, mocks.Service.LogUserDeleted(context.Background(), user, TestActorID, ""))
+
+		require.NotNil(t, metadata)
+		parsed := *metadata
+		assert.Contains(t, string(parsed.Before), `"email":"john@example.com"`)
+		assert.Empty(t, parsed.After)
+	})
+}
+
+// TestEventService_GetEventDiff tests listing the fields an event changed
+func TestEventService_GetEventDiff(t *testing.T) {
+	t.Run("Lists the changed fields", func(t *testing.T) {
+		mocks := SetupEventServiceMocks(t)
+		defer mocks.Teardown()
+
+		toolID := TestToolID
+		evt := CreateTestEvent(TestEventID, domain.EventTypeToolUpdated, &toolID, nil, nil, "")
+		evt.Metadata = &domain.EventMetadata{
+			Before: json.RawMessage(`{"name":"Drill","status":"IN_OFFICE"}`),
+			After:  json.RawMessage(`{"name":"Hammer drill","status":"IN_OFFICE"}`),
+		}
+		mocks.MockRepo.EXPECT().Get(gomock.Any(), TestEventID).Return(evt, nil)
+
+		diff, err := mocks.Service.GetEventDiff(context.Background(), TestEventID)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.EventTypeToolUpdated, diff.Type)
+		assert.Equal(t, &toolID, diff.ToolID)
+		assert.Equal(t, []domain.FieldChange{{Field: "name", Before: "Drill", After: "Hammer drill"}}, diff.Changes)
+	})
+
+	t.Run("Event without snapshots has no changes", func(t *testing.T) {
+		mocks := SetupEventServiceMocks(t)
+		defer mocks.Teardown()
+
+		evt := CreateTestEvent(TestEventID, domain.EventTypeReservationCreated, nil, nil, nil, "")
+		evt.Metadata = &domain.EventMetadata{ReservationID: TestResID}
+		mocks.MockRepo.EXPECT().Get(gomock.Any(), TestEventID).Return(evt, nil)
+
+		diff, err := mocks.Service.GetEventDiff(context.Background(), TestEventID)
 
 		require.NoError(t, err)
+		assert.Empty(t, diff.Changes)
+	})
+
+	t.Run("Invalid event ID", func(t *testing.T) {
+		mocks := SetupEventServiceMocks(t)
+		defer mocks.Teardown()
+
+		_, err := mocks.Service.GetEventDiff(context.Background(), InvalidUUID)
+
+		assert.ErrorIs(t, err, domain.ErrValidation)
 	})
 }
 
+// TestEventService_APIKeyContext tests that events logged under a request's API key record it
+func TestEventService_APIKeyContext(t *testing.T) {
+	mocks := SetupEventServiceMocks(t)
+	defer mocks.Teardown()
+
+	toolID := TestToolID
+	userID := TestUserID
+	actorID := TestActorID
+	expectedMetadata := domain.EventMetadata{APIKeyID: TestKeyID, APIKeyPrefix: "abcd1234", ReservationID: TestResID}
+
+	mocks.MockRepo.EXPECT().Create(gomock.Any(),
+		domain.EventTypeReservationCreated,
+		&toolID,
+		&userID,
+		&actorID,
+		"via script",
+		&expectedMetadata,
+	).Return(domain.Event{}, nil)
+
+	ctx := ContextWithAPIKey(context.Background(), domain.APIKey{ID: TestKeyID, Prefix: "abcd1234"})
+	err := mocks.Service.LogReservationCreated(ctx, TestResID, TestToolID, TestUserID, TestActorID, "via script")
+
+	require.NoError(t, err)
+}
+
 // TestEventService_GetEventsByType tests getting events by type
 func TestEventService_GetEventsByType(t *testing.T) {
 	t.Run("Successful get events by type", func(t *testing.T) {
@@ -329,9 +537,9 @@ func TestEventService_GetEventsByType(t *testing.T) {
 			CreateTestEvent("event2", domain.EventTypeToolCreated, nil, nil, nil, "Another tool created"),
 		}
 
-		mocks.MockRepo.EXPECT().ListByType(domain.EventTypeToolCreated, 50, 0).Return(expectedEvents, nil)
+		mocks.MockRepo.EXPECT().ListByType(gomock.Any(), domain.EventTypeToolCreated, 50, 0).Return(expectedEvents, nil)
 
-		result, err := mocks.Service.GetEventsByType(domain.EventTypeToolCreated, 50, 0)
+		result, err := mocks.Service.GetEventsByType(context.Background(), domain.EventTypeToolCreated, 50, 0)
 
 		require.NoError(t, err)
 		assert.Equal(t, expectedEvents, result)
@@ -341,9 +549,9 @@ func TestEventService_GetEventsByType(t *testing.T) {
 		mocks := SetupEventServiceMocks(t)
 		defer mocks.Teardown()
 
-		mocks.MockRepo.EXPECT().ListByType(domain.EventTypeToolCreated, 50, 0).Return([]domain.Event{}, nil)
+		mocks.MockRepo.EXPECT().ListByType(gomock.Any(), domain.EventTypeToolCreated, 50, 0).Return([]domain.Event{}, nil)
 
-		_, err := mocks.Service.GetEventsByType(domain.EventTypeToolCreated, 0, 0)
+		_, err := mocks.Service.GetEventsByType(context.Background(), domain.EventTypeToolCreated, 0, 0)
 
 		require.NoError(t, err)
 	})
@@ -352,9 +560,9 @@ func TestEventService_GetEventsByType(t *testing.T) {
 		mocks := SetupEventServiceMocks(t)
 		defer mocks.Teardown()
 
-		mocks.MockRepo.EXPECT().ListByType(domain.EventTypeToolCreated, 500, 0).Return([]domain.Event{}, nil)
+		mocks.MockRepo.EXPECT().ListByType(gomock.Any(), domain.EventTypeToolCreated, 500, 0).Return([]domain.Event{}, nil)
 
-		_, err := mocks.Service.GetEventsByType(domain.EventTypeToolCreated, 600, 0)
+		_, err := mocks.Service.GetEventsByType(context.Background(), domain.EventTypeToolCreated, 600, 0)
 
 		require.NoError(t, err)
 	})
@@ -363,9 +571,9 @@ func TestEventService_GetEventsByType(t *testing.T) {
 		mocks := SetupEventServiceMocks(t)
 		defer mocks.Teardown()
 
-		mocks.MockRepo.EXPECT().ListByType(domain.EventTypeToolCreated, 50, 0).Return([]domain.Event{}, nil)
+		mocks.MockRepo.EXPECT().ListByType(gomock.Any(), domain.EventTypeToolCreated, 50, 0).Return([]domain.Event{}, nil)
 
-		_, err := mocks.Service.GetEventsByType(domain.EventTypeToolCreated, 50, -5)
+		_, err := mocks.Service.GetEventsByType(context.Background(), domain.EventTypeToolCreated, 50, -5)
 
 		require.NoError(t, err)
 	})
@@ -379,9 +587,9 @@ func TestEventService_GetEventCount(t *testing.T) {
 
 		expectedCount := 100
 
-		mocks.MockRepo.EXPECT().Count().Return(expectedCount, nil)
+		mocks.MockRepo.EXPECT().Count(gomock.Any()).Return(expectedCount, nil)
 
-		result, err := mocks.Service.GetEventCount()
+		result, err := mocks.Service.GetEventCount(context.Background())
 
 		require.NoError(t, err)
 		assert.Equal(t, expectedCount, result)
@@ -392,9 +600,9 @@ func TestEventService_GetEventCount(t *testing.T) {
 		defer mocks.Teardown()
 
 		repoError := assert.AnError
-		mocks.MockRepo.EXPECT().Count().Return(0, repoError)
+		mocks.MockRepo.EXPECT().Count(gomock.Any()).Return(0, repoError)
 
-		_, err := mocks.Service.GetEventCount()
+		_, err := mocks.Service.GetEventCount(context.Background())
 
 		assert.Error(t, err)
 		assert.Equal(t, repoError, err)
@@ -403,6 +611,9 @@ func TestEventService_GetEventCount(t *testing.T) {
 
 // TestEventService_AdditionalLoggingMethods tests the remaining logging convenience methods
 func TestEventService_AdditionalLoggingMethods(t *testing.T) {
+	tool := CreateTestTool(TestToolID, "Test Hammer", domain.ToolStatusInOffice)
+	user := CreateTestUser(TestUserID, "John Doe", "john@example.com", domain.UserRoleEmployee)
+
 	t.Run("LogToolUpdated", func(t *testing.T) {
 		mocks := SetupEventServiceMocks(t)
 		defer mocks.Teardown()
@@ -411,16 +622,16 @@ func TestEventService_AdditionalLoggingMethods(t *testing.T) {
 		actorID := TestActorID
 		createdEvent := CreateTestEvent(TestEventID, domain.EventTypeToolUpdated, &toolID, nil, &actorID, "Tool updated")
 
-		mocks.MockRepo.EXPECT().Create(
+		mocks.MockRepo.EXPECT().Create(gomock.Any(),
 			domain.EventTypeToolUpdated,
 			&toolID,
 			(*string)(nil),
 			&actorID,
 			"Tool updated",
-			(*string)(nil),
+			gomock.Any(),
 		).Return(createdEvent, nil)
 
-		err := mocks.Service.LogToolUpdated(TestToolID, TestActorID, "Tool updated")
+		err := mocks.Service.LogToolUpdated(context.Background(), tool, tool, TestActorID, "Tool updated")
 
 		require.NoError(t, err)
 	})
@@ -433,16 +644,16 @@ func TestEventService_AdditionalLoggingMethods(t *testing.T) {
 		actorID := TestActorID
 		createdEvent := CreateTestEvent(TestEventID, domain.EventTypeToolDeleted, &toolID, nil, &actorID, "Tool deleted")
 
-		mocks.MockRepo.EXPECT().Create(
+		mocks.MockRepo.EXPECT().Create(gomock.Any(),
 			domain.EventTypeToolDeleted,
 			&toolID,
 			(*string)(nil),
 			&actorID,
 			"Tool deleted",
-			(*string)(nil),
+			gomock.Any(),
 		).Return(createdEvent, nil)
 
-		err := mocks.Service.LogToolDeleted(TestToolID, TestActorID, "Tool deleted")
+		err := mocks.Service.LogToolDeleted(context.Background(), tool, TestActorID, "Tool deleted")
 
 		require.NoError(t, err)
 	})
@@ -456,16 +667,16 @@ func TestEventService_AdditionalLoggingMethods(t *testing.T) {
 		actorID := TestActorID
 		createdEvent := CreateTestEvent(TestEventID, domain.EventTypeToolCheckedIn, &toolID, &userID, &actorID, "Tool checked in")
 
-		mocks.MockRepo.EXPECT().Create(
+		mocks.MockRepo.EXPECT().Create(gomock.Any(),
 			domain.EventTypeToolCheckedIn,
 			&toolID,
 			&userID,
 			&actorID,
 			"Tool checked in",
-			(*string)(nil),
+			gomock.Any(),
 		).Return(createdEvent, nil)
 
-		err := mocks.Service.LogToolCheckedIn(TestToolID, TestUserID, TestActorID, "Tool checked in")
+		err := mocks.Service.LogToolCheckedIn(context.Background(), tool, tool, TestUserID, TestActorID, "Tool checked in")
 
 		require.NoError(t, err)
 	})
@@ -476,18 +687,22 @@ func TestEventService_AdditionalLoggingMethods(t *testing.T) {
 
 		toolID := TestToolID
 		userID := TestUserID
-		createdEvent := CreateTestEvent(TestEventID, domain.EventTypeToolMaintenance, &toolID, &userID, nil, "Tool needs maintenance")
+		actorID := TestActorID
+		// the holder is the event's user and the caller its actor
+		held := tool
+		held.CurrentUserId = &userID
+		createdEvent := CreateTestEvent(TestEventID, domain.EventTypeToolMaintenance, &toolID, &userID, &actorID, "Tool needs maintenance")
 
-		mocks.MockRepo.EXPECT().Create(
+		mocks.MockRepo.EXPECT().Create(gomock.Any(),
 			domain.EventTypeToolMaintenance,
 			&toolID,
 			&userID,
-			(*string)(nil),
+			&actorID,
 			"Tool needs maintenance",
-			(*string)(nil),
+			gomock.Any(),
 		).Return(createdEvent, nil)
 
-		err := mocks.Service.LogToolMaintenance(TestToolID, TestUserID, "Tool needs maintenance")
+		err := mocks.Service.LogToolMaintenance(context.Background(), held, held, TestActorID, "Tool needs maintenance", "", nil)
 
 		require.NoError(t, err)
 	})
@@ -498,18 +713,22 @@ func TestEventService_AdditionalLoggingMethods(t *testing.T) {
 
 		toolID := TestToolID
 		userID := TestUserID
-		createdEvent := CreateTestEvent(TestEventID, domain.EventTypeToolLost, &toolID, &userID, nil, "Tool lost")
+		actorID := TestActorID
+		// the holder is the event's user and the caller its actor
+		held := tool
+		held.CurrentUserId = &userID
+		createdEvent := CreateTestEvent(TestEventID, domain.EventTypeToolLost, &toolID, &userID, &actorID, "Tool lost")
 
-		mocks.MockRepo.EXPECT().Create(
+		mocks.MockRepo.EXPECT().Create(gomock.Any(),
 			domain.EventTypeToolLost,
 			&toolID,
 			&userID,
-			(*string)(nil),
+			&actorID,
 			"Tool lost",
-			(*string)(nil),
+			gomock.Any(),
 		).Return(createdEvent, nil)
 
-		err := mocks.Service.LogToolLost(TestToolID, TestUserID, "Tool lost")
+		err := mocks.Service.LogToolLost(context.Background(), held, held, TestActorID, "Tool lost")
 
 		require.NoError(t, err)
 	})
@@ -522,16 +741,16 @@ func TestEventService_AdditionalLoggingMethods(t *testing.T) {
 		actorID := TestActorID
 		createdEvent := CreateTestEvent(TestEventID, domain.EventTypeUserCreated, nil, &userID, &actorID, "User created")
 
-		mocks.MockRepo.EXPECT().Create(
+		mocks.MockRepo.EXPECT().Create(gomock.Any(),
 			domain.EventTypeUserCreated,
 			(*string)(nil),
 			&userID,
 			&actorID,
 			"User created",
-			(*string)(nil),
+			gomock.Any(),
 		).Return(createdEvent, nil)
 
-		err := mocks.Service.LogUserCreated(TestUserID, TestActorID, "User created")
+		err := mocks.Service.LogUserCreated(context.Background(), user, TestActorID, "User created")
 
 		require.NoError(t, err)
 	})
@@ -544,16 +763,16 @@ func TestEventService_AdditionalLoggingMethods(t *testing.T) {
 		actorID := TestActorID
 		createdEvent := CreateTestEvent(TestEventID, domain.EventTypeUserUpdated, nil, &userID, &actorID, "User updated")
 
-		mocks.MockRepo.EXPECT().Create(
+		mocks.MockRepo.EXPECT().Create(gomock.Any(),
 			domain.EventTypeUserUpdated,
 			(*string)(nil),
 			&userID,
 			&actorID,
 			"User updated",
-			(*string)(nil),
+			gomock.Any(),
 		).Return(createdEvent, nil)
 
-		err := mocks.Service.LogUserUpdated(TestUserID, TestActorID, "User updated")
+		err := mocks.Service.LogUserUpdated(context.Background(), user, user, TestActorID, "User updated")
 
 		require.NoError(t, err)
 	})
@@ -566,16 +785,16 @@ func TestEventService_AdditionalLoggingMethods(t *testing.T) {
 		actorID := TestActorID
 		createdEvent := CreateTestEvent(TestEventID, domain.EventTypeUserDeleted, nil, &userID, &actorID, "User deleted")
 
-		mocks.MockRepo.EXPECT().Create(
+		mocks.MockRepo.EXPECT().Create(gomock.Any(),
 			domain.EventTypeUserDeleted,
 			(*string)(nil),
 			&userID,
 			&actorID,
 			"User deleted",
-			(*string)(nil),
+			gomock.Any(),
 		).Return(createdEvent, nil)
 
-		err := mocks.Service.LogUserDeleted(TestUserID, TestActorID, "User deleted")
+		err := mocks.Service.LogUserDeleted(context.Background(), user, TestActorID, "User deleted")
 
 		require.NoError(t, err)
 	})
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/logging"
+)
+
+// logOp logs a finished service operation with its outcome and latency through
+// the context's logger, so the line carries the caller's request_id. Defer it
+// first thing in the method, with a pointer to the named error result:
+//
+//	defer logOp(ctx, "tool.checkout", time.Now(), &opErr, "tool_id", toolID, "actor_id", actorID)
+func logOp(ctx context.Context, op string, start time.Time, err *error, attrs ...any) {
+	attrs = append(attrs, "op", op, "latency_ms", time.Since(start).Milliseconds())
+	level := slog.LevelInfo
+	if *err != nil {
+		level = slog.LevelWarn
+		attrs = append(attrs, "error", (*err).Error())
+	}
+	logging.FromContext(ctx).Log(ctx, level, "service call", attrs...)
+}
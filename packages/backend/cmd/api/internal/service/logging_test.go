@@ -0,0 +1,34 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/logging"
+)
+
+func TestLogOp(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, logging.FormatText, "info")
+	require.NoError(t, err)
+	ctx := logging.NewContext(context.Background(), logger.With("request_id", "r1"))
+
+	var opErr error
+	logOp(ctx, "tool.checkout", time.Now(), &opErr, "tool_id", TestToolID)
+	assert.Contains(t, buf.String(), "level=INFO")
+	assert.Contains(t, buf.String(), "request_id=r1")
+	assert.Contains(t, buf.String(), "tool_id="+TestToolID)
+	assert.Contains(t, buf.String(), "op=tool.checkout")
+	assert.Contains(t, buf.String(), "latency_ms=")
+
+	buf.Reset()
+	opErr = errors.New("boom")
+	logOp(ctx, "tool.checkout", time.Now(), &opErr)
+	assert.Contains(t, buf.String(), "level=WARN")
+	assert.Contains(t, buf.String(), "error=boom")
+}
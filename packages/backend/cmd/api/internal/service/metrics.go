@@ -0,0 +1,12 @@
+package service
+
+import "github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+
+// Metrics records business activity so it can be measured independently of HTTP
+// traffic (metrics.Metrics implements it). Services without one record nothing.
+type Metrics interface {
+	// ToolAction counts a tool action (checkout, checkin, ...) with its outcome; err is nil on success.
+	ToolAction(action string, err error)
+	// EventWritten counts an event stored in the audit log.
+	EventWritten(eventType domain.EventType)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// fakeMetrics records what the services report.
+type fakeMetrics struct {
+	actions map[string][]error
+	events  []domain.EventType
+}
+
+func newFakeMetrics() *fakeMetrics {
+	return &fakeMetrics{actions: map[string][]error{}}
+}
+
+func (m *fakeMetrics) ToolAction(action string, err error) {
+	m.actions[action] = append(m.actions[action], err)
+}
+
+func (m *fakeMetrics) EventWritten(eventType domain.EventType) {
+	m.events = append(m.events, eventType)
+}
+
+func TestToolService_Metrics(t *testing.T) {
+	t.Run("Successful checkout is counted", func(t *testing.T) {
+		mocks := SetupToolServiceMocks(t)
+		defer mocks.Teardown()
+		metrics := newFakeMetrics()
+		mocks.Service.WithMetrics(metrics)
+
+		checkedOut := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusCheckedOut)
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(CreateTestTool(TestToolID, "Hammer", domain.ToolStatusInOffice), nil)
+		mocks.MockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(checkedOut, nil)
+
+		_, err := mocks.Service.CheckOutTool(context.Background(), TestToolID, domain.AnyVersion, TestUserID, TestActorID, "", CheckoutOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]error{"checkout": {nil}}, metrics.actions)
+	})
+
+	t.Run("Rejected return is counted with its error", func(t *testing.T) {
+		mocks := SetupToolServiceMocks(t)
+		defer mocks.Teardown()
+		metrics := newFakeMetrics()
+		mocks.Service.WithMetrics(metrics)
+
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(CreateTestTool(TestToolID, "Hammer", domain.ToolStatusInOffice), nil)
+
+		_, err := mocks.Service.ReturnTool(context.Background(), TestToolID, domain.AnyVersion, TestActorID, "", ReturnOptions{})
+
+		require.Error(t, err)
+		require.Len(t, metrics.actions["checkin"], 1)
+		assert.Equal(t, err, metrics.actions["checkin"][0])
+	})
+}
+
+func TestEventService_Metrics(t *testing.T) {
+	t.Run("Stored event is counted by type", func(t *testing.T) {
+		mocks := SetupEventServiceMocks(t)
+		defer mocks.Teardown()
+		metrics := newFakeMetrics()
+		mocks.Service.WithMetrics(metrics)
+
+		toolID := TestToolID
+		mocks.MockRepo.EXPECT().Create(gomock.Any(), domain.EventTypeToolCreated, &toolID, nil, nil, "", nil).
+			Return(CreateTestEvent(TestEventID, domain.EventTypeToolCreated, &toolID, nil, nil, ""), nil)
+
+		_, err := mocks.Service.CreateEvent(context.Background(), domain.EventTypeToolCreated, &toolID, nil, nil, "", nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, []domain.EventType{domain.EventTypeToolCreated}, metrics.events)
+	})
+
+	t.Run("Events logged in a unit of work are left to it", func(t *testing.T) {
+		mocks := SetupEventServiceMocks(t)
+		defer mocks.Teardown()
+		metrics := newFakeMetrics()
+		bound := mocks.Service.WithMetrics(metrics).WithRepo(mocks.MockRepo).(*EventService)
+
+		toolID := TestToolID
+		mocks.MockRepo.EXPECT().Create(gomock.Any(), domain.EventTypeToolCreated, &toolID, nil, nil, "", nil).
+			Return(CreateTestEvent(TestEventID, domain.EventTypeToolCreated, &toolID, nil, nil, ""), nil)
+
+		_, err := bound.CreateEvent(context.Background(), domain.EventTypeToolCreated, &toolID, nil, nil, "", nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, metrics.events)
+	})
+
+	t.Run("Failed insert is not counted", func(t *testing.T) {
+		mocks := SetupEventServiceMocks(t)
+		defer mocks.Teardown()
+		metrics := newFakeMetrics()
+		mocks.Service.WithMetrics(metrics)
+
+		mocks.MockRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(domain.Event{}, errors.New("insert failed"))
+
+		_, err := mocks.Service.CreateEvent(context.Background(), domain.EventTypeToolCreated, nil, nil, nil, "", nil)
+
+		require.Error(t, err)
+		assert.Empty(t, metrics.events)
+	})
+}
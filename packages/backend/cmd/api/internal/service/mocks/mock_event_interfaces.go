@@ -5,7 +5,9 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	domain "github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
@@ -35,122 +37,242 @@ func (m *MockEventRepo) EXPECT() *MockEventRepoMockRecorder {
 	return m.recorder
 }
 
+// ChainHead mocks base method.
+func (m *MockEventRepo) ChainHead(ctx context.Context) (domain.ChainHead, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainHead", ctx)
+	ret0, _ := ret[0].(domain.ChainHead)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChainHead indicates an expected call of ChainHead.
+func (mr *MockEventRepoMockRecorder) ChainHead(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainHead", reflect.TypeOf((*MockEventRepo)(nil).ChainHead), ctx)
+}
+
 // Count mocks base method.
-func (m *MockEventRepo) Count() (int, error) {
+func (m *MockEventRepo) Count(ctx context.Context) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Count")
+	ret := m.ctrl.Call(m, "Count", ctx)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Count indicates an expected call of Count.
-func (mr *MockEventRepoMockRecorder) Count() *gomock.Call {
+func (mr *MockEventRepoMockRecorder) Count(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockEventRepo)(nil).Count), ctx)
+}
+
+// CountByType mocks base method.
+func (m *MockEventRepo) CountByType(ctx context.Context, since *time.Time) (map[domain.EventType]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByType", ctx, since)
+	ret0, _ := ret[0].(map[domain.EventType]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByType indicates an expected call of CountByType.
+func (mr *MockEventRepoMockRecorder) CountByType(ctx, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByType", reflect.TypeOf((*MockEventRepo)(nil).CountByType), ctx, since)
+}
+
+// CountUnchained mocks base method.
+func (m *MockEventRepo) CountUnchained(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnchained", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnchained indicates an expected call of CountUnchained.
+func (mr *MockEventRepoMockRecorder) CountUnchained(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnchained", reflect.TypeOf((*MockEventRepo)(nil).CountUnchained), ctx)
+}
+
+// CountWithFilter mocks base method.
+func (m *MockEventRepo) CountWithFilter(ctx context.Context, filter repo.EventFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWithFilter", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWithFilter indicates an expected call of CountWithFilter.
+func (mr *MockEventRepoMockRecorder) CountWithFilter(ctx, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockEventRepo)(nil).Count))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWithFilter", reflect.TypeOf((*MockEventRepo)(nil).CountWithFilter), ctx, filter)
 }
 
 // Create mocks base method.
-func (m *MockEventRepo) Create(eventType domain.EventType, toolID, userID, actorID *string, notes string, metadata *string) (domain.Event, error) {
+func (m *MockEventRepo) Create(ctx context.Context, eventType domain.EventType, toolID, userID, actorID *string, notes string, metadata *domain.EventMetadata) (domain.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", eventType, toolID, userID, actorID, notes, metadata)
+	ret := m.ctrl.Call(m, "Create", ctx, eventType, toolID, userID, actorID, notes, metadata)
 	ret0, _ := ret[0].(domain.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockEventRepoMockRecorder) Create(eventType, toolID, userID, actorID, notes, metadata interface{}) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) Create(ctx, eventType, toolID, userID, actorID, notes, metadata interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEventRepo)(nil).Create), eventType, toolID, userID, actorID, notes, metadata)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEventRepo)(nil).Create), ctx, eventType, toolID, userID, actorID, notes, metadata)
 }
 
 // Get mocks base method.
-func (m *MockEventRepo) Get(id string) (domain.Event, error) {
+func (m *MockEventRepo) Get(ctx context.Context, id string) (domain.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", id)
+	ret := m.ctrl.Call(m, "Get", ctx, id)
 	ret0, _ := ret[0].(domain.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockEventRepoMockRecorder) Get(id interface{}) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) Get(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockEventRepo)(nil).Get), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockEventRepo)(nil).Get), ctx, id)
 }
 
 // List mocks base method.
-func (m *MockEventRepo) List(limit, offset int) ([]domain.Event, error) {
+func (m *MockEventRepo) List(ctx context.Context, limit, offset int) ([]domain.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", limit, offset)
+	ret := m.ctrl.Call(m, "List", ctx, limit, offset)
 	ret0, _ := ret[0].([]domain.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockEventRepoMockRecorder) List(limit, offset interface{}) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) List(ctx, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockEventRepo)(nil).List), limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockEventRepo)(nil).List), ctx, limit, offset)
 }
 
 // ListByTool mocks base method.
-func (m *MockEventRepo) ListByTool(toolID string, limit, offset int) ([]domain.Event, error) {
+func (m *MockEventRepo) ListByTool(ctx context.Context, toolID string, after *domain.Cursor, limit, offset int) ([]domain.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByTool", toolID, limit, offset)
+	ret := m.ctrl.Call(m, "ListByTool", ctx, toolID, after, limit, offset)
 	ret0, _ := ret[0].([]domain.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListByTool indicates an expected call of ListByTool.
-func (mr *MockEventRepoMockRecorder) ListByTool(toolID, limit, offset interface{}) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) ListByTool(ctx, toolID, after, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByTool", reflect.TypeOf((*MockEventRepo)(nil).ListByTool), toolID, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByTool", reflect.TypeOf((*MockEventRepo)(nil).ListByTool), ctx, toolID, after, limit, offset)
 }
 
 // ListByType mocks base method.
-func (m *MockEventRepo) ListByType(eventType domain.EventType, limit, offset int) ([]domain.Event, error) {
+func (m *MockEventRepo) ListByType(ctx context.Context, eventType domain.EventType, limit, offset int) ([]domain.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByType", eventType, limit, offset)
+	ret := m.ctrl.Call(m, "ListByType", ctx, eventType, limit, offset)
 	ret0, _ := ret[0].([]domain.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListByType indicates an expected call of ListByType.
-func (mr *MockEventRepoMockRecorder) ListByType(eventType, limit, offset interface{}) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) ListByType(ctx, eventType, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByType", reflect.TypeOf((*MockEventRepo)(nil).ListByType), eventType, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByType", reflect.TypeOf((*MockEventRepo)(nil).ListByType), ctx, eventType, limit, offset)
 }
 
 // ListByUser mocks base method.
-func (m *MockEventRepo) ListByUser(userID string, limit, offset int) ([]domain.Event, error) {
+func (m *MockEventRepo) ListByUser(ctx context.Context, userID string, after *domain.Cursor, limit, offset int) ([]domain.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByUser", userID, limit, offset)
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID, after, limit, offset)
 	ret0, _ := ret[0].([]domain.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListByUser indicates an expected call of ListByUser.
-func (mr *MockEventRepoMockRecorder) ListByUser(userID, limit, offset interface{}) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) ListByUser(ctx, userID, after, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockEventRepo)(nil).ListByUser), ctx, userID, after, limit, offset)
+}
+
+// ListChain mocks base method.
+func (m *MockEventRepo) ListChain(ctx context.Context, afterSeq, throughSeq int64, limit int) ([]domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChain", ctx, afterSeq, throughSeq, limit)
+	ret0, _ := ret[0].([]domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChain indicates an expected call of ListChain.
+func (mr *MockEventRepoMockRecorder) ListChain(ctx, afterSeq, throughSeq, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChain", reflect.TypeOf((*MockEventRepo)(nil).ListChain), ctx, afterSeq, throughSeq, limit)
+}
+
+// ListStatusChanges mocks base method.
+func (m *MockEventRepo) ListStatusChanges(ctx context.Context, from, to time.Time, after *domain.Cursor, limit int) ([]domain.StatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStatusChanges", ctx, from, to, after, limit)
+	ret0, _ := ret[0].([]domain.StatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStatusChanges indicates an expected call of ListStatusChanges.
+func (mr *MockEventRepoMockRecorder) ListStatusChanges(ctx, from, to, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStatusChanges", reflect.TypeOf((*MockEventRepo)(nil).ListStatusChanges), ctx, from, to, after, limit)
+}
+
+// ListStatusesAt mocks base method.
+func (m *MockEventRepo) ListStatusesAt(ctx context.Context, at time.Time) ([]domain.StatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStatusesAt", ctx, at)
+	ret0, _ := ret[0].([]domain.StatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStatusesAt indicates an expected call of ListStatusesAt.
+func (mr *MockEventRepoMockRecorder) ListStatusesAt(ctx, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStatusesAt", reflect.TypeOf((*MockEventRepo)(nil).ListStatusesAt), ctx, at)
+}
+
+// ListToolReplay mocks base method.
+func (m *MockEventRepo) ListToolReplay(ctx context.Context, after *domain.Event, limit int) ([]domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListToolReplay", ctx, after, limit)
+	ret0, _ := ret[0].([]domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListToolReplay indicates an expected call of ListToolReplay.
+func (mr *MockEventRepoMockRecorder) ListToolReplay(ctx, after, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockEventRepo)(nil).ListByUser), userID, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListToolReplay", reflect.TypeOf((*MockEventRepo)(nil).ListToolReplay), ctx, after, limit)
 }
 
 // ListWithFilter mocks base method.
-func (m *MockEventRepo) ListWithFilter(filter repo.EventFilter, limit, offset int) ([]domain.Event, error) {
+func (m *MockEventRepo) ListWithFilter(ctx context.Context, filter repo.EventFilter, limit, offset int) ([]domain.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListWithFilter", filter, limit, offset)
+	ret := m.ctrl.Call(m, "ListWithFilter", ctx, filter, limit, offset)
 	ret0, _ := ret[0].([]domain.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListWithFilter indicates an expected call of ListWithFilter.
-func (mr *MockEventRepoMockRecorder) ListWithFilter(filter, limit, offset interface{}) *gomock.Call {
+func (mr *MockEventRepoMockRecorder) ListWithFilter(ctx, filter, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithFilter", reflect.TypeOf((*MockEventRepo)(nil).ListWithFilter), filter, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithFilter", reflect.TypeOf((*MockEventRepo)(nil).ListWithFilter), ctx, filter, limit, offset)
 }
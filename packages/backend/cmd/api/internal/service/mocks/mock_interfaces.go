@@ -5,10 +5,13 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	domain "github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	repo "github.com/wassaaa/tool-tracker/cmd/api/internal/repo"
 )
 
 // MockToolRepo is a mock of ToolRepo interface.
@@ -35,122 +38,273 @@ func (m *MockToolRepo) EXPECT() *MockToolRepoMockRecorder {
 }
 
 // Count mocks base method.
-func (m *MockToolRepo) Count() (int, error) {
+func (m *MockToolRepo) Count(ctx context.Context) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Count")
+	ret := m.ctrl.Call(m, "Count", ctx)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Count indicates an expected call of Count.
-func (mr *MockToolRepoMockRecorder) Count() *gomock.Call {
+func (mr *MockToolRepoMockRecorder) Count(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockToolRepo)(nil).Count))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockToolRepo)(nil).Count), ctx)
+}
+
+// CountByStatus mocks base method.
+func (m *MockToolRepo) CountByStatus(ctx context.Context) (map[domain.ToolStatus]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByStatus", ctx)
+	ret0, _ := ret[0].(map[domain.ToolStatus]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByStatus indicates an expected call of CountByStatus.
+func (mr *MockToolRepoMockRecorder) CountByStatus(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByStatus", reflect.TypeOf((*MockToolRepo)(nil).CountByStatus), ctx)
+}
+
+// CountCheckouts mocks base method.
+func (m *MockToolRepo) CountCheckouts(ctx context.Context, now time.Time) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountCheckouts", ctx, now)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CountCheckouts indicates an expected call of CountCheckouts.
+func (mr *MockToolRepoMockRecorder) CountCheckouts(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCheckouts", reflect.TypeOf((*MockToolRepo)(nil).CountCheckouts), ctx, now)
+}
+
+// CountWithFilter mocks base method.
+func (m *MockToolRepo) CountWithFilter(ctx context.Context, filter repo.ToolFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWithFilter", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWithFilter indicates an expected call of CountWithFilter.
+func (mr *MockToolRepoMockRecorder) CountWithFilter(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWithFilter", reflect.TypeOf((*MockToolRepo)(nil).CountWithFilter), ctx, filter)
 }
 
 // Create mocks base method.
-func (m *MockToolRepo) Create(name string, status domain.ToolStatus) (domain.Tool, error) {
+func (m *MockToolRepo) Create(ctx context.Context, name string, status domain.ToolStatus) (domain.Tool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", name, status)
+	ret := m.ctrl.Call(m, "Create", ctx, name, status)
 	ret0, _ := ret[0].(domain.Tool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockToolRepoMockRecorder) Create(name, status interface{}) *gomock.Call {
+func (mr *MockToolRepoMockRecorder) Create(ctx, name, status interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockToolRepo)(nil).Create), name, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockToolRepo)(nil).Create), ctx, name, status)
 }
 
 // Delete mocks base method.
-func (m *MockToolRepo) Delete(id string) error {
+func (m *MockToolRepo) Delete(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockToolRepoMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockToolRepoMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockToolRepo)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockToolRepo)(nil).Delete), ctx, id)
 }
 
 // Get mocks base method.
-func (m *MockToolRepo) Get(id string) (domain.Tool, error) {
+func (m *MockToolRepo) Get(ctx context.Context, id string) (domain.Tool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", id)
+	ret := m.ctrl.Call(m, "Get", ctx, id)
 	ret0, _ := ret[0].(domain.Tool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockToolRepoMockRecorder) Get(id interface{}) *gomock.Call {
+func (mr *MockToolRepoMockRecorder) Get(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockToolRepo)(nil).Get), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockToolRepo)(nil).Get), ctx, id)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockToolRepo) GetForUpdate(ctx context.Context, id string) (domain.Tool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, id)
+	ret0, _ := ret[0].(domain.Tool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockToolRepoMockRecorder) GetForUpdate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockToolRepo)(nil).GetForUpdate), ctx, id)
+}
+
+// GetIncludingDeleted mocks base method.
+func (m *MockToolRepo) GetIncludingDeleted(ctx context.Context, id string) (domain.Tool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncludingDeleted", ctx, id)
+	ret0, _ := ret[0].(domain.Tool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncludingDeleted indicates an expected call of GetIncludingDeleted.
+func (mr *MockToolRepoMockRecorder) GetIncludingDeleted(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncludingDeleted", reflect.TypeOf((*MockToolRepo)(nil).GetIncludingDeleted), ctx, id)
 }
 
 // List mocks base method.
-func (m *MockToolRepo) List(limit, offset int) ([]domain.Tool, error) {
+func (m *MockToolRepo) List(ctx context.Context, after *domain.Cursor, limit, offset int) ([]domain.Tool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", limit, offset)
+	ret := m.ctrl.Call(m, "List", ctx, after, limit, offset)
 	ret0, _ := ret[0].([]domain.Tool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockToolRepoMockRecorder) List(limit, offset interface{}) *gomock.Call {
+func (mr *MockToolRepoMockRecorder) List(ctx, after, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockToolRepo)(nil).List), limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockToolRepo)(nil).List), ctx, after, limit, offset)
 }
 
 // ListByStatus mocks base method.
-func (m *MockToolRepo) ListByStatus(status domain.ToolStatus, limit, offset int) ([]domain.Tool, error) {
+func (m *MockToolRepo) ListByStatus(ctx context.Context, status domain.ToolStatus, limit, offset int) ([]domain.Tool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByStatus", status, limit, offset)
+	ret := m.ctrl.Call(m, "ListByStatus", ctx, status, limit, offset)
 	ret0, _ := ret[0].([]domain.Tool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListByStatus indicates an expected call of ListByStatus.
-func (mr *MockToolRepoMockRecorder) ListByStatus(status, limit, offset interface{}) *gomock.Call {
+func (mr *MockToolRepoMockRecorder) ListByStatus(ctx, status, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByStatus", reflect.TypeOf((*MockToolRepo)(nil).ListByStatus), status, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByStatus", reflect.TypeOf((*MockToolRepo)(nil).ListByStatus), ctx, status, limit, offset)
 }
 
 // ListByUser mocks base method.
-func (m *MockToolRepo) ListByUser(userID string, limit, offset int) ([]domain.Tool, error) {
+func (m *MockToolRepo) ListByUser(ctx context.Context, userID string, limit, offset int) ([]domain.Tool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByUser", userID, limit, offset)
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID, limit, offset)
 	ret0, _ := ret[0].([]domain.Tool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListByUser indicates an expected call of ListByUser.
-func (mr *MockToolRepoMockRecorder) ListByUser(userID, limit, offset interface{}) *gomock.Call {
+func (mr *MockToolRepoMockRecorder) ListByUser(ctx, userID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockToolRepo)(nil).ListByUser), ctx, userID, limit, offset)
+}
+
+// ListOverdue mocks base method.
+func (m *MockToolRepo) ListOverdue(ctx context.Context, now time.Time, limit, offset int) ([]domain.Tool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverdue", ctx, now, limit, offset)
+	ret0, _ := ret[0].([]domain.Tool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverdue indicates an expected call of ListOverdue.
+func (mr *MockToolRepoMockRecorder) ListOverdue(ctx, now, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverdue", reflect.TypeOf((*MockToolRepo)(nil).ListOverdue), ctx, now, limit, offset)
+}
+
+// ListOverdueUnnotified mocks base method.
+func (m *MockToolRepo) ListOverdueUnnotified(ctx context.Context, now time.Time, limit int) ([]domain.Tool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverdueUnnotified", ctx, now, limit)
+	ret0, _ := ret[0].([]domain.Tool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverdueUnnotified indicates an expected call of ListOverdueUnnotified.
+func (mr *MockToolRepoMockRecorder) ListOverdueUnnotified(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverdueUnnotified", reflect.TypeOf((*MockToolRepo)(nil).ListOverdueUnnotified), ctx, now, limit)
+}
+
+// ListWithFilter mocks base method.
+func (m *MockToolRepo) ListWithFilter(ctx context.Context, filter repo.ToolFilter, limit, offset int) ([]domain.Tool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWithFilter", ctx, filter, limit, offset)
+	ret0, _ := ret[0].([]domain.Tool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWithFilter indicates an expected call of ListWithFilter.
+func (mr *MockToolRepoMockRecorder) ListWithFilter(ctx, filter, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithFilter", reflect.TypeOf((*MockToolRepo)(nil).ListWithFilter), ctx, filter, limit, offset)
+}
+
+// MostCheckedOut mocks base method.
+func (m *MockToolRepo) MostCheckedOut(ctx context.Context, since time.Time, limit int) ([]domain.ToolCheckouts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MostCheckedOut", ctx, since, limit)
+	ret0, _ := ret[0].([]domain.ToolCheckouts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MostCheckedOut indicates an expected call of MostCheckedOut.
+func (mr *MockToolRepoMockRecorder) MostCheckedOut(ctx, since, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MostCheckedOut", reflect.TypeOf((*MockToolRepo)(nil).MostCheckedOut), ctx, since, limit)
+}
+
+// Restore mocks base method.
+func (m *MockToolRepo) Restore(ctx context.Context, id string) (domain.Tool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(domain.Tool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockToolRepoMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockToolRepo)(nil).ListByUser), userID, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockToolRepo)(nil).Restore), ctx, id)
 }
 
 // Update mocks base method.
-func (m *MockToolRepo) Update(arg0 domain.Tool) (domain.Tool, error) {
+func (m *MockToolRepo) Update(ctx context.Context, t domain.Tool) (domain.Tool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0)
+	ret := m.ctrl.Call(m, "Update", ctx, t)
 	ret0, _ := ret[0].(domain.Tool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockToolRepoMockRecorder) Update(arg0 interface{}) *gomock.Call {
+func (mr *MockToolRepoMockRecorder) Update(ctx, t interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockToolRepo)(nil).Update), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockToolRepo)(nil).Update), ctx, t)
 }
 
 // MockEventLogger is a mock of EventLogger interface.
@@ -176,142 +330,226 @@ func (m *MockEventLogger) EXPECT() *MockEventLoggerMockRecorder {
 	return m.recorder
 }
 
+// LogReservationCancelled mocks base method.
+func (m *MockEventLogger) LogReservationCancelled(ctx context.Context, reservationID, toolID, userID, actorID, notes string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogReservationCancelled", ctx, reservationID, toolID, userID, actorID, notes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogReservationCancelled indicates an expected call of LogReservationCancelled.
+func (mr *MockEventLoggerMockRecorder) LogReservationCancelled(ctx, reservationID, toolID, userID, actorID, notes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogReservationCancelled", reflect.TypeOf((*MockEventLogger)(nil).LogReservationCancelled), ctx, reservationID, toolID, userID, actorID, notes)
+}
+
+// LogReservationConverted mocks base method.
+func (m *MockEventLogger) LogReservationConverted(ctx context.Context, reservationID, toolID, userID, actorID, notes string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogReservationConverted", ctx, reservationID, toolID, userID, actorID, notes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogReservationConverted indicates an expected call of LogReservationConverted.
+func (mr *MockEventLoggerMockRecorder) LogReservationConverted(ctx, reservationID, toolID, userID, actorID, notes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogReservationConverted", reflect.TypeOf((*MockEventLogger)(nil).LogReservationConverted), ctx, reservationID, toolID, userID, actorID, notes)
+}
+
+// LogReservationCreated mocks base method.
+func (m *MockEventLogger) LogReservationCreated(ctx context.Context, reservationID, toolID, userID, actorID, notes string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogReservationCreated", ctx, reservationID, toolID, userID, actorID, notes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogReservationCreated indicates an expected call of LogReservationCreated.
+func (mr *MockEventLoggerMockRecorder) LogReservationCreated(ctx, reservationID, toolID, userID, actorID, notes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogReservationCreated", reflect.TypeOf((*MockEventLogger)(nil).LogReservationCreated), ctx, reservationID, toolID, userID, actorID, notes)
+}
+
 // LogToolCheckedIn mocks base method.
-func (m *MockEventLogger) LogToolCheckedIn(toolID, userID, actorID, notes string) error {
+func (m *MockEventLogger) LogToolCheckedIn(ctx context.Context, before, after domain.Tool, userID, actorID, notes string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LogToolCheckedIn", toolID, userID, actorID, notes)
+	ret := m.ctrl.Call(m, "LogToolCheckedIn", ctx, before, after, userID, actorID, notes)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // LogToolCheckedIn indicates an expected call of LogToolCheckedIn.
-func (mr *MockEventLoggerMockRecorder) LogToolCheckedIn(toolID, userID, actorID, notes interface{}) *gomock.Call {
+func (mr *MockEventLoggerMockRecorder) LogToolCheckedIn(ctx, before, after, userID, actorID, notes interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogToolCheckedIn", reflect.TypeOf((*MockEventLogger)(nil).LogToolCheckedIn), toolID, userID, actorID, notes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogToolCheckedIn", reflect.TypeOf((*MockEventLogger)(nil).LogToolCheckedIn), ctx, before, after, userID, actorID, notes)
 }
 
 // LogToolCheckedOut mocks base method.
-func (m *MockEventLogger) LogToolCheckedOut(toolID, userID, actorID, notes string) error {
+func (m *MockEventLogger) LogToolCheckedOut(ctx context.Context, before, after domain.Tool, userID, actorID, notes string, condition domain.ToolCondition) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LogToolCheckedOut", toolID, userID, actorID, notes)
+	ret := m.ctrl.Call(m, "LogToolCheckedOut", ctx, before, after, userID, actorID, notes, condition)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // LogToolCheckedOut indicates an expected call of LogToolCheckedOut.
-func (mr *MockEventLoggerMockRecorder) LogToolCheckedOut(toolID, userID, actorID, notes interface{}) *gomock.Call {
+func (mr *MockEventLoggerMockRecorder) LogToolCheckedOut(ctx, before, after, userID, actorID, notes, condition interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogToolCheckedOut", reflect.TypeOf((*MockEventLogger)(nil).LogToolCheckedOut), toolID, userID, actorID, notes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogToolCheckedOut", reflect.TypeOf((*MockEventLogger)(nil).LogToolCheckedOut), ctx, before, after, userID, actorID, notes, condition)
 }
 
 // LogToolCreated mocks base method.
-func (m *MockEventLogger) LogToolCreated(toolID, actorID, notes string) error {
+func (m *MockEventLogger) LogToolCreated(ctx context.Context, tool domain.Tool, actorID, notes string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LogToolCreated", toolID, actorID, notes)
+	ret := m.ctrl.Call(m, "LogToolCreated", ctx, tool, actorID, notes)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // LogToolCreated indicates an expected call of LogToolCreated.
-func (mr *MockEventLoggerMockRecorder) LogToolCreated(toolID, actorID, notes interface{}) *gomock.Call {
+func (mr *MockEventLoggerMockRecorder) LogToolCreated(ctx, tool, actorID, notes interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogToolCreated", reflect.TypeOf((*MockEventLogger)(nil).LogToolCreated), toolID, actorID, notes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogToolCreated", reflect.TypeOf((*MockEventLogger)(nil).LogToolCreated), ctx, tool, actorID, notes)
 }
 
 // LogToolDeleted mocks base method.
-func (m *MockEventLogger) LogToolDeleted(toolID, actorID, notes string) error {
+func (m *MockEventLogger) LogToolDeleted(ctx context.Context, tool domain.Tool, actorID, notes string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LogToolDeleted", toolID, actorID, notes)
+	ret := m.ctrl.Call(m, "LogToolDeleted", ctx, tool, actorID, notes)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // LogToolDeleted indicates an expected call of LogToolDeleted.
-func (mr *MockEventLoggerMockRecorder) LogToolDeleted(toolID, actorID, notes interface{}) *gomock.Call {
+func (mr *MockEventLoggerMockRecorder) LogToolDeleted(ctx, tool, actorID, notes interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogToolDeleted", reflect.TypeOf((*MockEventLogger)(nil).LogToolDeleted), toolID, actorID, notes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogToolDeleted", reflect.TypeOf((*MockEventLogger)(nil).LogToolDeleted), ctx, tool, actorID, notes)
 }
 
 // LogToolLost mocks base method.
-func (m *MockEventLogger) LogToolLost(toolID, userID, notes string) error {
+func (m *MockEventLogger) LogToolLost(ctx context.Context, before, after domain.Tool, actorID, notes string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LogToolLost", toolID, userID, notes)
+	ret := m.ctrl.Call(m, "LogToolLost", ctx, before, after, actorID, notes)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // LogToolLost indicates an expected call of LogToolLost.
-func (mr *MockEventLoggerMockRecorder) LogToolLost(toolID, userID, notes interface{}) *gomock.Call {
+func (mr *MockEventLoggerMockRecorder) LogToolLost(ctx, before, after, actorID, notes interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogToolLost", reflect.TypeOf((*MockEventLogger)(nil).LogToolLost), toolID, userID, notes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogToolLost", reflect.TypeOf((*MockEventLogger)(nil).LogToolLost), ctx, before, after, actorID, notes)
 }
 
 // LogToolMaintenance mocks base method.
-func (m *MockEventLogger) LogToolMaintenance(toolID, userID, notes string) error {
+func (m *MockEventLogger) LogToolMaintenance(ctx context.Context, before, after domain.Tool, actorID, notes, vendor string, costCents *int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LogToolMaintenance", toolID, userID, notes)
+	ret := m.ctrl.Call(m, "LogToolMaintenance", ctx, before, after, actorID, notes, vendor, costCents)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // LogToolMaintenance indicates an expected call of LogToolMaintenance.
-func (mr *MockEventLoggerMockRecorder) LogToolMaintenance(toolID, userID, notes interface{}) *gomock.Call {
+func (mr *MockEventLoggerMockRecorder) LogToolMaintenance(ctx, before, after, actorID, notes, vendor, costCents interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogToolMaintenance", reflect.TypeOf((*MockEventLogger)(nil).LogToolMaintenance), ctx, before, after, actorID, notes, vendor, costCents)
+}
+
+// LogToolOverdue mocks base method.
+func (m *MockEventLogger) LogToolOverdue(ctx context.Context, toolID, userID string, dueAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogToolOverdue", ctx, toolID, userID, dueAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogToolOverdue indicates an expected call of LogToolOverdue.
+func (mr *MockEventLoggerMockRecorder) LogToolOverdue(ctx, toolID, userID, dueAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogToolMaintenance", reflect.TypeOf((*MockEventLogger)(nil).LogToolMaintenance), toolID, userID, notes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogToolOverdue", reflect.TypeOf((*MockEventLogger)(nil).LogToolOverdue), ctx, toolID, userID, dueAt)
+}
+
+// LogToolRestored mocks base method.
+func (m *MockEventLogger) LogToolRestored(ctx context.Context, before, after domain.Tool, actorID, notes string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogToolRestored", ctx, before, after, actorID, notes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogToolRestored indicates an expected call of LogToolRestored.
+func (mr *MockEventLoggerMockRecorder) LogToolRestored(ctx, before, after, actorID, notes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogToolRestored", reflect.TypeOf((*MockEventLogger)(nil).LogToolRestored), ctx, before, after, actorID, notes)
 }
 
 // LogToolUpdated mocks base method.
-func (m *MockEventLogger) LogToolUpdated(toolID, actorID, notes string) error {
+func (m *MockEventLogger) LogToolUpdated(ctx context.Context, before, after domain.Tool, actorID, notes string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LogToolUpdated", toolID, actorID, notes)
+	ret := m.ctrl.Call(m, "LogToolUpdated", ctx, before, after, actorID, notes)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // LogToolUpdated indicates an expected call of LogToolUpdated.
-func (mr *MockEventLoggerMockRecorder) LogToolUpdated(toolID, actorID, notes interface{}) *gomock.Call {
+func (mr *MockEventLoggerMockRecorder) LogToolUpdated(ctx, before, after, actorID, notes interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogToolUpdated", reflect.TypeOf((*MockEventLogger)(nil).LogToolUpdated), toolID, actorID, notes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogToolUpdated", reflect.TypeOf((*MockEventLogger)(nil).LogToolUpdated), ctx, before, after, actorID, notes)
 }
 
 // LogUserCreated mocks base method.
-func (m *MockEventLogger) LogUserCreated(userID, actorID, notes string) error {
+func (m *MockEventLogger) LogUserCreated(ctx context.Context, user domain.User, actorID, notes string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LogUserCreated", userID, actorID, notes)
+	ret := m.ctrl.Call(m, "LogUserCreated", ctx, user, actorID, notes)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // LogUserCreated indicates an expected call of LogUserCreated.
-func (mr *MockEventLoggerMockRecorder) LogUserCreated(userID, actorID, notes interface{}) *gomock.Call {
+func (mr *MockEventLoggerMockRecorder) LogUserCreated(ctx, user, actorID, notes interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogUserCreated", reflect.TypeOf((*MockEventLogger)(nil).LogUserCreated), userID, actorID, notes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogUserCreated", reflect.TypeOf((*MockEventLogger)(nil).LogUserCreated), ctx, user, actorID, notes)
 }
 
 // LogUserDeleted mocks base method.
-func (m *MockEventLogger) LogUserDeleted(userID, actorID, notes string) error {
+func (m *MockEventLogger) LogUserDeleted(ctx context.Context, user domain.User, actorID, notes string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LogUserDeleted", userID, actorID, notes)
+	ret := m.ctrl.Call(m, "LogUserDeleted", ctx, user, actorID, notes)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // LogUserDeleted indicates an expected call of LogUserDeleted.
-func (mr *MockEventLoggerMockRecorder) LogUserDeleted(userID, actorID, notes interface{}) *gomock.Call {
+func (mr *MockEventLoggerMockRecorder) LogUserDeleted(ctx, user, actorID, notes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogUserDeleted", reflect.TypeOf((*MockEventLogger)(nil).LogUserDeleted), ctx, user, actorID, notes)
+}
+
+// LogUserRestored mocks base method.
+func (m *MockEventLogger) LogUserRestored(ctx context.Context, before, after domain.User, actorID, notes string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogUserRestored", ctx, before, after, actorID, notes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogUserRestored indicates an expected call of LogUserRestored.
+func (mr *MockEventLoggerMockRecorder) LogUserRestored(ctx, before, after, actorID, notes interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogUserDeleted", reflect.TypeOf((*MockEventLogger)(nil).LogUserDeleted), userID, actorID, notes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogUserRestored", reflect.TypeOf((*MockEventLogger)(nil).LogUserRestored), ctx, before, after, actorID, notes)
 }
 
 // LogUserUpdated mocks base method.
-func (m *MockEventLogger) LogUserUpdated(userID, actorID, notes string) error {
+func (m *MockEventLogger) LogUserUpdated(ctx context.Context, before, after domain.User, actorID, notes string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LogUserUpdated", userID, actorID, notes)
+	ret := m.ctrl.Call(m, "LogUserUpdated", ctx, before, after, actorID, notes)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // LogUserUpdated indicates an expected call of LogUserUpdated.
-func (mr *MockEventLoggerMockRecorder) LogUserUpdated(userID, actorID, notes interface{}) *gomock.Call {
+func (mr *MockEventLoggerMockRecorder) LogUserUpdated(ctx, before, after, actorID, notes interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogUserUpdated", reflect.TypeOf((*MockEventLogger)(nil).LogUserUpdated), userID, actorID, notes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogUserUpdated", reflect.TypeOf((*MockEventLogger)(nil).LogUserUpdated), ctx, before, after, actorID, notes)
 }
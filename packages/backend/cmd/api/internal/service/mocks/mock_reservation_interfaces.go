@@ -0,0 +1,142 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: reservation_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// MockReservationRepo is a mock of ReservationRepo interface.
+type MockReservationRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockReservationRepoMockRecorder
+}
+
+// MockReservationRepoMockRecorder is the mock recorder for MockReservationRepo.
+type MockReservationRepoMockRecorder struct {
+	mock *MockReservationRepo
+}
+
+// NewMockReservationRepo creates a new mock instance.
+func NewMockReservationRepo(ctrl *gomock.Controller) *MockReservationRepo {
+	mock := &MockReservationRepo{ctrl: ctrl}
+	mock.recorder = &MockReservationRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReservationRepo) EXPECT() *MockReservationRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockReservationRepo) Create(ctx context.Context, r domain.Reservation) (domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, r)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReservationRepoMockRecorder) Create(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReservationRepo)(nil).Create), ctx, r)
+}
+
+// Get mocks base method.
+func (m *MockReservationRepo) Get(ctx context.Context, id string) (domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockReservationRepoMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockReservationRepo)(nil).Get), ctx, id)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockReservationRepo) GetForUpdate(ctx context.Context, id string) (domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, id)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockReservationRepoMockRecorder) GetForUpdate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockReservationRepo)(nil).GetForUpdate), ctx, id)
+}
+
+// ListActiveOverlapping mocks base method.
+func (m *MockReservationRepo) ListActiveOverlapping(ctx context.Context, toolID string, start, end time.Time) ([]domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveOverlapping", ctx, toolID, start, end)
+	ret0, _ := ret[0].([]domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveOverlapping indicates an expected call of ListActiveOverlapping.
+func (mr *MockReservationRepoMockRecorder) ListActiveOverlapping(ctx, toolID, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveOverlapping", reflect.TypeOf((*MockReservationRepo)(nil).ListActiveOverlapping), ctx, toolID, start, end)
+}
+
+// ListByTool mocks base method.
+func (m *MockReservationRepo) ListByTool(ctx context.Context, toolID string, from, to *time.Time) ([]domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByTool", ctx, toolID, from, to)
+	ret0, _ := ret[0].([]domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByTool indicates an expected call of ListByTool.
+func (mr *MockReservationRepoMockRecorder) ListByTool(ctx, toolID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByTool", reflect.TypeOf((*MockReservationRepo)(nil).ListByTool), ctx, toolID, from, to)
+}
+
+// ListByUser mocks base method.
+func (m *MockReservationRepo) ListByUser(ctx context.Context, userID string, limit, offset int) ([]domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID, limit, offset)
+	ret0, _ := ret[0].([]domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockReservationRepoMockRecorder) ListByUser(ctx, userID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockReservationRepo)(nil).ListByUser), ctx, userID, limit, offset)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockReservationRepo) UpdateStatus(ctx context.Context, id string, status domain.ReservationStatus) (domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, status)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockReservationRepoMockRecorder) UpdateStatus(ctx, id, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockReservationRepo)(nil).UpdateStatus), ctx, id, status)
+}
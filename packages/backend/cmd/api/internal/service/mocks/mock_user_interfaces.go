@@ -5,10 +5,13 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	domain "github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	repo "github.com/wassaaa/tool-tracker/cmd/api/internal/repo"
 )
 
 // MockUserRepo is a mock of UserRepo interface.
@@ -35,120 +38,225 @@ func (m *MockUserRepo) EXPECT() *MockUserRepoMockRecorder {
 }
 
 // Count mocks base method.
-func (m *MockUserRepo) Count() (int, error) {
+func (m *MockUserRepo) Count(ctx context.Context) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Count")
+	ret := m.ctrl.Call(m, "Count", ctx)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Count indicates an expected call of Count.
-func (mr *MockUserRepoMockRecorder) Count() *gomock.Call {
+func (mr *MockUserRepoMockRecorder) Count(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockUserRepo)(nil).Count))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockUserRepo)(nil).Count), ctx)
+}
+
+// CountByRole mocks base method.
+func (m *MockUserRepo) CountByRole(ctx context.Context) (map[domain.UserRole]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByRole", ctx)
+	ret0, _ := ret[0].(map[domain.UserRole]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByRole indicates an expected call of CountByRole.
+func (mr *MockUserRepoMockRecorder) CountByRole(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByRole", reflect.TypeOf((*MockUserRepo)(nil).CountByRole), ctx)
+}
+
+// CountWithFilter mocks base method.
+func (m *MockUserRepo) CountWithFilter(ctx context.Context, filter repo.UserFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWithFilter", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWithFilter indicates an expected call of CountWithFilter.
+func (mr *MockUserRepoMockRecorder) CountWithFilter(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWithFilter", reflect.TypeOf((*MockUserRepo)(nil).CountWithFilter), ctx, filter)
 }
 
 // Create mocks base method.
-func (m *MockUserRepo) Create(name, email string, role domain.UserRole) (domain.User, error) {
+func (m *MockUserRepo) Create(ctx context.Context, name, email string, role domain.UserRole) (domain.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", name, email, role)
+	ret := m.ctrl.Call(m, "Create", ctx, name, email, role)
 	ret0, _ := ret[0].(domain.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockUserRepoMockRecorder) Create(name, email, role interface{}) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) Create(ctx, name, email, role interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserRepo)(nil).Create), name, email, role)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserRepo)(nil).Create), ctx, name, email, role)
 }
 
 // Delete mocks base method.
-func (m *MockUserRepo) Delete(id string) error {
+func (m *MockUserRepo) Delete(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockUserRepoMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserRepo)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserRepo)(nil).Delete), ctx, id)
 }
 
 // Get mocks base method.
-func (m *MockUserRepo) Get(id string) (domain.User, error) {
+func (m *MockUserRepo) Get(ctx context.Context, id string) (domain.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", id)
+	ret := m.ctrl.Call(m, "Get", ctx, id)
 	ret0, _ := ret[0].(domain.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockUserRepoMockRecorder) Get(id interface{}) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) Get(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUserRepo)(nil).Get), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUserRepo)(nil).Get), ctx, id)
 }
 
 // GetByEmail mocks base method.
-func (m *MockUserRepo) GetByEmail(email string) (domain.User, error) {
+func (m *MockUserRepo) GetByEmail(ctx context.Context, email string) (domain.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByEmail", email)
+	ret := m.ctrl.Call(m, "GetByEmail", ctx, email)
 	ret0, _ := ret[0].(domain.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByEmail indicates an expected call of GetByEmail.
-func (mr *MockUserRepoMockRecorder) GetByEmail(email interface{}) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) GetByEmail(ctx, email interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockUserRepo)(nil).GetByEmail), email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockUserRepo)(nil).GetByEmail), ctx, email)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockUserRepo) GetForUpdate(ctx context.Context, id string) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, id)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockUserRepoMockRecorder) GetForUpdate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockUserRepo)(nil).GetForUpdate), ctx, id)
+}
+
+// GetIncludingDeleted mocks base method.
+func (m *MockUserRepo) GetIncludingDeleted(ctx context.Context, id string) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncludingDeleted", ctx, id)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncludingDeleted indicates an expected call of GetIncludingDeleted.
+func (mr *MockUserRepoMockRecorder) GetIncludingDeleted(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncludingDeleted", reflect.TypeOf((*MockUserRepo)(nil).GetIncludingDeleted), ctx, id)
 }
 
 // List mocks base method.
-func (m *MockUserRepo) List(limit, offset int) ([]domain.User, error) {
+func (m *MockUserRepo) List(ctx context.Context, after *domain.Cursor, limit, offset int) ([]domain.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", limit, offset)
+	ret := m.ctrl.Call(m, "List", ctx, after, limit, offset)
 	ret0, _ := ret[0].([]domain.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockUserRepoMockRecorder) List(limit, offset interface{}) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) List(ctx, after, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserRepo)(nil).List), limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserRepo)(nil).List), ctx, after, limit, offset)
 }
 
 // ListByRole mocks base method.
-func (m *MockUserRepo) ListByRole(role domain.UserRole, limit, offset int) ([]domain.User, error) {
+func (m *MockUserRepo) ListByRole(ctx context.Context, role domain.UserRole, after *domain.Cursor, limit, offset int) ([]domain.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByRole", role, limit, offset)
+	ret := m.ctrl.Call(m, "ListByRole", ctx, role, after, limit, offset)
 	ret0, _ := ret[0].([]domain.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListByRole indicates an expected call of ListByRole.
-func (mr *MockUserRepoMockRecorder) ListByRole(role, limit, offset interface{}) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) ListByRole(ctx, role, after, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByRole", reflect.TypeOf((*MockUserRepo)(nil).ListByRole), ctx, role, after, limit, offset)
+}
+
+// ListWithFilter mocks base method.
+func (m *MockUserRepo) ListWithFilter(ctx context.Context, filter repo.UserFilter, limit, offset int) ([]domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWithFilter", ctx, filter, limit, offset)
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWithFilter indicates an expected call of ListWithFilter.
+func (mr *MockUserRepoMockRecorder) ListWithFilter(ctx, filter, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithFilter", reflect.TypeOf((*MockUserRepo)(nil).ListWithFilter), ctx, filter, limit, offset)
+}
+
+// Restore mocks base method.
+func (m *MockUserRepo) Restore(ctx context.Context, id string) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockUserRepoMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockUserRepo)(nil).Restore), ctx, id)
+}
+
+// TopBorrowers mocks base method.
+func (m *MockUserRepo) TopBorrowers(ctx context.Context, since time.Time, limit int) ([]domain.UserCheckouts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TopBorrowers", ctx, since, limit)
+	ret0, _ := ret[0].([]domain.UserCheckouts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TopBorrowers indicates an expected call of TopBorrowers.
+func (mr *MockUserRepoMockRecorder) TopBorrowers(ctx, since, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByRole", reflect.TypeOf((*MockUserRepo)(nil).ListByRole), role, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopBorrowers", reflect.TypeOf((*MockUserRepo)(nil).TopBorrowers), ctx, since, limit)
 }
 
 // Update mocks base method.
-func (m *MockUserRepo) Update(id, name, email string, role domain.UserRole) (domain.User, error) {
+func (m *MockUserRepo) Update(ctx context.Context, id, name, email string, role domain.UserRole) (domain.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", id, name, email, role)
+	ret := m.ctrl.Call(m, "Update", ctx, id, name, email, role)
 	ret0, _ := ret[0].(domain.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockUserRepoMockRecorder) Update(id, name, email, role interface{}) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) Update(ctx, id, name, email, role interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepo)(nil).Update), id, name, email, role)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepo)(nil).Update), ctx, id, name, email, role)
 }
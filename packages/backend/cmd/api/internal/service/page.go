@@ -0,0 +1,72 @@
+package service
+
+import "github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+
+// fetchPage loads one page through list and the matching total through count. It asks
+// for one row more than req.Limit to learn whether another page follows; a cursor in req
+// takes the place of its offset. A nil cursorOf leaves NextCursor unset, for orders
+// the keyset cannot follow.
+func fetchPage[T any](req domain.PageRequest, cursorOf func(T) domain.Cursor, list func(after *domain.Cursor, limit, offset int) ([]T, error), count func() (int, error)) (domain.Page[T], error) {
+	var after *domain.Cursor
+	offset := req.Offset
+	if req.Cursor != "" {
+		c, err := domain.DecodeCursor(req.Cursor)
+		if err != nil {
+			return domain.Page[T]{}, err
+		}
+		after = &c
+		offset = 0
+	}
+
+	items, err := list(after, req.Limit+1, offset)
+	if err != nil {
+		return domain.Page[T]{}, err
+	}
+	total, err := count()
+	if err != nil {
+		return domain.Page[T]{}, err
+	}
+	if items == nil {
+		items = []T{}
+	}
+	page := domain.Page[T]{Items: items, Total: total, Limit: req.Limit, Offset: offset, Cursor: req.Cursor}
+	if len(items) > req.Limit {
+		page.Items = items[:req.Limit]
+		page.HasMore = true
+		if cursorOf != nil {
+			page.NextCursor = cursorOf(page.Items[len(page.Items)-1]).Encode()
+		}
+	}
+	return page, nil
+}
+
+// clampPage bounds the limit of req to [1, max], using def when it is unset, and
+// clears a negative offset.
+func clampPage(req domain.PageRequest, def, max int) domain.PageRequest {
+	if req.Limit <= 0 {
+		req.Limit = def
+	}
+	if req.Limit > max {
+		req.Limit = max
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+	return req
+}
+
+func toolCursor(t domain.Tool) domain.Cursor {
+	c := domain.Cursor{CreatedAt: t.CreatedAt}
+	if t.ID != nil {
+		c.ID = *t.ID
+	}
+	return c
+}
+
+func userCursor(u domain.User) domain.Cursor {
+	return domain.Cursor{CreatedAt: u.CreatedAt, ID: u.ID}
+}
+
+func eventCursor(e domain.Event) domain.Cursor {
+	return domain.Cursor{CreatedAt: e.CreatedAt, ID: e.ID}
+}
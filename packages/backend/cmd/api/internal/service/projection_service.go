@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/tracing"
+)
+
+// projectionBatch is how many events or tools the projection reads per query.
+const projectionBatch = 500
+
+// RepairNotes is the note of the TOOL_UPDATED event a projection repair logs.
+const RepairNotes = "Repaired from the event log"
+
+// ToolProjector rebuilds the state of each tool by replaying its events in order.
+//
+// An event that recorded the tool after the change (see domain.EventMetadata.After)
+// sets the state from that snapshot. Older events without one fall back to what
+// their type implies; TOOL_UPDATED then carries no state, as with utilization.
+type ToolProjector struct {
+	states map[string]domain.ToolState
+}
+
+func NewToolProjector() *ToolProjector {
+	return &ToolProjector{states: map[string]domain.ToolState{}}
+}
+
+// Apply replays one event. Events must be applied oldest first.
+func (p *ToolProjector) Apply(e domain.Event) {
+	if e.ToolID == nil {
+		return
+	}
+	id := *e.ToolID
+	state, seen := p.states[id]
+	if !seen {
+		state.Status = domain.ToolStatusInOffice
+	}
+
+	if after, ok := snapshotState(e.Metadata); ok {
+		p.states[id] = after
+		return
+	}
+	switch e.Type {
+	case domain.EventTypeToolCheckedOut:
+		state.Status = domain.ToolStatusCheckedOut
+		state.CurrentUserID = e.UserID
+	case domain.EventTypeToolCheckedIn:
+		state.Status = domain.ToolStatusInOffice
+		state.CurrentUserID = nil
+	case domain.EventTypeToolMaintenance:
+		state.Status = domain.ToolStatusMaintenance
+	case domain.EventTypeToolLost:
+		state.Status = domain.ToolStatusLost
+	}
+	p.states[id] = state
+}
+
+// State returns the replayed state of a tool; ok is false when it had no events.
+func (p *ToolProjector) State(toolID string) (_ domain.ToolState, ok bool) {
+	state, ok := p.states[toolID]
+	return state, ok
+}
+
+// snapshotState reads the state recorded in the After snapshot of m, if any.
+func snapshotState(m *domain.EventMetadata) (domain.ToolState, bool) {
+	if m == nil || len(m.After) == 0 {
+		return domain.ToolState{}, false
+	}
+	var after struct {
+		Status        domain.ToolStatus `json:"status"`
+		CurrentUserID *string           `json:"current_user_id"`
+	}
+	if err := json.Unmarshal(m.After, &after); err != nil || !after.Status.IsValid() {
+		return domain.ToolState{}, false
+	}
+	return domain.ToolState{Status: after.Status, CurrentUserID: after.CurrentUserID}, true
+}
+
+// ProjectionReport is the outcome of comparing the tools table with the projection.
+type ProjectionReport struct {
+	ToolsChecked   int
+	EventsReplayed int
+	Divergences    []domain.ToolDivergence
+	Repaired       int
+	GeneratedAt    time.Time
+}
+
+// ProjectionService checks the tools table against the state the event log leads
+// to, and can rewrite the rows that disagree. Soft-deleted tools are not checked.
+//
+// The events and the tools are read in separate queries, so a tool changed while
+// the check runs may show up as diverging; checking again settles it. A repair is
+// only applied to a row still at the version that was checked.
+type ProjectionService struct {
+	events EventRepo
+	tools  *ToolService
+	now    func() time.Time
+}
+
+func NewProjectionService(events EventRepo, tools *ToolService) *ProjectionService {
+	return &ProjectionService{events: events, tools: tools, now: time.Now}
+}
+
+// CheckTools replays every tool event and reports the tools whose status or current
+// user differ from the projection. With repair set, each of them is rewritten to the
+// projected state on behalf of actorID; tools without events are reported but left alone.
+func (s *ProjectionService) CheckTools(ctx context.Context, repair bool, actorID string) (_ ProjectionReport, opErr error) {
+	defer logOp(ctx, "projection.check_tools", time.Now(), &opErr, "repair", repair, "actor_id", actorID)
+	ctx, span := tracing.Start(ctx, "ProjectionService.CheckTools")
+	defer tracing.End(span, &opErr)
+
+	report := ProjectionReport{Divergences: []domain.ToolDivergence{}, GeneratedAt: s.now()}
+	projector := NewToolProjector()
+	var lastEvent *domain.Event
+	for {
+		events, err := s.events.ListToolReplay(ctx, lastEvent, projectionBatch)
+		if err != nil {
+			return ProjectionReport{}, err
+		}
+		for _, e := range events {
+			projector.Apply(e)
+		}
+		report.EventsReplayed += len(events)
+		if len(events) < projectionBatch {
+			break
+		}
+		lastEvent = &events[len(events)-1]
+	}
+
+	var after *domain.Cursor
+	for {
+		tools, err := s.tools.Repo.List(ctx, after, projectionBatch, 0)
+		if err != nil {
+			return ProjectionReport{}, err
+		}
+		for _, t := range tools {
+			if t.ID == nil {
+				continue
+			}
+			report.ToolsChecked++
+			if d, ok := s.compare(projector, t); ok {
+				if repair && d.Projected != nil {
+					s.repair(ctx, &d, t.Version, actorID)
+					if d.Repaired {
+						report.Repaired++
+					}
+				}
+				report.Divergences = append(report.Divergences, d)
+			}
+		}
+		if len(tools) < projectionBatch {
+			break
+		}
+		c := toolCursor(tools[len(tools)-1])
+		after = &c
+	}
+	return report, nil
+}
+
+// compare reports t as diverging when it has no events or its row differs from the projection.
+func (s *ProjectionService) compare(p *ToolProjector, t domain.Tool) (domain.ToolDivergence, bool) {
+	d := domain.ToolDivergence{ToolID: *t.ID, Name: t.Name, Fields: []string{}, Table: domain.StateOf(t)}
+	projected, ok := p.State(*t.ID)
+	if !ok {
+		return d, true
+	}
+	d.Projected = &projected
+	d.Fields = d.Table.Diff(projected)
+	return d, len(d.Fields) > 0
+}
+
+// repair rewrites the tool of d to its projected state, recording the outcome on d.
+func (s *ProjectionService) repair(ctx context.Context, d *domain.ToolDivergence, version int, actorID string) {
+	if _, err := s.tools.RepairToolState(ctx, d.ToolID, version, *d.Projected, actorID, RepairNotes); err != nil {
+		d.RepairError = err.Error()
+		return
+	}
+	d.Repaired = true
+}
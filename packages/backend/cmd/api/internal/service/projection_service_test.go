@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// TestToolProjector tests replaying events into tool state
+func TestToolProjector(t *testing.T) {
+	toolID, tool2ID, userID := TestToolID, TestToolID2, TestUserID
+
+	t.Run("Events without snapshots follow their type", func(t *testing.T) {
+		p := NewToolProjector()
+		p.Apply(CreateTestEvent("e1", domain.EventTypeToolCreated, &toolID, nil, nil, ""))
+		p.Apply(CreateTestEvent("e2", domain.EventTypeToolCheckedOut, &toolID, &userID, nil, ""))
+
+		state, ok := p.State(TestToolID)
+		require.True(t, ok)
+		assert.Equal(t, domain.ToolStatusCheckedOut, state.Status)
+		assert.Equal(t, &userID, state.CurrentUserID)
+
+		p.Apply(CreateTestEvent("e3", domain.EventTypeToolCheckedIn, &toolID, &userID, nil, ""))
+		p.Apply(CreateTestEvent("e4", domain.EventTypeToolMaintenance, &toolID, &userID, nil, ""))
+		// an update without a snapshot carries no state
+		p.Apply(CreateTestEvent("e5", domain.EventTypeToolUpdated, &toolID, nil, nil, ""))
+
+		state, _ = p.State(TestToolID)
+		assert.Equal(t, domain.ToolState{Status: domain.ToolStatusMaintenance}, state)
+	})
+
+	t.Run("Snapshots set the state", func(t *testing.T) {
+		p := NewToolProjector()
+		updated := CreateTestEvent("e1", domain.EventTypeToolUpdated, &toolID, nil, nil, "")
+		updated.Metadata = &domain.EventMetadata{After: json.RawMessage(`{"status":"LOST","current_user_id":"` + TestUserID + `"}`)}
+		p.Apply(updated)
+
+		state, ok := p.State(TestToolID)
+		require.True(t, ok)
+		assert.Equal(t, domain.ToolStatusLost, state.Status)
+		assert.Equal(t, &userID, state.CurrentUserID)
+	})
+
+	t.Run("Tools without events have no state", func(t *testing.T) {
+		p := NewToolProjector()
+		p.Apply(CreateTestEvent("e1", domain.EventTypeToolCreated, &toolID, nil, nil, ""))
+
+		_, ok := p.State(tool2ID)
+		assert.False(t, ok)
+	})
+}
+
+// TestProjectionService_CheckTools tests comparing the tools table with the projection
+func TestProjectionService_CheckTools(t *testing.T) {
+	toolID, userID := TestToolID, TestUserID
+	events := []domain.Event{
+		CreateTestEvent("e1", domain.EventTypeToolCreated, &toolID, nil, nil, ""),
+		CreateTestEvent("e2", domain.EventTypeToolCheckedOut, &toolID, &userID, nil, ""),
+	}
+	// the row says the tool is back, but no check-in was logged
+	drifted := CreateTestTool(TestToolID, "Drill", domain.ToolStatusInOffice)
+	drifted.Version = 4
+	untracked := CreateTestTool(TestToolID2, "Saw", domain.ToolStatusInOffice)
+
+	t.Run("Reports divergences", func(t *testing.T) {
+		mocks := SetupProjectionServiceMocks(t)
+		defer mocks.Teardown()
+		mocks.MockEvents.EXPECT().ListToolReplay(gomock.Any(), nil, projectionBatch).Return(events, nil)
+		mocks.MockTools.EXPECT().List(gomock.Any(), nil, projectionBatch, 0).Return([]domain.Tool{drifted, untracked}, nil)
+
+		report, err := mocks.Service.CheckTools(context.Background(), false, TestActorID)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, report.ToolsChecked)
+		assert.Equal(t, 2, report.EventsReplayed)
+		assert.Equal(t, 0, report.Repaired)
+		require.Len(t, report.Divergences, 2)
+
+		d := report.Divergences[0]
+		assert.Equal(t, TestToolID, d.ToolID)
+		assert.Equal(t, []string{"status", "current_user_id"}, d.Fields)
+		assert.Equal(t, domain.ToolState{Status: domain.ToolStatusInOffice}, d.Table)
+		require.NotNil(t, d.Projected)
+		assert.Equal(t, domain.ToolStatusCheckedOut, d.Projected.Status)
+		assert.False(t, d.Repaired)
+
+		assert.Equal(t, TestToolID2, report.Divergences[1].ToolID)
+		assert.Nil(t, report.Divergences[1].Projected)
+	})
+
+	t.Run("Matching tools are not reported", func(t *testing.T) {
+		mocks := SetupProjectionServiceMocks(t)
+		defer mocks.Teardown()
+		checkedOut := CreateTestTool(TestToolID, "Drill", domain.ToolStatusCheckedOut)
+		checkedOut.CurrentUserId = &userID
+		mocks.MockEvents.EXPECT().ListToolReplay(gomock.Any(), nil, projectionBatch).Return(events, nil)
+		mocks.MockTools.EXPECT().List(gomock.Any(), nil, projectionBatch, 0).Return([]domain.Tool{checkedOut}, nil)
+
+		report, err := mocks.Service.CheckTools(context.Background(), false, TestActorID)
+
+		require.NoError(t, err)
+		assert.Empty(t, report.Divergences)
+	})
+
+	t.Run("Repair rewrites the row and logs it", func(t *testing.T) {
+		mocks := SetupProjectionServiceMocks(t)
+		defer mocks.Teardown()
+		mocks.MockEvents.EXPECT().ListToolReplay(gomock.Any(), nil, projectionBatch).Return(events, nil)
+		mocks.MockTools.EXPECT().List(gomock.Any(), nil, projectionBatch, 0).Return([]domain.Tool{drifted, untracked}, nil)
+		mocks.MockTools.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(drifted, nil)
+		repaired := drifted
+		repaired.Status = domain.ToolStatusCheckedOut
+		repaired.CurrentUserId = &userID
+		mocks.MockTools.EXPECT().Update(gomock.Any(), repaired).Return(repaired, nil)
+		mocks.MockLogger.EXPECT().LogToolUpdated(gomock.Any(), drifted, repaired, TestActorID, RepairNotes).Return(nil)
+
+		report, err := mocks.Service.CheckTools(context.Background(), true, TestActorID)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Repaired)
+		assert.True(t, report.Divergences[0].Repaired)
+		// a tool without events has nothing to be repaired to
+		assert.False(t, report.Divergences[1].Repaired)
+		assert.Empty(t, report.Divergences[1].RepairError)
+	})
+
+	t.Run("Tool changed since the check is not repaired", func(t *testing.T) {
+		mocks := SetupProjectionServiceMocks(t)
+		defer mocks.Teardown()
+		mocks.MockEvents.EXPECT().ListToolReplay(gomock.Any(), nil, projectionBatch).Return(events, nil)
+		mocks.MockTools.EXPECT().List(gomock.Any(), nil, projectionBatch, 0).Return([]domain.Tool{drifted}, nil)
+		changed := drifted
+		changed.Version = 5
+		mocks.MockTools.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(changed, nil)
+
+		report, err := mocks.Service.CheckTools(context.Background(), true, TestActorID)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, report.Repaired)
+		assert.Contains(t, report.Divergences[0].RepairError, "precondition failed")
+	})
+
+	t.Run("Repository error should propagate", func(t *testing.T) {
+		mocks := SetupProjectionServiceMocks(t)
+		defer mocks.Teardown()
+		mocks.MockEvents.EXPECT().ListToolReplay(gomock.Any(), nil, projectionBatch).Return(nil, assert.AnError)
+
+		_, err := mocks.Service.CheckTools(context.Background(), false, TestActorID)
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
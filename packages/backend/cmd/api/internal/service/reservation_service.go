@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/tracing"
+)
+
+//go:generate mockgen -source=reservation_service.go -destination=mocks/mock_reservation_interfaces.go -package=mocks
+
+type ReservationRepo interface {
+	Create(ctx context.Context, r domain.Reservation) (domain.Reservation, error)
+	Get(ctx context.Context, id string) (domain.Reservation, error)
+	GetForUpdate(ctx context.Context, id string) (domain.Reservation, error)
+	ListByTool(ctx context.Context, toolID string, from, to *time.Time) ([]domain.Reservation, error)
+	ListActiveOverlapping(ctx context.Context, toolID string, start, end time.Time) ([]domain.Reservation, error)
+	ListByUser(ctx context.Context, userID string, limit, offset int) ([]domain.Reservation, error)
+	UpdateStatus(ctx context.Context, id string, status domain.ReservationStatus) (domain.Reservation, error)
+}
+
+type ReservationService struct {
+	Repo   ReservationRepo
+	events EventLogger
+	uow    UnitOfWork
+	now    func() time.Time
+}
+
+// NewReservationService needs the tool repo as well: reservations lock the tool
+// row, so bookings and checkouts of the same tool are serialized.
+func NewReservationService(r ReservationRepo, tools ToolRepo) *ReservationService {
+	return &ReservationService{
+		Repo: r,
+		uow:  directUnitOfWork{repos: TxRepos{Tools: tools, Reservations: r}},
+		now:  time.Now,
+	}
+}
+
+// WithEventLogger sets the event logger dependency (optional chaining style).
+func (s *ReservationService) WithEventLogger(l EventLogger) *ReservationService {
+	s.events = l
+	return s
+}
+
+// WithUnitOfWork makes each mutation commit atomically with its events (optional chaining style).
+func (s *ReservationService) WithUnitOfWork(u UnitOfWork) *ReservationService {
+	s.uow = u
+	return s
+}
+
+// logger returns the event logger for a unit of work (nil if none is configured).
+func (s *ReservationService) logger(tx TxRepos) EventLogger {
+	if s.events == nil {
+		return nil
+	}
+	return bindLogger(s.events, tx)
+}
+
+// CreateReservation books toolID for userID over [startsAt, endsAt). It fails with
+// domain.ErrConflict when the window overlaps another active reservation, or when
+// the tool is checked out to someone else and the reservation would already be running.
+func (s *ReservationService) CreateReservation(ctx context.Context, toolID, userID string, startsAt, endsAt time.Time, actorID, notes string) (_ domain.Reservation, opErr error) {
+	defer logOp(ctx, "reservation.create", time.Now(), &opErr, "tool_id", toolID, "user_id", userID, "actor_id", actorID)
+	ctx, span := tracing.Start(ctx, "ReservationService.CreateReservation", tracing.ToolID(toolID), tracing.UserID(userID))
+	defer tracing.End(span, &opErr)
+	res, err := domain.NewReservation(toolID, userID, startsAt, endsAt, notes)
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	now := s.now()
+	if !res.EndsAt.After(now) {
+		return domain.Reservation{}, fmt.Errorf("%w: ends_at must be in the future", domain.ErrValidation)
+	}
+
+	var created domain.Reservation
+	err = s.uow.Do(ctx, func(tx TxRepos) error {
+		tool, err := tx.Tools.GetForUpdate(ctx, toolID)
+		if err != nil {
+			return err
+		}
+		if tool.Status == domain.ToolStatusLost {
+			return fmt.Errorf("%w: tool is marked as lost", domain.ErrValidation)
+		}
+		// Another user's checkout blocks reservations that would already be running
+		// and, when it has a due date, those starting before the tool is due back.
+		// An open-ended checkout only blocks the former.
+		if tool.CurrentUserId != nil && *tool.CurrentUserId != userID {
+			if res.StartsAt.Before(now) {
+				return fmt.Errorf("%w: tool is currently checked out", domain.ErrConflict)
+			}
+			if tool.DueAt != nil && res.StartsAt.Before(*tool.DueAt) {
+				return fmt.Errorf("%w: tool is checked out until %s", domain.ErrConflict, tool.DueAt.UTC().Format(time.RFC3339))
+			}
+		}
+		if err := checkReservationConflict(ctx, tx.Reservations, toolID, "", res.StartsAt, res.EndsAt); err != nil {
+			return err
+		}
+
+		created, err = tx.Reservations.Create(ctx, res)
+		if err != nil {
+			return err
+		}
+		if l := s.logger(tx); l != nil {
+			return l.LogReservationCreated(ctx, created.ID, toolID, userID, actorID, notes)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	return created, nil
+}
+
+func (s *ReservationService) GetReservation(ctx context.Context, id string) (domain.Reservation, error) {
+	if err := domain.ValidateUUID(id, "reservation_id"); err != nil {
+		return domain.Reservation{}, err
+	}
+	return s.Repo.Get(ctx, id)
+}
+
+// ListToolReservations returns the booking calendar of a tool within [from, to).
+func (s *ReservationService) ListToolReservations(ctx context.Context, toolID string, from, to *time.Time) ([]domain.Reservation, error) {
+	if err := domain.ValidateUUID(toolID, "tool_id"); err != nil {
+		return nil, err
+	}
+	if from != nil && to != nil && !to.After(*from) {
+		return nil, fmt.Errorf("%w: to must be after from", domain.ErrValidation)
+	}
+	return s.Repo.ListByTool(ctx, toolID, from, to)
+}
+
+func (s *ReservationService) ListUserReservations(ctx context.Context, userID string, limit, offset int) ([]domain.Reservation, error) {
+	if err := domain.ValidateUUID(userID, "user_id"); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 500 {
+		limit = 500
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.Repo.ListByUser(ctx, userID, limit, offset)
+}
+
+// CancelReservation releases an active reservation.
+func (s *ReservationService) CancelReservation(ctx context.Context, id, actorID, notes string) (_ domain.Reservation, opErr error) {
+	defer logOp(ctx, "reservation.cancel", time.Now(), &opErr, "reservation_id", id, "actor_id", actorID)
+	ctx, span := tracing.Start(ctx, "ReservationService.CancelReservation", tracing.ReservationID(id))
+	defer tracing.End(span, &opErr)
+	if err := domain.ValidateUUID(id, "reservation_id"); err != nil {
+		return domain.Reservation{}, err
+	}
+
+	var cancelled domain.Reservation
+	err := s.uow.Do(ctx, func(tx TxRepos) error {
+		res, err := tx.Reservations.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !res.IsActive() {
+			return fmt.Errorf("%w: reservation is not active", domain.ErrValidation)
+		}
+
+		cancelled, err = tx.Reservations.UpdateStatus(ctx, id, domain.ReservationStatusCancelled)
+		if err != nil {
+			return err
+		}
+		if l := s.logger(tx); l != nil {
+			return l.LogReservationCancelled(ctx, id, res.ToolID, res.UserID, actorID, notes)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	return cancelled, nil
+}
+
+// ConvertToCheckout checks the reserved tool out to the reservation's user and
+// marks the reservation converted, in one unit of work. Early pickup is allowed
+// as long as no other active reservation is running.
+func (s *ReservationService) ConvertToCheckout(ctx context.Context, id, actorID, notes string) (_ domain.Tool, opErr error) {
+	defer logOp(ctx, "reservation.convert", time.Now(), &opErr, "reservation_id", id, "actor_id", actorID)
+	ctx, span := tracing.Start(ctx, "ReservationService.ConvertToCheckout", tracing.ReservationID(id))
+	defer tracing.End(span, &opErr)
+	if err := domain.ValidateUUID(id, "reservation_id"); err != nil {
+		return domain.Tool{}, err
+	}
+
+	var updated domain.Tool
+	err := s.uow.Do(ctx, func(tx TxRepos) error {
+		res, err := tx.Reservations.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !res.IsActive() {
+			return fmt.Errorf("%w: reservation is not active", domain.ErrValidation)
+		}
+		now := s.now()
+		if !now.Before(res.EndsAt) {
+			return fmt.Errorf("%w: reservation has already ended", domain.ErrValidation)
+		}
+
+		tool, err := tx.Tools.GetForUpdate(ctx, res.ToolID)
+		if err != nil {
+			return err
+		}
+		before := tool
+		if err := checkReservationConflict(ctx, tx.Reservations, res.ToolID, res.UserID, now, now); err != nil {
+			return err
+		}
+		// the tool is due back when the reservation ends
+		if err := tool.CheckOut(res.UserID, &res.EndsAt, now); err != nil {
+			return err
+		}
+		if err := tool.Validate(); err != nil {
+			return err
+		}
+
+		updated, err = tx.Tools.Update(ctx, tool)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Reservations.UpdateStatus(ctx, id, domain.ReservationStatusConverted); err != nil {
+			return err
+		}
+		if l := s.logger(tx); l != nil {
+			if err := l.LogToolCheckedOut(ctx, before, updated, res.UserID, pickActor(actorID, res.UserID), notes, ""); err != nil {
+				return err
+			}
+			return l.LogReservationConverted(ctx, id, res.ToolID, res.UserID, pickActor(actorID, res.UserID), notes)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Tool{}, err
+	}
+	return updated, nil
+}
+
+// checkReservationConflict returns domain.ErrConflict when an active reservation of
+// toolID held by someone other than exceptUserID overlaps [start, end). A zero-length
+// window checks the single instant start. A nil repo (no reservations configured) never conflicts.
+func checkReservationConflict(ctx context.Context, reservations ReservationRepo, toolID, exceptUserID string, start, end time.Time) error {
+	if reservations == nil {
+		return nil
+	}
+	if !end.After(start) {
+		end = start.Add(time.Nanosecond)
+	}
+	overlapping, err := reservations.ListActiveOverlapping(ctx, toolID, start, end)
+	if err != nil {
+		return err
+	}
+	for _, r := range overlapping {
+		if exceptUserID != "" && r.UserID == exceptUserID {
+			continue
+		}
+		return fmt.Errorf("%w: tool is reserved from %s to %s", domain.ErrConflict,
+			r.StartsAt.Format(time.RFC3339), r.EndsAt.Format(time.RFC3339))
+	}
+	return nil
+}
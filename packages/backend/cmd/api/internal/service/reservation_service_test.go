@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/service/mocks"
+)
+
+var testNow = time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+// TestReservationService_CreateReservation tests booking and overlap detection
+func TestReservationService_CreateReservation(t *testing.T) {
+	start := testNow.Add(24 * time.Hour)
+	end := start.Add(8 * time.Hour)
+
+	t.Run("Successful reservation", func(t *testing.T) {
+		mocks := SetupReservationServiceMocks(t, testNow)
+		defer mocks.Teardown()
+
+		tool := CreateTestTool(TestToolID, "Drill", domain.ToolStatusInOffice)
+		created := CreateTestReservation(TestResID, TestUserID, start, end)
+
+		mocks.MockTools.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(tool, nil)
+		mocks.MockRepo.EXPECT().ListActiveOverlapping(gomock.Any(), TestToolID, start, end).Return(nil, nil)
+		mocks.MockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(created, nil)
+		mocks.MockLogger.EXPECT().LogReservationCreated(gomock.Any(), TestResID, TestToolID, TestUserID, TestActorID, "site work").Return(nil)
+
+		result, err := mocks.Service.CreateReservation(context.Background(), TestToolID, TestUserID, start, end, TestActorID, "site work")
+
+		require.NoError(t, err)
+		assert.Equal(t, created, result)
+	})
+
+	t.Run("Overlapping reservation conflicts", func(t *testing.T) {
+		mocks := SetupReservationServiceMocks(t, testNow)
+		defer mocks.Teardown()
+
+		tool := CreateTestTool(TestToolID, "Drill", domain.ToolStatusInOffice)
+		existing := CreateTestReservation(TestResID, TestActorID, start.Add(-time.Hour), start.Add(time.Hour))
+
+		mocks.MockTools.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(tool, nil)
+		mocks.MockRepo.EXPECT().ListActiveOverlapping(gomock.Any(), TestToolID, start, end).Return([]domain.Reservation{existing}, nil)
+
+		_, err := mocks.Service.CreateReservation(context.Background(), TestToolID, TestUserID, start, end, TestActorID, "")
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+	})
+
+	t.Run("Running reservation conflicts with current checkout", func(t *testing.T) {
+		mocks := SetupReservationServiceMocks(t, testNow)
+		defer mocks.Teardown()
+
+		tool := CreateTestTool(TestToolID, "Drill", domain.ToolStatusCheckedOut)
+		holder := TestActorID
+		tool.CurrentUserId = &holder
+
+		mocks.MockTools.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(tool, nil)
+
+		_, err := mocks.Service.CreateReservation(context.Background(), TestToolID, TestUserID, testNow.Add(-time.Hour), end, TestActorID, "")
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+	})
+
+	t.Run("Reservation before the current checkout is due conflicts", func(t *testing.T) {
+		mocks := SetupReservationServiceMocks(t, testNow)
+		defer mocks.Teardown()
+
+		tool := CreateTestTool(TestToolID, "Drill", domain.ToolStatusCheckedOut)
+		holder := TestActorID
+		due := start.Add(time.Hour)
+		tool.CurrentUserId = &holder
+		tool.DueAt = &due
+
+		mocks.MockTools.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(tool, nil)
+
+		_, err := mocks.Service.CreateReservation(context.Background(), TestToolID, TestUserID, start, end, TestActorID, "")
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.ErrorContains(t, err, "checked out until")
+	})
+
+	t.Run("Reservation after the current checkout is due", func(t *testing.T) {
+		mocks := SetupReservationServiceMocks(t, testNow)
+		defer mocks.Teardown()
+
+		tool := CreateTestTool(TestToolID, "Drill", domain.ToolStatusCheckedOut)
+		holder := TestActorID
+		due := start
+		tool.CurrentUserId = &holder
+		tool.DueAt = &due
+		created := CreateTestReservation(TestResID, TestUserID, start, end)
+
+		mocks.MockTools.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(tool, nil)
+		mocks.MockRepo.EXPECT().ListActiveOverlapping(gomock.Any(), TestToolID, start, end).Return(nil, nil)
+		mocks.MockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(created, nil)
+		mocks.MockLogger.EXPECT().LogReservationCreated(gomock.Any(), TestResID, TestToolID, TestUserID, TestActorID, "").Return(nil)
+
+		_, err := mocks.Service.CreateReservation(context.Background(), TestToolID, TestUserID, start, end, TestActorID, "")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("End before start should fail", func(t *testing.T) {
+		mocks := SetupReservationServiceMocks(t, testNow)
+		defer mocks.Teardown()
+
+		_, err := mocks.Service.CreateReservation(context.Background(), TestToolID, TestUserID, end, start, TestActorID, "")
+
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+
+	t.Run("Reservation in the past should fail", func(t *testing.T) {
+		mocks := SetupReservationServiceMocks(t, testNow)
+		defer mocks.Teardown()
+
+		_, err := mocks.Service.CreateReservation(context.Background(), TestToolID, TestUserID, testNow.Add(-2*time.Hour), testNow.Add(-time.Hour), TestActorID, "")
+
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+}
+
+// TestReservationService_CancelReservation tests cancelling reservations
+func TestReservationService_CancelReservation(t *testing.T) {
+	start := testNow.Add(24 * time.Hour)
+
+	t.Run("Successful cancel", func(t *testing.T) {
+		mocks := SetupReservationServiceMocks(t, testNow)
+		defer mocks.Teardown()
+
+		active := CreateTestReservation(TestResID, TestUserID, start, start.Add(time.Hour))
+		cancelled := active
+		cancelled.Status = domain.ReservationStatusCancelled
+
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestResID).Return(active, nil)
+		mocks.MockRepo.EXPECT().UpdateStatus(gomock.Any(), TestResID, domain.ReservationStatusCancelled).Return(cancelled, nil)
+		mocks.MockLogger.EXPECT().LogReservationCancelled(gomock.Any(), TestResID, TestToolID, TestUserID, TestActorID, "plans changed").Return(nil)
+
+		result, err := mocks.Service.CancelReservation(context.Background(), TestResID, TestActorID, "plans changed")
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.ReservationStatusCancelled, result.Status)
+	})
+
+	t.Run("Cannot cancel a converted reservation", func(t *testing.T) {
+		mocks := SetupReservationServiceMocks(t, testNow)
+		defer mocks.Teardown()
+
+		converted := CreateTestReservation(TestResID, TestUserID, start, start.Add(time.Hour))
+		converted.Status = domain.ReservationStatusConverted
+
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestResID).Return(converted, nil)
+
+		_, err := mocks.Service.CancelReservation(context.Background(), TestResID, TestActorID, "")
+
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+}
+
+// TestReservationService_ConvertToCheckout tests turning a reservation into a checkout
+func TestReservationService_ConvertToCheckout(t *testing.T) {
+	t.Run("Successful conversion checks the tool out to the holder", func(t *testing.T) {
+		mocks := SetupReservationServiceMocks(t, testNow)
+		defer mocks.Teardown()
+
+		res := CreateTestReservation(TestResID, TestUserID, testNow.Add(-time.Hour), testNow.Add(time.Hour))
+		tool := CreateTestTool(TestToolID, "Drill", domain.ToolStatusInOffice)
+
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestResID).Return(res, nil)
+		mocks.MockTools.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(tool, nil)
+		mocks.MockRepo.EXPECT().ListActiveOverlapping(gomock.Any(), TestToolID, gomock.Any(), gomock.Any()).Return([]domain.Reservation{res}, nil)
+		mocks.MockTools.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, t domain.Tool) (domain.Tool, error) {
+			return t, nil
+		})
+		mocks.MockRepo.EXPECT().UpdateStatus(gomock.Any(), TestResID, domain.ReservationStatusConverted).Return(res, nil)
+		mocks.MockLogger.EXPECT().LogToolCheckedOut(gomock.Any(), tool, gomock.Any(), TestUserID, TestActorID, "", domain.ToolCondition("")).Return(nil)
+		mocks.MockLogger.EXPECT().LogReservationConverted(gomock.Any(), TestResID, TestToolID, TestUserID, TestActorID, "").Return(nil)
+
+		result, err := mocks.Service.ConvertToCheckout(context.Background(), TestResID, TestActorID, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.ToolStatusCheckedOut, result.Status)
+		require.NotNil(t, result.CurrentUserId)
+		assert.Equal(t, TestUserID, *result.CurrentUserId)
+	})
+
+	t.Run("Ended reservation cannot be converted", func(t *testing.T) {
+		mocks := SetupReservationServiceMocks(t, testNow)
+		defer mocks.Teardown()
+
+		res := CreateTestReservation(TestResID, TestUserID, testNow.Add(-2*time.Hour), testNow.Add(-time.Hour))
+
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestResID).Return(res, nil)
+
+		_, err := mocks.Service.ConvertToCheckout(context.Background(), TestResID, TestActorID, "")
+
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+
+	t.Run("Early pickup blocked by another running reservation", func(t *testing.T) {
+		mocks := SetupReservationServiceMocks(t, testNow)
+		defer mocks.Teardown()
+
+		res := CreateTestReservation(TestResID, TestUserID, testNow.Add(time.Hour), testNow.Add(2*time.Hour))
+		other := CreateTestReservation(TestEventID, TestActorID, testNow.Add(-time.Hour), testNow.Add(time.Hour))
+		tool := CreateTestTool(TestToolID, "Drill", domain.ToolStatusInOffice)
+
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestResID).Return(res, nil)
+		mocks.MockTools.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(tool, nil)
+		mocks.MockRepo.EXPECT().ListActiveOverlapping(gomock.Any(), TestToolID, gomock.Any(), gomock.Any()).Return([]domain.Reservation{other}, nil)
+
+		_, err := mocks.Service.ConvertToCheckout(context.Background(), TestResID, TestActorID, "")
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+	})
+}
+
+// TestToolService_CheckOutTool_Reservations tests that checkouts respect other users' reservations
+func TestToolService_CheckOutTool_Reservations(t *testing.T) {
+	setup := func(t *testing.T) (*ToolService, *mocks.MockToolRepo, *mocks.MockReservationRepo) {
+		ctrl := gomock.NewController(t)
+		tools := mocks.NewMockToolRepo(ctrl)
+		reservations := mocks.NewMockReservationRepo(ctrl)
+		uow := &fakeUnitOfWork{repos: TxRepos{Tools: tools, Reservations: reservations}}
+		return NewToolService(tools).WithUnitOfWork(uow), tools, reservations
+	}
+	now := time.Now()
+	othersReservation := CreateTestReservation(TestResID, TestActorID, now.Add(-time.Hour), now.Add(time.Hour))
+
+	t.Run("Another user's active reservation blocks checkout", func(t *testing.T) {
+		svc, tools, reservations := setup(t)
+
+		tools.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(CreateTestTool(TestToolID, "Drill", domain.ToolStatusInOffice), nil)
+		reservations.EXPECT().ListActiveOverlapping(gomock.Any(), TestToolID, gomock.Any(), gomock.Any()).Return([]domain.Reservation{othersReservation}, nil)
+
+		_, err := svc.CheckOutTool(context.Background(), TestToolID, domain.AnyVersion, TestUserID, TestUserID, "", CheckoutOptions{})
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+	})
+
+	t.Run("The holder's own reservation does not block", func(t *testing.T) {
+		svc, tools, reservations := setup(t)
+		own := CreateTestReservation(TestResID, TestUserID, now.Add(-time.Hour), now.Add(time.Hour))
+
+		tools.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(CreateTestTool(TestToolID, "Drill", domain.ToolStatusInOffice), nil)
+		reservations.EXPECT().ListActiveOverlapping(gomock.Any(), TestToolID, gomock.Any(), gomock.Any()).Return([]domain.Reservation{own}, nil)
+		tools.EXPECT().Update(gomock.Any(), gomock.Any()).Return(CreateTestTool(TestToolID, "Drill", domain.ToolStatusCheckedOut), nil)
+
+		_, err := svc.CheckOutTool(context.Background(), TestToolID, domain.AnyVersion, TestUserID, TestUserID, "", CheckoutOptions{})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("Override skips the reservation check", func(t *testing.T) {
+		svc, tools, _ := setup(t)
+
+		tools.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(CreateTestTool(TestToolID, "Drill", domain.ToolStatusInOffice), nil)
+		tools.EXPECT().Update(gomock.Any(), gomock.Any()).Return(CreateTestTool(TestToolID, "Drill", domain.ToolStatusCheckedOut), nil)
+
+		_, err := svc.CheckOutTool(context.Background(), TestToolID, domain.AnyVersion, TestUserID, TestActorID, "", CheckoutOptions{OverrideReservations: true})
+
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/tracing"
+)
+
+//go:generate mockgen -source=search_service.go -destination=mocks/mock_search_interfaces.go -package=mocks
+
+const (
+	// DefaultSearchLimit is the number of hits returned when no limit is given.
+	DefaultSearchLimit = 20
+	// MaxSearchLimit bounds the number of hits returned.
+	MaxSearchLimit = 50
+	// MaxSearchQueryLength bounds the query text, in characters.
+	MaxSearchQueryLength = 200
+	// MaxSearchTerms bounds the words a query may contain.
+	MaxSearchTerms = 8
+)
+
+type SearchRepo interface {
+	Search(ctx context.Context, terms []string, types []domain.SearchHitType, limit int) ([]domain.SearchHit, error)
+}
+
+// SearchService runs full-text queries over tools, users and event notes.
+type SearchService struct {
+	repo SearchRepo
+}
+
+func NewSearchService(r SearchRepo) *SearchService {
+	return &SearchService{repo: r}
+}
+
+// Search returns the records of the given types that match every word of q, best
+// match first. Words match as prefixes, so partly typed queries work. Nil types means every type and an
+// empty slice matches nothing; the caller restricts them to what the user may read.
+func (s *SearchService) Search(ctx context.Context, q string, types []domain.SearchHitType, limit int) (_ []domain.SearchHit, opErr error) {
+	defer logOp(ctx, "search.query", time.Now(), &opErr)
+	ctx, span := tracing.Start(ctx, "SearchService.Search")
+	defer tracing.End(span, &opErr)
+
+	if len([]rune(q)) > MaxSearchQueryLength {
+		return nil, fmt.Errorf("%w: q must be at most %d characters", domain.ErrValidation, MaxSearchQueryLength)
+	}
+	terms := domain.SearchTerms(q)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%w: q must contain a letter or digit", domain.ErrValidation)
+	}
+	if len(terms) > MaxSearchTerms {
+		return nil, fmt.Errorf("%w: q must have at most %d words", domain.ErrValidation, MaxSearchTerms)
+	}
+
+	if types == nil {
+		types = domain.AllSearchHitTypes
+	}
+	var distinct []domain.SearchHitType
+	for _, t := range types {
+		if err := domain.ValidateSearchHitType(t); err != nil {
+			return nil, err
+		}
+		if !slices.Contains(distinct, t) {
+			distinct = append(distinct, t)
+		}
+	}
+	if len(distinct) == 0 {
+		return []domain.SearchHit{}, nil
+	}
+
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+
+	return s.repo.Search(ctx, terms, distinct, limit)
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// TestSearchService_Search tests query parsing and parameter defaults
+func TestSearchService_Search(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Splits the query into words", func(t *testing.T) {
+		mocks := SetupSearchServiceMocks(t)
+		defer mocks.Teardown()
+		hits := []domain.SearchHit{{Type: domain.SearchHitTool, ID: TestToolID, Title: "Hilti Drill"}}
+		mocks.MockSearch.EXPECT().
+			Search(gomock.Any(), []string{"hilti", "dr"}, domain.AllSearchHitTypes, DefaultSearchLimit).
+			Return(hits, nil)
+
+		result, err := mocks.Service.Search(ctx, "  Hilti: dr*", nil, 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, hits, result)
+	})
+
+	t.Run("Types are deduplicated and the limit is capped", func(t *testing.T) {
+		mocks := SetupSearchServiceMocks(t)
+		defer mocks.Teardown()
+		mocks.MockSearch.EXPECT().
+			Search(gomock.Any(), []string{"saw"}, []domain.SearchHitType{domain.SearchHitUser, domain.SearchHitTool}, MaxSearchLimit).
+			Return([]domain.SearchHit{}, nil)
+
+		_, err := mocks.Service.Search(ctx, "saw", []domain.SearchHitType{domain.SearchHitUser, domain.SearchHitTool, domain.SearchHitUser}, 500)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("No allowed types finds nothing", func(t *testing.T) {
+		mocks := SetupSearchServiceMocks(t)
+		defer mocks.Teardown()
+
+		result, err := mocks.Service.Search(ctx, "saw", []domain.SearchHitType{}, 0)
+
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		mocks := SetupSearchServiceMocks(t)
+		defer mocks.Teardown()
+
+		for _, q := range []string{"", " *&! ", strings.Repeat("a", MaxSearchQueryLength+1), "a b c d e f g h i"} {
+			_, err := mocks.Service.Search(ctx, q, nil, 0)
+			assert.ErrorIs(t, err, domain.ErrValidation, q)
+		}
+
+		_, err := mocks.Service.Search(ctx, "saw", []domain.SearchHitType{"reservation"}, 0)
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+}
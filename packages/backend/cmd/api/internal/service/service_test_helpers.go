@@ -51,6 +51,21 @@ func CreateTestEvent(id string, eventType domain.EventType, toolID, userID, acto
 	}
 }
 
+// CreateTestReservation creates an active reservation of TestToolID for userID over [start, end)
+func CreateTestReservation(id, userID string, start, end time.Time) domain.Reservation {
+	now := time.Now()
+	return domain.Reservation{
+		ID:        id,
+		ToolID:    TestToolID,
+		UserID:    userID,
+		StartsAt:  start,
+		EndsAt:    end,
+		Status:    domain.ReservationStatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
 // Service test mock structures
 
 // UserServiceMocks holds all the mock dependencies for user service testing
@@ -138,6 +153,215 @@ func (esm *EventServiceMocks) Teardown() {
 	esm.Ctrl.Finish()
 }
 
+// APIKeyServiceMocks holds all the mock dependencies for api key service testing
+type APIKeyServiceMocks struct {
+	Ctrl     *gomock.Controller
+	MockRepo *mocks.MockAPIKeyRepo
+	Service  *APIKeyService
+}
+
+// SetupAPIKeyServiceMocks creates all necessary mocks for api key service testing
+func SetupAPIKeyServiceMocks(t *testing.T) *APIKeyServiceMocks {
+	ctrl := gomock.NewController(t)
+
+	mockRepo := mocks.NewMockAPIKeyRepo(ctrl)
+
+	return &APIKeyServiceMocks{
+		Ctrl:     ctrl,
+		MockRepo: mockRepo,
+		Service:  NewAPIKeyService(mockRepo),
+	}
+}
+
+// Teardown cleans up the api key service mocks
+func (asm *APIKeyServiceMocks) Teardown() {
+	asm.Ctrl.Finish()
+}
+
+// ReservationServiceMocks holds all the mock dependencies for reservation service testing
+type ReservationServiceMocks struct {
+	Ctrl       *gomock.Controller
+	MockRepo   *mocks.MockReservationRepo
+	MockTools  *mocks.MockToolRepo
+	MockLogger *mocks.MockEventLogger
+	Service    *ReservationService
+}
+
+// SetupReservationServiceMocks creates all necessary mocks for reservation service testing.
+// The service clock is pinned to now.
+func SetupReservationServiceMocks(t *testing.T, now time.Time) *ReservationServiceMocks {
+	ctrl := gomock.NewController(t)
+
+	mockRepo := mocks.NewMockReservationRepo(ctrl)
+	mockTools := mocks.NewMockToolRepo(ctrl)
+	mockLogger := mocks.NewMockEventLogger(ctrl)
+
+	svc := NewReservationService(mockRepo, mockTools).WithEventLogger(mockLogger)
+	svc.now = func() time.Time { return now }
+
+	return &ReservationServiceMocks{
+		Ctrl:       ctrl,
+		MockRepo:   mockRepo,
+		MockTools:  mockTools,
+		MockLogger: mockLogger,
+		Service:    svc,
+	}
+}
+
+// Teardown cleans up the reservation service mocks
+func (rsm *ReservationServiceMocks) Teardown() {
+	rsm.Ctrl.Finish()
+}
+
+// StatsServiceMocks holds all the mock dependencies for stats service testing
+type StatsServiceMocks struct {
+	Ctrl       *gomock.Controller
+	MockTools  *mocks.MockToolRepo
+	MockUsers  *mocks.MockUserRepo
+	MockEvents *mocks.MockEventRepo
+	Service    *StatsService
+}
+
+// SetupStatsServiceMocks creates all necessary mocks for stats service testing.
+// The service clock reads *now, so tests can move it forward.
+func SetupStatsServiceMocks(t *testing.T, now *time.Time) *StatsServiceMocks {
+	ctrl := gomock.NewController(t)
+
+	mockTools := mocks.NewMockToolRepo(ctrl)
+	mockUsers := mocks.NewMockUserRepo(ctrl)
+	mockEvents := mocks.NewMockEventRepo(ctrl)
+
+	svc := NewStatsService(mockTools, mockUsers, mockEvents)
+	svc.now = func() time.Time { return *now }
+
+	return &StatsServiceMocks{
+		Ctrl:       ctrl,
+		MockTools:  mockTools,
+		MockUsers:  mockUsers,
+		MockEvents: mockEvents,
+		Service:    svc,
+	}
+}
+
+// Teardown cleans up the stats service mocks
+func (ssm *StatsServiceMocks) Teardown() {
+	ssm.Ctrl.Finish()
+}
+
+// UtilizationServiceMocks holds all the mock dependencies for utilization service testing
+type UtilizationServiceMocks struct {
+	Ctrl       *gomock.Controller
+	MockEvents *mocks.MockEventRepo
+	Service    *UtilizationService
+}
+
+// SetupUtilizationServiceMocks creates all necessary mocks for utilization service testing.
+// The service clock is pinned to now.
+func SetupUtilizationServiceMocks(t *testing.T, now time.Time) *UtilizationServiceMocks {
+	ctrl := gomock.NewController(t)
+
+	mockEvents := mocks.NewMockEventRepo(ctrl)
+
+	svc := NewUtilizationService(mockEvents)
+	svc.now = func() time.Time { return now }
+
+	return &UtilizationServiceMocks{
+		Ctrl:       ctrl,
+		MockEvents: mockEvents,
+		Service:    svc,
+	}
+}
+
+// Teardown cleans up the utilization service mocks
+func (usm *UtilizationServiceMocks) Teardown() {
+	usm.Ctrl.Finish()
+}
+
+// ProjectionServiceMocks holds all the mock dependencies for projection service testing
+type ProjectionServiceMocks struct {
+	Ctrl       *gomock.Controller
+	MockEvents *mocks.MockEventRepo
+	MockTools  *mocks.MockToolRepo
+	MockLogger *mocks.MockEventLogger
+	Service    *ProjectionService
+}
+
+// SetupProjectionServiceMocks creates all necessary mocks for projection service testing
+func SetupProjectionServiceMocks(t *testing.T) *ProjectionServiceMocks {
+	ctrl := gomock.NewController(t)
+
+	mockEvents := mocks.NewMockEventRepo(ctrl)
+	mockTools := mocks.NewMockToolRepo(ctrl)
+	mockLogger := mocks.NewMockEventLogger(ctrl)
+
+	return &ProjectionServiceMocks{
+		Ctrl:       ctrl,
+		MockEvents: mockEvents,
+		MockTools:  mockTools,
+		MockLogger: mockLogger,
+		Service:    NewProjectionService(mockEvents, NewToolService(mockTools).WithEventLogger(mockLogger)),
+	}
+}
+
+// Teardown cleans up the projection service mocks
+func (psm *ProjectionServiceMocks) Teardown() {
+	psm.Ctrl.Finish()
+}
+
+// AuditServiceMocks holds all the mock dependencies for audit service testing
+type AuditServiceMocks struct {
+	Ctrl       *gomock.Controller
+	MockEvents *mocks.MockEventRepo
+	Service    *AuditService
+}
+
+// SetupAuditServiceMocks creates all necessary mocks for audit service testing.
+// The service clock is pinned to now.
+func SetupAuditServiceMocks(t *testing.T, now time.Time) *AuditServiceMocks {
+	ctrl := gomock.NewController(t)
+
+	mockEvents := mocks.NewMockEventRepo(ctrl)
+
+	svc := NewAuditService(mockEvents)
+	svc.now = func() time.Time { return now }
+
+	return &AuditServiceMocks{
+		Ctrl:       ctrl,
+		MockEvents: mockEvents,
+		Service:    svc,
+	}
+}
+
+// Teardown cleans up the audit service mocks
+func (asm *AuditServiceMocks) Teardown() {
+	asm.Ctrl.Finish()
+}
+
+// SearchServiceMocks holds all the mock dependencies for search service testing
+type SearchServiceMocks struct {
+	Ctrl       *gomock.Controller
+	MockSearch *mocks.MockSearchRepo
+	Service    *SearchService
+}
+
+// SetupSearchServiceMocks creates all necessary mocks for search service testing
+func SetupSearchServiceMocks(t *testing.T) *SearchServiceMocks {
+	ctrl := gomock.NewController(t)
+
+	mockSearch := mocks.NewMockSearchRepo(ctrl)
+
+	return &SearchServiceMocks{
+		Ctrl:       ctrl,
+		MockSearch: mockSearch,
+		Service:    NewSearchService(mockSearch),
+	}
+}
+
+// Teardown cleans up the search service mocks
+func (ssm *SearchServiceMocks) Teardown() {
+	ssm.Ctrl.Finish()
+}
+
 // Common test patterns
 
 // AssertValidationError checks if the error is a validation error with the expected message
@@ -157,6 +381,8 @@ const (
 	TestUserID  = "456e7890-e89b-12d3-a456-426614174000"
 	TestActorID = "789e0123-e89b-12d3-a456-426614174000"
 	TestEventID = "abc12345-e89b-12d3-a456-426614174000"
+	TestKeyID   = "def67890-e89b-12d3-a456-426614174000"
+	TestResID   = "fed09876-e89b-12d3-a456-426614174000"
 	TestToolID2 = "tool2-567-e89b-12d3-a456-426614174000"
 	TestUserID2 = "user2-890-e89b-12d3-a456-426614174000"
 	InvalidUUID = "invalid-uuid"
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+const (
+	// DefaultStatsWindow is the activity period of Stats when none is given.
+	DefaultStatsWindow = 30 * 24 * time.Hour
+	// MaxStatsWindow bounds the activity period, and so the events scanned.
+	MaxStatsWindow = 365 * 24 * time.Hour
+	// statsTopN is the length of the top borrowers and most used tools lists.
+	statsTopN = 5
+	// defaultStatsTTL is how long a computed snapshot is served before it is recomputed.
+	defaultStatsTTL = 30 * time.Second
+)
+
+// Stats is a snapshot of the system for administrators. Totals and breakdowns
+// describe the current state; EventsByType, TopBorrowers and MostUsedTools
+// cover the activity window starting at Since.
+type Stats struct {
+	TotalTools      int
+	TotalUsers      int
+	TotalEvents     int
+	ToolsByStatus   map[domain.ToolStatus]int
+	UsersByRole     map[domain.UserRole]int
+	ActiveCheckouts int
+	OverdueTools    int
+	Window          time.Duration
+	Since           time.Time
+	EventsByType    map[domain.EventType]int
+	TopBorrowers    []domain.UserCheckouts
+	MostUsedTools   []domain.ToolCheckouts
+	GeneratedAt     time.Time
+}
+
+// StatsService computes Stats from aggregate queries and caches each window's
+// snapshot briefly, so a dashboard polling the endpoint costs one set of
+// queries per TTL rather than one per request.
+type StatsService struct {
+	tools  ToolRepo
+	users  UserRepo
+	events EventRepo
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[time.Duration]Stats
+}
+
+func NewStatsService(tools ToolRepo, users UserRepo, events EventRepo) *StatsService {
+	return &StatsService{
+		tools:  tools,
+		users:  users,
+		events: events,
+		ttl:    defaultStatsTTL,
+		now:    time.Now,
+		cache:  map[time.Duration]Stats{},
+	}
+}
+
+// WithCacheTTL sets how long a snapshot is reused; 0 recomputes on every call (optional chaining style).
+func (s *StatsService) WithCacheTTL(ttl time.Duration) *StatsService {
+	s.ttl = ttl
+	return s
+}
+
+// Stats returns the snapshot for an activity window (0 means DefaultStatsWindow),
+// from the cache when it is younger than the TTL.
+func (s *StatsService) Stats(ctx context.Context, window time.Duration) (Stats, error) {
+	if window == 0 {
+		window = DefaultStatsWindow
+	}
+	if window < 0 || window > MaxStatsWindow {
+		return Stats{}, fmt.Errorf("%w: window must be positive and at most %d days", domain.ErrValidation, int(MaxStatsWindow.Hours()/24))
+	}
+
+	now := s.now()
+	s.mu.Lock()
+	cached, ok := s.cache[window]
+	s.mu.Unlock()
+	if ok && now.Sub(cached.GeneratedAt) < s.ttl {
+		return cached, nil
+	}
+
+	stats, err := s.compute(ctx, window, now)
+	if err != nil {
+		return Stats{}, err
+	}
+	s.mu.Lock()
+	s.cache[window] = stats
+	s.mu.Unlock()
+	return stats, nil
+}
+
+func (s *StatsService) compute(ctx context.Context, window time.Duration, now time.Time) (Stats, error) {
+	stats := Stats{Window: window, Since: now.Add(-window), GeneratedAt: now}
+	var err error
+
+	if stats.TotalTools, err = s.tools.Count(ctx); err != nil {
+		return Stats{}, err
+	}
+	if stats.ToolsByStatus, err = s.tools.CountByStatus(ctx); err != nil {
+		return Stats{}, err
+	}
+	if stats.ActiveCheckouts, stats.OverdueTools, err = s.tools.CountCheckouts(ctx, now); err != nil {
+		return Stats{}, err
+	}
+	if stats.MostUsedTools, err = s.tools.MostCheckedOut(ctx, stats.Since, statsTopN); err != nil {
+		return Stats{}, err
+	}
+
+	if stats.TotalUsers, err = s.users.Count(ctx); err != nil {
+		return Stats{}, err
+	}
+	if stats.UsersByRole, err = s.users.CountByRole(ctx); err != nil {
+		return Stats{}, err
+	}
+	if stats.TopBorrowers, err = s.users.TopBorrowers(ctx, stats.Since, statsTopN); err != nil {
+		return Stats{}, err
+	}
+
+	if stats.TotalEvents, err = s.events.Count(ctx); err != nil {
+		return Stats{}, err
+	}
+	if stats.EventsByType, err = s.events.CountByType(ctx, &stats.Since); err != nil {
+		return Stats{}, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// TestStatsService_Stats tests the admin stats snapshot
+func TestStatsService_Stats(t *testing.T) {
+	// expectQueries sets up one full computation of the snapshot for the window starting at since
+	expectQueries := func(m *StatsServiceMocks, now, since time.Time) {
+		m.MockTools.EXPECT().Count(gomock.Any()).Return(6, nil)
+		m.MockTools.EXPECT().CountByStatus(gomock.Any()).Return(map[domain.ToolStatus]int{domain.ToolStatusInOffice: 4, domain.ToolStatusCheckedOut: 2}, nil)
+		m.MockTools.EXPECT().CountCheckouts(gomock.Any(), now).Return(2, 1, nil)
+		m.MockTools.EXPECT().MostCheckedOut(gomock.Any(), since, statsTopN).Return([]domain.ToolCheckouts{{ToolID: TestToolID, Name: "Hammer", Checkouts: 7}}, nil)
+		m.MockUsers.EXPECT().Count(gomock.Any()).Return(3, nil)
+		m.MockUsers.EXPECT().CountByRole(gomock.Any()).Return(map[domain.UserRole]int{domain.UserRoleEmployee: 2, domain.UserRoleAdmin: 1}, nil)
+		m.MockUsers.EXPECT().TopBorrowers(gomock.Any(), since, statsTopN).Return([]domain.UserCheckouts{{UserID: TestUserID, Name: "Ann", Checkouts: 5}}, nil)
+		m.MockEvents.EXPECT().Count(gomock.Any()).Return(40, nil)
+		m.MockEvents.EXPECT().CountByType(gomock.Any(), &since).Return(map[domain.EventType]int{domain.EventTypeToolCheckedOut: 7}, nil)
+	}
+
+	t.Run("Snapshot of the default window", func(t *testing.T) {
+		now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+		mocks := SetupStatsServiceMocks(t, &now)
+		defer mocks.Teardown()
+		since := now.Add(-DefaultStatsWindow)
+		expectQueries(mocks, now, since)
+
+		stats, err := mocks.Service.Stats(context.Background(), 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, 6, stats.TotalTools)
+		assert.Equal(t, 2, stats.ToolsByStatus[domain.ToolStatusCheckedOut])
+		assert.Equal(t, 1, stats.UsersByRole[domain.UserRoleAdmin])
+		assert.Equal(t, 2, stats.ActiveCheckouts)
+		assert.Equal(t, 1, stats.OverdueTools)
+		assert.Equal(t, since, stats.Since)
+		assert.Equal(t, 7, stats.EventsByType[domain.EventTypeToolCheckedOut])
+		assert.Equal(t, "Ann", stats.TopBorrowers[0].Name)
+		assert.Equal(t, "Hammer", stats.MostUsedTools[0].Name)
+		assert.Equal(t, now, stats.GeneratedAt)
+	})
+
+	t.Run("Snapshot is cached per window until the TTL passes", func(t *testing.T) {
+		now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+		mocks := SetupStatsServiceMocks(t, &now)
+		defer mocks.Teardown()
+		week := 7 * 24 * time.Hour
+		expectQueries(mocks, now, now.Add(-week))
+
+		first, err := mocks.Service.Stats(context.Background(), week)
+		require.NoError(t, err)
+		now = now.Add(defaultStatsTTL - time.Second)
+		second, err := mocks.Service.Stats(context.Background(), week)
+		require.NoError(t, err)
+		assert.Equal(t, first, second)
+
+		now = now.Add(time.Second)
+		expectQueries(mocks, now, now.Add(-week))
+		third, err := mocks.Service.Stats(context.Background(), week)
+		require.NoError(t, err)
+		assert.Equal(t, now, third.GeneratedAt)
+	})
+
+	t.Run("Failed query is not cached", func(t *testing.T) {
+		now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+		mocks := SetupStatsServiceMocks(t, &now)
+		defer mocks.Teardown()
+		mocks.MockTools.EXPECT().Count(gomock.Any()).Return(0, assert.AnError)
+
+		_, err := mocks.Service.Stats(context.Background(), 0)
+		assert.ErrorIs(t, err, assert.AnError)
+
+		expectQueries(mocks, now, now.Add(-DefaultStatsWindow))
+		_, err = mocks.Service.Stats(context.Background(), 0)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Window out of range", func(t *testing.T) {
+		now := time.Now()
+		mocks := SetupStatsServiceMocks(t, &now)
+		defer mocks.Teardown()
+
+		_, err := mocks.Service.Stats(context.Background(), MaxStatsWindow+time.Hour)
+		assert.ErrorIs(t, err, domain.ErrValidation)
+
+		_, err = mocks.Service.Stats(context.Background(), -time.Hour)
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+}
@@ -1,45 +1,71 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/logging"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/repo"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/tracing"
 )
 
 //go:generate mockgen -source=tool_service.go -destination=mocks/mock_interfaces.go -package=mocks
 
 type ToolRepo interface {
-	Create(name string, status domain.ToolStatus) (domain.Tool, error)
-	List(limit, offset int) ([]domain.Tool, error)
-	Get(id string) (domain.Tool, error)
-	Update(domain.Tool) (domain.Tool, error)
-	Delete(id string) error
-	ListByStatus(status domain.ToolStatus, limit, offset int) ([]domain.Tool, error)
-	ListByUser(userID string, limit, offset int) ([]domain.Tool, error)
-	Count() (int, error)
+	Create(ctx context.Context, name string, status domain.ToolStatus) (domain.Tool, error)
+	List(ctx context.Context, after *domain.Cursor, limit, offset int) ([]domain.Tool, error)
+	ListWithFilter(ctx context.Context, filter repo.ToolFilter, limit, offset int) ([]domain.Tool, error)
+	CountWithFilter(ctx context.Context, filter repo.ToolFilter) (int, error)
+	Get(ctx context.Context, id string) (domain.Tool, error)
+	GetIncludingDeleted(ctx context.Context, id string) (domain.Tool, error)
+	GetForUpdate(ctx context.Context, id string) (domain.Tool, error)
+	Update(ctx context.Context, t domain.Tool) (domain.Tool, error)
+	Delete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) (domain.Tool, error)
+	ListByStatus(ctx context.Context, status domain.ToolStatus, limit, offset int) ([]domain.Tool, error)
+	ListByUser(ctx context.Context, userID string, limit, offset int) ([]domain.Tool, error)
+	Count(ctx context.Context) (int, error)
+	ListOverdue(ctx context.Context, now time.Time, limit, offset int) ([]domain.Tool, error)
+	ListOverdueUnnotified(ctx context.Context, now time.Time, limit int) ([]domain.Tool, error)
+	CountByStatus(ctx context.Context) (map[domain.ToolStatus]int, error)
+	CountCheckouts(ctx context.Context, now time.Time) (active, overdue int, err error)
+	MostCheckedOut(ctx context.Context, since time.Time, limit int) ([]domain.ToolCheckouts, error)
 }
 
 type ToolService struct {
-	Repo   ToolRepo
-	events EventLogger
+	Repo    ToolRepo
+	events  EventLogger
+	uow     UnitOfWork
+	metrics Metrics
+	now     func() time.Time
 }
 
 // EventLogger provides event logging for tool lifecycle actions.
 type EventLogger interface {
-	LogToolCheckedOut(toolID string, userID string, actorID string, notes string) error
-	LogToolCheckedIn(toolID string, userID string, actorID string, notes string) error
-	LogToolMaintenance(toolID string, userID string, notes string) error
-	LogToolLost(toolID string, userID string, notes string) error
-	LogToolCreated(toolID string, actorID string, notes string) error
-	LogToolUpdated(toolID string, actorID string, notes string) error
-	LogToolDeleted(toolID string, actorID string, notes string) error
-	LogUserCreated(userID string, actorID string, notes string) error
-	LogUserUpdated(userID string, actorID string, notes string) error
-	LogUserDeleted(userID string, actorID string, notes string) error
+	LogToolCheckedOut(ctx context.Context, before, after domain.Tool, userID string, actorID string, notes string, condition domain.ToolCondition) error
+	LogToolCheckedIn(ctx context.Context, before, after domain.Tool, userID string, actorID string, notes string) error
+	LogToolMaintenance(ctx context.Context, before, after domain.Tool, actorID string, notes string, vendor string, costCents *int64) error
+	LogToolLost(ctx context.Context, before, after domain.Tool, actorID string, notes string) error
+	LogToolOverdue(ctx context.Context, toolID string, userID string, dueAt time.Time) error
+	LogToolCreated(ctx context.Context, tool domain.Tool, actorID string, notes string) error
+	LogToolUpdated(ctx context.Context, before, after domain.Tool, actorID string, notes string) error
+	LogToolDeleted(ctx context.Context, tool domain.Tool, actorID string, notes string) error
+	LogToolRestored(ctx context.Context, before, after domain.Tool, actorID string, notes string) error
+	LogUserCreated(ctx context.Context, user domain.User, actorID string, notes string) error
+	LogUserUpdated(ctx context.Context, before, after domain.User, actorID string, notes string) error
+	LogUserDeleted(ctx context.Context, user domain.User, actorID string, notes string) error
+	LogUserRestored(ctx context.Context, before, after domain.User, actorID string, notes string) error
+	LogReservationCreated(ctx context.Context, reservationID, toolID, userID, actorID, notes string) error
+	LogReservationCancelled(ctx context.Context, reservationID, toolID, userID, actorID, notes string) error
+	LogReservationConverted(ctx context.Context, reservationID, toolID, userID, actorID, notes string) error
 }
 
 func NewToolService(r ToolRepo) *ToolService {
-	return &ToolService{Repo: r}
+	return &ToolService{Repo: r, uow: directUnitOfWork{repos: TxRepos{Tools: r}}, now: time.Now}
 }
 
 // WithEventLogger sets the event logger dependency (optional chaining style).
@@ -48,63 +74,179 @@ func (s *ToolService) WithEventLogger(l EventLogger) *ToolService {
 	return s
 }
 
-func (s *ToolService) CreateTool(name string, status domain.ToolStatus, actorID, notes string) (domain.Tool, error) {
+// WithUnitOfWork makes each mutation commit atomically with its event (optional chaining style).
+func (s *ToolService) WithUnitOfWork(u UnitOfWork) *ToolService {
+	s.uow = u
+	return s
+}
+
+// WithMetrics counts tool actions and their outcomes (optional chaining style).
+func (s *ToolService) WithMetrics(m Metrics) *ToolService {
+	s.metrics = m
+	return s
+}
+
+// logger returns the event logger for a unit of work (nil if none is configured).
+func (s *ToolService) logger(tx TxRepos) EventLogger {
+	if s.events == nil {
+		return nil
+	}
+	return bindLogger(s.events, tx)
+}
+
+func (s *ToolService) CreateTool(ctx context.Context, name string, status domain.ToolStatus, actorID, notes string) (_ domain.Tool, opErr error) {
+	defer logOp(ctx, "tool.create", time.Now(), &opErr, "actor_id", actorID)
+	defer s.recordAction("create", &opErr)
+	ctx, span := tracing.Start(ctx, "ToolService.CreateTool")
+	defer tracing.End(span, &opErr)
 	t, err := domain.NewTool(name, status)
 	if err != nil {
 		return domain.Tool{}, err
 	}
-	created, err := s.Repo.Create(t.Name, t.Status)
+	var created domain.Tool
+	err = s.uow.Do(ctx, func(tx TxRepos) error {
+		var err error
+		created, err = tx.Tools.Create(ctx, t.Name, t.Status)
+		if err != nil {
+			return err
+		}
+		if l := s.logger(tx); l != nil && created.ID != nil {
+			return l.LogToolCreated(ctx, created, actorID, notes)
+		}
+		return nil
+	})
 	if err != nil {
 		return domain.Tool{}, err
 	}
-	if s.events != nil {
-		if created.ID != nil {
-			_ = s.events.LogToolCreated(*created.ID, actorID, notes)
-		}
-	}
 	return created, nil
 }
 
-func (s *ToolService) ListTools(limit, offset int) ([]domain.Tool, error) {
-	if limit <= 0 {
-		limit = 10
+// ToolQuery holds the filters and sort order of a tool listing as the client gave them.
+// Empty fields do not filter.
+type ToolQuery struct {
+	Statuses         []string
+	CurrentUserID    string
+	Name             string
+	CheckedOutBefore *time.Time
+	CheckedOutAfter  *time.Time
+	CreatedBefore    *time.Time
+	CreatedAfter     *time.Time
+	Overdue          *bool
+	// IncludeDeleted also lists soft-deleted tools.
+	IncludeDeleted bool
+	// Sort is a comma-separated list of fields, each descending when prefixed with "-".
+	Sort string
+}
+
+// filter validates q and turns it into a repo filter, judging overdue at now.
+func (q ToolQuery) filter(now time.Time) (repo.ToolFilter, error) {
+	f := repo.ToolFilter{
+		CheckedOutBefore: q.CheckedOutBefore,
+		CheckedOutAfter:  q.CheckedOutAfter,
+		CreatedBefore:    q.CreatedBefore,
+		CreatedAfter:     q.CreatedAfter,
+		Overdue:          q.Overdue,
+		Now:              now,
+		IncludeDeleted:   q.IncludeDeleted,
 	}
-	if limit > 100 {
-		limit = 100
+	for _, raw := range q.Statuses {
+		status := domain.ToolStatus(raw)
+		if err := domain.ValidateToolStatus(status); err != nil {
+			return repo.ToolFilter{}, err
+		}
+		f.Statuses = append(f.Statuses, status)
 	}
-	if offset < 0 {
-		offset = 0
+	if q.CurrentUserID != "" {
+		if err := domain.ValidateUUID(q.CurrentUserID, "current_user_id"); err != nil {
+			return repo.ToolFilter{}, err
+		}
+		f.CurrentUserID = &q.CurrentUserID
+	}
+	if name := strings.TrimSpace(q.Name); name != "" {
+		f.NameContains = &name
+	}
+	sort, err := repo.ParseToolSort(q.Sort)
+	if err != nil {
+		return repo.ToolFilter{}, err
 	}
+	f.Sort = sort
+	return f, nil
+}
 
-	return s.Repo.List(limit, offset)
+// ListTools returns one page of the tools matching q, newest first unless q sorts them.
+// Cursors follow the default order only, so a sorted listing pages by offset.
+func (s *ToolService) ListTools(ctx context.Context, q ToolQuery, req domain.PageRequest) (domain.Page[domain.Tool], error) {
+	filter, err := q.filter(s.now())
+	if err != nil {
+		return domain.Page[domain.Tool]{}, err
+	}
+	cursorOf := toolCursor
+	if len(filter.Sort) > 0 {
+		if req.Cursor != "" {
+			return domain.Page[domain.Tool]{}, fmt.Errorf("%w: cursor cannot be combined with sort", domain.ErrValidation)
+		}
+		cursorOf = nil
+	}
+
+	req = clampPage(req, 10, 100)
+	return fetchPage(req, cursorOf, func(after *domain.Cursor, limit, offset int) ([]domain.Tool, error) {
+		page := filter
+		page.After = after
+		return s.Repo.ListWithFilter(ctx, page, limit, offset)
+	}, func() (int, error) {
+		return s.Repo.CountWithFilter(ctx, filter)
+	})
 }
 
-func (s *ToolService) GetTool(id string) (domain.Tool, error) {
+func (s *ToolService) GetTool(ctx context.Context, id string) (domain.Tool, error) {
 	if err := domain.ValidateUUID(id, "tool_id"); err != nil {
 		return domain.Tool{}, err
 	}
-	return s.Repo.Get(id)
+	return s.Repo.Get(ctx, id)
 }
 
-func (s *ToolService) UpdateTool(id string, name string, status domain.ToolStatus, actorID, notes string) (domain.Tool, error) {
-	tool, err := s.applyAndSave(id, func(t *domain.Tool) error {
+// GetToolIncludingDeleted is GetTool that also finds soft-deleted tools.
+func (s *ToolService) GetToolIncludingDeleted(ctx context.Context, id string) (domain.Tool, error) {
+	if err := domain.ValidateUUID(id, "tool_id"); err != nil {
+		return domain.Tool{}, err
+	}
+	return s.Repo.GetIncludingDeleted(ctx, id)
+}
+
+// UpdateTool replaces the editable fields of a tool; a nil defaultLoanDays clears the default loan period.
+func (s *ToolService) UpdateTool(ctx context.Context, id string, expectedVersion int, name string, status domain.ToolStatus, defaultLoanDays *int, actorID, notes string) (_ domain.Tool, opErr error) {
+	defer logOp(ctx, "tool.update", time.Now(), &opErr, "tool_id", id, "actor_id", actorID)
+	defer s.recordAction("update", &opErr)
+	ctx, span := tracing.Start(ctx, "ToolService.UpdateTool", tracing.ToolID(id))
+	defer tracing.End(span, &opErr)
+	return s.applyAndSave(ctx, id, expectedVersion, func(_ context.Context, _ TxRepos, t *domain.Tool) error {
 		t.Name = name
 		t.Status = status
+		t.DefaultLoanDays = defaultLoanDays
 		return nil
+	}, func(ctx context.Context, l EventLogger, before, after domain.Tool) error {
+		return l.LogToolUpdated(ctx, before, after, actorID, notes)
 	})
-	if err != nil {
-		return domain.Tool{}, err
-	}
-	if s.events != nil {
-		if tool.ID != nil {
-			_ = s.events.LogToolUpdated(*tool.ID, actorID, notes)
-		}
-	}
-	return tool, nil
 }
 
-// CheckOutTool: internal controlled mutation (sets CurrentUserId, LastCheckedOutAt, Status)
-func (s *ToolService) CheckOutTool(toolID, userID, actorID, notes string) (domain.Tool, error) {
+// CheckoutOptions tweak CheckOutTool.
+type CheckoutOptions struct {
+	// DueAt is when the tool should come back; nil falls back to the tool's default loan period.
+	DueAt *time.Time
+	// OverrideReservations checks the tool out even when another user's active
+	// reservation covers the current time. Callers must restrict it to managers.
+	OverrideReservations bool
+	// Condition is the state the tool leaves in, recorded on the checkout event.
+	Condition domain.ToolCondition
+}
+
+// CheckOutTool: internal controlled mutation (sets CurrentUserId, LastCheckedOutAt, Status).
+// It refuses with domain.ErrConflict while another user holds an active reservation, unless overridden.
+func (s *ToolService) CheckOutTool(ctx context.Context, toolID string, expectedVersion int, userID, actorID, notes string, opts CheckoutOptions) (_ domain.Tool, opErr error) {
+	defer logOp(ctx, "tool.checkout", time.Now(), &opErr, "tool_id", toolID, "user_id", userID, "actor_id", actorID)
+	defer s.recordAction("checkout", &opErr)
+	ctx, span := tracing.Start(ctx, "ToolService.CheckOutTool", tracing.ToolID(toolID), tracing.UserID(userID))
+	defer tracing.End(span, &opErr)
 	if err := domain.ValidateUUID(userID, "user_id"); err != nil {
 		return domain.Tool{}, err
 	}
@@ -113,53 +255,71 @@ func (s *ToolService) CheckOutTool(toolID, userID, actorID, notes string) (domai
 			return domain.Tool{}, err
 		}
 	}
-	tool, err := s.applyAndSave(toolID, func(t *domain.Tool) error {
-		if t.CurrentUserId != nil {
-			return fmt.Errorf("%w: tool is already checked out", domain.ErrValidation)
+	if err := domain.ValidateToolCondition(opts.Condition); err != nil {
+		return domain.Tool{}, err
+	}
+	return s.applyAndSave(ctx, toolID, expectedVersion, func(ctx context.Context, tx TxRepos, t *domain.Tool) error {
+		now := s.now()
+		if err := t.CheckOut(userID, opts.DueAt, now); err != nil {
+			return err
 		}
-		if t.Status == domain.ToolStatusLost {
-			return fmt.Errorf("%w: tool is marked as lost", domain.ErrValidation)
+		if opts.OverrideReservations {
+			return nil
 		}
-		t.Status = domain.ToolStatusCheckedOut
-		t.CurrentUserId = &userID
-		return nil
+		return checkReservationConflict(ctx, tx.Reservations, toolID, userID, now, now)
+	}, func(ctx context.Context, l EventLogger, before, after domain.Tool) error {
+		return l.LogToolCheckedOut(ctx, before, after, userID, pickActor(actorID, userID), notes, opts.Condition)
 	})
-	if err != nil {
-		return domain.Tool{}, err
-	}
-	if s.events != nil {
-		_ = s.events.LogToolCheckedOut(toolID, userID, pickActor(actorID, userID), notes)
-	}
-	return tool, nil
+}
+
+// ReturnOptions restrict who may check a tool in; the zero value allows anyone.
+type ReturnOptions struct {
+	// AuthorizeHolder is called with the current holder once the tool is locked, so
+	// the holder it approves is the one the check-in clears. An error aborts it.
+	AuthorizeHolder func(holderID string) error
 }
 
 // ReturnTool: clears checkout state
-func (s *ToolService) ReturnTool(toolID, actorID, notes string) (domain.Tool, error) {
+func (s *ToolService) ReturnTool(ctx context.Context, toolID string, expectedVersion int, actorID, notes string, opts ReturnOptions) (_ domain.Tool, opErr error) {
+	defer logOp(ctx, "tool.checkin", time.Now(), &opErr, "tool_id", toolID, "actor_id", actorID)
+	defer s.recordAction("checkin", &opErr)
+	ctx, span := tracing.Start(ctx, "ToolService.ReturnTool", tracing.ToolID(toolID))
+	defer tracing.End(span, &opErr)
 	var priorUserID string
-	tool, err := s.applyAndSave(toolID, func(t *domain.Tool) error {
-		if t.CurrentUserId == nil {
-			return fmt.Errorf("%w: tool is already checked in", domain.ErrValidation)
-		}
+	return s.applyAndSave(ctx, toolID, expectedVersion, func(_ context.Context, _ TxRepos, t *domain.Tool) error {
 		// capture prior user id before clearing
 		if t.CurrentUserId != nil {
 			priorUserID = *t.CurrentUserId
+			if opts.AuthorizeHolder != nil {
+				if err := opts.AuthorizeHolder(priorUserID); err != nil {
+					return err
+				}
+			}
 		}
-		t.CurrentUserId = nil
-		t.Status = domain.ToolStatusInOffice
-		return nil
+		return t.CheckIn()
+	}, func(ctx context.Context, l EventLogger, before, after domain.Tool) error {
+		return l.LogToolCheckedIn(ctx, before, after, priorUserID, pickActor(actorID, priorUserID), notes)
 	})
-	if err != nil {
-		return domain.Tool{}, err
-	}
-	if s.events != nil {
-		_ = s.events.LogToolCheckedIn(toolID, priorUserID, pickActor(actorID, priorUserID), notes)
-	}
-	return tool, nil
+}
+
+// MaintenanceOptions describe the repair a tool is sent to maintenance for; both are optional.
+type MaintenanceOptions struct {
+	Vendor string
+	// CostCents is the quoted cost of the repair, in cents.
+	CostCents *int64
 }
 
 // SendToMaintenance moves a tool to maintenance status.
-func (s *ToolService) SendToMaintenance(toolID, actorID, notes string) (domain.Tool, error) {
-	tool, err := s.applyAndSave(toolID, func(t *domain.Tool) error {
+func (s *ToolService) SendToMaintenance(ctx context.Context, toolID string, expectedVersion int, actorID, notes string, opts MaintenanceOptions) (_ domain.Tool, opErr error) {
+	defer logOp(ctx, "tool.maintenance", time.Now(), &opErr, "tool_id", toolID, "actor_id", actorID)
+	defer s.recordAction("maintenance", &opErr)
+	ctx, span := tracing.Start(ctx, "ToolService.SendToMaintenance", tracing.ToolID(toolID))
+	defer tracing.End(span, &opErr)
+	opts.Vendor = strings.TrimSpace(opts.Vendor)
+	if err := (domain.EventMetadata{Vendor: opts.Vendor, CostCents: opts.CostCents}).Validate(domain.EventTypeToolMaintenance); err != nil {
+		return domain.Tool{}, err
+	}
+	return s.applyAndSave(ctx, toolID, expectedVersion, func(_ context.Context, _ TxRepos, t *domain.Tool) error {
 		if t.Status == domain.ToolStatusLost {
 			return fmt.Errorf("%w: lost tools cannot be sent to maintenance", domain.ErrValidation)
 		}
@@ -168,32 +328,26 @@ func (s *ToolService) SendToMaintenance(toolID, actorID, notes string) (domain.T
 		}
 		t.Status = domain.ToolStatusMaintenance
 		return nil
+	}, func(ctx context.Context, l EventLogger, before, after domain.Tool) error {
+		return l.LogToolMaintenance(ctx, before, after, actorID, notes, opts.Vendor, opts.CostCents)
 	})
-	if err != nil {
-		return domain.Tool{}, err
-	}
-	if s.events != nil {
-		_ = s.events.LogToolMaintenance(toolID, pickActor(actorID, ""), notes)
-	}
-	return tool, nil
 }
 
 // MarkLost marks a tool as lost.
-func (s *ToolService) MarkLost(toolID, actorID, notes string) (domain.Tool, error) {
-	tool, err := s.applyAndSave(toolID, func(t *domain.Tool) error {
+func (s *ToolService) MarkLost(ctx context.Context, toolID string, expectedVersion int, actorID, notes string) (_ domain.Tool, opErr error) {
+	defer logOp(ctx, "tool.lost", time.Now(), &opErr, "tool_id", toolID, "actor_id", actorID)
+	defer s.recordAction("lost", &opErr)
+	ctx, span := tracing.Start(ctx, "ToolService.MarkLost", tracing.ToolID(toolID))
+	defer tracing.End(span, &opErr)
+	return s.applyAndSave(ctx, toolID, expectedVersion, func(_ context.Context, _ TxRepos, t *domain.Tool) error {
 		if t.Status == domain.ToolStatusLost {
 			return nil
 		}
 		t.Status = domain.ToolStatusLost
 		return nil
+	}, func(ctx context.Context, l EventLogger, before, after domain.Tool) error {
+		return l.LogToolLost(ctx, before, after, actorID, notes)
 	})
-	if err != nil {
-		return domain.Tool{}, err
-	}
-	if s.events != nil {
-		_ = s.events.LogToolLost(toolID, pickActor(actorID, ""), notes)
-	}
-	return tool, nil
 }
 
 // pickActor chooses actorID if provided, else fallback.
@@ -204,60 +358,240 @@ func pickActor(actorID, fallback string) string {
 	return fallback
 }
 
-func (s *ToolService) DeleteTool(id, actorID, notes string) error {
+// DeleteTool soft-deletes a tool; RestoreTool brings it back. expectedVersion is checked
+// against the locked row (domain.AnyVersion skips it).
+func (s *ToolService) DeleteTool(ctx context.Context, id string, expectedVersion int, actorID, notes string) (opErr error) {
+	defer logOp(ctx, "tool.delete", time.Now(), &opErr, "tool_id", id, "actor_id", actorID)
+	defer s.recordAction("delete", &opErr)
+	ctx, span := tracing.Start(ctx, "ToolService.DeleteTool", tracing.ToolID(id))
+	defer tracing.End(span, &opErr)
 	if err := domain.ValidateUUID(id, "tool_id"); err != nil {
 		return err
 	}
-	// load to get ID pointer value
-	t, err := s.Repo.Get(id)
-	if err != nil {
-		return err
-	}
-	if err := s.Repo.Delete(id); err != nil {
-		return err
+	return s.uow.Do(ctx, func(tx TxRepos) error {
+		// load (and lock) the state the event snapshots
+		t, err := tx.Tools.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := domain.CheckVersion(expectedVersion, t.Version); err != nil {
+			return err
+		}
+		if l := s.logger(tx); l != nil && t.ID != nil {
+			if err := l.LogToolDeleted(ctx, t, actorID, notes); err != nil {
+				return err
+			}
+		}
+		return tx.Tools.Delete(ctx, id)
+	})
+}
+
+// RestoreTool undoes the soft delete of a tool, which comes back as it was when deleted.
+// expectedVersion is checked against the deleted row (domain.AnyVersion skips it).
+func (s *ToolService) RestoreTool(ctx context.Context, id string, expectedVersion int, actorID, notes string) (_ domain.Tool, opErr error) {
+	defer logOp(ctx, "tool.restore", time.Now(), &opErr, "tool_id", id, "actor_id", actorID)
+	defer s.recordAction("restore", &opErr)
+	ctx, span := tracing.Start(ctx, "ToolService.RestoreTool", tracing.ToolID(id))
+	defer tracing.End(span, &opErr)
+	if err := domain.ValidateUUID(id, "tool_id"); err != nil {
+		return domain.Tool{}, err
 	}
-	if s.events != nil && t.ID != nil {
-		_ = s.events.LogToolDeleted(*t.ID, actorID, notes)
+
+	var restored domain.Tool
+	err := s.uow.Do(ctx, func(tx TxRepos) error {
+		t, err := tx.Tools.GetIncludingDeleted(ctx, id)
+		if err != nil {
+			return err
+		}
+		if t.DeletedAt == nil {
+			return fmt.Errorf("%w: tool is not deleted", domain.ErrConflict)
+		}
+		if err := domain.CheckVersion(expectedVersion, t.Version); err != nil {
+			return err
+		}
+		// Restore only matches a deleted row, so a concurrent restore makes this one fail
+		restored, err = tx.Tools.Restore(ctx, id)
+		if err != nil {
+			return err
+		}
+		if l := s.logger(tx); l != nil && restored.ID != nil {
+			return l.LogToolRestored(ctx, t, restored, actorID, notes)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Tool{}, err
 	}
-	return nil
+	return restored, nil
+}
+
+// RepairToolState rewrites the status and current user of a tool to state and logs
+// TOOL_UPDATED, so the repair itself is in the audit log. Clearing the current user
+// also clears the due date. expectedVersion is the version state was checked against.
+func (s *ToolService) RepairToolState(ctx context.Context, id string, expectedVersion int, state domain.ToolState, actorID, notes string) (_ domain.Tool, opErr error) {
+	defer logOp(ctx, "tool.repair", time.Now(), &opErr, "tool_id", id, "actor_id", actorID)
+	defer s.recordAction("repair", &opErr)
+	ctx, span := tracing.Start(ctx, "ToolService.RepairToolState", tracing.ToolID(id))
+	defer tracing.End(span, &opErr)
+	return s.applyAndSave(ctx, id, expectedVersion, func(_ context.Context, _ TxRepos, t *domain.Tool) error {
+		t.Status = state.Status
+		t.CurrentUserId = state.CurrentUserID
+		if state.CurrentUserID == nil {
+			t.DueAt = nil
+			t.OverdueNotifiedAt = nil
+		}
+		return nil
+	}, func(ctx context.Context, l EventLogger, before, after domain.Tool) error {
+		return l.LogToolUpdated(ctx, before, after, actorID, notes)
+	})
 }
 
-func (s *ToolService) ListToolsByUser(userID string, limit, offset int) ([]domain.Tool, error) {
+func (s *ToolService) ListToolsByUser(ctx context.Context, userID string, limit, offset int) ([]domain.Tool, error) {
 	if err := domain.ValidateUUID(userID, "user_id"); err != nil {
 		return nil, err
 	}
-	return s.Repo.ListByUser(userID, limit, offset)
+	return s.Repo.ListByUser(ctx, userID, limit, offset)
 }
 
-func (s *ToolService) ListToolsByStatus(status domain.ToolStatus, limit, offset int) ([]domain.Tool, error) {
+func (s *ToolService) ListToolsByStatus(ctx context.Context, status domain.ToolStatus, limit, offset int) ([]domain.Tool, error) {
 	// Validate status
 	if err := domain.ValidateToolStatus(status); err != nil {
 		return nil, err
 	}
-	return s.Repo.ListByStatus(status, limit, offset)
+	return s.Repo.ListByStatus(ctx, status, limit, offset)
 }
 
-func (s *ToolService) GetToolCount() (int, error) {
-	return s.Repo.Count()
+// ListOverdueTools returns checked-out tools past their due date, most overdue first.
+func (s *ToolService) ListOverdueTools(ctx context.Context, limit, offset int) ([]domain.Tool, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.Repo.ListOverdue(ctx, s.now(), limit, offset)
 }
 
-// applyAndSave centralizes: id validation, load, mutation, validation, timestamp, persist.
-func (s *ToolService) applyAndSave(id string, mutate func(*domain.Tool) error) (domain.Tool, error) {
-	if err := domain.ValidateUUID(id, "tool_id"); err != nil {
-		return domain.Tool{}, err
-	}
-	current, err := s.Repo.Get(id)
+// overdueSweepBatch bounds how many tools one SweepOverdue call handles.
+const overdueSweepBatch = 100
+
+// SweepOverdue emits one TOOL_OVERDUE event per checkout that has passed its due date.
+// Each tool is re-checked under its row lock and marked notified in the same unit of work
+// as its event, so concurrent sweeps never emit twice. A tool that fails is logged and
+// skipped so the rest are still notified; the failures are returned together with the
+// number of events emitted.
+func (s *ToolService) SweepOverdue(ctx context.Context) (int, error) {
+	now := s.now()
+	candidates, err := s.Repo.ListOverdueUnnotified(ctx, now, overdueSweepBatch)
 	if err != nil {
-		return domain.Tool{}, err
+		return 0, err
 	}
-	if err := mutate(&current); err != nil {
-		return domain.Tool{}, err
+
+	emitted := 0
+	var errs []error
+	for _, c := range candidates {
+		if c.ID == nil {
+			continue
+		}
+		id := *c.ID
+		notified := false
+		err := s.uow.Do(ctx, func(tx TxRepos) error {
+			t, err := tx.Tools.GetForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			// returned, re-checked out or already handled since the candidate list was read
+			if !t.IsOverdue(now) || t.OverdueNotifiedAt != nil {
+				return nil
+			}
+			t.OverdueNotifiedAt = &now
+			if _, err := tx.Tools.Update(ctx, t); err != nil {
+				return err
+			}
+			notified = true
+			if l := s.logger(tx); l != nil {
+				return l.LogToolOverdue(ctx, id, *t.CurrentUserId, *t.DueAt)
+			}
+			return nil
+		})
+		if err != nil {
+			logging.FromContext(ctx).Warn("failed to sweep overdue tool", "tool_id", id, "error", err)
+			errs = append(errs, fmt.Errorf("failed to sweep overdue tool %s: %w", id, err))
+			continue
+		}
+		if notified {
+			emitted++
+			if s.metrics != nil {
+				s.metrics.ToolAction("flag_overdue", nil)
+			}
+		}
+	}
+	return emitted, errors.Join(errs...)
+}
+
+func (s *ToolService) GetToolCount(ctx context.Context) (int, error) {
+	return s.Repo.Count(ctx)
+}
+
+// CountToolsByStatus returns the number of tools in each status.
+func (s *ToolService) CountToolsByStatus(ctx context.Context) (map[domain.ToolStatus]int, error) {
+	return s.Repo.CountByStatus(ctx)
+}
+
+// CountCheckouts returns how many tools are checked out now and how many of those are overdue.
+func (s *ToolService) CountCheckouts(ctx context.Context) (active, overdue int, err error) {
+	return s.Repo.CountCheckouts(ctx, s.now())
+}
+
+// recordAction counts a finished tool action and its outcome; defer it with the named error result.
+func (s *ToolService) recordAction(action string, err *error) {
+	if s.metrics != nil {
+		s.metrics.ToolAction(action, *err)
 	}
-	if err := current.Validate(); err != nil {
+}
+
+// applyAndSave centralizes: id validation, locked load, version check, mutation, validation, persist, event.
+// All of it runs in one unit of work, so the state change and its event commit together
+// and concurrent mutations of the same tool are serialized by the row lock.
+// expectedVersion is the client's If-Match version (domain.AnyVersion skips the check).
+// mutate and log receive the context of applyAndSave's span, so their queries are traced under it.
+// log gets the tool as it was loaded and as it was saved, for the event snapshots.
+func (s *ToolService) applyAndSave(ctx context.Context, id string, expectedVersion int, mutate func(context.Context, TxRepos, *domain.Tool) error, log func(ctx context.Context, l EventLogger, before, after domain.Tool) error) (_ domain.Tool, opErr error) {
+	ctx, span := tracing.Start(ctx, "ToolService.applyAndSave", tracing.ToolID(id))
+	defer tracing.End(span, &opErr)
+	if err := domain.ValidateUUID(id, "tool_id"); err != nil {
 		return domain.Tool{}, err
 	}
 
-	updated, err := s.Repo.Update(current)
+	var updated domain.Tool
+	err := s.uow.Do(ctx, func(tx TxRepos) error {
+		current, err := tx.Tools.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := domain.CheckVersion(expectedVersion, current.Version); err != nil {
+			return err
+		}
+		before := current
+		if err := mutate(ctx, tx, &current); err != nil {
+			return err
+		}
+		if err := current.Validate(); err != nil {
+			return err
+		}
+
+		updated, err = tx.Tools.Update(ctx, current)
+		if err != nil {
+			return err
+		}
+		if l := s.logger(tx); l != nil {
+			return log(ctx, l, before, updated)
+		}
+		return nil
+	})
 	if err != nil {
 		return domain.Tool{}, err
 	}
@@ -1,12 +1,15 @@
 package service
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/repo"
 )
 
 // TestToolService_CreateTool tests the tool creation workflow
@@ -18,11 +21,11 @@ func TestToolService_CreateTool(t *testing.T) {
 		createdTool := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusInOffice)
 
 		// Set expectations
-		mocks.MockRepo.EXPECT().Create("Hammer", domain.ToolStatusInOffice).Return(createdTool, nil)
-		mocks.MockLogger.EXPECT().LogToolCreated(TestToolID, TestActorID, "Tool created").Return(nil)
+		mocks.MockRepo.EXPECT().Create(gomock.Any(), "Hammer", domain.ToolStatusInOffice).Return(createdTool, nil)
+		mocks.MockLogger.EXPECT().LogToolCreated(gomock.Any(), createdTool, TestActorID, "Tool created").Return(nil)
 
 		// Execute
-		result, err := mocks.ServiceWithLogger.CreateTool("Hammer", domain.ToolStatusInOffice, TestActorID, "Tool created")
+		result, err := mocks.ServiceWithLogger.CreateTool(context.Background(), "Hammer", domain.ToolStatusInOffice, TestActorID, "Tool created")
 
 		// Assert
 		require.NoError(t, err)
@@ -35,9 +38,9 @@ func TestToolService_CreateTool(t *testing.T) {
 
 		createdTool := CreateTestTool(TestToolID, "Screwdriver", domain.ToolStatusInOffice)
 
-		mocks.MockRepo.EXPECT().Create("Screwdriver", domain.ToolStatusInOffice).Return(createdTool, nil)
+		mocks.MockRepo.EXPECT().Create(gomock.Any(), "Screwdriver", domain.ToolStatusInOffice).Return(createdTool, nil)
 
-		result, err := mocks.Service.CreateTool("Screwdriver", domain.ToolStatusInOffice, TestActorID, "Tool created")
+		result, err := mocks.Service.CreateTool(context.Background(), "Screwdriver", domain.ToolStatusInOffice, TestActorID, "Tool created")
 
 		require.NoError(t, err)
 		assert.Equal(t, createdTool, result)
@@ -48,9 +51,9 @@ func TestToolService_CreateTool(t *testing.T) {
 		defer mocks.Teardown()
 
 		repoError := assert.AnError
-		mocks.MockRepo.EXPECT().Create("Hammer", domain.ToolStatusInOffice).Return(domain.Tool{}, repoError)
+		mocks.MockRepo.EXPECT().Create(gomock.Any(), "Hammer", domain.ToolStatusInOffice).Return(domain.Tool{}, repoError)
 
-		_, err := mocks.Service.CreateTool("Hammer", domain.ToolStatusInOffice, TestActorID, "Tool created")
+		_, err := mocks.Service.CreateTool(context.Background(), "Hammer", domain.ToolStatusInOffice, TestActorID, "Tool created")
 
 		assert.Error(t, err)
 		assert.Equal(t, repoError, err)
@@ -71,12 +74,12 @@ func TestToolService_CheckOutTool(t *testing.T) {
 		checkedOutTool.CurrentUserId = &userID
 
 		// Set expectations
-		mocks.MockRepo.EXPECT().Get(TestToolID).Return(availableTool, nil)
-		mocks.MockRepo.EXPECT().Update(gomock.Any()).Return(checkedOutTool, nil)
-		mocks.MockLogger.EXPECT().LogToolCheckedOut(TestToolID, TestUserID, TestActorID, "Checking out for project").Return(nil)
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(availableTool, nil)
+		mocks.MockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(checkedOutTool, nil)
+		mocks.MockLogger.EXPECT().LogToolCheckedOut(gomock.Any(), availableTool, checkedOutTool, TestUserID, TestActorID, "Checking out for project", domain.ToolCondition("")).Return(nil)
 
 		// Execute
-		result, err := mocks.ServiceWithLogger.CheckOutTool(TestToolID, TestUserID, TestActorID, "Checking out for project")
+		result, err := mocks.ServiceWithLogger.CheckOutTool(context.Background(), TestToolID, domain.AnyVersion, TestUserID, TestActorID, "Checking out for project", CheckoutOptions{})
 
 		// Assert
 		require.NoError(t, err)
@@ -92,10 +95,10 @@ func TestToolService_CheckOutTool(t *testing.T) {
 		userID2 := TestUserID2
 		checkedOutTool.CurrentUserId = &userID2
 
-		mocks.MockRepo.EXPECT().Get(TestToolID).Return(checkedOutTool, nil)
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(checkedOutTool, nil)
 
 		// Execute
-		_, err := mocks.Service.CheckOutTool(TestToolID, TestUserID, TestActorID, "Checking out for project")
+		_, err := mocks.Service.CheckOutTool(context.Background(), TestToolID, domain.AnyVersion, TestUserID, TestActorID, "Checking out for project", CheckoutOptions{})
 
 		// Assert
 		assert.Error(t, err)
@@ -107,7 +110,7 @@ func TestToolService_CheckOutTool(t *testing.T) {
 		defer mocks.Teardown()
 
 		// Execute with invalid user ID - no mock expectations needed since validation happens first
-		_, err := mocks.Service.CheckOutTool(TestToolID, InvalidUUID, TestActorID, "Checking out for project")
+		_, err := mocks.Service.CheckOutTool(context.Background(), TestToolID, domain.AnyVersion, InvalidUUID, TestActorID, "Checking out for project", CheckoutOptions{})
 
 		// Assert
 		assert.Error(t, err)
@@ -118,7 +121,7 @@ func TestToolService_CheckOutTool(t *testing.T) {
 		mocks := SetupToolServiceMocks(t)
 		defer mocks.Teardown()
 
-		_, err := mocks.Service.CheckOutTool(InvalidUUID, TestUserID, TestActorID, "Checking out for project")
+		_, err := mocks.Service.CheckOutTool(context.Background(), InvalidUUID, domain.AnyVersion, TestUserID, TestActorID, "Checking out for project", CheckoutOptions{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "tool_id must be a valid UUID")
@@ -138,25 +141,47 @@ func TestToolService_ReturnTool(t *testing.T) {
 		// Returned tool
 		returnedTool := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusInOffice)
 
-		mocks.MockRepo.EXPECT().Get(TestToolID).Return(checkedOutTool, nil)
-		mocks.MockRepo.EXPECT().Update(gomock.Any()).Return(returnedTool, nil)
-		mocks.MockLogger.EXPECT().LogToolCheckedIn(TestToolID, TestUserID, TestActorID, "Returning tool").Return(nil)
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(checkedOutTool, nil)
+		mocks.MockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(returnedTool, nil)
+		mocks.MockLogger.EXPECT().LogToolCheckedIn(gomock.Any(), checkedOutTool, returnedTool, TestUserID, TestActorID, "Returning tool").Return(nil)
 
-		result, err := mocks.ServiceWithLogger.ReturnTool(TestToolID, TestActorID, "Returning tool")
+		result, err := mocks.ServiceWithLogger.ReturnTool(context.Background(), TestToolID, domain.AnyVersion, TestActorID, "Returning tool", ReturnOptions{})
 
 		require.NoError(t, err)
 		assert.Equal(t, returnedTool, result)
 	})
 
+	t.Run("Holder is authorized on the locked tool", func(t *testing.T) {
+		mocks := SetupToolServiceMocks(t)
+		defer mocks.Teardown()
+
+		checkedOutTool := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusCheckedOut)
+		userID := TestUserID2
+		checkedOutTool.CurrentUserId = &userID
+
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(checkedOutTool, nil)
+
+		var authorized string
+		_, err := mocks.ServiceWithLogger.ReturnTool(context.Background(), TestToolID, domain.AnyVersion, TestActorID, "Returning tool", ReturnOptions{
+			AuthorizeHolder: func(holderID string) error {
+				authorized = holderID
+				return domain.ErrForbidden
+			},
+		})
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+		assert.Equal(t, TestUserID2, authorized)
+	})
+
 	t.Run("Cannot return tool that is already checked in", func(t *testing.T) {
 		mocks := SetupToolServiceMocks(t)
 		defer mocks.Teardown()
 
 		availableTool := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusInOffice)
 
-		mocks.MockRepo.EXPECT().Get(TestToolID).Return(availableTool, nil)
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(availableTool, nil)
 
-		_, err := mocks.Service.ReturnTool(TestToolID, TestActorID, "Returning tool")
+		_, err := mocks.Service.ReturnTool(context.Background(), TestToolID, domain.AnyVersion, TestActorID, "Returning tool", ReturnOptions{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "tool is already checked in")
@@ -166,7 +191,7 @@ func TestToolService_ReturnTool(t *testing.T) {
 		mocks := SetupToolServiceMocks(t)
 		defer mocks.Teardown()
 
-		_, err := mocks.Service.ReturnTool(InvalidUUID, TestActorID, "Returning tool")
+		_, err := mocks.Service.ReturnTool(context.Background(), InvalidUUID, domain.AnyVersion, TestActorID, "Returning tool", ReturnOptions{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "tool_id must be a valid UUID")
@@ -184,21 +209,27 @@ func TestToolService_ListTools(t *testing.T) {
 			CreateTestTool("tool2", "Screwdriver", domain.ToolStatusCheckedOut),
 		}
 
-		mocks.MockRepo.EXPECT().List(10, 0).Return(expectedTools, nil)
+		mocks.MockRepo.EXPECT().ListWithFilter(gomock.Any(), gomock.Any(), 11, 0).Return(expectedTools, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), gomock.Any()).Return(3, nil)
 
-		result, err := mocks.Service.ListTools(10, 0)
+		result, err := mocks.Service.ListTools(context.Background(), ToolQuery{}, domain.PageRequest{Limit: 10})
 
 		require.NoError(t, err)
-		assert.Equal(t, expectedTools, result)
+		assert.Equal(t, expectedTools, result.Items)
+		assert.Equal(t, 3, result.Total)
+		assert.Equal(t, 10, result.Limit)
+		assert.False(t, result.HasMore)
+		assert.Empty(t, result.NextCursor)
 	})
 
 	t.Run("Default limit applied when zero", func(t *testing.T) {
 		mocks := SetupToolServiceMocks(t)
 		defer mocks.Teardown()
 
-		mocks.MockRepo.EXPECT().List(10, 0).Return([]domain.Tool{}, nil)
+		mocks.MockRepo.EXPECT().ListWithFilter(gomock.Any(), gomock.Any(), 11, 0).Return([]domain.Tool{}, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), gomock.Any()).Return(3, nil)
 
-		_, err := mocks.Service.ListTools(0, 0)
+		_, err := mocks.Service.ListTools(context.Background(), ToolQuery{}, domain.PageRequest{})
 
 		require.NoError(t, err)
 	})
@@ -207,9 +238,10 @@ func TestToolService_ListTools(t *testing.T) {
 		mocks := SetupToolServiceMocks(t)
 		defer mocks.Teardown()
 
-		mocks.MockRepo.EXPECT().List(100, 0).Return([]domain.Tool{}, nil)
+		mocks.MockRepo.EXPECT().ListWithFilter(gomock.Any(), gomock.Any(), 101, 0).Return([]domain.Tool{}, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), gomock.Any()).Return(3, nil)
 
-		_, err := mocks.Service.ListTools(150, 0)
+		_, err := mocks.Service.ListTools(context.Background(), ToolQuery{}, domain.PageRequest{Limit: 150})
 
 		require.NoError(t, err)
 	})
@@ -218,12 +250,117 @@ func TestToolService_ListTools(t *testing.T) {
 		mocks := SetupToolServiceMocks(t)
 		defer mocks.Teardown()
 
-		mocks.MockRepo.EXPECT().List(10, 0).Return([]domain.Tool{}, nil)
+		mocks.MockRepo.EXPECT().ListWithFilter(gomock.Any(), gomock.Any(), 11, 0).Return([]domain.Tool{}, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), gomock.Any()).Return(3, nil)
 
-		_, err := mocks.Service.ListTools(10, -5)
+		_, err := mocks.Service.ListTools(context.Background(), ToolQuery{}, domain.PageRequest{Limit: 10, Offset: -5})
 
 		require.NoError(t, err)
 	})
+
+	t.Run("Next page follows the cursor", func(t *testing.T) {
+		mocks := SetupToolServiceMocks(t)
+		defer mocks.Teardown()
+
+		tools := []domain.Tool{
+			CreateTestTool("tool1", "Hammer", domain.ToolStatusInOffice),
+			CreateTestTool(TestToolID, "Screwdriver", domain.ToolStatusInOffice),
+			CreateTestTool("tool3", "Saw", domain.ToolStatusInOffice),
+		}
+		now := time.Now()
+		mocks.Service.now = func() time.Time { return now }
+		mocks.MockRepo.EXPECT().ListWithFilter(gomock.Any(), repo.ToolFilter{Now: now}, 3, 0).Return(tools, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), repo.ToolFilter{Now: now}).Return(3, nil)
+
+		first, err := mocks.Service.ListTools(context.Background(), ToolQuery{}, domain.PageRequest{Limit: 2})
+
+		require.NoError(t, err)
+		assert.Equal(t, tools[:2], first.Items)
+		assert.True(t, first.HasMore)
+		require.NotEmpty(t, first.NextCursor)
+
+		// the cursor is the last tool on the page and takes the place of the offset
+		after := &domain.Cursor{CreatedAt: tools[1].CreatedAt.UTC(), ID: TestToolID}
+		mocks.MockRepo.EXPECT().ListWithFilter(gomock.Any(), repo.ToolFilter{Now: now, After: after}, 3, 0).Return(tools[2:], nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), repo.ToolFilter{Now: now}).Return(3, nil)
+
+		second, err := mocks.Service.ListTools(context.Background(), ToolQuery{}, domain.PageRequest{Limit: 2, Offset: 40, Cursor: first.NextCursor})
+
+		require.NoError(t, err)
+		assert.Equal(t, tools[2:], second.Items)
+		assert.False(t, second.HasMore)
+		assert.Empty(t, second.NextCursor)
+	})
+
+	t.Run("Malformed cursor should fail", func(t *testing.T) {
+		mocks := SetupToolServiceMocks(t)
+		defer mocks.Teardown()
+
+		_, err := mocks.Service.ListTools(context.Background(), ToolQuery{}, domain.PageRequest{Cursor: "not-a-cursor"})
+
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+
+	t.Run("Filters and sort are passed to the repo", func(t *testing.T) {
+		mocks := SetupToolServiceMocks(t)
+		defer mocks.Teardown()
+
+		now := time.Now()
+		mocks.Service.now = func() time.Time { return now }
+		since := now.Add(-24 * time.Hour)
+		overdue := true
+		userID := TestUserID
+		name := "drill"
+		expectedFilter := repo.ToolFilter{
+			Statuses:        []domain.ToolStatus{domain.ToolStatusCheckedOut, domain.ToolStatusLost},
+			CurrentUserID:   &userID,
+			NameContains:    &name,
+			CheckedOutAfter: &since,
+			Overdue:         &overdue,
+			Now:             now,
+			Sort:            []repo.SortKey{{Field: "due_at"}, {Field: "name", Desc: true}},
+		}
+		tools := []domain.Tool{
+			CreateTestTool("tool1", "Drill", domain.ToolStatusCheckedOut),
+			CreateTestTool("tool2", "Drill press", domain.ToolStatusLost),
+		}
+		mocks.MockRepo.EXPECT().ListWithFilter(gomock.Any(), expectedFilter, 2, 5).Return(tools, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), expectedFilter).Return(9, nil)
+
+		result, err := mocks.Service.ListTools(context.Background(), ToolQuery{
+			Statuses:        []string{"CHECKED_OUT", "LOST"},
+			CurrentUserID:   TestUserID,
+			Name:            "  drill ",
+			CheckedOutAfter: &since,
+			Overdue:         &overdue,
+			Sort:            "due_at,-name",
+		}, domain.PageRequest{Limit: 1, Offset: 5})
+
+		require.NoError(t, err)
+		assert.Equal(t, tools[:1], result.Items)
+		assert.Equal(t, 9, result.Total)
+		// a sorted list pages by offset only
+		assert.True(t, result.HasMore)
+		assert.Empty(t, result.NextCursor)
+	})
+
+	t.Run("Invalid filters should fail", func(t *testing.T) {
+		mocks := SetupToolServiceMocks(t)
+		defer mocks.Teardown()
+
+		for _, q := range []ToolQuery{
+			{Statuses: []string{"BROKEN"}},
+			{CurrentUserID: InvalidUUID},
+			{Sort: "name,password"},
+			{Sort: "name,-name"},
+		} {
+			_, err := mocks.Service.ListTools(context.Background(), q, domain.PageRequest{})
+			assert.ErrorIs(t, err, domain.ErrValidation, q)
+		}
+
+		_, err := mocks.Service.ListTools(context.Background(), ToolQuery{Sort: "name"}, domain.PageRequest{Cursor: domain.Cursor{CreatedAt: time.Now(), ID: TestToolID}.Encode()})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
 }
 
 // TestToolService_GetTool tests tool retrieval by ID
@@ -234,9 +371,9 @@ func TestToolService_GetTool(t *testing.T) {
 
 		expectedTool := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusInOffice)
 
-		mocks.MockRepo.EXPECT().Get(TestToolID).Return(expectedTool, nil)
+		mocks.MockRepo.EXPECT().Get(gomock.Any(), TestToolID).Return(expectedTool, nil)
 
-		result, err := mocks.Service.GetTool(TestToolID)
+		result, err := mocks.Service.GetTool(context.Background(), TestToolID)
 
 		require.NoError(t, err)
 		assert.Equal(t, expectedTool, result)
@@ -246,7 +383,7 @@ func TestToolService_GetTool(t *testing.T) {
 		mocks := SetupToolServiceMocks(t)
 		defer mocks.Teardown()
 
-		_, err := mocks.Service.GetTool(InvalidUUID)
+		_, err := mocks.Service.GetTool(context.Background(), InvalidUUID)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "tool_id must be a valid UUID")
@@ -262,11 +399,11 @@ func TestToolService_UpdateTool(t *testing.T) {
 		existingTool := CreateTestTool(TestToolID, "Old Hammer", domain.ToolStatusInOffice)
 		updatedTool := CreateTestTool(TestToolID, "New Hammer", domain.ToolStatusMaintenance)
 
-		mocks.MockRepo.EXPECT().Get(TestToolID).Return(existingTool, nil)
-		mocks.MockRepo.EXPECT().Update(gomock.Any()).Return(updatedTool, nil)
-		mocks.MockLogger.EXPECT().LogToolUpdated(TestToolID, TestActorID, "Tool updated").Return(nil)
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(existingTool, nil)
+		mocks.MockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(updatedTool, nil)
+		mocks.MockLogger.EXPECT().LogToolUpdated(gomock.Any(), existingTool, updatedTool, TestActorID, "Tool updated").Return(nil)
 
-		result, err := mocks.ServiceWithLogger.UpdateTool(TestToolID, "New Hammer", domain.ToolStatusMaintenance, TestActorID, "Tool updated")
+		result, err := mocks.ServiceWithLogger.UpdateTool(context.Background(), TestToolID, domain.AnyVersion, "New Hammer", domain.ToolStatusMaintenance, nil, TestActorID, "Tool updated")
 
 		require.NoError(t, err)
 		assert.Equal(t, updatedTool, result)
@@ -276,11 +413,43 @@ func TestToolService_UpdateTool(t *testing.T) {
 		mocks := SetupToolServiceMocks(t)
 		defer mocks.Teardown()
 
-		_, err := mocks.Service.UpdateTool(InvalidUUID, "Hammer", domain.ToolStatusInOffice, TestActorID, "Tool updated")
+		_, err := mocks.Service.UpdateTool(context.Background(), InvalidUUID, domain.AnyVersion, "Hammer", domain.ToolStatusInOffice, nil, TestActorID, "Tool updated")
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "tool_id must be a valid UUID")
 	})
+
+	t.Run("Stale version should fail the precondition", func(t *testing.T) {
+		mocks := SetupToolServiceMocks(t)
+		defer mocks.Teardown()
+
+		existingTool := CreateTestTool(TestToolID, "Old Hammer", domain.ToolStatusInOffice)
+		existingTool.Version = 5
+
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(existingTool, nil)
+
+		_, err := mocks.ServiceWithLogger.UpdateTool(context.Background(), TestToolID, 4, "New Hammer", domain.ToolStatusInOffice, nil, TestActorID, "Tool updated")
+
+		assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+	})
+
+	t.Run("Matching version is applied", func(t *testing.T) {
+		mocks := SetupToolServiceMocks(t)
+		defer mocks.Teardown()
+
+		existingTool := CreateTestTool(TestToolID, "Old Hammer", domain.ToolStatusInOffice)
+		existingTool.Version = 5
+		updatedTool := CreateTestTool(TestToolID, "New Hammer", domain.ToolStatusInOffice)
+		updatedTool.Version = 6
+
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(existingTool, nil)
+		mocks.MockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(updatedTool, nil)
+
+		result, err := mocks.Service.UpdateTool(context.Background(), TestToolID, 5, "New Hammer", domain.ToolStatusInOffice, nil, TestActorID, "Tool updated")
+
+		require.NoError(t, err)
+		assert.Equal(t, 6, result.Version)
+	})
 }
 
 // TestToolService_SendToMaintenance tests sending tools to maintenance
@@ -292,11 +461,11 @@ func TestToolService_SendToMaintenance(t *testing.T) {
 		existingTool := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusInOffice)
 		maintenanceTool := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusMaintenance)
 
-		mocks.MockRepo.EXPECT().Get(TestToolID).Return(existingTool, nil)
-		mocks.MockRepo.EXPECT().Update(gomock.Any()).Return(maintenanceTool, nil)
-		mocks.MockLogger.EXPECT().LogToolMaintenance(TestToolID, TestActorID, "Needs repair").Return(nil)
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(existingTool, nil)
+		mocks.MockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(maintenanceTool, nil)
+		mocks.MockLogger.EXPECT().LogToolMaintenance(gomock.Any(), existingTool, maintenanceTool, TestActorID, "Needs repair", "", (*int64)(nil)).Return(nil)
 
-		result, err := mocks.ServiceWithLogger.SendToMaintenance(TestToolID, TestActorID, "Needs repair")
+		result, err := mocks.ServiceWithLogger.SendToMaintenance(context.Background(), TestToolID, domain.AnyVersion, TestActorID, "Needs repair", MaintenanceOptions{})
 
 		require.NoError(t, err)
 		assert.Equal(t, maintenanceTool, result)
@@ -308,9 +477,9 @@ func TestToolService_SendToMaintenance(t *testing.T) {
 
 		lostTool := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusLost)
 
-		mocks.MockRepo.EXPECT().Get(TestToolID).Return(lostTool, nil)
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(lostTool, nil)
 
-		_, err := mocks.Service.SendToMaintenance(TestToolID, TestActorID, "Needs repair")
+		_, err := mocks.Service.SendToMaintenance(context.Background(), TestToolID, domain.AnyVersion, TestActorID, "Needs repair", MaintenanceOptions{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "lost tools cannot be sent to maintenance")
@@ -322,11 +491,11 @@ func TestToolService_SendToMaintenance(t *testing.T) {
 
 		maintenanceTool := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusMaintenance)
 
-		mocks.MockRepo.EXPECT().Get(TestToolID).Return(maintenanceTool, nil)
-		mocks.MockRepo.EXPECT().Update(gomock.Any()).Return(maintenanceTool, nil)
-		mocks.MockLogger.EXPECT().LogToolMaintenance(TestToolID, TestActorID, "Still in maintenance").Return(nil)
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(maintenanceTool, nil)
+		mocks.MockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(maintenanceTool, nil)
+		mocks.MockLogger.EXPECT().LogToolMaintenance(gomock.Any(), maintenanceTool, maintenanceTool, TestActorID, "Still in maintenance", "", (*int64)(nil)).Return(nil)
 
-		result, err := mocks.ServiceWithLogger.SendToMaintenance(TestToolID, TestActorID, "Still in maintenance")
+		result, err := mocks.ServiceWithLogger.SendToMaintenance(context.Background(), TestToolID, domain.AnyVersion, TestActorID, "Still in maintenance", MaintenanceOptions{})
 
 		require.NoError(t, err)
 		assert.Equal(t, maintenanceTool, result)
@@ -342,11 +511,11 @@ func TestToolService_MarkLost(t *testing.T) {
 		existingTool := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusCheckedOut)
 		lostTool := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusLost)
 
-		mocks.MockRepo.EXPECT().Get(TestToolID).Return(existingTool, nil)
-		mocks.MockRepo.EXPECT().Update(gomock.Any()).Return(lostTool, nil)
-		mocks.MockLogger.EXPECT().LogToolLost(TestToolID, TestActorID, "Tool went missing").Return(nil)
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(existingTool, nil)
+		mocks.MockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(lostTool, nil)
+		mocks.MockLogger.EXPECT().LogToolLost(gomock.Any(), existingTool, lostTool, TestActorID, "Tool went missing").Return(nil)
 
-		result, err := mocks.ServiceWithLogger.MarkLost(TestToolID, TestActorID, "Tool went missing")
+		result, err := mocks.ServiceWithLogger.MarkLost(context.Background(), TestToolID, domain.AnyVersion, TestActorID, "Tool went missing")
 
 		require.NoError(t, err)
 		assert.Equal(t, lostTool, result)
@@ -358,11 +527,11 @@ func TestToolService_MarkLost(t *testing.T) {
 
 		lostTool := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusLost)
 
-		mocks.MockRepo.EXPECT().Get(TestToolID).Return(lostTool, nil)
-		mocks.MockRepo.EXPECT().Update(gomock.Any()).Return(lostTool, nil)
-		mocks.MockLogger.EXPECT().LogToolLost(TestToolID, TestActorID, "Still lost").Return(nil)
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(lostTool, nil)
+		mocks.MockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(lostTool, nil)
+		mocks.MockLogger.EXPECT().LogToolLost(gomock.Any(), lostTool, lostTool, TestActorID, "Still lost").Return(nil)
 
-		result, err := mocks.ServiceWithLogger.MarkLost(TestToolID, TestActorID, "Still lost")
+		result, err := mocks.ServiceWithLogger.MarkLost(context.Background(), TestToolID, domain.AnyVersion, TestActorID, "Still lost")
 
 		require.NoError(t, err)
 		assert.Equal(t, lostTool, result)
@@ -377,11 +546,11 @@ func TestToolService_DeleteTool(t *testing.T) {
 
 		toolToDelete := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusInOffice)
 
-		mocks.MockRepo.EXPECT().Get(TestToolID).Return(toolToDelete, nil)
-		mocks.MockRepo.EXPECT().Delete(TestToolID).Return(nil)
-		mocks.MockLogger.EXPECT().LogToolDeleted(TestToolID, TestActorID, "Tool deleted").Return(nil)
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(toolToDelete, nil)
+		mocks.MockRepo.EXPECT().Delete(gomock.Any(), TestToolID).Return(nil)
+		mocks.MockLogger.EXPECT().LogToolDeleted(gomock.Any(), toolToDelete, TestActorID, "Tool deleted").Return(nil)
 
-		err := mocks.ServiceWithLogger.DeleteTool(TestToolID, TestActorID, "Tool deleted")
+		err := mocks.ServiceWithLogger.DeleteTool(context.Background(), TestToolID, domain.AnyVersion, TestActorID, "Tool deleted")
 
 		require.NoError(t, err)
 	})
@@ -390,13 +559,55 @@ func TestToolService_DeleteTool(t *testing.T) {
 		mocks := SetupToolServiceMocks(t)
 		defer mocks.Teardown()
 
-		err := mocks.Service.DeleteTool(InvalidUUID, TestActorID, "Tool deleted")
+		err := mocks.Service.DeleteTool(context.Background(), InvalidUUID, domain.AnyVersion, TestActorID, "Tool deleted")
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "tool_id must be a valid UUID")
 	})
 }
 
+// TestToolService_RestoreTool tests undoing a soft delete
+func TestToolService_RestoreTool(t *testing.T) {
+	t.Run("Successful tool restore", func(t *testing.T) {
+		mocks := SetupToolServiceMocks(t)
+		defer mocks.Teardown()
+
+		deleted := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusInOffice)
+		deletedAt := time.Now()
+		deleted.DeletedAt = &deletedAt
+		restored := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusInOffice)
+
+		mocks.MockRepo.EXPECT().GetIncludingDeleted(gomock.Any(), TestToolID).Return(deleted, nil)
+		mocks.MockRepo.EXPECT().Restore(gomock.Any(), TestToolID).Return(restored, nil)
+		mocks.MockLogger.EXPECT().LogToolRestored(gomock.Any(), deleted, restored, TestActorID, "Tool restored").Return(nil)
+
+		result, err := mocks.ServiceWithLogger.RestoreTool(context.Background(), TestToolID, domain.AnyVersion, TestActorID, "Tool restored")
+
+		require.NoError(t, err)
+		assert.Equal(t, restored, result)
+	})
+
+	t.Run("Tool that is not deleted conflicts", func(t *testing.T) {
+		mocks := SetupToolServiceMocks(t)
+		defer mocks.Teardown()
+
+		mocks.MockRepo.EXPECT().GetIncludingDeleted(gomock.Any(), TestToolID).Return(CreateTestTool(TestToolID, "Hammer", domain.ToolStatusInOffice), nil)
+
+		_, err := mocks.ServiceWithLogger.RestoreTool(context.Background(), TestToolID, domain.AnyVersion, TestActorID, "")
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+	})
+
+	t.Run("Invalid tool ID should fail", func(t *testing.T) {
+		mocks := SetupToolServiceMocks(t)
+		defer mocks.Teardown()
+
+		_, err := mocks.Service.RestoreTool(context.Background(), InvalidUUID, domain.AnyVersion, TestActorID, "")
+
+		assert.ErrorContains(t, err, "tool_id must be a valid UUID")
+	})
+}
+
 // TestToolService_ListToolsByUser tests listing tools by user
 func TestToolService_ListToolsByUser(t *testing.T) {
 	t.Run("Successful list tools by user", func(t *testing.T) {
@@ -408,9 +619,9 @@ func TestToolService_ListToolsByUser(t *testing.T) {
 			CreateTestTool("tool2", "Screwdriver", domain.ToolStatusCheckedOut),
 		}
 
-		mocks.MockRepo.EXPECT().ListByUser(TestUserID, 10, 0).Return(expectedTools, nil)
+		mocks.MockRepo.EXPECT().ListByUser(gomock.Any(), TestUserID, 10, 0).Return(expectedTools, nil)
 
-		result, err := mocks.Service.ListToolsByUser(TestUserID, 10, 0)
+		result, err := mocks.Service.ListToolsByUser(context.Background(), TestUserID, 10, 0)
 
 		require.NoError(t, err)
 		assert.Equal(t, expectedTools, result)
@@ -420,7 +631,7 @@ func TestToolService_ListToolsByUser(t *testing.T) {
 		mocks := SetupToolServiceMocks(t)
 		defer mocks.Teardown()
 
-		_, err := mocks.Service.ListToolsByUser(InvalidUUID, 10, 0)
+		_, err := mocks.Service.ListToolsByUser(context.Background(), InvalidUUID, 10, 0)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "user_id must be a valid UUID")
@@ -438,9 +649,9 @@ func TestToolService_ListToolsByStatus(t *testing.T) {
 			CreateTestTool("tool2", "Saw", domain.ToolStatusInOffice),
 		}
 
-		mocks.MockRepo.EXPECT().ListByStatus(domain.ToolStatusInOffice, 10, 0).Return(expectedTools, nil)
+		mocks.MockRepo.EXPECT().ListByStatus(gomock.Any(), domain.ToolStatusInOffice, 10, 0).Return(expectedTools, nil)
 
-		result, err := mocks.Service.ListToolsByStatus(domain.ToolStatusInOffice, 10, 0)
+		result, err := mocks.Service.ListToolsByStatus(context.Background(), domain.ToolStatusInOffice, 10, 0)
 
 		require.NoError(t, err)
 		assert.Equal(t, expectedTools, result)
@@ -455,9 +666,9 @@ func TestToolService_GetToolCount(t *testing.T) {
 
 		expectedCount := 42
 
-		mocks.MockRepo.EXPECT().Count().Return(expectedCount, nil)
+		mocks.MockRepo.EXPECT().Count(gomock.Any()).Return(expectedCount, nil)
 
-		result, err := mocks.Service.GetToolCount()
+		result, err := mocks.Service.GetToolCount(context.Background())
 
 		require.NoError(t, err)
 		assert.Equal(t, expectedCount, result)
@@ -468,11 +679,79 @@ func TestToolService_GetToolCount(t *testing.T) {
 		defer mocks.Teardown()
 
 		repoError := assert.AnError
-		mocks.MockRepo.EXPECT().Count().Return(0, repoError)
+		mocks.MockRepo.EXPECT().Count(gomock.Any()).Return(0, repoError)
 
-		_, err := mocks.Service.GetToolCount()
+		_, err := mocks.Service.GetToolCount(context.Background())
 
 		assert.Error(t, err)
 		assert.Equal(t, repoError, err)
 	})
 }
+
+// TestToolService_SweepOverdue tests that overdue checkouts emit a single TOOL_OVERDUE event
+func TestToolService_SweepOverdue(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	overdueTool := func() domain.Tool {
+		tool := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusCheckedOut)
+		userID := TestUserID
+		dueAt := now.Add(-time.Hour)
+		tool.CurrentUserId = &userID
+		tool.DueAt = &dueAt
+		return tool
+	}
+
+	t.Run("Overdue checkout is notified once", func(t *testing.T) {
+		mocks := SetupToolServiceMocks(t)
+		defer mocks.Teardown()
+		mocks.ServiceWithLogger.now = func() time.Time { return now }
+
+		mocks.MockRepo.EXPECT().ListOverdueUnnotified(gomock.Any(), now, gomock.Any()).Return([]domain.Tool{overdueTool()}, nil)
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(overdueTool(), nil)
+		mocks.MockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, tool domain.Tool) (domain.Tool, error) {
+			require.NotNil(t, tool.OverdueNotifiedAt)
+			return tool, nil
+		})
+		mocks.MockLogger.EXPECT().LogToolOverdue(gomock.Any(), TestToolID, TestUserID, now.Add(-time.Hour)).Return(nil)
+
+		emitted, err := mocks.ServiceWithLogger.SweepOverdue(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, emitted)
+	})
+
+	t.Run("Tool returned since listing is skipped", func(t *testing.T) {
+		mocks := SetupToolServiceMocks(t)
+		defer mocks.Teardown()
+		mocks.ServiceWithLogger.now = func() time.Time { return now }
+
+		mocks.MockRepo.EXPECT().ListOverdueUnnotified(gomock.Any(), now, gomock.Any()).Return([]domain.Tool{overdueTool()}, nil)
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(CreateTestTool(TestToolID, "Hammer", domain.ToolStatusInOffice), nil)
+
+		emitted, err := mocks.ServiceWithLogger.SweepOverdue(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, emitted)
+	})
+	t.Run("A failing tool does not stop the sweep", func(t *testing.T) {
+		mocks := SetupToolServiceMocks(t)
+		defer mocks.Teardown()
+		mocks.ServiceWithLogger.now = func() time.Time { return now }
+		broken := overdueTool()
+		brokenID := TestToolID2
+		broken.ID = &brokenID
+
+		mocks.MockRepo.EXPECT().ListOverdueUnnotified(gomock.Any(), now, gomock.Any()).Return([]domain.Tool{broken, overdueTool()}, nil)
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID2).Return(domain.Tool{}, assert.AnError)
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(overdueTool(), nil)
+		mocks.MockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, tool domain.Tool) (domain.Tool, error) {
+			return tool, nil
+		})
+		mocks.MockLogger.EXPECT().LogToolOverdue(gomock.Any(), TestToolID, TestUserID, now.Add(-time.Hour)).Return(nil)
+
+		emitted, err := mocks.ServiceWithLogger.SweepOverdue(context.Background())
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, TestToolID2)
+		assert.Equal(t, 1, emitted)
+	})
+}
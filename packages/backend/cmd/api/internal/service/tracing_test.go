@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/service/mocks"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestToolService_Tracing tests the span tree of a checkout
+func TestToolService_Tracing(t *testing.T) {
+	setup := func(t *testing.T) (*ToolService, *mocks.MockToolRepo, *mocks.MockEventRepo) {
+		ctrl := gomock.NewController(t)
+		txTools := mocks.NewMockToolRepo(ctrl)
+		txEvents := mocks.NewMockEventRepo(ctrl)
+		svc := NewToolService(mocks.NewMockToolRepo(ctrl)).
+			WithEventLogger(NewEventService(mocks.NewMockEventRepo(ctrl))).
+			WithUnitOfWork(&fakeUnitOfWork{repos: TxRepos{Tools: txTools, Events: txEvents}})
+		return svc, txTools, txEvents
+	}
+	// spanOf returns the ID of the span a repo call was made under, where its SQL spans would hang.
+	spanOf := func(ctx context.Context) trace.SpanID {
+		return trace.SpanFromContext(ctx).SpanContext().SpanID()
+	}
+
+	t.Run("Checkout", func(t *testing.T) {
+		rec := tracing.RecordSpans(t)
+		svc, txTools, txEvents := setup(t)
+
+		var toolQueries, eventQueries []trace.SpanID
+		checkedOut := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusCheckedOut)
+		txTools.EXPECT().GetForUpdate(gomock.Any(), TestToolID).DoAndReturn(func(ctx context.Context, _ string) (domain.Tool, error) {
+			toolQueries = append(toolQueries, spanOf(ctx))
+			return CreateTestTool(TestToolID, "Hammer", domain.ToolStatusInOffice), nil
+		})
+		txTools.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, _ domain.Tool) (domain.Tool, error) {
+			toolQueries = append(toolQueries, spanOf(ctx))
+			return checkedOut, nil
+		})
+		txEvents.EXPECT().Create(gomock.Any(), domain.EventTypeToolCheckedOut, gomock.Any(), gomock.Any(), gomock.Any(), "", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, eventType domain.EventType, _, _, _ *string, _ string, _ *domain.EventMetadata) (domain.Event, error) {
+				eventQueries = append(eventQueries, spanOf(ctx))
+				return domain.Event{ID: TestEventID, Type: eventType}, nil
+			})
+
+		_, err := svc.CheckOutTool(context.Background(), TestToolID, domain.AnyVersion, TestUserID, TestActorID, "", CheckoutOptions{})
+		require.NoError(t, err)
+
+		require.Len(t, rec.Ended(), 3)
+		checkout := tracing.SpanNamed(rec, "ToolService.CheckOutTool")
+		apply := tracing.SpanNamed(rec, "ToolService.applyAndSave")
+		event := tracing.SpanNamed(rec, "EventService.CreateEvent")
+		require.NotNil(t, checkout)
+		require.NotNil(t, apply)
+		require.NotNil(t, event)
+
+		assert.False(t, checkout.Parent().IsValid())
+		assert.Equal(t, checkout.SpanContext().SpanID(), apply.Parent().SpanID())
+		assert.Equal(t, apply.SpanContext().SpanID(), event.Parent().SpanID())
+		assert.Equal(t, []trace.SpanID{apply.SpanContext().SpanID(), apply.SpanContext().SpanID()}, toolQueries)
+		assert.Equal(t, []trace.SpanID{event.SpanContext().SpanID()}, eventQueries)
+
+		assert.Contains(t, checkout.Attributes(), attribute.String("tool_id", TestToolID))
+		assert.Contains(t, checkout.Attributes(), attribute.String("user_id", TestUserID))
+		assert.Contains(t, event.Attributes(), attribute.String("event_type", string(domain.EventTypeToolCheckedOut)))
+		assert.Contains(t, event.Attributes(), attribute.String("tool_id", TestToolID))
+	})
+
+	t.Run("Rejected checkout marks its spans failed", func(t *testing.T) {
+		rec := tracing.RecordSpans(t)
+		svc, txTools, _ := setup(t)
+
+		txTools.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(CreateTestTool(TestToolID, "Hammer", domain.ToolStatusLost), nil)
+
+		_, err := svc.CheckOutTool(context.Background(), TestToolID, domain.AnyVersion, TestUserID, TestActorID, "", CheckoutOptions{})
+		require.Error(t, err)
+
+		require.Len(t, rec.Ended(), 2)
+		for _, span := range rec.Ended() {
+			assert.Equal(t, codes.Error, span.Status().Code, span.Name())
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/repo"
+)
+
+// TxRepos are the repositories handed to a unit of work. A nil field means the
+// repository is not available in that unit of work.
+type TxRepos struct {
+	Tools        ToolRepo
+	Users        UserRepo
+	Events       EventRepo
+	Reservations ReservationRepo
+}
+
+// UnitOfWork runs fn so that every write made through the given repos either
+// commits together or not at all.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(tx TxRepos) error) error
+}
+
+// repoBoundLogger is implemented by event loggers that can write through another
+// EventRepo, so their events join a unit of work. It is kept out of EventLogger
+// because the generated mocks must not depend on this package.
+type repoBoundLogger interface {
+	WithRepo(r EventRepo) EventLogger
+}
+
+// bindLogger returns l bound to the unit of work's event repo when both support it,
+// otherwise l itself.
+func bindLogger(l EventLogger, tx TxRepos) EventLogger {
+	if b, ok := l.(repoBoundLogger); ok && tx.Events != nil {
+		return b.WithRepo(tx.Events)
+	}
+	return l
+}
+
+// directUnitOfWork runs fn against the service's own repos with no transaction.
+// It is the default until WithUnitOfWork is called, which keeps services usable
+// with plain repos (e.g. in tests) but offers no atomicity.
+type directUnitOfWork struct {
+	repos TxRepos
+}
+
+func (u directUnitOfWork) Do(ctx context.Context, fn func(tx TxRepos) error) error {
+	return fn(u.repos)
+}
+
+// PostgresUnitOfWork runs fn in a database transaction using the Postgres repos.
+// The events fn logs join the audit log's hash chain just before commit.
+type PostgresUnitOfWork struct {
+	db      *sql.DB
+	metrics Metrics
+}
+
+func NewPostgresUnitOfWork(db *sql.DB) *PostgresUnitOfWork {
+	return &PostgresUnitOfWork{db: db}
+}
+
+// WithMetrics counts the events each unit of work commits, by type (optional chaining style).
+func (u *PostgresUnitOfWork) WithMetrics(m Metrics) *PostgresUnitOfWork {
+	u.metrics = m
+	return u
+}
+
+func (u *PostgresUnitOfWork) Do(ctx context.Context, fn func(tx TxRepos) error) error {
+	var committed []domain.Event
+	err := repo.WithTx(ctx, u.db, func(tx repo.DBTX) error {
+		events := repo.NewPostgresEventRepo(tx).WithDeferredChain()
+		err := fn(TxRepos{
+			Tools:        repo.NewPostgresToolRepo(tx),
+			Users:        repo.NewPostgresUserRepo(tx),
+			Events:       events,
+			Reservations: repo.NewPostgresReservationRepo(tx),
+		})
+		if err != nil {
+			return err
+		}
+		// chained last, so every unit of work takes the chain head after its row locks
+		committed, err = events.ChainPending(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if u.metrics != nil {
+		for _, e := range committed {
+			u.metrics.EventWritten(e.Type)
+		}
+	}
+	return nil
+}
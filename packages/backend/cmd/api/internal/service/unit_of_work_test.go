@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/service/mocks"
+)
+
+// fakeUnitOfWork hands out fixed tx repos and records whether the work committed.
+type fakeUnitOfWork struct {
+	repos     TxRepos
+	committed bool
+	rolled    bool
+}
+
+func (u *fakeUnitOfWork) Do(ctx context.Context, fn func(tx TxRepos) error) error {
+	if err := fn(u.repos); err != nil {
+		u.rolled = true
+		return err
+	}
+	u.committed = true
+	return nil
+}
+
+// TestToolService_UnitOfWork tests that mutations and their events share one unit of work
+func TestToolService_UnitOfWork(t *testing.T) {
+	setup := func(t *testing.T) (*ToolService, *fakeUnitOfWork, *mocks.MockToolRepo, *mocks.MockEventRepo) {
+		ctrl := gomock.NewController(t)
+		// the service's own repos must not be touched by mutations
+		outerTools := mocks.NewMockToolRepo(ctrl)
+		outerEvents := mocks.NewMockEventRepo(ctrl)
+		txTools := mocks.NewMockToolRepo(ctrl)
+		txEvents := mocks.NewMockEventRepo(ctrl)
+
+		uow := &fakeUnitOfWork{repos: TxRepos{Tools: txTools, Events: txEvents}}
+		svc := NewToolService(outerTools).
+			WithEventLogger(NewEventService(outerEvents)).
+			WithUnitOfWork(uow)
+		return svc, uow, txTools, txEvents
+	}
+
+	t.Run("Checkout and event are written through the unit of work", func(t *testing.T) {
+		svc, uow, txTools, txEvents := setup(t)
+
+		availableTool := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusInOffice)
+		checkedOutTool := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusCheckedOut)
+
+		// Set expectations
+		txTools.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(availableTool, nil)
+		txTools.EXPECT().Update(gomock.Any(), gomock.Any()).Return(checkedOutTool, nil)
+		txEvents.EXPECT().Create(gomock.Any(), domain.EventTypeToolCheckedOut, gomock.Any(), gomock.Any(), gomock.Any(), "notes", gomock.Any()).
+			Return(domain.Event{ID: TestEventID}, nil)
+
+		// Execute
+		_, err := svc.CheckOutTool(context.Background(), TestToolID, domain.AnyVersion, TestUserID, TestActorID, "notes", CheckoutOptions{})
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, uow.committed)
+	})
+
+	t.Run("Event failure rolls back the checkout", func(t *testing.T) {
+		svc, uow, txTools, txEvents := setup(t)
+
+		availableTool := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusInOffice)
+
+		txTools.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(availableTool, nil)
+		txTools.EXPECT().Update(gomock.Any(), gomock.Any()).Return(availableTool, nil)
+		txEvents.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(domain.Event{}, assert.AnError)
+
+		_, err := svc.CheckOutTool(context.Background(), TestToolID, domain.AnyVersion, TestUserID, TestActorID, "notes", CheckoutOptions{})
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.True(t, uow.rolled)
+		assert.False(t, uow.committed)
+	})
+
+	t.Run("Delete logs its event before removing the tool", func(t *testing.T) {
+		svc, uow, txTools, txEvents := setup(t)
+
+		toolToDelete := CreateTestTool(TestToolID, "Hammer", domain.ToolStatusInOffice)
+
+		gomock.InOrder(
+			txTools.EXPECT().GetForUpdate(gomock.Any(), TestToolID).Return(toolToDelete, nil),
+			txEvents.EXPECT().Create(gomock.Any(), domain.EventTypeToolDeleted, gomock.Any(), gomock.Any(), gomock.Any(), "gone", gomock.Any()).
+				Return(domain.Event{ID: TestEventID}, nil),
+			txTools.EXPECT().Delete(gomock.Any(), TestToolID).Return(nil),
+		)
+
+		err := svc.DeleteTool(context.Background(), TestToolID, domain.AnyVersion, TestActorID, "gone")
+
+		require.NoError(t, err)
+		assert.True(t, uow.committed)
+	})
+}
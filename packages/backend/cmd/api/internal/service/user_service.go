@@ -1,31 +1,43 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/repo"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/tracing"
 )
 
 //go:generate mockgen -source=user_service.go -destination=mocks/mock_user_interfaces.go -package=mocks
 
 type UserRepo interface {
-	Create(name string, email string, role domain.UserRole) (domain.User, error)
-	List(limit, offset int) ([]domain.User, error)
-	Get(id string) (domain.User, error)
-	GetByEmail(email string) (domain.User, error)
-	Update(id string, name string, email string, role domain.UserRole) (domain.User, error)
-	Delete(id string) error
-	ListByRole(role domain.UserRole, limit, offset int) ([]domain.User, error)
-	Count() (int, error)
+	Create(ctx context.Context, name string, email string, role domain.UserRole) (domain.User, error)
+	List(ctx context.Context, after *domain.Cursor, limit, offset int) ([]domain.User, error)
+	ListWithFilter(ctx context.Context, filter repo.UserFilter, limit, offset int) ([]domain.User, error)
+	CountWithFilter(ctx context.Context, filter repo.UserFilter) (int, error)
+	Get(ctx context.Context, id string) (domain.User, error)
+	GetIncludingDeleted(ctx context.Context, id string) (domain.User, error)
+	GetForUpdate(ctx context.Context, id string) (domain.User, error)
+	GetByEmail(ctx context.Context, email string) (domain.User, error)
+	Update(ctx context.Context, id string, name string, email string, role domain.UserRole) (domain.User, error)
+	Delete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) (domain.User, error)
+	ListByRole(ctx context.Context, role domain.UserRole, after *domain.Cursor, limit, offset int) ([]domain.User, error)
+	Count(ctx context.Context) (int, error)
+	CountByRole(ctx context.Context) (map[domain.UserRole]int, error)
+	TopBorrowers(ctx context.Context, since time.Time, limit int) ([]domain.UserCheckouts, error)
 }
 
 type UserService struct {
 	Repo   UserRepo
 	events EventLogger
+	uow    UnitOfWork
 }
 
 func NewUserService(r UserRepo) *UserService {
-	return &UserService{Repo: r}
+	return &UserService{Repo: r, uow: directUnitOfWork{repos: TxRepos{Users: r}}}
 }
 
 // WithEventLogger sets the event logger dependency (optional chaining style).
@@ -34,59 +46,108 @@ func (s *UserService) WithEventLogger(l EventLogger) *UserService {
 	return s
 }
 
-func (s *UserService) CreateUser(name string, email string, role domain.UserRole, actorID, notes string) (domain.User, error) {
+// WithUnitOfWork makes each mutation commit atomically with its event (optional chaining style).
+func (s *UserService) WithUnitOfWork(u UnitOfWork) *UserService {
+	s.uow = u
+	return s
+}
+
+// logger returns the event logger for a unit of work (nil if none is configured).
+func (s *UserService) logger(tx TxRepos) EventLogger {
+	if s.events == nil {
+		return nil
+	}
+	return bindLogger(s.events, tx)
+}
+
+func (s *UserService) CreateUser(ctx context.Context, name string, email string, role domain.UserRole, actorID, notes string) (_ domain.User, opErr error) {
+	defer logOp(ctx, "user.create", time.Now(), &opErr, "actor_id", actorID)
+	ctx, span := tracing.Start(ctx, "UserService.CreateUser")
+	defer tracing.End(span, &opErr)
 	u, err := domain.NewUser(name, email, role)
 	if err != nil {
 		return domain.User{}, err
 	}
 
-	// Check if user with email already exists
-	if existing, err := s.Repo.GetByEmail(u.Email); err == nil && existing.ID != "" {
-		return domain.User{}, fmt.Errorf("%w: user with email '%s' already exists", domain.ErrConflict, u.Email)
-	}
+	var created domain.User
+	err = s.uow.Do(ctx, func(tx TxRepos) error {
+		// Check if user with email already exists
+		if existing, err := tx.Users.GetByEmail(ctx, u.Email); err == nil && existing.ID != "" {
+			return fmt.Errorf("%w: user with email '%s' already exists", domain.ErrConflict, u.Email)
+		}
 
-	created, err := s.Repo.Create(u.Name, u.Email, u.Role)
+		var err error
+		created, err = tx.Users.Create(ctx, u.Name, u.Email, u.Role)
+		if err != nil {
+			return err
+		}
+		if l := s.logger(tx); l != nil && created.ID != "" {
+			return l.LogUserCreated(ctx, created, actorID, notes)
+		}
+		return nil
+	})
 	if err != nil {
 		return domain.User{}, err
 	}
-	if s.events != nil {
-		if created.ID != "" {
-			_ = s.events.LogUserCreated(created.ID, actorID, notes)
-		}
-	}
 	return created, nil
 }
 
-func (s *UserService) ListUsers(limit, offset int) ([]domain.User, error) {
-	if limit <= 0 {
-		limit = 10
-	}
-	if limit > 100 {
-		limit = 100
-	}
-	if offset < 0 {
-		offset = 0
+// UserQuery holds the filters of a user listing. Empty fields do not filter.
+type UserQuery struct {
+	Role domain.UserRole
+	// IncludeDeleted also lists soft-deleted users.
+	IncludeDeleted bool
+}
+
+// ListUsers returns one page of the users matching q, newest first.
+func (s *UserService) ListUsers(ctx context.Context, q UserQuery, req domain.PageRequest) (domain.Page[domain.User], error) {
+	filter := repo.UserFilter{IncludeDeleted: q.IncludeDeleted}
+	if q.Role != "" {
+		if err := domain.ValidateUserRole(q.Role); err != nil {
+			return domain.Page[domain.User]{}, err
+		}
+		filter.Role = &q.Role
 	}
 
-	return s.Repo.List(limit, offset)
+	req = clampPage(req, 10, 100)
+	return fetchPage(req, userCursor, func(after *domain.Cursor, limit, offset int) ([]domain.User, error) {
+		page := filter
+		page.After = after
+		return s.Repo.ListWithFilter(ctx, page, limit, offset)
+	}, func() (int, error) {
+		return s.Repo.CountWithFilter(ctx, filter)
+	})
 }
 
-func (s *UserService) GetUser(id string) (domain.User, error) {
+func (s *UserService) GetUser(ctx context.Context, id string) (domain.User, error) {
 	if err := domain.ValidateUUID(id, "user_id"); err != nil {
 		return domain.User{}, err
 	}
-	return s.Repo.Get(id)
+	return s.Repo.Get(ctx, id)
 }
 
-func (s *UserService) GetUserByEmail(email string) (domain.User, error) {
+// GetUserIncludingDeleted is GetUser that also finds soft-deleted users.
+func (s *UserService) GetUserIncludingDeleted(ctx context.Context, id string) (domain.User, error) {
+	if err := domain.ValidateUUID(id, "user_id"); err != nil {
+		return domain.User{}, err
+	}
+	return s.Repo.GetIncludingDeleted(ctx, id)
+}
+
+func (s *UserService) GetUserByEmail(ctx context.Context, email string) (domain.User, error) {
 	if email == "" {
 		return domain.User{}, fmt.Errorf("user email cannot be empty")
 	}
 
-	return s.Repo.GetByEmail(email)
+	return s.Repo.GetByEmail(ctx, email)
 }
 
-func (s *UserService) UpdateUser(id string, name string, email string, role domain.UserRole, actorID, notes string) (domain.User, error) {
+// UpdateUser replaces a user's details. expectedVersion is checked against the locked row
+// (domain.AnyVersion skips it).
+func (s *UserService) UpdateUser(ctx context.Context, id string, expectedVersion int, name string, email string, role domain.UserRole, actorID, notes string) (_ domain.User, opErr error) {
+	defer logOp(ctx, "user.update", time.Now(), &opErr, "user_id", id, "actor_id", actorID)
+	ctx, span := tracing.Start(ctx, "UserService.UpdateUser", tracing.UserID(id))
+	defer tracing.End(span, &opErr)
 	if err := domain.ValidateUUID(id, "user_id"); err != nil {
 		return domain.User{}, err
 	}
@@ -95,59 +156,105 @@ func (s *UserService) UpdateUser(id string, name string, email string, role doma
 		return domain.User{}, err
 	}
 
-	// Ensure email uniqueness (if another user has this email)
-	if existing, err := s.Repo.GetByEmail(u.Email); err == nil && existing.ID != id {
-		return domain.User{}, fmt.Errorf("%w: user with email '%s' already exists", domain.ErrConflict, u.Email)
-	}
+	var updated domain.User
+	err = s.uow.Do(ctx, func(tx TxRepos) error {
+		current, err := tx.Users.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := domain.CheckVersion(expectedVersion, current.Version); err != nil {
+			return err
+		}
+
+		// Ensure email uniqueness (if another user has this email)
+		if existing, err := tx.Users.GetByEmail(ctx, u.Email); err == nil && existing.ID != id {
+			return fmt.Errorf("%w: user with email '%s' already exists", domain.ErrConflict, u.Email)
+		}
 
-	updated, err := s.Repo.Update(id, u.Name, u.Email, u.Role)
+		updated, err = tx.Users.Update(ctx, id, u.Name, u.Email, u.Role)
+		if err != nil {
+			return err
+		}
+		if l := s.logger(tx); l != nil && updated.ID != "" {
+			return l.LogUserUpdated(ctx, current, updated, actorID, notes)
+		}
+		return nil
+	})
 	if err != nil {
 		return domain.User{}, err
 	}
-	if s.events != nil {
-		if updated.ID != "" {
-			_ = s.events.LogUserUpdated(updated.ID, actorID, notes)
-		}
-	}
 	return updated, nil
 }
 
-func (s *UserService) DeleteUser(id string, actorID, notes string) error {
+// DeleteUser soft-deletes a user; RestoreUser brings them back. expectedVersion is checked
+// against the locked row (domain.AnyVersion skips it).
+func (s *UserService) DeleteUser(ctx context.Context, id string, expectedVersion int, actorID, notes string) (opErr error) {
+	defer logOp(ctx, "user.delete", time.Now(), &opErr, "user_id", id, "actor_id", actorID)
+	ctx, span := tracing.Start(ctx, "UserService.DeleteUser", tracing.UserID(id))
+	defer tracing.End(span, &opErr)
 	if err := domain.ValidateUUID(id, "user_id"); err != nil {
 		return err
 	}
-	u, err := s.Repo.Get(id)
-	if err != nil {
-		return err
-	}
-	if err := s.Repo.Delete(id); err != nil {
-		return err
-	}
-	if s.events != nil && u.ID != "" {
-		_ = s.events.LogUserDeleted(u.ID, actorID, notes)
-	}
-	return nil
+	return s.uow.Do(ctx, func(tx TxRepos) error {
+		u, err := tx.Users.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := domain.CheckVersion(expectedVersion, u.Version); err != nil {
+			return err
+		}
+		if l := s.logger(tx); l != nil && u.ID != "" {
+			if err := l.LogUserDeleted(ctx, u, actorID, notes); err != nil {
+				return err
+			}
+		}
+		return tx.Users.Delete(ctx, id)
+	})
 }
 
-func (s *UserService) ListUsersByRole(role domain.UserRole, limit, offset int) ([]domain.User, error) {
-	// Validate role
-	if err := domain.ValidateUserRole(role); err != nil {
-		return nil, err
+// RestoreUser undoes the soft delete of a user. It fails with a conflict when a live user
+// has taken their email meanwhile. expectedVersion is checked against the deleted row
+// (domain.AnyVersion skips it).
+func (s *UserService) RestoreUser(ctx context.Context, id string, expectedVersion int, actorID, notes string) (_ domain.User, opErr error) {
+	defer logOp(ctx, "user.restore", time.Now(), &opErr, "user_id", id, "actor_id", actorID)
+	ctx, span := tracing.Start(ctx, "UserService.RestoreUser", tracing.UserID(id))
+	defer tracing.End(span, &opErr)
+	if err := domain.ValidateUUID(id, "user_id"); err != nil {
+		return domain.User{}, err
 	}
 
-	if limit <= 0 {
-		limit = 10
-	}
-	if limit > 100 {
-		limit = 100
-	}
-	if offset < 0 {
-		offset = 0
-	}
+	var restored domain.User
+	err := s.uow.Do(ctx, func(tx TxRepos) error {
+		u, err := tx.Users.GetIncludingDeleted(ctx, id)
+		if err != nil {
+			return err
+		}
+		if u.DeletedAt == nil {
+			return fmt.Errorf("%w: user is not deleted", domain.ErrConflict)
+		}
+		if err := domain.CheckVersion(expectedVersion, u.Version); err != nil {
+			return err
+		}
+		if existing, err := tx.Users.GetByEmail(ctx, u.Email); err == nil && existing.ID != id {
+			return fmt.Errorf("%w: user with email '%s' already exists", domain.ErrConflict, u.Email)
+		}
 
-	return s.Repo.ListByRole(role, limit, offset)
+		// Restore only matches a deleted row, so a concurrent restore makes this one fail
+		restored, err = tx.Users.Restore(ctx, id)
+		if err != nil {
+			return err
+		}
+		if l := s.logger(tx); l != nil && restored.ID != "" {
+			return l.LogUserRestored(ctx, u, restored, actorID, notes)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+	return restored, nil
 }
 
-func (s *UserService) GetUserCount() (int, error) {
-	return s.Repo.Count()
+func (s *UserService) GetUserCount(ctx context.Context) (int, error) {
+	return s.Repo.Count(ctx)
 }
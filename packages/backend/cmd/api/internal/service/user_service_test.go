@@ -1,11 +1,15 @@
 package service
 
 import (
+	"context"
+	"github.com/golang/mock/gomock"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/repo"
 )
 
 // TestUserService_CreateUser tests the user creation workflow
@@ -17,12 +21,12 @@ func TestUserService_CreateUser(t *testing.T) {
 		createdUser := CreateTestUser(TestUserID, "John Doe", "john@example.com", domain.UserRoleEmployee)
 
 		// Set expectations - check email doesn't exist, then create
-		mocks.MockRepo.EXPECT().GetByEmail("john@example.com").Return(domain.User{}, assert.AnError)
-		mocks.MockRepo.EXPECT().Create("John Doe", "john@example.com", domain.UserRoleEmployee).Return(createdUser, nil)
-		mocks.MockLogger.EXPECT().LogUserCreated(TestUserID, TestActorID, "User created").Return(nil)
+		mocks.MockRepo.EXPECT().GetByEmail(gomock.Any(), "john@example.com").Return(domain.User{}, assert.AnError)
+		mocks.MockRepo.EXPECT().Create(gomock.Any(), "John Doe", "john@example.com", domain.UserRoleEmployee).Return(createdUser, nil)
+		mocks.MockLogger.EXPECT().LogUserCreated(gomock.Any(), createdUser, TestActorID, "User created").Return(nil)
 
 		// Execute
-		result, err := mocks.ServiceWithLogger.CreateUser("John Doe", "john@example.com", domain.UserRoleEmployee, TestActorID, "User created")
+		result, err := mocks.ServiceWithLogger.CreateUser(context.Background(), "John Doe", "john@example.com", domain.UserRoleEmployee, TestActorID, "User created")
 
 		// Assert
 		require.NoError(t, err)
@@ -35,10 +39,10 @@ func TestUserService_CreateUser(t *testing.T) {
 
 		createdUser := CreateTestUser(TestUserID, "Jane Doe", "jane@example.com", domain.UserRoleAdmin)
 
-		mocks.MockRepo.EXPECT().GetByEmail("jane@example.com").Return(domain.User{}, assert.AnError)
-		mocks.MockRepo.EXPECT().Create("Jane Doe", "jane@example.com", domain.UserRoleAdmin).Return(createdUser, nil)
+		mocks.MockRepo.EXPECT().GetByEmail(gomock.Any(), "jane@example.com").Return(domain.User{}, assert.AnError)
+		mocks.MockRepo.EXPECT().Create(gomock.Any(), "Jane Doe", "jane@example.com", domain.UserRoleAdmin).Return(createdUser, nil)
 
-		result, err := mocks.Service.CreateUser("Jane Doe", "jane@example.com", domain.UserRoleAdmin, TestActorID, "User created")
+		result, err := mocks.Service.CreateUser(context.Background(), "Jane Doe", "jane@example.com", domain.UserRoleAdmin, TestActorID, "User created")
 
 		require.NoError(t, err)
 		assert.Equal(t, createdUser, result)
@@ -50,9 +54,9 @@ func TestUserService_CreateUser(t *testing.T) {
 
 		existingUser := CreateTestUser(TestUserID2, "Existing User", "john@example.com", domain.UserRoleEmployee)
 
-		mocks.MockRepo.EXPECT().GetByEmail("john@example.com").Return(existingUser, nil)
+		mocks.MockRepo.EXPECT().GetByEmail(gomock.Any(), "john@example.com").Return(existingUser, nil)
 
-		_, err := mocks.Service.CreateUser("John Doe", "john@example.com", domain.UserRoleEmployee, TestActorID, "User created")
+		_, err := mocks.Service.CreateUser(context.Background(), "John Doe", "john@example.com", domain.UserRoleEmployee, TestActorID, "User created")
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "user with email 'john@example.com' already exists")
@@ -63,10 +67,10 @@ func TestUserService_CreateUser(t *testing.T) {
 		defer mocks.Teardown()
 
 		repoError := assert.AnError
-		mocks.MockRepo.EXPECT().GetByEmail("john@example.com").Return(domain.User{}, assert.AnError)
-		mocks.MockRepo.EXPECT().Create("John Doe", "john@example.com", domain.UserRoleEmployee).Return(domain.User{}, repoError)
+		mocks.MockRepo.EXPECT().GetByEmail(gomock.Any(), "john@example.com").Return(domain.User{}, assert.AnError)
+		mocks.MockRepo.EXPECT().Create(gomock.Any(), "John Doe", "john@example.com", domain.UserRoleEmployee).Return(domain.User{}, repoError)
 
-		_, err := mocks.Service.CreateUser("John Doe", "john@example.com", domain.UserRoleEmployee, TestActorID, "User created")
+		_, err := mocks.Service.CreateUser(context.Background(), "John Doe", "john@example.com", domain.UserRoleEmployee, TestActorID, "User created")
 
 		assert.Error(t, err)
 		assert.Equal(t, repoError, err)
@@ -79,14 +83,16 @@ func TestUserService_UpdateUser(t *testing.T) {
 		mocks := SetupUserServiceMocks(t)
 		defer mocks.Teardown()
 
+		currentUser := CreateTestUser(TestUserID, "John Doe", "john@example.com", domain.UserRoleEmployee)
 		updatedUser := CreateTestUser(TestUserID, "John Smith", "john.smith@example.com", domain.UserRoleAdmin)
 
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestUserID).Return(currentUser, nil)
 		// Email is changing, so check it's not taken by another user
-		mocks.MockRepo.EXPECT().GetByEmail("john.smith@example.com").Return(domain.User{}, assert.AnError)
-		mocks.MockRepo.EXPECT().Update(TestUserID, "John Smith", "john.smith@example.com", domain.UserRoleAdmin).Return(updatedUser, nil)
-		mocks.MockLogger.EXPECT().LogUserUpdated(TestUserID, TestActorID, "User updated").Return(nil)
+		mocks.MockRepo.EXPECT().GetByEmail(gomock.Any(), "john.smith@example.com").Return(domain.User{}, assert.AnError)
+		mocks.MockRepo.EXPECT().Update(gomock.Any(), TestUserID, "John Smith", "john.smith@example.com", domain.UserRoleAdmin).Return(updatedUser, nil)
+		mocks.MockLogger.EXPECT().LogUserUpdated(gomock.Any(), currentUser, updatedUser, TestActorID, "User updated").Return(nil)
 
-		result, err := mocks.ServiceWithLogger.UpdateUser(TestUserID, "John Smith", "john.smith@example.com", domain.UserRoleAdmin, TestActorID, "User updated")
+		result, err := mocks.ServiceWithLogger.UpdateUser(context.Background(), TestUserID, domain.AnyVersion, "John Smith", "john.smith@example.com", domain.UserRoleAdmin, TestActorID, "User updated")
 
 		require.NoError(t, err)
 		assert.Equal(t, updatedUser, result)
@@ -96,21 +102,37 @@ func TestUserService_UpdateUser(t *testing.T) {
 		mocks := SetupUserServiceMocks(t)
 		defer mocks.Teardown()
 
+		currentUser := CreateTestUser(TestUserID, "John Doe", "john@example.com", domain.UserRoleEmployee)
 		existingUser := CreateTestUser(TestUserID2, "Another User", "taken@example.com", domain.UserRoleEmployee)
 
-		mocks.MockRepo.EXPECT().GetByEmail("taken@example.com").Return(existingUser, nil)
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestUserID).Return(currentUser, nil)
+		mocks.MockRepo.EXPECT().GetByEmail(gomock.Any(), "taken@example.com").Return(existingUser, nil)
 
-		_, err := mocks.Service.UpdateUser(TestUserID, "John Smith", "taken@example.com", domain.UserRoleAdmin, TestActorID, "User updated")
+		_, err := mocks.Service.UpdateUser(context.Background(), TestUserID, domain.AnyVersion, "John Smith", "taken@example.com", domain.UserRoleAdmin, TestActorID, "User updated")
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "user with email 'taken@example.com' already exists")
 	})
 
+	t.Run("Stale version should fail the precondition", func(t *testing.T) {
+		mocks := SetupUserServiceMocks(t)
+		defer mocks.Teardown()
+
+		currentUser := CreateTestUser(TestUserID, "John Doe", "john@example.com", domain.UserRoleEmployee)
+		currentUser.Version = 3
+
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestUserID).Return(currentUser, nil)
+
+		_, err := mocks.Service.UpdateUser(context.Background(), TestUserID, 2, "John Smith", "john@example.com", domain.UserRoleAdmin, TestActorID, "User updated")
+
+		assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+	})
+
 	t.Run("Invalid user ID should fail", func(t *testing.T) {
 		mocks := SetupUserServiceMocks(t)
 		defer mocks.Teardown()
 
-		_, err := mocks.Service.UpdateUser(InvalidUUID, "John Smith", "john@example.com", domain.UserRoleAdmin, TestActorID, "User updated")
+		_, err := mocks.Service.UpdateUser(context.Background(), InvalidUUID, domain.AnyVersion, "John Smith", "john@example.com", domain.UserRoleAdmin, TestActorID, "User updated")
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "user_id must be a valid UUID")
@@ -125,9 +147,9 @@ func TestUserService_GetUser(t *testing.T) {
 
 		expectedUser := CreateTestUser(TestUserID, "John Doe", "john@example.com", domain.UserRoleEmployee)
 
-		mocks.MockRepo.EXPECT().Get(TestUserID).Return(expectedUser, nil)
+		mocks.MockRepo.EXPECT().Get(gomock.Any(), TestUserID).Return(expectedUser, nil)
 
-		result, err := mocks.Service.GetUser(TestUserID)
+		result, err := mocks.Service.GetUser(context.Background(), TestUserID)
 
 		require.NoError(t, err)
 		assert.Equal(t, expectedUser, result)
@@ -137,7 +159,7 @@ func TestUserService_GetUser(t *testing.T) {
 		mocks := SetupUserServiceMocks(t)
 		defer mocks.Teardown()
 
-		_, err := mocks.Service.GetUser(InvalidUUID)
+		_, err := mocks.Service.GetUser(context.Background(), InvalidUUID)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "user_id must be a valid UUID")
@@ -152,9 +174,9 @@ func TestUserService_GetUserByEmail(t *testing.T) {
 
 		expectedUser := CreateTestUser(TestUserID, "John Doe", "john@example.com", domain.UserRoleEmployee)
 
-		mocks.MockRepo.EXPECT().GetByEmail("john@example.com").Return(expectedUser, nil)
+		mocks.MockRepo.EXPECT().GetByEmail(gomock.Any(), "john@example.com").Return(expectedUser, nil)
 
-		result, err := mocks.Service.GetUserByEmail("john@example.com")
+		result, err := mocks.Service.GetUserByEmail(context.Background(), "john@example.com")
 
 		require.NoError(t, err)
 		assert.Equal(t, expectedUser, result)
@@ -164,7 +186,7 @@ func TestUserService_GetUserByEmail(t *testing.T) {
 		mocks := SetupUserServiceMocks(t)
 		defer mocks.Teardown()
 
-		_, err := mocks.Service.GetUserByEmail("")
+		_, err := mocks.Service.GetUserByEmail(context.Background(), "")
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "user email cannot be empty")
@@ -179,11 +201,11 @@ func TestUserService_DeleteUser(t *testing.T) {
 
 		userToDelete := CreateTestUser(TestUserID, "John Doe", "john@example.com", domain.UserRoleEmployee)
 
-		mocks.MockRepo.EXPECT().Get(TestUserID).Return(userToDelete, nil)
-		mocks.MockRepo.EXPECT().Delete(TestUserID).Return(nil)
-		mocks.MockLogger.EXPECT().LogUserDeleted(TestUserID, TestActorID, "User deleted").Return(nil)
+		mocks.MockRepo.EXPECT().GetForUpdate(gomock.Any(), TestUserID).Return(userToDelete, nil)
+		mocks.MockRepo.EXPECT().Delete(gomock.Any(), TestUserID).Return(nil)
+		mocks.MockLogger.EXPECT().LogUserDeleted(gomock.Any(), userToDelete, TestActorID, "User deleted").Return(nil)
 
-		err := mocks.ServiceWithLogger.DeleteUser(TestUserID, TestActorID, "User deleted")
+		err := mocks.ServiceWithLogger.DeleteUser(context.Background(), TestUserID, domain.AnyVersion, TestActorID, "User deleted")
 
 		require.NoError(t, err)
 	})
@@ -192,92 +214,94 @@ func TestUserService_DeleteUser(t *testing.T) {
 		mocks := SetupUserServiceMocks(t)
 		defer mocks.Teardown()
 
-		err := mocks.Service.DeleteUser(InvalidUUID, TestActorID, "User deleted")
+		err := mocks.Service.DeleteUser(context.Background(), InvalidUUID, domain.AnyVersion, TestActorID, "User deleted")
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "user_id must be a valid UUID")
 	})
 }
 
-// TestUserService_ListUsers tests user listing
-func TestUserService_ListUsers(t *testing.T) {
-	t.Run("Successful users listing", func(t *testing.T) {
-		mocks := SetupUserServiceMocks(t)
-		defer mocks.Teardown()
-
-		expectedUsers := []domain.User{
-			CreateTestUser("user1", "John Doe", "john@example.com", domain.UserRoleEmployee),
-			CreateTestUser("user2", "Jane Smith", "jane@example.com", domain.UserRoleAdmin),
-		}
-
-		mocks.MockRepo.EXPECT().List(10, 0).Return(expectedUsers, nil)
-
-		result, err := mocks.Service.ListUsers(10, 0)
-
-		require.NoError(t, err)
-		assert.Equal(t, expectedUsers, result)
-	})
+// TestUserService_RestoreUser tests undoing a soft delete
+func TestUserService_RestoreUser(t *testing.T) {
+	deletedUser := func() domain.User {
+		u := CreateTestUser(TestUserID, "John Doe", "john@example.com", domain.UserRoleEmployee)
+		deletedAt := time.Now()
+		u.DeletedAt = &deletedAt
+		return u
+	}
 
-	t.Run("Default limit applied when zero", func(t *testing.T) {
+	t.Run("Successful user restore", func(t *testing.T) {
 		mocks := SetupUserServiceMocks(t)
 		defer mocks.Teardown()
 
-		mocks.MockRepo.EXPECT().List(10, 0).Return([]domain.User{}, nil)
+		restored := CreateTestUser(TestUserID, "John Doe", "john@example.com", domain.UserRoleEmployee)
 
-		_, err := mocks.Service.ListUsers(0, 0)
+		mocks.MockRepo.EXPECT().GetIncludingDeleted(gomock.Any(), TestUserID).Return(deletedUser(), nil)
+		mocks.MockRepo.EXPECT().GetByEmail(gomock.Any(), "john@example.com").Return(domain.User{}, domain.ErrUserNotFound)
+		mocks.MockRepo.EXPECT().Restore(gomock.Any(), TestUserID).Return(restored, nil)
+		mocks.MockLogger.EXPECT().LogUserRestored(gomock.Any(), gomock.Any(), restored, TestActorID, "User restored").Return(nil)
+
+		result, err := mocks.ServiceWithLogger.RestoreUser(context.Background(), TestUserID, domain.AnyVersion, TestActorID, "User restored")
 
 		require.NoError(t, err)
+		assert.Equal(t, restored, result)
 	})
 
-	t.Run("Maximum limit enforced", func(t *testing.T) {
+	t.Run("Email taken meanwhile conflicts", func(t *testing.T) {
 		mocks := SetupUserServiceMocks(t)
 		defer mocks.Teardown()
 
-		mocks.MockRepo.EXPECT().List(100, 0).Return([]domain.User{}, nil)
+		other := CreateTestUser("other-user", "Johnny", "john@example.com", domain.UserRoleEmployee)
+		mocks.MockRepo.EXPECT().GetIncludingDeleted(gomock.Any(), TestUserID).Return(deletedUser(), nil)
+		mocks.MockRepo.EXPECT().GetByEmail(gomock.Any(), "john@example.com").Return(other, nil)
 
-		_, err := mocks.Service.ListUsers(150, 0)
+		_, err := mocks.ServiceWithLogger.RestoreUser(context.Background(), TestUserID, domain.AnyVersion, TestActorID, "")
 
-		require.NoError(t, err)
+		assert.ErrorIs(t, err, domain.ErrConflict)
 	})
 
-	t.Run("Negative offset corrected", func(t *testing.T) {
+	t.Run("User that is not deleted conflicts", func(t *testing.T) {
 		mocks := SetupUserServiceMocks(t)
 		defer mocks.Teardown()
 
-		mocks.MockRepo.EXPECT().List(10, 0).Return([]domain.User{}, nil)
+		live := CreateTestUser(TestUserID, "John Doe", "john@example.com", domain.UserRoleEmployee)
+		mocks.MockRepo.EXPECT().GetIncludingDeleted(gomock.Any(), TestUserID).Return(live, nil)
 
-		_, err := mocks.Service.ListUsers(10, -5)
+		_, err := mocks.ServiceWithLogger.RestoreUser(context.Background(), TestUserID, domain.AnyVersion, TestActorID, "")
 
-		require.NoError(t, err)
+		assert.ErrorIs(t, err, domain.ErrConflict)
 	})
 }
 
-// TestUserService_ListUsersByRole tests listing users by role
-func TestUserService_ListUsersByRole(t *testing.T) {
-	t.Run("Successful list users by role", func(t *testing.T) {
+// TestUserService_ListUsers tests user listing
+func TestUserService_ListUsers(t *testing.T) {
+	t.Run("Successful users listing", func(t *testing.T) {
 		mocks := SetupUserServiceMocks(t)
 		defer mocks.Teardown()
 
 		expectedUsers := []domain.User{
-			CreateTestUser("user1", "John Doe", "john@example.com", domain.UserRoleAdmin),
+			CreateTestUser("user1", "John Doe", "john@example.com", domain.UserRoleEmployee),
 			CreateTestUser("user2", "Jane Smith", "jane@example.com", domain.UserRoleAdmin),
 		}
 
-		mocks.MockRepo.EXPECT().ListByRole(domain.UserRoleAdmin, 10, 0).Return(expectedUsers, nil)
+		mocks.MockRepo.EXPECT().ListWithFilter(gomock.Any(), repo.UserFilter{}, 11, 0).Return(expectedUsers, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), repo.UserFilter{}).Return(3, nil)
 
-		result, err := mocks.Service.ListUsersByRole(domain.UserRoleAdmin, 10, 0)
+		result, err := mocks.Service.ListUsers(context.Background(), UserQuery{}, domain.PageRequest{Limit: 10})
 
 		require.NoError(t, err)
-		assert.Equal(t, expectedUsers, result)
+		assert.Equal(t, expectedUsers, result.Items)
+		assert.Equal(t, 3, result.Total)
 	})
 
 	t.Run("Default limit applied when zero", func(t *testing.T) {
 		mocks := SetupUserServiceMocks(t)
 		defer mocks.Teardown()
 
-		mocks.MockRepo.EXPECT().ListByRole(domain.UserRoleEmployee, 10, 0).Return([]domain.User{}, nil)
+		mocks.MockRepo.EXPECT().ListWithFilter(gomock.Any(), repo.UserFilter{}, 11, 0).Return([]domain.User{}, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), repo.UserFilter{}).Return(3, nil)
 
-		_, err := mocks.Service.ListUsersByRole(domain.UserRoleEmployee, 0, 0)
+		_, err := mocks.Service.ListUsers(context.Background(), UserQuery{}, domain.PageRequest{})
 
 		require.NoError(t, err)
 	})
@@ -286,9 +310,10 @@ func TestUserService_ListUsersByRole(t *testing.T) {
 		mocks := SetupUserServiceMocks(t)
 		defer mocks.Teardown()
 
-		mocks.MockRepo.EXPECT().ListByRole(domain.UserRoleEmployee, 100, 0).Return([]domain.User{}, nil)
+		mocks.MockRepo.EXPECT().ListWithFilter(gomock.Any(), repo.UserFilter{}, 101, 0).Return([]domain.User{}, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), repo.UserFilter{}).Return(3, nil)
 
-		_, err := mocks.Service.ListUsersByRole(domain.UserRoleEmployee, 150, 0)
+		_, err := mocks.Service.ListUsers(context.Background(), UserQuery{}, domain.PageRequest{Limit: 150})
 
 		require.NoError(t, err)
 	})
@@ -297,12 +322,38 @@ func TestUserService_ListUsersByRole(t *testing.T) {
 		mocks := SetupUserServiceMocks(t)
 		defer mocks.Teardown()
 
-		mocks.MockRepo.EXPECT().ListByRole(domain.UserRoleEmployee, 10, 0).Return([]domain.User{}, nil)
+		mocks.MockRepo.EXPECT().ListWithFilter(gomock.Any(), repo.UserFilter{}, 11, 0).Return([]domain.User{}, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), repo.UserFilter{}).Return(3, nil)
 
-		_, err := mocks.Service.ListUsersByRole(domain.UserRoleEmployee, 10, -5)
+		_, err := mocks.Service.ListUsers(context.Background(), UserQuery{}, domain.PageRequest{Limit: 10, Offset: -5})
 
 		require.NoError(t, err)
 	})
+
+	t.Run("Role and deleted users filter", func(t *testing.T) {
+		mocks := SetupUserServiceMocks(t)
+		defer mocks.Teardown()
+
+		role := domain.UserRoleAdmin
+		filter := repo.UserFilter{Role: &role, IncludeDeleted: true}
+		expectedUsers := []domain.User{CreateTestUser("user1", "John Doe", "john@example.com", domain.UserRoleAdmin)}
+		mocks.MockRepo.EXPECT().ListWithFilter(gomock.Any(), filter, 11, 0).Return(expectedUsers, nil)
+		mocks.MockRepo.EXPECT().CountWithFilter(gomock.Any(), filter).Return(1, nil)
+
+		result, err := mocks.Service.ListUsers(context.Background(), UserQuery{Role: domain.UserRoleAdmin, IncludeDeleted: true}, domain.PageRequest{Limit: 10})
+
+		require.NoError(t, err)
+		assert.Equal(t, expectedUsers, result.Items)
+	})
+
+	t.Run("Invalid role", func(t *testing.T) {
+		mocks := SetupUserServiceMocks(t)
+		defer mocks.Teardown()
+
+		_, err := mocks.Service.ListUsers(context.Background(), UserQuery{Role: "OWNER"}, domain.PageRequest{})
+
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
 }
 
 // TestUserService_GetUserCount tests getting user count
@@ -313,9 +364,9 @@ func TestUserService_GetUserCount(t *testing.T) {
 
 		expectedCount := 25
 
-		mocks.MockRepo.EXPECT().Count().Return(expectedCount, nil)
+		mocks.MockRepo.EXPECT().Count(gomock.Any()).Return(expectedCount, nil)
 
-		result, err := mocks.Service.GetUserCount()
+		result, err := mocks.Service.GetUserCount(context.Background())
 
 		require.NoError(t, err)
 		assert.Equal(t, expectedCount, result)
@@ -326,9 +377,9 @@ func TestUserService_GetUserCount(t *testing.T) {
 		defer mocks.Teardown()
 
 		repoError := assert.AnError
-		mocks.MockRepo.EXPECT().Count().Return(0, repoError)
+		mocks.MockRepo.EXPECT().Count(gomock.Any()).Return(0, repoError)
 
-		_, err := mocks.Service.GetUserCount()
+		_, err := mocks.Service.GetUserCount(context.Background())
 
 		assert.Error(t, err)
 		assert.Equal(t, repoError, err)
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/tracing"
+)
+
+const (
+	// DefaultUtilizationWindow is the report period when no start is given.
+	DefaultUtilizationWindow = 30 * 24 * time.Hour
+	// MaxUtilizationWindow bounds the report period.
+	MaxUtilizationWindow = 366 * 24 * time.Hour
+	// utilizationBatch is how many status changes Utilization reads per query.
+	utilizationBatch = 500
+)
+
+// UtilizationReport is the utilization of every tool (or borrower) over [From, To).
+type UtilizationReport struct {
+	From    time.Time
+	To      time.Time
+	GroupBy domain.UtilizationGroup
+	Rows    []domain.Utilization
+}
+
+// UtilizationService derives tool usage from the event history. It seeds each tool's
+// status at the start of the window from its last change before it, then replays the
+// status changes inside the window into intervals and measures them against it.
+type UtilizationService struct {
+	events EventRepo
+	now    func() time.Time
+}
+
+func NewUtilizationService(events EventRepo) *UtilizationService {
+	return &UtilizationService{events: events, now: time.Now}
+}
+
+// Utilization reports usage over [from, to). A nil to means now and a nil from means
+// DefaultUtilizationWindow before to. Time after now is not counted.
+func (s *UtilizationService) Utilization(ctx context.Context, from, to *time.Time, groupBy domain.UtilizationGroup) (_ UtilizationReport, opErr error) {
+	defer logOp(ctx, "report.utilization", time.Now(), &opErr, "group_by", string(groupBy))
+	ctx, span := tracing.Start(ctx, "UtilizationService.Utilization")
+	defer tracing.End(span, &opErr)
+
+	if groupBy == "" {
+		groupBy = domain.UtilizationByTool
+	}
+	if err := domain.ValidateUtilizationGroup(groupBy); err != nil {
+		return UtilizationReport{}, err
+	}
+	now := s.now()
+	report := UtilizationReport{To: now, GroupBy: groupBy}
+	if to != nil {
+		report.To = *to
+	}
+	report.From = report.To.Add(-DefaultUtilizationWindow)
+	if from != nil {
+		report.From = *from
+	}
+	if !report.From.Before(report.To) {
+		return UtilizationReport{}, fmt.Errorf("%w: from must be before to", domain.ErrValidation)
+	}
+	if report.To.Sub(report.From) > MaxUtilizationWindow {
+		return UtilizationReport{}, fmt.Errorf("%w: window must be at most %d days", domain.ErrValidation, int(MaxUtilizationWindow.Hours()/24))
+	}
+
+	end := report.To
+	if now.Before(end) {
+		end = now
+	}
+	replay := newUtilizationReplay(report.From, end)
+	seed, err := s.events.ListStatusesAt(ctx, report.From)
+	if err != nil {
+		return UtilizationReport{}, err
+	}
+	for _, c := range seed {
+		replay.apply(c)
+	}
+	var after *domain.Cursor
+	for {
+		changes, err := s.events.ListStatusChanges(ctx, report.From, report.To, after, utilizationBatch)
+		if err != nil {
+			return UtilizationReport{}, err
+		}
+		for _, c := range changes {
+			replay.apply(c)
+		}
+		if len(changes) < utilizationBatch {
+			break
+		}
+		last := changes[len(changes)-1]
+		after = &domain.Cursor{CreatedAt: last.At, ID: last.EventID}
+	}
+	report.Rows = replay.rows(groupBy)
+	return report, nil
+}
+
+// toolTimeline is the replay state of one tool.
+type toolTimeline struct {
+	name    string
+	status  domain.ToolStatus
+	since   time.Time
+	exists  bool
+	holder  *string
+	loanAt  time.Time
+	onLoan  bool
+	tracked time.Duration
+	inState map[domain.ToolStatus]time.Duration
+	loans   int
+	loanSum time.Duration
+}
+
+// borrower accumulates the loans of one user.
+type borrower struct {
+	name       string
+	checkedOut time.Duration
+	loans      int
+	loanSum    time.Duration
+}
+
+// utilizationReplay replays status changes (oldest first) and measures them against
+// [from, end). Loans are counted when they start inside the window; an open loan is
+// measured up to end.
+type utilizationReplay struct {
+	from  time.Time
+	end   time.Time
+	tools map[string]*toolTimeline
+	users map[string]*borrower
+}
+
+func newUtilizationReplay(from, end time.Time) *utilizationReplay {
+	return &utilizationReplay{from: from, end: end, tools: map[string]*toolTimeline{}, users: map[string]*borrower{}}
+}
+
+// closeInterval attributes the time since the tool's last change to its status and holder
+func (r *utilizationReplay) closeInterval(t *toolTimeline, at time.Time) {
+	if !t.exists {
+		return
+	}
+	d := overlap(t.since, at, r.from, r.end)
+	t.tracked += d
+	t.inState[t.status] += d
+	if t.status == domain.ToolStatusCheckedOut && t.holder != nil {
+		r.users[*t.holder].checkedOut += d
+	}
+	t.since = at
+}
+
+// endLoan records the duration of a loan that started inside the window
+func (r *utilizationReplay) endLoan(t *toolTimeline, at time.Time) {
+	if !t.onLoan {
+		return
+	}
+	t.onLoan = false
+	if t.loanAt.Before(r.from) || !t.loanAt.Before(r.end) {
+		return
+	}
+	if at.After(r.end) {
+		at = r.end
+	}
+	t.loans++
+	t.loanSum += at.Sub(t.loanAt)
+	if t.holder != nil {
+		u := r.users[*t.holder]
+		u.loans++
+		u.loanSum += at.Sub(t.loanAt)
+	}
+}
+
+func (r *utilizationReplay) apply(c domain.StatusChange) {
+	t, ok := r.tools[c.ToolID]
+	if !ok {
+		// tools without a TOOL_CREATED event are tracked from their first change
+		t = &toolTimeline{status: domain.ToolStatusInOffice, since: c.At, exists: true, inState: map[domain.ToolStatus]time.Duration{}}
+		r.tools[c.ToolID] = t
+	}
+	t.name = c.ToolName
+	r.closeInterval(t, c.At)
+
+	eventType := c.Type
+	if eventType == domain.EventTypeToolUpdated {
+		// an edited status counts as the event that would have set it
+		eventType = statusEventType(c.Status)
+	}
+	switch eventType {
+	case domain.EventTypeToolCreated:
+		t.exists = true
+		t.status = domain.ToolStatusInOffice
+	case domain.EventTypeToolCheckedOut:
+		r.endLoan(t, c.At)
+		t.status = domain.ToolStatusCheckedOut
+		t.holder = c.UserID
+		if c.UserID != nil {
+			if _, ok := r.users[*c.UserID]; !ok {
+				r.users[*c.UserID] = &borrower{}
+			}
+			r.users[*c.UserID].name = c.UserName
+		}
+		t.onLoan = true
+		t.loanAt = c.At
+	case domain.EventTypeToolCheckedIn:
+		r.endLoan(t, c.At)
+		t.status = domain.ToolStatusInOffice
+		t.holder = nil
+	case domain.EventTypeToolMaintenance:
+		t.status = domain.ToolStatusMaintenance
+	case domain.EventTypeToolLost:
+		t.status = domain.ToolStatusLost
+	case domain.EventTypeToolDeleted:
+		r.endLoan(t, c.At)
+		t.exists = false
+		t.holder = nil
+	case domain.EventTypeToolRestored:
+		// back in the status it was deleted in; the time it spent deleted is not tracked
+		t.exists = true
+		t.since = c.At
+	}
+}
+
+// statusEventType returns the event that moves a tool into status.
+func statusEventType(status domain.ToolStatus) domain.EventType {
+	switch status {
+	case domain.ToolStatusCheckedOut:
+		return domain.EventTypeToolCheckedOut
+	case domain.ToolStatusInOffice:
+		return domain.EventTypeToolCheckedIn
+	case domain.ToolStatusMaintenance:
+		return domain.EventTypeToolMaintenance
+	case domain.ToolStatusLost:
+		return domain.EventTypeToolLost
+	}
+	return ""
+}
+
+// rows closes the open intervals and loans at end and returns the report rows.
+func (r *utilizationReplay) rows(groupBy domain.UtilizationGroup) []domain.Utilization {
+	for _, t := range r.tools {
+		r.closeInterval(t, r.end)
+		r.endLoan(t, r.end)
+	}
+
+	rows := []domain.Utilization{}
+	if groupBy == domain.UtilizationByUser {
+		window := r.end.Sub(r.from)
+		for id, u := range r.users {
+			if u.checkedOut == 0 && u.loans == 0 {
+				continue
+			}
+			userID := id
+			rows = append(rows, domain.Utilization{
+				UserID:            &userID,
+				Name:              u.name,
+				TrackedSeconds:    seconds(window),
+				CheckedOutSeconds: seconds(u.checkedOut),
+				CheckedOutPct:     percent(u.checkedOut, window),
+				Loans:             u.loans,
+				MeanLoanSeconds:   meanSeconds(u.loanSum, u.loans),
+			})
+		}
+	} else {
+		for id, t := range r.tools {
+			if t.tracked == 0 {
+				continue
+			}
+			toolID := id
+			rows = append(rows, domain.Utilization{
+				ToolID:             &toolID,
+				Name:               t.name,
+				TrackedSeconds:     seconds(t.tracked),
+				CheckedOutSeconds:  seconds(t.inState[domain.ToolStatusCheckedOut]),
+				CheckedOutPct:      percent(t.inState[domain.ToolStatusCheckedOut], t.tracked),
+				Loans:              t.loans,
+				MeanLoanSeconds:    meanSeconds(t.loanSum, t.loans),
+				MaintenanceSeconds: seconds(t.inState[domain.ToolStatusMaintenance]),
+				LostSeconds:        seconds(t.inState[domain.ToolStatusLost]),
+			})
+		}
+	}
+
+	// busiest first; ties by name, then ID so the order is stable
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].CheckedOutPct != rows[j].CheckedOutPct {
+			return rows[i].CheckedOutPct > rows[j].CheckedOutPct
+		}
+		if rows[i].Name != rows[j].Name {
+			return rows[i].Name < rows[j].Name
+		}
+		return rowID(rows[i]) < rowID(rows[j])
+	})
+	return rows
+}
+
+// overlap returns how much of [start, stop) falls inside [from, end).
+func overlap(start, stop, from, end time.Time) time.Duration {
+	if start.Before(from) {
+		start = from
+	}
+	if stop.After(end) {
+		stop = end
+	}
+	if !stop.After(start) {
+		return 0
+	}
+	return stop.Sub(start)
+}
+
+func seconds(d time.Duration) int64 {
+	return int64(d / time.Second)
+}
+
+func meanSeconds(sum time.Duration, n int) int64 {
+	if n == 0 {
+		return 0
+	}
+	return seconds(sum / time.Duration(n))
+}
+
+// percent returns part/whole as a percentage rounded to two decimals.
+func percent(part, whole time.Duration) float64 {
+	if whole <= 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(whole)*10000) / 100
+}
+
+func rowID(u domain.Utilization) string {
+	if u.ToolID != nil {
+		return *u.ToolID
+	}
+	if u.UserID != nil {
+		return *u.UserID
+	}
+	return ""
+}
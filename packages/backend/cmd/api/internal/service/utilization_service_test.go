@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+)
+
+// TestUtilizationService_Utilization tests deriving usage from status changes
+func TestUtilizationService_Utilization(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(100 * time.Hour)
+	at := func(h int) time.Time { return from.Add(time.Duration(h) * time.Hour) }
+	userID, user2ID := TestUserID, TestUserID2
+	change := func(eventType domain.EventType, toolID, toolName string, user *string, h int) domain.StatusChange {
+		sc := domain.StatusChange{EventID: fmt.Sprintf("%s-%d", toolName, h), Type: eventType, ToolID: toolID, ToolName: toolName, UserID: user, At: at(h)}
+		if user != nil {
+			sc.UserName = map[string]string{TestUserID: "Ann", TestUserID2: "Bob"}[*user]
+		}
+		return sc
+	}
+	// drill: created before the window, out 10h-30h to Ann, out 50h-... (open) to Bob
+	seed := []domain.StatusChange{change(domain.EventTypeToolCreated, TestToolID, "Drill", nil, -50)}
+	changes := []domain.StatusChange{
+		change(domain.EventTypeToolCheckedOut, TestToolID, "Drill", &userID, 10),
+		change(domain.EventTypeToolCheckedIn, TestToolID, "Drill", &userID, 30),
+		change(domain.EventTypeToolCheckedOut, TestToolID, "Drill", &user2ID, 50),
+		// saw: created at 20h, in maintenance 40h-60h (closed by a checkout), lost from 80h
+		change(domain.EventTypeToolCreated, TestToolID2, "Saw", nil, 20),
+		change(domain.EventTypeToolMaintenance, TestToolID2, "Saw", &userID, 40),
+		change(domain.EventTypeToolCheckedOut, TestToolID2, "Saw", &userID, 60),
+		change(domain.EventTypeToolLost, TestToolID2, "Saw", &userID, 80),
+	}
+
+	// expect returns the seed at from, then the changes in the window in one page
+	expect := func(mocks *UtilizationServiceMocks, seed, changes []domain.StatusChange) {
+		mocks.MockEvents.EXPECT().ListStatusesAt(gomock.Any(), from).Return(seed, nil)
+		mocks.MockEvents.EXPECT().ListStatusChanges(gomock.Any(), from, to, nil, utilizationBatch).Return(changes, nil)
+	}
+
+	t.Run("By tool", func(t *testing.T) {
+		mocks := SetupUtilizationServiceMocks(t, to.Add(time.Hour))
+		defer mocks.Teardown()
+		expect(mocks, seed, changes)
+
+		report, err := mocks.Service.Utilization(context.Background(), &from, &to, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.UtilizationByTool, report.GroupBy)
+		require.Len(t, report.Rows, 2)
+
+		drill := report.Rows[0]
+		assert.Equal(t, "Drill", drill.Name)
+		assert.Equal(t, TestToolID, *drill.ToolID)
+		assert.Equal(t, int64(100*3600), drill.TrackedSeconds)
+		assert.Equal(t, int64(70*3600), drill.CheckedOutSeconds)
+		assert.Equal(t, 70.0, drill.CheckedOutPct)
+		assert.Equal(t, 2, drill.Loans)
+		// 20h closed loan and 50h open loan measured to the end of the window
+		assert.Equal(t, int64(35*3600), drill.MeanLoanSeconds)
+
+		saw := report.Rows[1]
+		assert.Equal(t, "Saw", saw.Name)
+		assert.Equal(t, int64(80*3600), saw.TrackedSeconds)
+		assert.Equal(t, int64(20*3600), saw.CheckedOutSeconds)
+		assert.Equal(t, 25.0, saw.CheckedOutPct)
+		assert.Equal(t, int64(20*3600), saw.MaintenanceSeconds)
+		assert.Equal(t, int64(20*3600), saw.LostSeconds)
+		assert.Equal(t, 1, saw.Loans)
+	})
+
+	t.Run("By user", func(t *testing.T) {
+		mocks := SetupUtilizationServiceMocks(t, to.Add(time.Hour))
+		defer mocks.Teardown()
+		expect(mocks, seed, changes)
+
+		report, err := mocks.Service.Utilization(context.Background(), &from, &to, domain.UtilizationByUser)
+
+		require.NoError(t, err)
+		require.Len(t, report.Rows, 2)
+		bob, ann := report.Rows[0], report.Rows[1]
+		assert.Equal(t, "Bob", bob.Name)
+		assert.Equal(t, int64(50*3600), bob.CheckedOutSeconds)
+		assert.Equal(t, 50.0, bob.CheckedOutPct)
+		assert.Equal(t, "Ann", ann.Name)
+		assert.Equal(t, TestUserID, *ann.UserID)
+		// drill 10h-30h and saw 60h-80h; lost time is no longer checked out
+		assert.Equal(t, int64(40*3600), ann.CheckedOutSeconds)
+		assert.Equal(t, 2, ann.Loans)
+		// a lost tool is still on loan until it is checked in
+		assert.Equal(t, int64(30*3600), ann.MeanLoanSeconds)
+	})
+
+	t.Run("Time after now is not counted", func(t *testing.T) {
+		mocks := SetupUtilizationServiceMocks(t, at(60))
+		defer mocks.Teardown()
+		expect(mocks, seed, changes[:3])
+
+		report, err := mocks.Service.Utilization(context.Background(), &from, &to, domain.UtilizationByTool)
+
+		require.NoError(t, err)
+		require.Len(t, report.Rows, 1)
+		assert.Equal(t, int64(60*3600), report.Rows[0].TrackedSeconds)
+		assert.Equal(t, int64(30*3600), report.Rows[0].CheckedOutSeconds)
+	})
+
+	t.Run("Defaults to the last 30 days", func(t *testing.T) {
+		mocks := SetupUtilizationServiceMocks(t, to)
+		defer mocks.Teardown()
+		mocks.MockEvents.EXPECT().ListStatusesAt(gomock.Any(), to.Add(-DefaultUtilizationWindow)).Return(nil, nil)
+		mocks.MockEvents.EXPECT().ListStatusChanges(gomock.Any(), to.Add(-DefaultUtilizationWindow), to, nil, utilizationBatch).Return(nil, nil)
+
+		report, err := mocks.Service.Utilization(context.Background(), nil, nil, domain.UtilizationByTool)
+
+		require.NoError(t, err)
+		assert.Equal(t, to.Add(-DefaultUtilizationWindow), report.From)
+		assert.Equal(t, to, report.To)
+		assert.Empty(t, report.Rows)
+	})
+
+	t.Run("Status before the window is seeded", func(t *testing.T) {
+		mocks := SetupUtilizationServiceMocks(t, to)
+		defer mocks.Teardown()
+		// out to Ann since before the window, back at 40h; the loan started outside it
+		expect(mocks, []domain.StatusChange{
+			change(domain.EventTypeToolCreated, TestToolID, "Drill", nil, -50),
+			change(domain.EventTypeToolCheckedOut, TestToolID, "Drill", &userID, -10),
+		}, []domain.StatusChange{
+			change(domain.EventTypeToolCheckedIn, TestToolID, "Drill", &userID, 40),
+		})
+
+		report, err := mocks.Service.Utilization(context.Background(), &from, &to, domain.UtilizationByUser)
+
+		require.NoError(t, err)
+		require.Len(t, report.Rows, 1)
+		assert.Equal(t, "Ann", report.Rows[0].Name)
+		assert.Equal(t, int64(40*3600), report.Rows[0].CheckedOutSeconds)
+		assert.Equal(t, 0, report.Rows[0].Loans)
+	})
+
+	t.Run("Status edits count", func(t *testing.T) {
+		mocks := SetupUtilizationServiceMocks(t, to)
+		defer mocks.Teardown()
+		// in maintenance from 20h until an edit puts it back in the office at 30h,
+		// then an edit checks it out to Ann at 60h
+		fixed := change(domain.EventTypeToolUpdated, TestToolID, "Drill", nil, 30)
+		fixed.Status = domain.ToolStatusInOffice
+		lent := change(domain.EventTypeToolUpdated, TestToolID, "Drill", &userID, 60)
+		lent.Status = domain.ToolStatusCheckedOut
+		expect(mocks, seed, []domain.StatusChange{
+			change(domain.EventTypeToolMaintenance, TestToolID, "Drill", nil, 20),
+			fixed,
+			lent,
+		})
+
+		report, err := mocks.Service.Utilization(context.Background(), &from, &to, domain.UtilizationByTool)
+
+		require.NoError(t, err)
+		require.Len(t, report.Rows, 1)
+		assert.Equal(t, int64(10*3600), report.Rows[0].MaintenanceSeconds)
+		assert.Equal(t, int64(40*3600), report.Rows[0].CheckedOutSeconds)
+		assert.Equal(t, 1, report.Rows[0].Loans)
+	})
+
+	t.Run("Changes are read in pages", func(t *testing.T) {
+		mocks := SetupUtilizationServiceMocks(t, to)
+		defer mocks.Teardown()
+		full := make([]domain.StatusChange, 0, utilizationBatch)
+		for i := 0; i < utilizationBatch; i++ {
+			full = append(full, change(domain.EventTypeToolMaintenance, TestToolID, "Drill", nil, 10))
+		}
+		last := full[len(full)-1]
+		mocks.MockEvents.EXPECT().ListStatusesAt(gomock.Any(), from).Return(seed, nil)
+		gomock.InOrder(
+			mocks.MockEvents.EXPECT().ListStatusChanges(gomock.Any(), from, to, nil, utilizationBatch).Return(full, nil),
+			mocks.MockEvents.EXPECT().ListStatusChanges(gomock.Any(), from, to, &domain.Cursor{CreatedAt: last.At, ID: last.EventID}, utilizationBatch).
+				Return([]domain.StatusChange{change(domain.EventTypeToolCheckedIn, TestToolID, "Drill", nil, 50)}, nil),
+		)
+
+		report, err := mocks.Service.Utilization(context.Background(), &from, &to, domain.UtilizationByTool)
+
+		require.NoError(t, err)
+		require.Len(t, report.Rows, 1)
+		assert.Equal(t, int64(40*3600), report.Rows[0].MaintenanceSeconds)
+	})
+
+	t.Run("Repository error should propagate", func(t *testing.T) {
+		mocks := SetupUtilizationServiceMocks(t, to)
+		defer mocks.Teardown()
+		mocks.MockEvents.EXPECT().ListStatusesAt(gomock.Any(), from).Return(seed, nil)
+		mocks.MockEvents.EXPECT().ListStatusChanges(gomock.Any(), from, to, nil, utilizationBatch).Return(nil, assert.AnError)
+
+		_, err := mocks.Service.Utilization(context.Background(), &from, &to, domain.UtilizationByTool)
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		mocks := SetupUtilizationServiceMocks(t, to)
+		defer mocks.Teardown()
+		tooEarly := to.Add(-MaxUtilizationWindow - time.Hour)
+
+		_, err := mocks.Service.Utilization(context.Background(), &from, &to, "week")
+		assert.ErrorIs(t, err, domain.ErrValidation)
+
+		_, err = mocks.Service.Utilization(context.Background(), &to, &from, domain.UtilizationByTool)
+		assert.ErrorIs(t, err, domain.ErrValidation)
+
+		_, err = mocks.Service.Utilization(context.Background(), &tooEarly, &to, domain.UtilizationByTool)
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+}
@@ -0,0 +1,143 @@
+// Package tracing sets up OpenTelemetry tracing for the API and holds the
+// helpers the HTTP, service and repository layers use to record spans.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/wassaaa/tool-tracker/cmd/api/internal/domain"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exporters accepted by Setup.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// instrumentationName names the tracer of every span the API records.
+const instrumentationName = "github.com/wassaaa/tool-tracker/cmd/api"
+
+// ValidateExporter reports whether exporter is one Setup accepts.
+func ValidateExporter(exporter string) error {
+	switch strings.ToLower(exporter) {
+	case ExporterNone, ExporterStdout, ExporterOTLP:
+		return nil
+	default:
+		return fmt.Errorf("invalid trace exporter %q, use %s, %s or %s", exporter, ExporterNone, ExporterStdout, ExporterOTLP)
+	}
+}
+
+// Setup installs the global tracer provider and W3C trace-context propagator.
+// The stdout exporter writes spans to w; the OTLP exporter sends them over
+// HTTP to endpoint (a URL such as http://collector:4318), or to the
+// OTEL_EXPORTER_OTLP_* environment settings when endpoint is empty. Sampling
+// follows OTEL_TRACES_SAMPLER and defaults to sampling every trace. With
+// ExporterNone no provider is installed and spans cost next to nothing.
+// The returned function flushes buffered spans and must be called on shutdown.
+func Setup(ctx context.Context, exporter, endpoint, serviceName string, w io.Writer) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var spanExporter sdktrace.SpanExporter
+	var err error
+	switch strings.ToLower(exporter) {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(w))
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+		}
+		spanExporter, err = otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, ValidateExporter(exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts an internal span as a child of the span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return start(ctx, trace.SpanKindInternal, name, attrs)
+}
+
+// StartServer starts the span of an inbound request.
+func StartServer(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return start(ctx, trace.SpanKindServer, name, attrs)
+}
+
+// StartClient starts the span of an outbound call, such as a SQL statement.
+func StartClient(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return start(ctx, trace.SpanKindClient, name, attrs)
+}
+
+// start looks the tracer up on every call, so spans follow whichever provider
+// is currently installed (tests swap in a recorder).
+func start(ctx context.Context, kind trace.SpanKind, name string, attrs []attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// End records *err on span, if any, and ends it. Defer it with a pointer to the
+// named error result:
+//
+//	ctx, span := tracing.Start(ctx, "ToolService.CheckOutTool", tracing.ToolID(toolID))
+//	defer tracing.End(span, &opErr)
+func End(span trace.Span, err *error) {
+	if err != nil {
+		RecordError(span, *err)
+	}
+	span.End()
+}
+
+// RecordError marks span as failed with err; a nil err is ignored.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Domain attributes shared by every layer, so spans of one tool or user can be
+// found with a single query.
+
+// ToolID returns the tool_id attribute.
+func ToolID(id string) attribute.KeyValue { return attribute.String("tool_id", id) }
+
+// UserID returns the user_id attribute.
+func UserID(id string) attribute.KeyValue { return attribute.String("user_id", id) }
+
+// ReservationID returns the reservation_id attribute.
+func ReservationID(id string) attribute.KeyValue { return attribute.String("reservation_id", id) }
+
+// EventType returns the event_type attribute.
+func EventType(t domain.EventType) attribute.KeyValue {
+	return attribute.String("event_type", string(t))
+}
@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestSpans(t *testing.T) {
+	t.Run("Children share the trace and point at their parent", func(t *testing.T) {
+		rec := RecordSpans(t)
+
+		ctx, parent := Start(context.Background(), "parent", ToolID("tool-1"))
+		_, child := StartClient(ctx, "child")
+		child.End()
+		parent.End()
+
+		require.Len(t, rec.Ended(), 2)
+		p, c := SpanNamed(rec, "parent"), SpanNamed(rec, "child")
+		assert.Equal(t, p.SpanContext().SpanID(), c.Parent().SpanID())
+		assert.Equal(t, p.SpanContext().TraceID(), c.SpanContext().TraceID())
+		assert.Contains(t, p.Attributes(), attribute.String("tool_id", "tool-1"))
+	})
+
+	t.Run("End records the error result", func(t *testing.T) {
+		rec := RecordSpans(t)
+
+		func() (opErr error) {
+			_, span := Start(context.Background(), "failing")
+			defer End(span, &opErr)
+			return errors.New("boom")
+		}()
+		func() (opErr error) {
+			_, span := Start(context.Background(), "passing")
+			defer End(span, &opErr)
+			return nil
+		}()
+
+		assert.Equal(t, codes.Error, SpanNamed(rec, "failing").Status().Code)
+		assert.Equal(t, "boom", SpanNamed(rec, "failing").Status().Description)
+		assert.Equal(t, codes.Unset, SpanNamed(rec, "passing").Status().Code)
+	})
+}
+
+func TestSetup(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	t.Run("Stdout exporter writes spans on shutdown", func(t *testing.T) {
+		var out bytes.Buffer
+		shutdown, err := Setup(context.Background(), ExporterStdout, "", "test-service", &out)
+		require.NoError(t, err)
+
+		_, span := Start(context.Background(), "exported")
+		span.End()
+		require.NoError(t, shutdown(context.Background()))
+
+		assert.Contains(t, out.String(), `"Name":"exported"`)
+		assert.Contains(t, out.String(), "test-service")
+	})
+
+	t.Run("None installs nothing", func(t *testing.T) {
+		before := otel.GetTracerProvider()
+
+		shutdown, err := Setup(context.Background(), ExporterNone, "", "test-service", nil)
+
+		require.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+		assert.Equal(t, before, otel.GetTracerProvider())
+	})
+
+	t.Run("Unknown exporter", func(t *testing.T) {
+		_, err := Setup(context.Background(), "jaeger", "", "test-service", nil)
+
+		assert.ErrorContains(t, err, `invalid trace exporter "jaeger"`)
+	})
+}
@@ -26,6 +26,11 @@ const docTemplate = `{
     "paths": {
         "/admin/audit": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "Get recent audit events for administrative review",
                 "consumes": [
                     "application/json"
@@ -57,9 +62,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/stats": {
+        "/admin/audit/head": {
             "get": {
-                "description": "Get comprehensive statistics about tools, users, and events",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the sequence number and hash of the newest chained event, signed with the configured Ed25519 key. Storing the export outside the database anchors the chain: if the event at that seq later carries a different hash, the log was rewritten, even when every hash after it was recomputed. The signature covers payload exactly.",
                 "consumes": [
                     "application/json"
                 ],
@@ -69,12 +79,21 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "Get system statistics",
+                "summary": "Export the signed head of the audit log's hash chain",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/server.StatsResponse"
+                            "$ref": "#/definitions/domain.SignedChainHead"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
@@ -85,13 +104,27 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "503": {
+                        "description": "audit.signing_key is not set",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/events": {
+        "/admin/audit/verify": {
             "get": {
-                "description": "Get a list of events with pagination and optional filtering",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Walk the hash chain of the event log from the first chained event up to the current head, recomputing each event's hash, and report the first broken link: a missing event, an event whose prev_hash is not its predecessor's hash, or one whose contents no longer match its hash. Events logged before the chain existed are counted as unchained_events.",
                 "consumes": [
                     "application/json"
                 ],
@@ -99,58 +132,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "events"
-                ],
-                "summary": "List all events",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 50,
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "Offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by event type",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by tool ID",
-                        "name": "tool_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by user ID",
-                        "name": "user_id",
-                        "in": "query"
-                    }
+                    "admin"
                 ],
+                "summary": "Verify the audit log's hash chain",
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ChainVerification"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/domain.Event"
-                                }
+                                "type": "string"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -161,9 +163,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/events/{id}": {
+        "/admin/jobs": {
             "get": {
-                "description": "Get a specific event by its ID",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the registered background jobs with their schedule, next run and recent runs",
                 "consumes": [
                     "application/json"
                 ],
@@ -171,27 +178,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "events"
-                ],
-                "summary": "Get an event by ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Event ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "List background jobs",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Event"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/jobs.JobInfo"
+                                }
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -202,9 +206,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tools": {
-            "get": {
-                "description": "Get a list of tools with pagination and optional status filtering",
+        "/admin/jobs/{name}/run": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start a run of the named job in the background. The run shows up in GET /admin/jobs.",
                 "consumes": [
                     "application/json"
                 ],
@@ -212,46 +221,48 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tools"
+                    "admin"
                 ],
-                "summary": "List all tools",
+                "summary": "Run a background job now",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "Offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Filter by status",
-                        "name": "status",
-                        "in": "query"
+                        "description": "Job name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/domain.Tool"
-                                }
+                                "type": "string"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -260,9 +271,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new tool with name and status",
+            }
+        },
+        "/admin/projection/tools": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replay every tool event in order to rebuild each tool's status and current user, and list the tools whose row differs from that projection (or that have no events). Soft-deleted tools are not checked. Nothing is changed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -270,29 +288,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tools"
+                    "admin"
                 ],
-                "summary": "Create a new tool",
-                "parameters": [
-                    {
-                        "description": "Tool data",
-                        "name": "tool",
-                        "in": "body",
-                        "required": true,
+                "summary": "Check tools against the event log",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/server.CreateToolRequest"
+                            "$ref": "#/definitions/server.ProjectionReportResponse"
                         }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/domain.Tool"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -303,9 +319,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tools/{id}": {
-            "get": {
-                "description": "Get a specific tool by its ID",
+        "/admin/projection/tools/repair": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Run the same check as GET /admin/projection/tools and rewrite each diverging tool to the projected status and current user. Every repair logs a TOOL_UPDATED event. A tool changed since it was checked is skipped and reports repair_error.",
                 "consumes": [
                     "application/json"
                 ],
@@ -313,27 +334,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tools"
-                ],
-                "summary": "Get a tool by ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Tool ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "Repair tools from the event log",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Tool"
+                            "$ref": "#/definitions/server.ProjectionReportResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -342,9 +363,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update a tool's name and status",
+            }
+        },
+        "/admin/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get tool, user and event totals with their breakdowns, current and overdue checkouts, and the events, top borrowers and most used tools of the last ` + "`" + `days` + "`" + ` days. The snapshot is cached for up to 30 seconds.",
                 "consumes": [
                     "application/json"
                 ],
@@ -352,32 +380,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tools"
+                    "admin"
                 ],
-                "summary": "Update a tool",
+                "summary": "Get system statistics",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Tool ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated tool data",
-                        "name": "tool",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/server.UpdateToolRequest"
-                        }
+                        "type": "integer",
+                        "default": 30,
+                        "description": "Activity window in days (1-365)",
+                        "name": "days",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Tool"
+                            "$ref": "#/definitions/server.StatsResponse"
                         }
                     },
                     "400": {
@@ -389,8 +408,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -399,9 +418,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a tool from the system",
+            }
+        },
+        "/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a list of events with pagination and optional filtering. Metadata fields filter as metadata.\u003ckey\u003e=value, e.g. metadata.vendor=Acme or metadata.condition=DAMAGED; keys are api_key_id, api_key_prefix, due_at, condition, vendor, cost_cents and reservation_id, or before.\u003cfield\u003e and after.\u003cfield\u003e to look into the tool or user snapshots (e.g. metadata.after.status=LOST).",
                 "consumes": [
                     "application/json"
                 ],
@@ -409,21 +435,67 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tools"
+                    "events"
                 ],
-                "summary": "Delete a tool",
+                "summary": "List all events",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Tool ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Cursor from next_cursor of the previous page. Best-effort: a row written by a transaction still open when the page was read can be skipped",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset (deprecated, use cursor)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by event type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by tool ID",
+                        "name": "tool_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by user ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a metadata field (any metadata.\u003ckey\u003e is accepted, see the description)",
+                        "name": "metadata.condition",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.EventListResponse"
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of rows matching the filters"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -433,6 +505,43 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/events/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a specific event by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get an event by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Event"
+                        }
                     },
                     "404": {
                         "description": "Not Found",
@@ -446,9 +555,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tools/{id}/checkin": {
-            "post": {
-                "description": "Check in a tool that was previously checked out",
+        "/events/{id}/diff": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the fields an event changed on its tool or user, from the snapshots recorded with it. Creates show every field coming from null and deletes every field going to null. Events that record no snapshot (overdue and reservation events, and older events) have no changes.",
                 "consumes": [
                     "application/json"
                 ],
@@ -456,33 +570,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tools"
+                    "events"
                 ],
-                "summary": "Check in a tool from a user",
+                "summary": "Get what an event changed",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tool ID",
+                        "description": "Event ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Checkin data",
-                        "name": "checkin",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/server.CheckinToolRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/domain.EventDiff"
                         }
                     },
                     "400": {
@@ -506,9 +610,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tools/{id}/checkout": {
-            "post": {
-                "description": "Check out a tool to a specific user with optional notes",
+        "/reports/utilization": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Derive per-tool (or per-borrower) usage from the event history over [from, to): share of time checked out, number of loans started, mean loan duration, and time in maintenance or lost. Durations are in seconds.",
                 "consumes": [
                     "application/json"
                 ],
@@ -516,33 +625,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tools"
+                    "reports"
                 ],
-                "summary": "Check out a tool to a user",
+                "summary": "Tool utilization report",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tool ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Window start (RFC3339), default 30 days before to",
+                        "name": "from",
+                        "in": "query"
                     },
                     {
-                        "description": "Checkout data",
-                        "name": "checkout",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/server.CheckoutToolRequest"
-                        }
+                        "type": "string",
+                        "description": "Window end (RFC3339), default now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "tool",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "tool",
+                        "description": "Aggregate by tool or user",
+                        "name": "group_by",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/server.UtilizationResponse"
                         }
                     },
                     "400": {
@@ -554,8 +669,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -566,9 +681,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tools/{id}/history": {
+        "/reservations/{id}": {
             "get": {
-                "description": "Get the complete event history for a specific tool",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a single reservation by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -576,13 +696,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tools"
+                    "reservations"
                 ],
-                "summary": "Get tool history",
+                "summary": "Get a reservation",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tool ID",
+                        "description": "Reservation ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -591,18 +711,21 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Reservation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/domain.Event"
-                                }
+                                "type": "string"
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -613,9 +736,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tools/{id}/lost": {
+        "/reservations/{id}/cancel": {
             "post": {
-                "description": "Mark a tool as lost or missing",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancel an active reservation. Only its holder or a manager may cancel it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -623,24 +751,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tools"
+                    "reservations"
                 ],
-                "summary": "Mark a tool as lost",
+                "summary": "Cancel a reservation",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tool ID",
+                        "description": "Reservation ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Lost data",
-                        "name": "lost",
+                        "description": "Optional notes",
+                        "name": "cancel",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/server.MarkLostRequest"
+                            "$ref": "#/definitions/server.ReservationActionRequest"
                         }
                     }
                 ],
@@ -648,8 +775,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/domain.Reservation"
                         }
                     },
                     "400": {
@@ -661,6 +787,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -673,9 +808,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tools/{id}/maintenance": {
+        "/reservations/{id}/checkout": {
             "post": {
-                "description": "Mark a tool as being in maintenance",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Convert an active reservation into a checkout of its tool to the reservation's holder. Only its holder or a manager may convert it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -683,24 +823,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tools"
+                    "reservations"
                 ],
-                "summary": "Send a tool to maintenance",
+                "summary": "Check out a reserved tool",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tool ID",
+                        "description": "Reservation ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Maintenance data",
-                        "name": "maintenance",
+                        "description": "Optional notes",
+                        "name": "checkout",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/server.MaintenanceRequest"
+                            "$ref": "#/definitions/server.ReservationActionRequest"
                         }
                     }
                 ],
@@ -721,6 +860,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -729,13 +877,27 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/users": {
+        "/search": {
             "get": {
-                "description": "Get a list of users with pagination and optional role filtering",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Full-text search over tool names, user names and emails, and event notes. Every word of q must match, and words match as prefixes so partly typed queries work (type-ahead). Hits are ranked best first; snippet is HTML with the matched words wrapped in \u003cmark\u003e. Only the kinds of records the caller may read are searched.",
                 "consumes": [
                     "application/json"
                 ],
@@ -743,46 +905,59 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "search"
                 ],
-                "summary": "List all users",
+                "summary": "Search tools, users and events",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Search text",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "Offset",
-                        "name": "offset",
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "tool",
+                                "user",
+                                "event"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Kinds of records to search (repeat or comma-separate), default all the caller may read",
+                        "name": "type",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Filter by role (EMPLOYEE, ADMIN, MANAGER)",
-                        "name": "role",
+                        "maximum": 50,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Maximum number of hits",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.SearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/domain.User"
-                                }
+                                "type": "string"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -791,9 +966,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new user with name, email, and role",
+            }
+        },
+        "/tools": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a list of tools with pagination, filtering and sorting. Filters combine with AND. A sorted list pages by offset; cursors follow the default newest-first order only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -801,17 +983,153 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "tools"
                 ],
-                "summary": "Create a new user",
+                "summary": "List all tools",
                 "parameters": [
                     {
-                        "description": "User data",
-                        "name": "user",
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from next_cursor of the previous page. Best-effort: a row written by a transaction still open when the page was read can be skipped",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset (deprecated, use cursor)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filter by status; repeat or comma-separate for several",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by the user holding the tool",
+                        "name": "current_user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by name containing this text, ignoring case",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last checked out before (RFC3339)",
+                        "name": "checked_out_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last checked out at or after (RFC3339)",
+                        "name": "checked_out_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before (RFC3339)",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after (RFC3339)",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only overdue (true) or not overdue (false) tools",
+                        "name": "overdue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields, prefixed with - for descending: name, status, created_at, updated_at, last_checked_out_at, due_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also list soft-deleted tools (admin only)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.ToolListResponse"
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of rows matching the filters"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new tool with name and status",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tools"
+                ],
+                "summary": "Create a new tool",
+                "parameters": [
+                    {
+                        "description": "Tool data",
+                        "name": "tool",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/server.CreateUserRequest"
+                            "$ref": "#/definitions/server.CreateToolRequest"
                         }
                     }
                 ],
@@ -819,7 +1137,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/domain.User"
+                            "$ref": "#/definitions/domain.Tool"
                         }
                     },
                     "400": {
@@ -834,9 +1152,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
+        "/tools/overdue": {
             "get": {
-                "description": "Get a specific user by their ID",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get checked-out tools that are past their due date, most overdue first",
                 "consumes": [
                     "application/json"
                 ],
@@ -844,27 +1167,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "tools"
                 ],
-                "summary": "Get a user by ID",
+                "summary": "List overdue tools",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.User"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/domain.Tool"
+                                }
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -873,9 +1209,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update a user's name, email, and role",
+            }
+        },
+        "/tools/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a specific tool by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -883,36 +1226,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "tools"
                 ],
-                "summary": "Update a user",
+                "summary": "Get a tool by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Tool ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated user data",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/server.UpdateUserRequest"
-                        }
+                        "type": "boolean",
+                        "description": "Also find a soft-deleted tool (admin only)",
+                        "name": "include_deleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.User"
+                            "$ref": "#/definitions/domain.Tool"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -931,8 +1271,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Delete a user from the system",
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a tool's name, status and default loan period",
                 "consumes": [
                     "application/json"
                 ],
@@ -940,21 +1285,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "tools"
                 ],
-                "summary": "Delete a user",
+                "summary": "Update a tool",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Tool ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Updated tool data",
+                        "name": "tool",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.UpdateToolRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Tool"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -973,13 +1336,34 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
-            }
-        },
-        "/users/{id}/activity": {
-            "get": {
-                "description": "Get the complete activity history for a specific user",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft-delete a tool: it disappears from listings but keeps its history and can be restored",
                 "consumes": [
                     "application/json"
                 ],
@@ -987,33 +1371,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "tools"
                 ],
-                "summary": "Get user activity",
+                "summary": "Delete a tool",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Tool ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/domain.Event"
-                                }
+                                "type": "string"
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1024,9 +1432,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/tools": {
-            "get": {
-                "description": "Get list of tools currently checked out by a specific user",
+        "/tools/{id}/checkin": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Check in a tool that was previously checked out",
                 "consumes": [
                     "application/json"
                 ],
@@ -1034,33 +1447,79 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "tools"
                 ],
-                "summary": "Get tools assigned to user",
+                "summary": "Check in a tool from a user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Tool ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Checkin data",
+                        "name": "checkin",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.CheckinToolRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "array",
-                                "items": {
-                                    "type": "string"
-                                }
+                                "type": "string"
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1070,88 +1529,1978 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "domain.Event": {
-            "type": "object",
-            "properties": {
-                "actor_id": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "metadata": {
-                    "type": "string"
-                },
-                "notes": {
-                    "type": "string"
-                },
-                "tool_id": {
-                    "type": "string"
-                },
-                "type": {
-                    "$ref": "#/definitions/domain.EventType"
-                },
-                "user_id": {
-                    "type": "string"
+        },
+        "/tools/{id}/checkout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Check out a tool to a specific user with optional notes and due date. Refused with 409 while another user's reservation is active, unless a manager sets override_reservation.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tools"
+                ],
+                "summary": "Check out a tool to a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tool ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Checkout data",
+                        "name": "checkout",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.CheckoutToolRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
                 }
             }
         },
-        "domain.EventType": {
-            "type": "string",
-            "enum": [
-                "TOOL_CREATED",
-                "TOOL_UPDATED",
-                "TOOL_DELETED",
-                "TOOL_CHECKED_OUT",
-                "TOOL_CHECKED_IN",
-                "TOOL_MAINTENANCE",
-                "TOOL_LOST",
-                "USER_CREATED",
-                "USER_UPDATED",
-                "USER_DELETED"
+        "/tools/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the event history for a specific tool, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tools"
+                ],
+                "summary": "Get tool history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tool ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1000,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from next_cursor of the previous page. Best-effort: a row written by a transaction still open when the page was read can be skipped",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.EventListResponse"
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of rows matching the filters"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tools/{id}/lost": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark a tool as lost or missing",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tools"
+                ],
+                "summary": "Mark a tool as lost",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tool ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Lost data",
+                        "name": "lost",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.MarkLostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tools/{id}/maintenance": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark a tool as being in maintenance",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tools"
+                ],
+                "summary": "Send a tool to maintenance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tool ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Maintenance data",
+                        "name": "maintenance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.MaintenanceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tools/{id}/reservations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List reservations of a tool (any status) overlapping the optional [from, to) window, ordered by start",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Get a tool's booking calendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tool ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Window start (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Window end (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/domain.Reservation"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Book a tool for a user over [starts_at, ends_at). Fails with 409 when the window overlaps another active reservation, or when the tool is checked out to someone else and the window has already started.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Reserve a tool",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tool ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reservation data",
+                        "name": "reservation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.CreateReservationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Reservation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tools/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Undo the soft delete of a tool; it comes back as it was when deleted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tools"
+                ],
+                "summary": "Restore a deleted tool",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tool ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Tool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a list of users with pagination and optional role filtering",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List all users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from next_cursor of the previous page. Best-effort: a row written by a transaction still open when the page was read can be skipped",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset (deprecated, use cursor)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by role (EMPLOYEE, ADMIN, MANAGER)",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also list soft-deleted users (admin only)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.UserListResponse"
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of rows matching the filters"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new user with name, email, and role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "User data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a specific user by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get a user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also find a soft-deleted user (admin only)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a user's name, email, and role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Updated user data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft-delete a user: they disappear from listings and can no longer sign in, but keep their history and can be restored",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/activity": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the activity history for a specific user, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user activity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1000,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from next_cursor of the previous page. Best-effort: a row written by a transaction still open when the page was read can be skipped",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.EventListResponse"
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of rows matching the filters"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List API keys for a user, including revoked and expired keys",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "List a user's API keys",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/domain.APIKey"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issue a new API key for the calling user; no one can create a key for another user. The plaintext key is only returned in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Create a personal API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "API key data",
+                        "name": "api_key",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.CreateAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/server.CreateAPIKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/api-keys/{keyId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke one of a user's API keys. Revoked keys can no longer authenticate.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Revoke an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "keyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/reservations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List reservations held by a user, most recent start first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Get a user's reservations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/domain.Reservation"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Undo the soft delete of a user. Fails with 409 when a live user has taken their email meanwhile.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Restore a deleted user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/tools": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get list of tools currently checked out by a specific user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get tools assigned to user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.APIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.BrokenLink": {
+            "type": "object",
+            "properties": {
+                "event_id": {
+                    "description": "EventID is the event stored at Seq; empty when the event is missing.",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "seq": {
+                    "description": "Seq is the position in the chain that failed.",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ChainHead": {
+            "type": "object",
+            "properties": {
+                "hash": {
+                    "type": "string"
+                },
+                "seq": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ChainVerification": {
+            "type": "object",
+            "properties": {
+                "broken_link": {
+                    "$ref": "#/definitions/domain.BrokenLink"
+                },
+                "events_checked": {
+                    "description": "EventsChecked counts the chained events that were walked.",
+                    "type": "integer"
+                },
+                "head": {
+                    "$ref": "#/definitions/domain.ChainHead"
+                },
+                "unchained_events": {
+                    "description": "UnchainedEvents counts events without a place in the chain. Events logged\nbefore the chain existed are unchained; the count should never grow.",
+                    "type": "integer"
+                },
+                "valid": {
+                    "description": "Valid is true when every chained event up to Head hashes as stored and links to its predecessor.",
+                    "type": "boolean"
+                },
+                "verified_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Event": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "string"
+                },
+                "chain_seq": {
+                    "description": "ChainSeq, PrevHash and Hash place the event in the audit log's hash chain (see\nHashEvent); events logged before the chain existed have none.",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "description": "Metadata holds the details of the event; which fields it may set depends on Type.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.EventMetadata"
+                        }
+                    ]
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "prev_hash": {
+                    "type": "string"
+                },
+                "tool_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/domain.EventType"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.EventDiff": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FieldChange"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "tool_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/domain.EventType"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.EventMetadata": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "object"
+                },
+                "api_key_id": {
+                    "description": "APIKeyID and APIKeyPrefix identify the API key the event was logged with, if any.",
+                    "type": "string"
+                },
+                "api_key_prefix": {
+                    "type": "string"
+                },
+                "before": {
+                    "description": "Before and After are JSON snapshots of the tool or user; Before is absent on\ncreation and After on deletion.",
+                    "type": "object"
+                },
+                "changes": {
+                    "description": "Changes lists the fields an update changed.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FieldChange"
+                    }
+                },
+                "condition": {
+                    "description": "Condition is the state of the tool at checkout.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.ToolCondition"
+                        }
+                    ]
+                },
+                "cost_cents": {
+                    "type": "integer"
+                },
+                "due_at": {
+                    "description": "DueAt is the due date of a checkout or of an overdue loan.",
+                    "type": "string"
+                },
+                "reservation_id": {
+                    "description": "ReservationID is the reservation a reservation event concerns.",
+                    "type": "string"
+                },
+                "vendor": {
+                    "description": "Vendor and CostCents describe the repair a tool is sent to maintenance for.",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.EventType": {
+            "type": "string",
+            "enum": [
+                "TOOL_CREATED",
+                "TOOL_UPDATED",
+                "TOOL_DELETED",
+                "TOOL_CHECKED_OUT",
+                "TOOL_CHECKED_IN",
+                "TOOL_MAINTENANCE",
+                "TOOL_LOST",
+                "TOOL_OVERDUE",
+                "TOOL_RESTORED",
+                "USER_CREATED",
+                "USER_UPDATED",
+                "USER_DELETED",
+                "USER_RESTORED",
+                "RESERVATION_CREATED",
+                "RESERVATION_CANCELLED",
+                "RESERVATION_CONVERTED"
+            ],
+            "x-enum-varnames": [
+                "EventTypeToolCreated",
+                "EventTypeToolUpdated",
+                "EventTypeToolDeleted",
+                "EventTypeToolCheckedOut",
+                "EventTypeToolCheckedIn",
+                "EventTypeToolMaintenance",
+                "EventTypeToolLost",
+                "EventTypeToolOverdue",
+                "EventTypeToolRestored",
+                "EventTypeUserCreated",
+                "EventTypeUserUpdated",
+                "EventTypeUserDeleted",
+                "EventTypeUserRestored",
+                "EventTypeReservationCreated",
+                "EventTypeReservationCancelled",
+                "EventTypeReservationConverted"
+            ]
+        },
+        "domain.FieldChange": {
+            "type": "object",
+            "properties": {
+                "after": {},
+                "before": {},
+                "field": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.JobRun": {
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "job_name": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.JobRunStatus"
+                },
+                "trigger": {
+                    "$ref": "#/definitions/domain.JobRunTrigger"
+                }
+            }
+        },
+        "domain.JobRunStatus": {
+            "type": "string",
+            "enum": [
+                "RUNNING",
+                "SUCCEEDED",
+                "FAILED"
+            ],
+            "x-enum-varnames": [
+                "JobRunStatusRunning",
+                "JobRunStatusSucceeded",
+                "JobRunStatusFailed"
+            ]
+        },
+        "domain.JobRunTrigger": {
+            "type": "string",
+            "enum": [
+                "SCHEDULE",
+                "MANUAL"
+            ],
+            "x-enum-varnames": [
+                "JobRunTriggerSchedule",
+                "JobRunTriggerManual"
+            ]
+        },
+        "domain.Reservation": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.ReservationStatus"
+                },
+                "tool_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ReservationStatus": {
+            "type": "string",
+            "enum": [
+                "ACTIVE",
+                "CANCELLED",
+                "CONVERTED"
+            ],
+            "x-enum-varnames": [
+                "ReservationStatusActive",
+                "ReservationStatusCancelled",
+                "ReservationStatusConverted"
+            ]
+        },
+        "domain.SearchHit": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "number"
+                },
+                "snippet": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "tool_id": {
+                    "description": "ToolID and UserID link an event hit to the tool and user it concerns.",
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/domain.SearchHitType"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SearchHitType": {
+            "type": "string",
+            "enum": [
+                "tool",
+                "user",
+                "event"
+            ],
+            "x-enum-varnames": [
+                "SearchHitTool",
+                "SearchHitUser",
+                "SearchHitEvent"
+            ]
+        },
+        "domain.SignedChainHead": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string"
+                },
+                "exported_at": {
+                    "type": "string"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "Payload is the exact text that was signed (see ChainHeadPayload).",
+                    "type": "string"
+                },
+                "public_key": {
+                    "description": "PublicKey and Signature are base64 encoded.",
+                    "type": "string"
+                },
+                "seq": {
+                    "type": "integer"
+                },
+                "signature": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Tool": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current_user_id": {
+                    "type": "string"
+                },
+                "default_loan_days": {
+                    "description": "DefaultLoanDays sets DueAt on checkouts that do not specify one.",
+                    "type": "integer"
+                },
+                "deleted_at": {
+                    "description": "DeletedAt is set while the tool is soft-deleted.",
+                    "type": "string"
+                },
+                "due_at": {
+                    "description": "DueAt is when the current checkout should come back (nil: open-ended).",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_checked_out_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "overdue": {
+                    "description": "Overdue is computed on read: checked out and past DueAt.",
+                    "type": "boolean"
+                },
+                "overdue_notified_at": {
+                    "description": "OverdueNotifiedAt records the TOOL_OVERDUE event of the current checkout, so it is emitted once.",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.ToolStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ToolCheckouts": {
+            "type": "object",
+            "properties": {
+                "checkouts": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "tool_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ToolCondition": {
+            "type": "string",
+            "enum": [
+                "GOOD",
+                "FAIR",
+                "POOR",
+                "DAMAGED"
             ],
             "x-enum-varnames": [
-                "EventTypeToolCreated",
-                "EventTypeToolUpdated",
-                "EventTypeToolDeleted",
-                "EventTypeToolCheckedOut",
-                "EventTypeToolCheckedIn",
-                "EventTypeToolMaintenance",
-                "EventTypeToolLost",
-                "EventTypeUserCreated",
-                "EventTypeUserUpdated",
-                "EventTypeUserDeleted"
+                "ToolConditionGood",
+                "ToolConditionFair",
+                "ToolConditionPoor",
+                "ToolConditionDamaged"
             ]
         },
-        "domain.Tool": {
+        "domain.ToolDivergence": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "fields": {
+                    "description": "Fields names the fields that differ; it is empty when the tool has no events.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "current_user_id": {
+                "name": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
+                "projected": {
+                    "description": "Projected is the state the events lead to, nil when the tool has no events.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.ToolState"
+                        }
+                    ]
                 },
-                "last_checked_out_at": {
+                "repair_error": {
+                    "description": "RepairError tells why a repair was attempted but not applied.",
                     "type": "string"
                 },
-                "name": {
+                "repaired": {
+                    "description": "Repaired is set once the row has been rewritten to the projected state.",
+                    "type": "boolean"
+                },
+                "table": {
+                    "$ref": "#/definitions/domain.ToolState"
+                },
+                "tool_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ToolState": {
+            "type": "object",
+            "properties": {
+                "current_user_id": {
                     "type": "string"
                 },
                 "status": {
                     "$ref": "#/definitions/domain.ToolStatus"
-                },
-                "updated_at": {
-                    "type": "string"
                 }
             }
         },
@@ -1176,6 +3525,10 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
+                "deleted_at": {
+                    "description": "DeletedAt is set while the user is soft-deleted.",
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
@@ -1190,6 +3543,23 @@ const docTemplate = `{
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.UserCheckouts": {
+            "type": "object",
+            "properties": {
+                "checkouts": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
@@ -1206,6 +3576,75 @@ const docTemplate = `{
                 "UserRoleManager"
             ]
         },
+        "domain.Utilization": {
+            "type": "object",
+            "properties": {
+                "checked_out_pct": {
+                    "type": "number"
+                },
+                "checked_out_seconds": {
+                    "type": "integer"
+                },
+                "loans": {
+                    "type": "integer"
+                },
+                "lost_seconds": {
+                    "type": "integer"
+                },
+                "maintenance_seconds": {
+                    "type": "integer"
+                },
+                "mean_loan_seconds": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "tool_id": {
+                    "type": "string"
+                },
+                "tracked_seconds": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.UtilizationGroup": {
+            "type": "string",
+            "enum": [
+                "tool",
+                "user"
+            ],
+            "x-enum-varnames": [
+                "UtilizationByTool",
+                "UtilizationByUser"
+            ]
+        },
+        "jobs.JobInfo": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "recent_runs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.JobRun"
+                    }
+                },
+                "running": {
+                    "type": "boolean"
+                },
+                "schedule": {
+                    "type": "string"
+                }
+            }
+        },
         "server.CheckinToolRequest": {
             "type": "object",
             "required": [
@@ -1226,9 +3665,78 @@ const docTemplate = `{
                 "user_id"
             ],
             "properties": {
+                "condition": {
+                    "description": "Condition is the state the tool leaves in: GOOD, FAIR, POOR or DAMAGED.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.ToolCondition"
+                        }
+                    ]
+                },
+                "due_at": {
+                    "description": "DueAt is when the tool should come back; omitted, the tool's default loan period applies.",
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "override_reservation": {
+                    "description": "OverrideReservation checks out despite another user's active reservation (managers only).",
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "server.CreateAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "$ref": "#/definitions/domain.APIKey"
+                },
+                "key": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.CreateReservationRequest": {
+            "type": "object",
+            "required": [
+                "ends_at",
+                "starts_at",
+                "user_id"
+            ],
+            "properties": {
+                "ends_at": {
+                    "type": "string"
+                },
                 "notes": {
                     "type": "string"
                 },
+                "starts_at": {
+                    "type": "string"
+                },
                 "user_id": {
                     "type": "string"
                 }
@@ -1266,17 +3774,53 @@ const docTemplate = `{
                 }
             }
         },
+        "server.EventListResponse": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "type": "string"
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Event"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
         "server.MaintenanceRequest": {
             "type": "object",
             "required": [
                 "user_id"
             ],
             "properties": {
+                "cost_cents": {
+                    "type": "integer"
+                },
                 "notes": {
                     "type": "string"
                 },
                 "user_id": {
                     "type": "string"
+                },
+                "vendor": {
+                    "description": "Vendor and CostCents (the quoted cost in cents) describe the repair; both are optional.",
+                    "type": "string"
                 }
             }
         },
@@ -1294,9 +3838,81 @@ const docTemplate = `{
                 }
             }
         },
+        "server.ProjectionReportResponse": {
+            "type": "object",
+            "properties": {
+                "divergences": {
+                    "description": "Divergences lists the tools whose row disagrees with their events, or that have no events.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ToolDivergence"
+                    }
+                },
+                "events_replayed": {
+                    "type": "integer"
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "repaired": {
+                    "description": "Repaired counts the divergences rewritten to the projected state.",
+                    "type": "integer"
+                },
+                "tools_checked": {
+                    "type": "integer"
+                }
+            }
+        },
+        "server.ReservationActionRequest": {
+            "type": "object",
+            "properties": {
+                "notes": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.SearchResponse": {
+            "type": "object",
+            "properties": {
+                "hits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SearchHit"
+                    }
+                },
+                "query": {
+                    "type": "string"
+                }
+            }
+        },
         "server.StatsResponse": {
             "type": "object",
             "properties": {
+                "active_checkouts": {
+                    "type": "integer"
+                },
+                "events_by_type": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "generated_at": {
+                    "description": "GeneratedAt is when the snapshot was computed; it is cached for a short while.",
+                    "type": "string"
+                },
+                "most_used_tools": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ToolCheckouts"
+                    }
+                },
+                "overdue_tools": {
+                    "type": "integer"
+                },
+                "since": {
+                    "type": "string"
+                },
                 "tools_by_status": {
                     "type": "object",
                     "properties": {
@@ -1314,6 +3930,12 @@ const docTemplate = `{
                         }
                     }
                 },
+                "top_borrowers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.UserCheckouts"
+                    }
+                },
                 "total_events": {
                     "type": "integer"
                 },
@@ -1336,6 +3958,39 @@ const docTemplate = `{
                             "type": "integer"
                         }
                     }
+                },
+                "window_days": {
+                    "description": "The activity figures below cover the last WindowDays days, starting at Since.",
+                    "type": "integer"
+                }
+            }
+        },
+        "server.ToolListResponse": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "type": "string"
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Tool"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
@@ -1345,6 +4000,10 @@ const docTemplate = `{
                 "name"
             ],
             "properties": {
+                "default_loan_days": {
+                    "description": "DefaultLoanDays sets the due date of checkouts that do not give one; omit to clear it.",
+                    "type": "integer"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -1371,6 +4030,63 @@ const docTemplate = `{
                     "$ref": "#/definitions/domain.UserRole"
                 }
             }
+        },
+        "server.UserListResponse": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "type": "string"
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.User"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "server.UtilizationResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "$ref": "#/definitions/domain.UtilizationGroup"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Utilization"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "JWT bearer token, e.g. \"Bearer eyJhbGciOi...\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
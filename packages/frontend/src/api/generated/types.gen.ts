@@ -71,6 +71,16 @@ export type ServerCreateUserRequest = {
   role?: DomainUserRole;
 };
 
+export type ServerEventListResponse = {
+  cursor?: string;
+  has_more?: boolean;
+  items?: Array<DomainEvent>;
+  limit?: number;
+  next_cursor?: string;
+  offset?: number;
+  total?: number;
+};
+
 export type ServerMaintenanceRequest = {
   notes?: string;
   user_id: string;
@@ -98,6 +108,16 @@ export type ServerStatsResponse = {
   };
 };
 
+export type ServerToolListResponse = {
+  cursor?: string;
+  has_more?: boolean;
+  items?: Array<DomainTool>;
+  limit?: number;
+  next_cursor?: string;
+  offset?: number;
+  total?: number;
+};
+
 export type ServerUpdateToolRequest = {
   name: string;
   status?: DomainToolStatus;
@@ -109,6 +129,16 @@ export type ServerUpdateUserRequest = {
   role: DomainUserRole;
 };
 
+export type ServerUserListResponse = {
+  cursor?: string;
+  has_more?: boolean;
+  items?: Array<DomainUser>;
+  limit?: number;
+  next_cursor?: string;
+  offset?: number;
+  total?: number;
+};
+
 export type GetAdminAuditData = {
   body?: never;
   path?: never;
@@ -174,7 +204,11 @@ export type GetEventsData = {
      */
     limit?: number;
     /**
-     * Offset
+     * Cursor from next_cursor of the previous page
+     */
+    cursor?: string;
+    /**
+     * Offset (deprecated, use cursor)
      */
     offset?: number;
     /**
@@ -208,9 +242,7 @@ export type GetEventsResponses = {
   /**
    * OK
    */
-  200: {
-    [key: string]: Array<DomainEvent>;
-  };
+  200: ServerEventListResponse;
 };
 
 export type GetEventsResponse = GetEventsResponses[keyof GetEventsResponses];
@@ -256,13 +288,53 @@ export type GetToolsData = {
      */
     limit?: number;
     /**
-     * Offset
+     * Cursor from next_cursor of the previous page
+     */
+    cursor?: string;
+    /**
+     * Offset (deprecated, use cursor)
      */
     offset?: number;
     /**
-     * Filter by status
+     * Filter by status; repeat or comma-separate for several
+     */
+    status?: Array<string>;
+    /**
+     * Filter by the user holding the tool
+     */
+    current_user_id?: string;
+    /**
+     * Filter by name containing this text, ignoring case
+     */
+    name?: string;
+    /**
+     * Last checked out before (RFC3339)
+     */
+    checked_out_before?: string;
+    /**
+     * Last checked out at or after (RFC3339)
+     */
+    checked_out_after?: string;
+    /**
+     * Created before (RFC3339)
+     */
+    created_before?: string;
+    /**
+     * Created at or after (RFC3339)
+     */
+    created_after?: string;
+    /**
+     * Only overdue (true) or not overdue (false) tools
+     */
+    overdue?: boolean;
+    /**
+     * Comma-separated fields, prefixed with - for descending: name, status, created_at, updated_at, last_checked_out_at, due_at
      */
-    status?: string;
+    sort?: string;
+    /**
+     * Also list soft-deleted tools (admin only)
+     */
+    include_deleted?: boolean;
   };
   url: '/tools';
 };
@@ -274,6 +346,12 @@ export type GetToolsErrors = {
   400: {
     [key: string]: string;
   };
+  /**
+   * Forbidden
+   */
+  403: {
+    [key: string]: string;
+  };
 };
 
 export type GetToolsError = GetToolsErrors[keyof GetToolsErrors];
@@ -282,9 +360,7 @@ export type GetToolsResponses = {
   /**
    * OK
    */
-  200: {
-    [key: string]: Array<DomainTool>;
-  };
+  200: ServerToolListResponse;
 };
 
 export type GetToolsResponse = GetToolsResponses[keyof GetToolsResponses];
@@ -545,9 +621,7 @@ export type GetToolsByIdHistoryResponses = {
   /**
    * OK
    */
-  200: {
-    [key: string]: Array<DomainEvent>;
-  };
+  200: ServerEventListResponse;
 };
 
 export type GetToolsByIdHistoryResponse =
@@ -651,13 +725,21 @@ export type GetUsersData = {
      */
     limit?: number;
     /**
-     * Offset
+     * Cursor from next_cursor of the previous page
+     */
+    cursor?: string;
+    /**
+     * Offset (deprecated, use cursor)
      */
     offset?: number;
     /**
      * Filter by role (EMPLOYEE, ADMIN, MANAGER)
      */
     role?: string;
+    /**
+     * Also list soft-deleted users (admin only)
+     */
+    include_deleted?: boolean;
   };
   url: '/users';
 };
@@ -669,6 +751,12 @@ export type GetUsersErrors = {
   400: {
     [key: string]: string;
   };
+  /**
+   * Forbidden
+   */
+  403: {
+    [key: string]: string;
+  };
 };
 
 export type GetUsersError = GetUsersErrors[keyof GetUsersErrors];
@@ -677,9 +765,7 @@ export type GetUsersResponses = {
   /**
    * OK
    */
-  200: {
-    [key: string]: Array<DomainUser>;
-  };
+  200: ServerUserListResponse;
 };
 
 export type GetUsersResponse = GetUsersResponses[keyof GetUsersResponses];
@@ -851,9 +937,7 @@ export type GetUsersByIdActivityResponses = {
   /**
    * OK
    */
-  200: {
-    [key: string]: Array<DomainEvent>;
-  };
+  200: ServerEventListResponse;
 };
 
 export type GetUsersByIdActivityResponse =
@@ -20,9 +20,8 @@ export const ToolListDemo: React.FC = () => {
 
         // The response has the data property
         if (response.data) {
-          // Response is typed as Record<string, DomainTool[]>
-          const toolsArray = Object.values(response.data).flat();
-          setTools(toolsArray);
+          // Lists come in an envelope: { items, total, limit, offset | cursor }
+          setTools(response.data.items ?? []);
         }
       } catch (err) {
         setError(err instanceof Error ? err.message : 'Failed to fetch tools');